@@ -94,6 +94,8 @@ type DefaultDispatcher struct {
 	router routing.Router
 	policy policy.Manager
 	stats  stats.Manager
+
+	ipLimitWarning sync.Once
 }
 
 func init() {
@@ -151,24 +153,56 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *tran
 		user = sessionInbound.User
 	}
 
-	if user != nil && len(user.Email) > 0 {
+	// Traffic looped back into routing has been counted when it was dispatched the first time.
+	if user != nil && len(user.Email) > 0 && !sessionInbound.Loopback {
 		p := d.policy.ForLevel(user.Level)
-		if p.Stats.UserUplink {
-			name := "user>>>" + user.Email + ">>>traffic>>>uplink"
+		hasQuota := user.Quota > 0
+		var counters []stats.Counter
+		if p.Stats.UserUplink || hasQuota {
+			name := stats.UserUplinkCounterName(user.Email)
 			if c, _ := stats.GetOrRegisterCounter(d.stats, name); c != nil {
 				inboundLink.Writer = &SizeStatWriter{
 					Counter: c,
 					Writer:  inboundLink.Writer,
 				}
+				counters = append(counters, c)
 			}
 		}
-		if p.Stats.UserDownlink {
-			name := "user>>>" + user.Email + ">>>traffic>>>downlink"
+		if p.Stats.UserDownlink || hasQuota {
+			name := stats.UserDownlinkCounterName(user.Email)
 			if c, _ := stats.GetOrRegisterCounter(d.stats, name); c != nil {
 				outboundLink.Writer = &SizeStatWriter{
 					Counter: c,
 					Writer:  outboundLink.Writer,
 				}
+				counters = append(counters, c)
+			}
+		}
+		if hasQuota || user.Expiry > 0 {
+			inboundLink.Writer = &QuotaWriter{
+				User:     user,
+				Counters: counters,
+				Writer:   inboundLink.Writer,
+			}
+			outboundLink.Writer = &QuotaWriter{
+				User:     user,
+				Counters: counters,
+				Writer:   outboundLink.Writer,
+			}
+		}
+		if m, ok := d.stats.(stats.UserIPManager); ok && p.IPLimit.MaxIPs > 0 && sessionInbound.Source.IsValid() && sessionInbound.Source.Address.Family().IsIP() {
+			source := sessionInbound.Source.Address.String()
+			inboundLink.Writer = &UserIPWriter{
+				Manager: m,
+				Email:   user.Email,
+				IP:      source,
+				Writer:  inboundLink.Writer,
+			}
+			outboundLink.Writer = &UserIPWriter{
+				Manager: m,
+				Email:   user.Email,
+				IP:      source,
+				Writer:  outboundLink.Writer,
 			}
 		}
 	}
@@ -185,11 +219,45 @@ func shouldOverride(result SniffResult, domainOverride []string) bool {
 	return false
 }
 
+// checkUser returns an error if the user in the inbound session is not allowed to make more connections.
+func (d *DefaultDispatcher) checkUser(ctx context.Context) error {
+	sessionInbound := session.InboundFromContext(ctx)
+	if sessionInbound == nil || sessionInbound.User == nil {
+		return nil
+	}
+	if err := stats.CheckUserQuota(d.stats, sessionInbound.User); err != nil {
+		return err
+	}
+	return d.checkIPLimit(sessionInbound.User, sessionInbound.Source)
+}
+
+// checkIPLimit returns an error if the user has too many active source IPs other than the given one.
+func (d *DefaultDispatcher) checkIPLimit(user *protocol.MemoryUser, source net.Destination) error {
+	p := d.policy.ForLevel(user.Level)
+	if p.IPLimit.MaxIPs == 0 || len(user.Email) == 0 || !source.IsValid() || !source.Address.Family().IsIP() {
+		return nil
+	}
+	m, ok := d.stats.(stats.UserIPManager)
+	if !ok {
+		d.ipLimitWarning.Do(func() {
+			newError("source IP limit of users is not enforced, which requires the stats app").AtWarning().WriteToLog()
+		})
+		return nil
+	}
+	if !m.AddUserIP(user.Email, source.Address.String(), p.IPLimit.MaxIPs, p.IPLimit.Window) {
+		return newError("user ", user.Email, " has too many source IPs, rejecting ", source.Address)
+	}
+	return nil
+}
+
 // Dispatch implements routing.Dispatcher.
 func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destination) (*transport.Link, error) {
 	if !destination.IsValid() {
 		panic("Dispatcher: Invalid destination.")
 	}
+	if err := d.checkUser(ctx); err != nil {
+		return nil, err
+	}
 	ob := &session.Outbound{
 		Target: destination,
 	}
@@ -3,8 +3,11 @@
 package dispatcher
 
 import (
+	"time"
+
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/features/stats"
 )
 
@@ -25,3 +28,62 @@ func (w *SizeStatWriter) Close() error {
 func (w *SizeStatWriter) Interrupt() {
 	common.Interrupt(w.Writer)
 }
+
+// QuotaWriter is a Writer that fails once the user expires or its traffic counters exceed its quota.
+type QuotaWriter struct {
+	User     *protocol.MemoryUser
+	Counters []stats.Counter
+	Writer   buf.Writer
+}
+
+func (w *QuotaWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if w.User.IsExpired(time.Now()) {
+		buf.ReleaseMulti(mb)
+		return newError("user ", w.User.Email, " has expired")
+	}
+	if w.User.Quota > 0 {
+		var usage int64
+		for _, c := range w.Counters {
+			usage += c.Value()
+		}
+		if usage >= w.User.Quota {
+			buf.ReleaseMulti(mb)
+			return newError("user ", w.User.Email, " has exceeded quota")
+		}
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *QuotaWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *QuotaWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
+
+// UserIPWriter is a Writer that keeps the source IP of a user active in the stats manager, while traffic goes
+// through the connection.
+type UserIPWriter struct {
+	Manager  stats.UserIPManager
+	Email    string
+	IP       string
+	Writer   buf.Writer
+	lastSeen time.Time
+}
+
+func (w *UserIPWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if now := time.Now(); now.Sub(w.lastSeen) >= time.Second {
+		w.lastSeen = now
+		w.Manager.RefreshUserIP(w.Email, w.IP)
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *UserIPWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *UserIPWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
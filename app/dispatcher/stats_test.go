@@ -2,10 +2,13 @@ package dispatcher_test
 
 import (
 	"testing"
+	"time"
 
 	. "v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/features/stats"
 )
 
 type TestCounter int64
@@ -42,3 +45,68 @@ func TestStatsWriter(t *testing.T) {
 		t.Fatal("unexpected counter value. want 7, but got ", c.Value())
 	}
 }
+
+func TestQuotaWriter(t *testing.T) {
+	var c TestCounter
+	writer := &QuotaWriter{
+		User: &protocol.MemoryUser{
+			Email: "test@v2ray.com",
+			Quota: 5,
+		},
+		Counters: []stats.Counter{&c},
+		Writer: &SizeStatWriter{
+			Counter: &c,
+			Writer:  buf.Discard,
+		},
+	}
+
+	common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("abcd"))))
+	common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("efg"))))
+	if err := writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("h"))); err == nil {
+		t.Error("expect error after quota is exceeded")
+	}
+	if c.Value() != 7 {
+		t.Error("unexpected counter value. want 7, but got ", c.Value())
+	}
+
+	expired := &QuotaWriter{
+		User: &protocol.MemoryUser{
+			Email:  "test@v2ray.com",
+			Expiry: time.Now().Add(-time.Second).Unix(),
+		},
+		Writer: buf.Discard,
+	}
+	if err := expired.WriteMultiBuffer(buf.MergeBytes(nil, []byte("abcd"))); err == nil {
+		t.Error("expect error for expired user")
+	}
+}
+
+type testUserIPManager struct {
+	refreshed []string
+}
+
+func (m *testUserIPManager) AddUserIP(email string, ip string, maxIPs uint32, window time.Duration) bool {
+	return true
+}
+
+func (m *testUserIPManager) RefreshUserIP(email string, ip string) {
+	m.refreshed = append(m.refreshed, email+" "+ip)
+}
+
+func TestUserIPWriter(t *testing.T) {
+	m := new(testUserIPManager)
+	writer := &UserIPWriter{
+		Manager: m,
+		Email:   "test@v2ray.com",
+		IP:      "1.1.1.1",
+		Writer:  buf.Discard,
+	}
+
+	// Writes within a second refresh the IP only once.
+	for i := 0; i < 3; i++ {
+		common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("abcd"))))
+	}
+	if len(m.refreshed) != 1 || m.refreshed[0] != "test@v2ray.com 1.1.1.1" {
+		t.Error("unexpected refreshes: ", m.refreshed)
+	}
+}
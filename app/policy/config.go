@@ -58,6 +58,14 @@ func (p *Policy) overrideWith(another *Policy) {
 			Connection: another.Buffer.Connection,
 		}
 	}
+	if another.IpLimit != nil {
+		p.IpLimit = &Policy_IPLimit{
+			MaxIps: another.IpLimit.MaxIps,
+		}
+		if another.IpLimit.Window != nil {
+			p.IpLimit.Window = &Second{Value: another.IpLimit.Window.Value}
+		}
+	}
 }
 
 // ToCorePolicy converts this Policy to policy.Session.
@@ -77,6 +85,12 @@ func (p *Policy) ToCorePolicy() policy.Session {
 	if p.Buffer != nil {
 		cp.Buffer.PerConnection = p.Buffer.Connection
 	}
+	if p.IpLimit != nil {
+		cp.IPLimit.MaxIPs = p.IpLimit.MaxIps
+		if p.IpLimit.Window != nil && p.IpLimit.Window.Value > 0 {
+			cp.IPLimit.Window = p.IpLimit.Window.Duration()
+		}
+	}
 	return cp
 }
 
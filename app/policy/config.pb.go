@@ -60,6 +60,7 @@ type Policy struct {
 	Timeout              *Policy_Timeout `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
 	Stats                *Policy_Stats   `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
 	Buffer               *Policy_Buffer  `protobuf:"bytes,3,opt,name=buffer,proto3" json:"buffer,omitempty"`
+	IpLimit              *Policy_IPLimit `protobuf:"bytes,4,opt,name=ip_limit,json=ipLimit,proto3" json:"ip_limit,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -111,6 +112,13 @@ func (m *Policy) GetBuffer() *Policy_Buffer {
 	return nil
 }
 
+func (m *Policy) GetIpLimit() *Policy_IPLimit {
+	if m != nil {
+		return m.IpLimit
+	}
+	return nil
+}
+
 // Timeout is a message for timeout settings in various stages, in seconds.
 type Policy_Timeout struct {
 	Handshake            *Second  `protobuf:"bytes,1,opt,name=handshake,proto3" json:"handshake,omitempty"`
@@ -262,6 +270,58 @@ func (m *Policy_Buffer) GetConnection() int32 {
 	return 0
 }
 
+// IPLimit limits the number of distinct source IPs of a user. Source IPs are tracked by the
+// stats app, which must be configured for the limit to take effect.
+type Policy_IPLimit struct {
+	// Maximum number of distinct source IPs per user. 0 for unlimited.
+	MaxIps uint32 `protobuf:"varint,1,opt,name=max_ips,json=maxIps,proto3" json:"max_ips,omitempty"`
+	// Sliding window in which a source IP is considered active, after its last
+	// connection ends. 0 for the default of 300 seconds.
+	Window               *Second  `protobuf:"bytes,2,opt,name=window,proto3" json:"window,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Policy_IPLimit) Reset()         { *m = Policy_IPLimit{} }
+func (m *Policy_IPLimit) String() string { return proto.CompactTextString(m) }
+func (*Policy_IPLimit) ProtoMessage()    {}
+func (*Policy_IPLimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48f54a345c1316d1, []int{1, 3}
+}
+
+func (m *Policy_IPLimit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Policy_IPLimit.Unmarshal(m, b)
+}
+func (m *Policy_IPLimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Policy_IPLimit.Marshal(b, m, deterministic)
+}
+func (m *Policy_IPLimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Policy_IPLimit.Merge(m, src)
+}
+func (m *Policy_IPLimit) XXX_Size() int {
+	return xxx_messageInfo_Policy_IPLimit.Size(m)
+}
+func (m *Policy_IPLimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Policy_IPLimit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Policy_IPLimit proto.InternalMessageInfo
+
+func (m *Policy_IPLimit) GetMaxIps() uint32 {
+	if m != nil {
+		return m.MaxIps
+	}
+	return 0
+}
+
+func (m *Policy_IPLimit) GetWindow() *Second {
+	if m != nil {
+		return m.Window
+	}
+	return nil
+}
+
 type SystemPolicy struct {
 	Stats                *SystemPolicy_Stats `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
@@ -401,6 +461,7 @@ func init() {
 	proto.RegisterType((*Policy_Timeout)(nil), "v2ray.core.app.policy.Policy.Timeout")
 	proto.RegisterType((*Policy_Stats)(nil), "v2ray.core.app.policy.Policy.Stats")
 	proto.RegisterType((*Policy_Buffer)(nil), "v2ray.core.app.policy.Policy.Buffer")
+	proto.RegisterType((*Policy_IPLimit)(nil), "v2ray.core.app.policy.Policy.IPLimit")
 	proto.RegisterType((*SystemPolicy)(nil), "v2ray.core.app.policy.SystemPolicy")
 	proto.RegisterType((*SystemPolicy_Stats)(nil), "v2ray.core.app.policy.SystemPolicy.Stats")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.policy.Config")
@@ -412,38 +473,41 @@ func init() {
 }
 
 var fileDescriptor_48f54a345c1316d1 = []byte{
-	// 513 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xdb, 0x6a, 0x13, 0x41,
-	0x1c, 0xc6, 0xd9, 0xa4, 0xbb, 0xad, 0xff, 0x24, 0x6d, 0x19, 0x2c, 0xc4, 0x05, 0x6b, 0x49, 0xad,
-	0xa4, 0x37, 0x13, 0x48, 0x6f, 0xd4, 0x6a, 0xc5, 0x78, 0x00, 0x41, 0xb1, 0x4c, 0x3c, 0xa0, 0x37,
-	0x61, 0xb3, 0x3b, 0xb1, 0x4b, 0x26, 0x33, 0xc3, 0x1e, 0x22, 0xfb, 0x1a, 0x3e, 0x46, 0x9f, 0xc6,
-	0x27, 0xf0, 0x59, 0x64, 0xe7, 0xe0, 0xa6, 0x92, 0x43, 0xef, 0x26, 0x1f, 0xbf, 0xef, 0x63, 0xbe,
-	0x7f, 0xfe, 0xb3, 0xf0, 0x68, 0xde, 0x4f, 0x82, 0x02, 0x87, 0x62, 0xd6, 0x0b, 0x45, 0x42, 0x7b,
-	0x81, 0x94, 0x3d, 0x29, 0x58, 0x1c, 0x16, 0xbd, 0x50, 0xf0, 0x49, 0xfc, 0x03, 0xcb, 0x44, 0x64,
-	0x02, 0x1d, 0x58, 0x2e, 0xa1, 0x38, 0x90, 0x12, 0x6b, 0xa6, 0x73, 0x08, 0xde, 0x90, 0x86, 0x82,
-	0x47, 0xe8, 0x2e, 0xb8, 0xf3, 0x80, 0xe5, 0xb4, 0xed, 0x1c, 0x39, 0xdd, 0x16, 0xd1, 0x3f, 0x3a,
-	0xbf, 0xb7, 0xc0, 0xbb, 0x54, 0x28, 0x7a, 0x01, 0xdb, 0x59, 0x3c, 0xa3, 0x22, 0xcf, 0x14, 0xd2,
-	0xe8, 0x9f, 0xe0, 0xa5, 0x99, 0x58, 0xf3, 0xf8, 0x93, 0x86, 0x89, 0x75, 0xa1, 0x27, 0xe0, 0xa6,
-	0x59, 0x90, 0xa5, 0xed, 0x9a, 0xb2, 0x1f, 0xaf, 0xb7, 0x0f, 0x4b, 0x94, 0x68, 0x07, 0x7a, 0x06,
-	0xde, 0x38, 0x9f, 0x4c, 0x68, 0xd2, 0xae, 0x2b, 0xef, 0xc3, 0xf5, 0xde, 0x81, 0x62, 0x89, 0xf1,
-	0xf8, 0xbf, 0x6a, 0xb0, 0x6d, 0x6e, 0x83, 0xce, 0xe1, 0xce, 0x55, 0xc0, 0xa3, 0xf4, 0x2a, 0x98,
-	0x52, 0xd3, 0xe3, 0xfe, 0x8a, 0x30, 0x3d, 0x18, 0x52, 0xf1, 0xe8, 0x2d, 0xec, 0x85, 0x82, 0x73,
-	0x1a, 0x66, 0xb1, 0xe0, 0xa3, 0x38, 0x62, 0xd4, 0x74, 0xd9, 0x10, 0xb1, 0x5b, 0xb9, 0xde, 0x45,
-	0x8c, 0xa2, 0x0b, 0x68, 0xe4, 0x92, 0xc5, 0x7c, 0x3a, 0x12, 0x9c, 0x15, 0xa6, 0xd3, 0x86, 0x0c,
-	0xd0, 0x8e, 0x8f, 0x9c, 0x15, 0x68, 0x00, 0xad, 0x48, 0xfc, 0xe4, 0x55, 0xc2, 0xd6, 0x6d, 0x12,
-	0x9a, 0xd6, 0x53, 0x66, 0xf8, 0x1f, 0xc0, 0x55, 0x23, 0x46, 0x0f, 0xa0, 0x91, 0xa7, 0x34, 0x19,
-	0xe9, 0x7c, 0x35, 0x93, 0x1d, 0x02, 0xa5, 0xf4, 0x59, 0x29, 0xe8, 0x18, 0x5a, 0x0a, 0xb0, 0x76,
-	0xd5, 0x79, 0x87, 0x34, 0x4b, 0xf1, 0xb5, 0xd1, 0xfc, 0x2e, 0x78, 0x7a, 0xea, 0xe8, 0x10, 0xa0,
-	0xaa, 0xab, 0xe2, 0x5c, 0xb2, 0xa0, 0x74, 0xae, 0x1d, 0x68, 0x0e, 0x8b, 0x34, 0xa3, 0xb3, 0x7f,
-	0x8b, 0x65, 0xf6, 0x42, 0xff, 0x1d, 0xa7, 0xab, 0x5a, 0x2c, 0x78, 0x6e, 0x6c, 0x87, 0xff, 0xcd,
-	0x56, 0x39, 0x81, 0xdd, 0x98, 0x8f, 0x45, 0xce, 0xa3, 0x9b, 0x6d, 0x5a, 0x46, 0x35, 0x85, 0x4e,
-	0x61, 0xdf, 0x62, 0xff, 0x75, 0xda, 0x33, 0xba, 0xad, 0xd5, 0xf9, 0xe3, 0x80, 0xf7, 0x4a, 0xbd,
-	0x23, 0x74, 0x01, 0x2e, 0xa3, 0x73, 0xca, 0xda, 0xce, 0x51, 0xbd, 0xdb, 0xe8, 0x77, 0x57, 0x5c,
-	0x53, 0xd3, 0xf8, 0x7d, 0x89, 0xbe, 0xe1, 0x59, 0x52, 0x10, 0x6d, 0x43, 0xe7, 0xe0, 0xa5, 0xaa,
-	0xc2, 0x86, 0xfd, 0x5f, 0xec, 0x49, 0x8c, 0xc5, 0xff, 0x0a, 0x50, 0x25, 0xa2, 0x7d, 0xa8, 0x4f,
-	0x69, 0x61, 0x5e, 0x6a, 0x79, 0x44, 0x67, 0xf6, 0xf5, 0xae, 0xdf, 0x47, 0x93, 0xaa, 0xd9, 0xa7,
-	0xb5, 0xc7, 0xce, 0xe0, 0x39, 0xdc, 0x0b, 0xc5, 0x6c, 0x39, 0x7e, 0xe9, 0x7c, 0xf7, 0xf4, 0xe9,
-	0xba, 0x76, 0xf0, 0xa5, 0x4f, 0x82, 0xb2, 0x5d, 0x42, 0xf1, 0x4b, 0x29, 0x4d, 0xd2, 0xd8, 0x53,
-	0x5f, 0x97, 0xb3, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xe9, 0xde, 0xee, 0x20, 0x87, 0x04, 0x00,
-	0x00,
+	// 568 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xed, 0x6e, 0xd3, 0x30,
+	0x14, 0x86, 0x95, 0x76, 0x49, 0xc7, 0xe9, 0xba, 0x4d, 0x16, 0x13, 0x21, 0x12, 0x63, 0xea, 0x18,
+	0xea, 0xfe, 0xa4, 0x52, 0x27, 0x24, 0x60, 0x30, 0xa0, 0x7c, 0x48, 0x95, 0x86, 0xa8, 0x5c, 0x3e,
+	0x34, 0xfe, 0x54, 0x69, 0xe2, 0x32, 0xab, 0x89, 0x6d, 0xe5, 0xa3, 0x5d, 0x6e, 0x83, 0xcb, 0xd8,
+	0x45, 0x71, 0x07, 0xdc, 0x03, 0x8a, 0xed, 0xd0, 0x0e, 0xad, 0xa5, 0xff, 0x9c, 0xa3, 0xe7, 0x7d,
+	0x95, 0xf3, 0x9e, 0x63, 0xc3, 0xe3, 0x69, 0x27, 0xf6, 0x72, 0xd7, 0xe7, 0x51, 0xdb, 0xe7, 0x31,
+	0x69, 0x7b, 0x42, 0xb4, 0x05, 0x0f, 0xa9, 0x9f, 0xb7, 0x7d, 0xce, 0xc6, 0xf4, 0x87, 0x2b, 0x62,
+	0x9e, 0x72, 0xb4, 0x57, 0x72, 0x31, 0x71, 0x3d, 0x21, 0x5c, 0xc5, 0x34, 0xf7, 0xc1, 0x1a, 0x10,
+	0x9f, 0xb3, 0x00, 0xdd, 0x05, 0x73, 0xea, 0x85, 0x19, 0xb1, 0x8d, 0x03, 0xa3, 0xd5, 0xc0, 0xea,
+	0xa3, 0xf9, 0xdb, 0x04, 0xab, 0x2f, 0x51, 0xf4, 0x0a, 0x6a, 0x29, 0x8d, 0x08, 0xcf, 0x52, 0x89,
+	0xd4, 0x3b, 0x47, 0xee, 0xad, 0x9e, 0xae, 0xe2, 0xdd, 0xcf, 0x0a, 0xc6, 0xa5, 0x0a, 0x3d, 0x03,
+	0x33, 0x49, 0xbd, 0x34, 0xb1, 0x2b, 0x52, 0x7e, 0xb8, 0x5a, 0x3e, 0x28, 0x50, 0xac, 0x14, 0xe8,
+	0x05, 0x58, 0xa3, 0x6c, 0x3c, 0x26, 0xb1, 0x5d, 0x95, 0xda, 0x47, 0xab, 0xb5, 0x5d, 0xc9, 0x62,
+	0xad, 0x41, 0xaf, 0x61, 0x93, 0x8a, 0x61, 0x48, 0x23, 0x9a, 0xda, 0x1b, 0xeb, 0xfc, 0x7a, 0xaf,
+	0x7f, 0x5e, 0xc0, 0xb8, 0x46, 0x85, 0x3c, 0x38, 0x3f, 0x2b, 0x50, 0xd3, 0xfd, 0xa0, 0x53, 0xb8,
+	0x73, 0xe9, 0xb1, 0x20, 0xb9, 0xf4, 0x26, 0x44, 0x27, 0xf1, 0x60, 0x89, 0x9d, 0x8a, 0x16, 0xcf,
+	0x79, 0xf4, 0x01, 0x76, 0x7c, 0xce, 0x18, 0xf1, 0x53, 0xca, 0xd9, 0x90, 0x06, 0x21, 0xb1, 0x2b,
+	0xeb, 0x58, 0x6c, 0xcf, 0x55, 0xbd, 0x20, 0x24, 0xe8, 0x0c, 0xea, 0x99, 0x08, 0x29, 0x9b, 0x0c,
+	0x39, 0x0b, 0x73, 0xbb, 0xba, 0x8e, 0x07, 0x28, 0xc5, 0x27, 0x16, 0xe6, 0xa8, 0x0b, 0x8d, 0x80,
+	0xcf, 0xd8, 0xdc, 0x61, 0x63, 0x1d, 0x87, 0xad, 0x52, 0x53, 0x78, 0x38, 0x1f, 0xc1, 0x94, 0x43,
+	0x42, 0x0f, 0xa1, 0x9e, 0x25, 0x24, 0x1e, 0x2a, 0x7f, 0x99, 0xc9, 0x26, 0x86, 0xa2, 0xf4, 0x45,
+	0x56, 0xd0, 0x21, 0x34, 0x24, 0x50, 0xca, 0x65, 0xcf, 0x9b, 0x78, 0xab, 0x28, 0xbe, 0xd3, 0x35,
+	0xa7, 0x05, 0x96, 0x9a, 0x1b, 0xda, 0x07, 0x98, 0xb7, 0x2b, 0xed, 0x4c, 0xbc, 0x50, 0x71, 0x2e,
+	0xa0, 0xa6, 0x27, 0x84, 0xee, 0x41, 0x2d, 0xf2, 0xae, 0x86, 0x54, 0x24, 0x7a, 0x6f, 0xad, 0xc8,
+	0xbb, 0xea, 0x89, 0x04, 0x3d, 0x01, 0x6b, 0x46, 0x59, 0xc0, 0x67, 0xeb, 0xe5, 0xab, 0xe1, 0xe6,
+	0xb5, 0x01, 0x5b, 0x83, 0x3c, 0x49, 0x49, 0xf4, 0x77, 0xeb, 0xf5, 0xd2, 0xaa, 0x49, 0x1f, 0x2f,
+	0xb3, 0x59, 0xd0, 0xdc, 0x58, 0x5d, 0xe7, 0xa2, 0x4c, 0xe9, 0x08, 0xb6, 0x29, 0x1b, 0xf1, 0x8c,
+	0x05, 0x37, 0x83, 0x6a, 0xe8, 0xaa, 0xce, 0xea, 0x18, 0x76, 0x4b, 0xec, 0x9f, 0xb8, 0x76, 0x74,
+	0xbd, 0x4c, 0xac, 0xf9, 0xcb, 0x00, 0xeb, 0xad, 0xbc, 0xe4, 0xe8, 0x0c, 0xcc, 0x90, 0x4c, 0x49,
+	0x68, 0x1b, 0x07, 0xd5, 0x56, 0xbd, 0xd3, 0x5a, 0xf2, 0x9b, 0x8a, 0x76, 0xcf, 0x0b, 0xf4, 0x3d,
+	0x4b, 0xe3, 0x1c, 0x2b, 0x19, 0x3a, 0x05, 0x2b, 0x91, 0x2d, 0xfc, 0xe7, 0x72, 0x2e, 0xf6, 0x89,
+	0xb5, 0xc4, 0xf9, 0x06, 0x30, 0x77, 0x44, 0xbb, 0x50, 0x9d, 0x90, 0x5c, 0x8f, 0xa3, 0x38, 0xa2,
+	0x93, 0xf2, 0x69, 0x59, 0x3d, 0x0a, 0xed, 0xaa, 0xd8, 0xe7, 0x95, 0xa7, 0x46, 0xf7, 0x25, 0xdc,
+	0xf7, 0x79, 0x74, 0x3b, 0xde, 0x37, 0xbe, 0x5b, 0xea, 0x74, 0x5d, 0xd9, 0xfb, 0xda, 0xc1, 0x5e,
+	0xd1, 0x5d, 0x4c, 0xdc, 0x37, 0x42, 0x68, 0xa7, 0x91, 0x25, 0x9f, 0xbe, 0x93, 0x3f, 0x03, 0x00,
+	0xf8, 0xf3, 0x23, 0x09, 0x24, 0x05, 0x00, 0x00,
 }
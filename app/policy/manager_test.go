@@ -43,3 +43,30 @@ func TestPolicy(t *testing.T) {
 		}
 	}
 }
+
+func TestIPLimitWindow(t *testing.T) {
+	manager, err := New(context.Background(), &Config{
+		Level: map[uint32]*Policy{
+			0: {
+				IpLimit: &Policy_IPLimit{
+					MaxIps: 2,
+					Window: &Second{Value: 0},
+				},
+			},
+			1: {
+				IpLimit: &Policy_IPLimit{
+					MaxIps: 2,
+					Window: &Second{Value: 60},
+				},
+			},
+		},
+	})
+	common.Must(err)
+
+	if w := manager.ForLevel(0).IPLimit.Window; w != policy.SessionDefault().IPLimit.Window {
+		t.Error("expect default window for 0, but got ", w)
+	}
+	if w := manager.ForLevel(1).IPLimit.Window; w != time.Minute {
+		t.Error("expect 1 min window, but got ", w)
+	}
+}
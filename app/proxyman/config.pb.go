@@ -76,6 +76,37 @@ func (AllocationStrategy_Type) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_b07f45dd938bc1b0, []int{1, 0}
 }
 
+type SendThroughPool_Strategy int32
+
+const (
+	// Pick a random IP for each connection.
+	SendThroughPool_Random SendThroughPool_Strategy = 0
+	// Pick IPs one after another.
+	SendThroughPool_RoundRobin SendThroughPool_Strategy = 1
+	// Pick the same IP for connections of the same user.
+	SendThroughPool_UserSticky SendThroughPool_Strategy = 2
+)
+
+var SendThroughPool_Strategy_name = map[int32]string{
+	0: "Random",
+	1: "RoundRobin",
+	2: "UserSticky",
+}
+
+var SendThroughPool_Strategy_value = map[string]int32{
+	"Random":     0,
+	"RoundRobin": 1,
+	"UserSticky": 2,
+}
+
+func (x SendThroughPool_Strategy) String() string {
+	return proto.EnumName(SendThroughPool_Strategy_name, int32(x))
+}
+
+func (SendThroughPool_Strategy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b07f45dd938bc1b0, []int{8, 0}
+}
+
 type InboundConfig struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -312,11 +343,16 @@ type ReceiverConfig struct {
 	ReceiveOriginalDestination bool                   `protobuf:"varint,5,opt,name=receive_original_destination,json=receiveOriginalDestination,proto3" json:"receive_original_destination,omitempty"`
 	// Override domains for the given protocol.
 	// Deprecated. Use sniffing_settings.
-	DomainOverride       []KnownProtocols `protobuf:"varint,7,rep,packed,name=domain_override,json=domainOverride,proto3,enum=v2ray.core.app.proxyman.KnownProtocols" json:"domain_override,omitempty"` // Deprecated: Do not use.
-	SniffingSettings     *SniffingConfig  `protobuf:"bytes,8,opt,name=sniffing_settings,json=sniffingSettings,proto3" json:"sniffing_settings,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	DomainOverride   []KnownProtocols `protobuf:"varint,7,rep,packed,name=domain_override,json=domainOverride,proto3,enum=v2ray.core.app.proxyman.KnownProtocols" json:"domain_override,omitempty"` // Deprecated: Do not use.
+	SniffingSettings *SniffingConfig  `protobuf:"bytes,8,opt,name=sniffing_settings,json=sniffingSettings,proto3" json:"sniffing_settings,omitempty"`
+	// Whether or not to read PROXY protocol v1 or v2 header from incoming TCP
+	// connections, and take the client address in it as the source.
+	// Enabling either this or stream_settings.socket_settings.accept_proxy_protocol
+	// turns it on.
+	AcceptProxyProtocol  bool     `protobuf:"varint,9,opt,name=accept_proxy_protocol,json=acceptProxyProtocol,proto3" json:"accept_proxy_protocol,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ReceiverConfig) Reset()         { *m = ReceiverConfig{} }
@@ -394,6 +430,13 @@ func (m *ReceiverConfig) GetSniffingSettings() *SniffingConfig {
 	return nil
 }
 
+func (m *ReceiverConfig) GetAcceptProxyProtocol() bool {
+	if m != nil {
+		return m.AcceptProxyProtocol
+	}
+	return false
+}
+
 type InboundHandlerConfig struct {
 	Tag                  string               `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
 	ReceiverSettings     *serial.TypedMessage `protobuf:"bytes,2,opt,name=receiver_settings,json=receiverSettings,proto3" json:"receiver_settings,omitempty"`
@@ -482,13 +525,15 @@ var xxx_messageInfo_OutboundConfig proto.InternalMessageInfo
 
 type SenderConfig struct {
 	// Send traffic through the given IP. Only IP is allowed.
-	Via                  *net.IPOrDomain        `protobuf:"bytes,1,opt,name=via,proto3" json:"via,omitempty"`
-	StreamSettings       *internet.StreamConfig `protobuf:"bytes,2,opt,name=stream_settings,json=streamSettings,proto3" json:"stream_settings,omitempty"`
-	ProxySettings        *internet.ProxyConfig  `protobuf:"bytes,3,opt,name=proxy_settings,json=proxySettings,proto3" json:"proxy_settings,omitempty"`
-	MultiplexSettings    *MultiplexingConfig    `protobuf:"bytes,4,opt,name=multiplex_settings,json=multiplexSettings,proto3" json:"multiplex_settings,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+	Via               *net.IPOrDomain        `protobuf:"bytes,1,opt,name=via,proto3" json:"via,omitempty"`
+	StreamSettings    *internet.StreamConfig `protobuf:"bytes,2,opt,name=stream_settings,json=streamSettings,proto3" json:"stream_settings,omitempty"`
+	ProxySettings     *internet.ProxyConfig  `protobuf:"bytes,3,opt,name=proxy_settings,json=proxySettings,proto3" json:"proxy_settings,omitempty"`
+	MultiplexSettings *MultiplexingConfig    `protobuf:"bytes,4,opt,name=multiplex_settings,json=multiplexSettings,proto3" json:"multiplex_settings,omitempty"`
+	// Send traffic through IPs picked from the pool. Overrides via if set.
+	ViaPool              *SendThroughPool `protobuf:"bytes,5,opt,name=via_pool,json=viaPool,proto3" json:"via_pool,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *SenderConfig) Reset()         { *m = SenderConfig{} }
@@ -544,6 +589,118 @@ func (m *SenderConfig) GetMultiplexSettings() *MultiplexingConfig {
 	return nil
 }
 
+func (m *SenderConfig) GetViaPool() *SendThroughPool {
+	if m != nil {
+		return m.ViaPool
+	}
+	return nil
+}
+
+// CIDR is an IP range for sending traffic through.
+type CIDR struct {
+	// IP address, should be either 4 or 16 bytes.
+	Ip []byte `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Number of leading ones in the network mask.
+	Prefix               uint32   `protobuf:"varint,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CIDR) Reset()         { *m = CIDR{} }
+func (m *CIDR) String() string { return proto.CompactTextString(m) }
+func (*CIDR) ProtoMessage()    {}
+func (*CIDR) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b07f45dd938bc1b0, []int{7}
+}
+
+func (m *CIDR) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CIDR.Unmarshal(m, b)
+}
+func (m *CIDR) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CIDR.Marshal(b, m, deterministic)
+}
+func (m *CIDR) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CIDR.Merge(m, src)
+}
+func (m *CIDR) XXX_Size() int {
+	return xxx_messageInfo_CIDR.Size(m)
+}
+func (m *CIDR) XXX_DiscardUnknown() {
+	xxx_messageInfo_CIDR.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CIDR proto.InternalMessageInfo
+
+func (m *CIDR) GetIp() []byte {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *CIDR) GetPrefix() uint32 {
+	if m != nil {
+		return m.Prefix
+	}
+	return 0
+}
+
+type SendThroughPool struct {
+	Address              []*net.IPOrDomain        `protobuf:"bytes,1,rep,name=address,proto3" json:"address,omitempty"`
+	Cidr                 []*CIDR                  `protobuf:"bytes,2,rep,name=cidr,proto3" json:"cidr,omitempty"`
+	Strategy             SendThroughPool_Strategy `protobuf:"varint,3,opt,name=strategy,proto3,enum=v2ray.core.app.proxyman.SendThroughPool_Strategy" json:"strategy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *SendThroughPool) Reset()         { *m = SendThroughPool{} }
+func (m *SendThroughPool) String() string { return proto.CompactTextString(m) }
+func (*SendThroughPool) ProtoMessage()    {}
+func (*SendThroughPool) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b07f45dd938bc1b0, []int{8}
+}
+
+func (m *SendThroughPool) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendThroughPool.Unmarshal(m, b)
+}
+func (m *SendThroughPool) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendThroughPool.Marshal(b, m, deterministic)
+}
+func (m *SendThroughPool) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendThroughPool.Merge(m, src)
+}
+func (m *SendThroughPool) XXX_Size() int {
+	return xxx_messageInfo_SendThroughPool.Size(m)
+}
+func (m *SendThroughPool) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendThroughPool.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendThroughPool proto.InternalMessageInfo
+
+func (m *SendThroughPool) GetAddress() []*net.IPOrDomain {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *SendThroughPool) GetCidr() []*CIDR {
+	if m != nil {
+		return m.Cidr
+	}
+	return nil
+}
+
+func (m *SendThroughPool) GetStrategy() SendThroughPool_Strategy {
+	if m != nil {
+		return m.Strategy
+	}
+	return SendThroughPool_Random
+}
+
 type MultiplexingConfig struct {
 	// Whether or not Mux is enabled.
 	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
@@ -558,7 +715,7 @@ func (m *MultiplexingConfig) Reset()         { *m = MultiplexingConfig{} }
 func (m *MultiplexingConfig) String() string { return proto.CompactTextString(m) }
 func (*MultiplexingConfig) ProtoMessage()    {}
 func (*MultiplexingConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b07f45dd938bc1b0, []int{7}
+	return fileDescriptor_b07f45dd938bc1b0, []int{9}
 }
 
 func (m *MultiplexingConfig) XXX_Unmarshal(b []byte) error {
@@ -596,6 +753,7 @@ func (m *MultiplexingConfig) GetConcurrency() uint32 {
 func init() {
 	proto.RegisterEnum("v2ray.core.app.proxyman.KnownProtocols", KnownProtocols_name, KnownProtocols_value)
 	proto.RegisterEnum("v2ray.core.app.proxyman.AllocationStrategy_Type", AllocationStrategy_Type_name, AllocationStrategy_Type_value)
+	proto.RegisterEnum("v2ray.core.app.proxyman.SendThroughPool_Strategy", SendThroughPool_Strategy_name, SendThroughPool_Strategy_value)
 	proto.RegisterType((*InboundConfig)(nil), "v2ray.core.app.proxyman.InboundConfig")
 	proto.RegisterType((*AllocationStrategy)(nil), "v2ray.core.app.proxyman.AllocationStrategy")
 	proto.RegisterType((*AllocationStrategy_AllocationStrategyConcurrency)(nil), "v2ray.core.app.proxyman.AllocationStrategy.AllocationStrategyConcurrency")
@@ -605,6 +763,8 @@ func init() {
 	proto.RegisterType((*InboundHandlerConfig)(nil), "v2ray.core.app.proxyman.InboundHandlerConfig")
 	proto.RegisterType((*OutboundConfig)(nil), "v2ray.core.app.proxyman.OutboundConfig")
 	proto.RegisterType((*SenderConfig)(nil), "v2ray.core.app.proxyman.SenderConfig")
+	proto.RegisterType((*CIDR)(nil), "v2ray.core.app.proxyman.CIDR")
+	proto.RegisterType((*SendThroughPool)(nil), "v2ray.core.app.proxyman.SendThroughPool")
 	proto.RegisterType((*MultiplexingConfig)(nil), "v2ray.core.app.proxyman.MultiplexingConfig")
 }
 
@@ -613,57 +773,68 @@ func init() {
 }
 
 var fileDescriptor_b07f45dd938bc1b0 = []byte{
-	// 825 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x95, 0x4f, 0x6f, 0xdb, 0x36,
-	0x18, 0xc6, 0x6b, 0xcb, 0x8d, 0x9d, 0x37, 0x8d, 0xaa, 0xb0, 0x01, 0xaa, 0x79, 0x1b, 0xe0, 0x79,
-	0xc3, 0x6a, 0x74, 0x83, 0xd4, 0xba, 0xd8, 0x61, 0xa7, 0x2d, 0x4d, 0x0a, 0x34, 0xdb, 0x82, 0x78,
-	0xb4, 0xb1, 0x43, 0xb1, 0x41, 0x60, 0x24, 0x46, 0x23, 0x26, 0x91, 0x02, 0x49, 0xbb, 0xd1, 0x57,
-	0xda, 0x79, 0x1f, 0x60, 0xc7, 0x1d, 0xf6, 0xa1, 0x06, 0x89, 0x92, 0xff, 0xd4, 0x56, 0xd6, 0xa0,
-	0x37, 0xda, 0x7a, 0xde, 0x9f, 0xde, 0xf7, 0xe1, 0x43, 0x0a, 0x46, 0x8b, 0xb1, 0x24, 0xb9, 0x17,
-	0x8a, 0xd4, 0x0f, 0x85, 0xa4, 0x3e, 0xc9, 0x32, 0x3f, 0x93, 0xe2, 0x26, 0x4f, 0x09, 0xf7, 0x43,
-	0xc1, 0xaf, 0x59, 0xec, 0x65, 0x52, 0x68, 0x81, 0x1e, 0xd7, 0x4a, 0x49, 0x3d, 0x92, 0x65, 0x5e,
-	0xad, 0xea, 0x3f, 0x79, 0x07, 0x11, 0x8a, 0x34, 0x15, 0xdc, 0xe7, 0x54, 0xfb, 0x24, 0x8a, 0x24,
-	0x55, 0xca, 0x10, 0xfa, 0x5f, 0x34, 0x0b, 0x33, 0x21, 0x75, 0xa5, 0xf2, 0xde, 0x51, 0x69, 0x49,
-	0xb8, 0x2a, 0x9e, 0xfb, 0x8c, 0x6b, 0x2a, 0x0b, 0xf5, 0x7a, 0x5f, 0xfd, 0x67, 0xbb, 0xa9, 0x8a,
-	0x4a, 0x46, 0x12, 0x5f, 0xe7, 0x19, 0x8d, 0x82, 0x94, 0x2a, 0x45, 0x62, 0x6a, 0x2a, 0x86, 0x0f,
-	0xe1, 0xf0, 0x9c, 0x5f, 0x89, 0x39, 0x8f, 0x4e, 0x4b, 0xd0, 0xf0, 0x6f, 0x0b, 0xd0, 0x49, 0x92,
-	0x88, 0x90, 0x68, 0x26, 0xf8, 0x54, 0x4b, 0xa2, 0x69, 0x9c, 0xa3, 0x33, 0xe8, 0x14, 0xe5, 0x6e,
-	0x6b, 0xd0, 0x1a, 0xd9, 0xe3, 0x67, 0x5e, 0x83, 0x01, 0xde, 0x76, 0xa9, 0x37, 0xcb, 0x33, 0x8a,
-	0xcb, 0x6a, 0xf4, 0x07, 0x1c, 0x84, 0x82, 0x87, 0x73, 0x29, 0x29, 0x0f, 0x73, 0xb7, 0x3d, 0x68,
-	0x8d, 0x0e, 0xc6, 0xe7, 0x77, 0x81, 0x6d, 0xff, 0x75, 0xba, 0x02, 0xe2, 0x75, 0x3a, 0x0a, 0xa0,
-	0x2b, 0xe9, 0xb5, 0xa4, 0xea, 0x77, 0xd7, 0x2a, 0x5f, 0xf4, 0xea, 0xc3, 0x5e, 0x84, 0x0d, 0x0c,
-	0xd7, 0xd4, 0xfe, 0x37, 0xf0, 0xe9, 0xad, 0xed, 0xa0, 0x63, 0xb8, 0xbf, 0x20, 0xc9, 0xdc, 0xb8,
-	0x76, 0x88, 0xcd, 0x8f, 0xfe, 0x73, 0xf8, 0xa8, 0x11, 0xbe, 0xbb, 0x64, 0xf8, 0x35, 0x74, 0x0a,
-	0x17, 0x11, 0xc0, 0xde, 0x49, 0xf2, 0x96, 0xe4, 0xca, 0xb9, 0x57, 0xac, 0x31, 0xe1, 0x91, 0x48,
-	0x9d, 0x16, 0x7a, 0x00, 0xbd, 0x57, 0x37, 0x45, 0x20, 0x48, 0xe2, 0xb4, 0x87, 0xbf, 0x81, 0x3d,
-	0xe5, 0xec, 0xfa, 0x9a, 0xf1, 0xd8, 0x6c, 0x2a, 0x72, 0xa1, 0x4b, 0x39, 0xb9, 0x4a, 0x68, 0x54,
-	0x72, 0x7b, 0xb8, 0xfe, 0x89, 0x9e, 0xc3, 0x71, 0x44, 0x95, 0x66, 0xbc, 0xec, 0x26, 0x10, 0x0b,
-	0x2a, 0x25, 0x8b, 0xa8, 0xdb, 0x1e, 0x58, 0xa3, 0x7d, 0xfc, 0x68, 0xed, 0xd9, 0x65, 0xf5, 0x68,
-	0xf8, 0x57, 0x07, 0x6c, 0x4c, 0x43, 0xca, 0x16, 0x54, 0x56, 0xfc, 0xef, 0x00, 0x8a, 0x54, 0x06,
-	0x92, 0xf0, 0xd8, 0xb4, 0x7e, 0x30, 0x1e, 0xac, 0xbb, 0x6d, 0x82, 0xe8, 0x71, 0xaa, 0xbd, 0x89,
-	0x90, 0x1a, 0x17, 0x3a, 0xbc, 0x9f, 0xd5, 0x4b, 0xf4, 0x2d, 0xec, 0x25, 0x4c, 0x69, 0xca, 0xab,
-	0x4c, 0x7c, 0xd6, 0x50, 0x7c, 0x3e, 0xb9, 0x94, 0x67, 0x22, 0x25, 0x8c, 0xe3, 0xaa, 0x00, 0xfd,
-	0x0a, 0x8f, 0xc8, 0xd2, 0xce, 0x40, 0x55, 0x7e, 0x56, 0x5b, 0xfe, 0xd5, 0x1d, 0xb6, 0x1c, 0x23,
-	0xb2, 0x9d, 0xfb, 0x19, 0x3c, 0x54, 0x5a, 0x52, 0x92, 0x06, 0x8a, 0x6a, 0xcd, 0x78, 0xac, 0xdc,
-	0xce, 0x36, 0x79, 0x79, 0x2e, 0xbd, 0xfa, 0x5c, 0x7a, 0xd3, 0xb2, 0xca, 0xf8, 0x83, 0x6d, 0xc3,
-	0x98, 0x56, 0x08, 0xf4, 0x3d, 0x7c, 0x22, 0x8d, 0x83, 0x81, 0x90, 0x2c, 0x66, 0x9c, 0x24, 0xc1,
-	0x9a, 0xd5, 0xee, 0xfd, 0x72, 0x93, 0xfa, 0x95, 0xe6, 0xb2, 0x92, 0x9c, 0xad, 0x14, 0x45, 0x5f,
-	0x51, 0xe9, 0xc3, 0x6a, 0xcb, 0xba, 0x03, 0x6b, 0x64, 0x8f, 0x9f, 0x34, 0x4e, 0xfc, 0x23, 0x17,
-	0x6f, 0xf9, 0xa4, 0x38, 0xf5, 0xa1, 0x48, 0xd4, 0xcb, 0xb6, 0xdb, 0xc2, 0xb6, 0x61, 0xd4, 0x5b,
-	0x8b, 0x66, 0x70, 0xa4, 0xaa, 0xe4, 0xac, 0xe6, 0xed, 0x95, 0xf3, 0x36, 0x73, 0x37, 0xb3, 0x86,
-	0x9d, 0x9a, 0x50, 0x4f, 0xfb, 0x43, 0xa7, 0xb7, 0xe7, 0x74, 0x87, 0xff, 0xb6, 0xe0, 0xb8, 0xba,
-	0x6a, 0x5e, 0x13, 0x1e, 0x25, 0xcb, 0xf0, 0x38, 0x60, 0x69, 0x12, 0x97, 0xa9, 0xd9, 0xc7, 0xc5,
-	0x12, 0x4d, 0xe1, 0xa8, 0x1a, 0x5d, 0xae, 0xda, 0x30, 0xc1, 0xf8, 0x72, 0x47, 0x30, 0xcc, 0xf5,
-	0x56, 0xde, 0x33, 0xd1, 0x85, 0xb9, 0xdd, 0xb0, 0x53, 0x03, 0x96, 0x9e, 0x5f, 0x80, 0x5d, 0xb6,
-	0xbc, 0x22, 0x5a, 0x77, 0x22, 0x1e, 0x96, 0xd5, 0x35, 0x6e, 0xe8, 0x80, 0x7d, 0x39, 0xd7, 0xeb,
-	0x37, 0xe7, 0x3f, 0x6d, 0x78, 0x30, 0xa5, 0x3c, 0x5a, 0x0e, 0xf6, 0x02, 0xac, 0x05, 0x23, 0xd5,
-	0x71, 0x78, 0x8f, 0x44, 0x17, 0xea, 0x5d, 0x81, 0x6b, 0x7f, 0x78, 0xe0, 0x7e, 0x6e, 0x18, 0xfe,
-	0xe9, 0xff, 0x40, 0x27, 0x45, 0x51, 0xc5, 0xdc, 0x34, 0x00, 0xbd, 0x01, 0x94, 0xce, 0x13, 0xcd,
-	0xb2, 0x84, 0xde, 0xdc, 0x7a, 0x38, 0x36, 0xc2, 0x72, 0x51, 0x97, 0xac, 0x02, 0x73, 0xb4, 0xc4,
-	0x2c, 0xcd, 0x9d, 0x00, 0xda, 0x16, 0xde, 0x72, 0x8b, 0x0d, 0xb6, 0xbf, 0x2b, 0x87, 0x1b, 0x1f,
-	0x83, 0xa7, 0x9f, 0x83, 0xbd, 0x99, 0x7f, 0xd4, 0x83, 0xce, 0xeb, 0xd9, 0x6c, 0xe2, 0xdc, 0x43,
-	0x5d, 0xb0, 0x66, 0x3f, 0x4d, 0x9d, 0xd6, 0xcb, 0x53, 0xf8, 0x38, 0x14, 0x69, 0x53, 0xef, 0x93,
-	0xd6, 0x9b, 0x5e, 0xbd, 0xfe, 0xb3, 0xfd, 0xf8, 0x97, 0x31, 0x26, 0xb9, 0x77, 0x5a, 0xa8, 0x4e,
-	0xb2, 0xcc, 0x38, 0x95, 0x12, 0x7e, 0xb5, 0x57, 0x7e, 0x58, 0x5f, 0xfc, 0x17, 0x00, 0x00, 0xff,
-	0xff, 0x4d, 0xe1, 0x89, 0x0d, 0x4e, 0x08, 0x00, 0x00,
+	// 997 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x96, 0xdf, 0x6e, 0xe3, 0xc4,
+	0x17, 0xc7, 0xeb, 0x24, 0xdb, 0xa4, 0xa7, 0xad, 0xeb, 0x4e, 0xfb, 0xfb, 0xad, 0x09, 0xac, 0x14,
+	0x0c, 0x62, 0xa3, 0x05, 0x39, 0xdb, 0xac, 0x40, 0x42, 0x5c, 0x40, 0x37, 0x5d, 0x69, 0x0b, 0x54,
+	0x0d, 0x93, 0xc0, 0xc5, 0x0a, 0x64, 0x4d, 0xed, 0x69, 0x3a, 0x5a, 0x7b, 0xc6, 0x1a, 0x4f, 0xb2,
+	0xcd, 0x1b, 0x70, 0xc3, 0x8b, 0xf0, 0x14, 0x3c, 0x00, 0x0f, 0xc2, 0x63, 0x20, 0x8f, 0xff, 0x24,
+	0x69, 0xea, 0xd2, 0x6a, 0xef, 0x66, 0x32, 0xe7, 0x7c, 0x7c, 0xfe, 0x7c, 0xe7, 0x4c, 0xa0, 0x3b,
+	0xeb, 0x4b, 0x32, 0x77, 0x7d, 0x11, 0xf5, 0x7c, 0x21, 0x69, 0x8f, 0xc4, 0x71, 0x2f, 0x96, 0xe2,
+	0x7a, 0x1e, 0x11, 0xde, 0xf3, 0x05, 0xbf, 0x64, 0x13, 0x37, 0x96, 0x42, 0x09, 0xf4, 0xb8, 0xb0,
+	0x94, 0xd4, 0x25, 0x71, 0xec, 0x16, 0x56, 0xed, 0xa7, 0x37, 0x10, 0xbe, 0x88, 0x22, 0xc1, 0x7b,
+	0x9c, 0xaa, 0x1e, 0x09, 0x02, 0x49, 0x93, 0x24, 0x23, 0xb4, 0x3f, 0xad, 0x36, 0x8c, 0x85, 0x54,
+	0xb9, 0x95, 0x7b, 0xc3, 0x4a, 0x49, 0xc2, 0x93, 0xf4, 0xbc, 0xc7, 0xb8, 0xa2, 0x32, 0xb5, 0x5e,
+	0x8e, 0xab, 0xfd, 0xfc, 0x76, 0x6a, 0x42, 0x25, 0x23, 0x61, 0x4f, 0xcd, 0x63, 0x1a, 0x78, 0x11,
+	0x4d, 0x12, 0x32, 0xa1, 0x99, 0x87, 0xb3, 0x07, 0xbb, 0xa7, 0xfc, 0x42, 0x4c, 0x79, 0x30, 0xd0,
+	0x20, 0xe7, 0xaf, 0x3a, 0xa0, 0xe3, 0x30, 0x14, 0x3e, 0x51, 0x4c, 0xf0, 0x91, 0x92, 0x44, 0xd1,
+	0xc9, 0x1c, 0x9d, 0x40, 0x23, 0x75, 0xb7, 0x8d, 0x8e, 0xd1, 0x35, 0xfb, 0xcf, 0xdd, 0x8a, 0x02,
+	0xb8, 0xeb, 0xae, 0xee, 0x78, 0x1e, 0x53, 0xac, 0xbd, 0xd1, 0x5b, 0xd8, 0xf6, 0x05, 0xf7, 0xa7,
+	0x52, 0x52, 0xee, 0xcf, 0xed, 0x5a, 0xc7, 0xe8, 0x6e, 0xf7, 0x4f, 0x1f, 0x02, 0x5b, 0xff, 0x69,
+	0xb0, 0x00, 0xe2, 0x65, 0x3a, 0xf2, 0xa0, 0x29, 0xe9, 0xa5, 0xa4, 0xc9, 0x95, 0x5d, 0xd7, 0x1f,
+	0x7a, 0xf5, 0x7e, 0x1f, 0xc2, 0x19, 0x0c, 0x17, 0xd4, 0xf6, 0x97, 0xf0, 0xe4, 0xce, 0x70, 0xd0,
+	0x21, 0x3c, 0x9a, 0x91, 0x70, 0x9a, 0x55, 0x6d, 0x17, 0x67, 0x9b, 0xf6, 0x11, 0x7c, 0x50, 0x09,
+	0xbf, 0xdd, 0xc5, 0xf9, 0x02, 0x1a, 0x69, 0x15, 0x11, 0xc0, 0xe6, 0x71, 0xf8, 0x8e, 0xcc, 0x13,
+	0x6b, 0x23, 0x5d, 0x63, 0xc2, 0x03, 0x11, 0x59, 0x06, 0xda, 0x81, 0xd6, 0xab, 0xeb, 0x54, 0x10,
+	0x24, 0xb4, 0x6a, 0xce, 0x6f, 0x60, 0x8e, 0x38, 0xbb, 0xbc, 0x64, 0x7c, 0x92, 0x35, 0x15, 0xd9,
+	0xd0, 0xa4, 0x9c, 0x5c, 0x84, 0x34, 0xd0, 0xdc, 0x16, 0x2e, 0xb6, 0xe8, 0x08, 0x0e, 0x03, 0x9a,
+	0x28, 0xc6, 0x75, 0x34, 0x9e, 0x98, 0x51, 0x29, 0x59, 0x40, 0xed, 0x5a, 0xa7, 0xde, 0xdd, 0xc2,
+	0x07, 0x4b, 0x67, 0xe7, 0xf9, 0x91, 0xf3, 0x4f, 0x03, 0x4c, 0x4c, 0x7d, 0xca, 0x66, 0x54, 0xe6,
+	0xfc, 0x6f, 0x01, 0x52, 0x55, 0x7a, 0x92, 0xf0, 0x49, 0x16, 0xfa, 0x76, 0xbf, 0xb3, 0x5c, 0xed,
+	0x4c, 0x88, 0x2e, 0xa7, 0xca, 0x1d, 0x0a, 0xa9, 0x70, 0x6a, 0x87, 0xb7, 0xe2, 0x62, 0x89, 0xbe,
+	0x86, 0xcd, 0x90, 0x25, 0x8a, 0xf2, 0x5c, 0x13, 0x1f, 0x57, 0x38, 0x9f, 0x0e, 0xcf, 0xe5, 0x89,
+	0x88, 0x08, 0xe3, 0x38, 0x77, 0x40, 0xbf, 0xc2, 0x01, 0x29, 0xcb, 0xe9, 0x25, 0x79, 0x3d, 0xf3,
+	0x96, 0x7f, 0xfe, 0x80, 0x96, 0x63, 0x44, 0xd6, 0x75, 0x3f, 0x86, 0xbd, 0x44, 0x49, 0x4a, 0x22,
+	0x2f, 0xa1, 0x4a, 0x31, 0x3e, 0x49, 0xec, 0xc6, 0x3a, 0xb9, 0xbc, 0x97, 0x6e, 0x71, 0x2f, 0xdd,
+	0x91, 0xf6, 0xca, 0xea, 0x83, 0xcd, 0x8c, 0x31, 0xca, 0x11, 0xe8, 0x3b, 0xf8, 0x48, 0x66, 0x15,
+	0xf4, 0x84, 0x64, 0x13, 0xc6, 0x49, 0xe8, 0x2d, 0x95, 0xda, 0x7e, 0xa4, 0x9b, 0xd4, 0xce, 0x6d,
+	0xce, 0x73, 0x93, 0x93, 0x85, 0x45, 0x1a, 0x57, 0xa0, 0xeb, 0xb0, 0x68, 0x59, 0xb3, 0x53, 0xef,
+	0x9a, 0xfd, 0xa7, 0x95, 0x19, 0xff, 0xc0, 0xc5, 0x3b, 0x3e, 0x4c, 0x6f, 0xbd, 0x2f, 0xc2, 0xe4,
+	0x65, 0xcd, 0x36, 0xb0, 0x99, 0x31, 0x8a, 0xd6, 0xa2, 0x31, 0xec, 0x27, 0xb9, 0x72, 0x16, 0xf9,
+	0xb6, 0x74, 0xbe, 0xd5, 0xdc, 0x55, 0xad, 0x61, 0xab, 0x20, 0x94, 0xd9, 0xf6, 0xe1, 0x7f, 0xc4,
+	0xf7, 0x69, 0xac, 0x3c, 0xed, 0xe3, 0xc5, 0x79, 0x0c, 0xf6, 0x96, 0x4e, 0xf3, 0x20, 0x3b, 0x1c,
+	0xa6, 0x67, 0x45, 0x78, 0xdf, 0x37, 0x5a, 0x9b, 0x56, 0xd3, 0xf9, 0xdb, 0x80, 0xc3, 0x7c, 0x3c,
+	0xbd, 0x26, 0x3c, 0x08, 0x4b, 0xc1, 0x59, 0x50, 0x57, 0x64, 0xa2, 0x95, 0xb6, 0x85, 0xd3, 0x25,
+	0x1a, 0xc1, 0x7e, 0x5e, 0x2e, 0xb9, 0x08, 0x3d, 0x13, 0xd3, 0x67, 0xb7, 0x88, 0x29, 0x1b, 0x89,
+	0x7a, 0x36, 0x05, 0x67, 0xd9, 0x44, 0xc4, 0x56, 0x01, 0x28, 0x23, 0x3f, 0x03, 0x33, 0x0b, 0xb9,
+	0x24, 0xd6, 0x1f, 0x44, 0xdc, 0xd5, 0xde, 0x05, 0xce, 0xb1, 0xc0, 0x3c, 0x9f, 0xaa, 0xe5, 0x69,
+	0xfb, 0x47, 0x1d, 0x76, 0x46, 0x94, 0x07, 0x65, 0x62, 0x2f, 0xa0, 0x3e, 0x63, 0xc4, 0x36, 0xee,
+	0x7b, 0x0b, 0x52, 0xeb, 0xdb, 0x44, 0x5a, 0x7b, 0x7f, 0x91, 0xfe, 0x54, 0x91, 0xfc, 0xb3, 0xff,
+	0x80, 0xea, 0x46, 0xe6, 0xcc, 0xd5, 0x02, 0xa0, 0x37, 0x80, 0xa2, 0x69, 0xa8, 0x58, 0x1c, 0xd2,
+	0xeb, 0x3b, 0x2f, 0xd4, 0x8a, 0xc0, 0xce, 0x0a, 0x97, 0x85, 0xc8, 0xf6, 0x4b, 0x4c, 0xc9, 0x1e,
+	0x40, 0x6b, 0xc6, 0x88, 0x17, 0x0b, 0x11, 0xea, 0xfb, 0xb3, 0xdd, 0xef, 0x56, 0x4b, 0x96, 0xf2,
+	0x60, 0x7c, 0x25, 0xc5, 0x74, 0x72, 0x35, 0x14, 0x22, 0xc4, 0xcd, 0x19, 0x23, 0xe9, 0xc2, 0x71,
+	0xa1, 0x31, 0x38, 0x3d, 0xc1, 0xc8, 0x84, 0x1a, 0x8b, 0x75, 0x17, 0x76, 0x70, 0x8d, 0xc5, 0xe8,
+	0xff, 0xb0, 0x19, 0x4b, 0x7a, 0xc9, 0xae, 0x75, 0x61, 0x77, 0x71, 0xbe, 0x73, 0x7e, 0xaf, 0xc1,
+	0xde, 0x0d, 0x18, 0xfa, 0x06, 0x9a, 0xf9, 0x5b, 0x6f, 0x1b, 0x9d, 0xfa, 0xfd, 0xda, 0x58, 0x78,
+	0xa0, 0x23, 0x68, 0xf8, 0x2c, 0x90, 0x7a, 0xfe, 0x6e, 0xf7, 0x9f, 0x54, 0x66, 0x90, 0x46, 0x89,
+	0xb5, 0x29, 0x3a, 0x83, 0xd6, 0xca, 0xd4, 0x33, 0xfb, 0x47, 0xf7, 0x4d, 0xdc, 0x2d, 0x67, 0x5f,
+	0x89, 0x70, 0xbe, 0x82, 0x56, 0xf1, 0xeb, 0xd2, 0x1b, 0xb3, 0x81, 0x4c, 0x00, 0x9c, 0x2a, 0x17,
+	0x8b, 0x0b, 0xc6, 0x2d, 0x23, 0xdd, 0xff, 0x9c, 0x50, 0x39, 0x52, 0xcc, 0x7f, 0x3b, 0xb7, 0x6a,
+	0xce, 0x10, 0xd0, 0x7a, 0xa3, 0xee, 0x78, 0x79, 0x3a, 0xeb, 0xff, 0x05, 0x76, 0x57, 0x1e, 0xf0,
+	0x67, 0x9f, 0x80, 0xb9, 0x3a, 0xb3, 0x50, 0x0b, 0x1a, 0xaf, 0xc7, 0xe3, 0xa1, 0xb5, 0x81, 0x9a,
+	0x50, 0x1f, 0xff, 0x38, 0xb2, 0x8c, 0x97, 0x03, 0xf8, 0xd0, 0x17, 0x51, 0x55, 0xc2, 0x43, 0xe3,
+	0x4d, 0xab, 0x58, 0xff, 0x59, 0x7b, 0xfc, 0x4b, 0x1f, 0x93, 0xb9, 0x3b, 0x48, 0xad, 0x8e, 0xe3,
+	0x38, 0x53, 0x6a, 0x44, 0xf8, 0xc5, 0xa6, 0x1e, 0x49, 0x2f, 0xfe, 0x1d, 0x00, 0x23, 0x33, 0xc6,
+	0x24, 0x02, 0x0a, 0x00, 0x00,
 }
@@ -87,12 +87,19 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 		}
 		mss.SocketSettings.ReceiveOriginalDestAddress = true
 	}
+	if receiverConfig.AcceptProxyProtocol {
+		if mss.SocketSettings == nil {
+			mss.SocketSettings = &internet.SocketConfig{}
+		}
+		mss.SocketSettings.AcceptProxyProtocol = true
+	}
 
 	for port := pr.From; port <= pr.To; port++ {
 		if net.HasNetwork(nl, net.Network_TCP) {
 			newError("creating stream worker on ", address, ":", port).AtDebug().WriteToLog()
 
 			worker := &tcpWorker{
+				ctx:             ctx,
 				address:         address,
 				port:            net.Port(port),
 				proxy:           p,
@@ -54,6 +54,12 @@ func NewDynamicInboundHandler(ctx context.Context, tag string, receiverConfig *p
 		}
 		mss.SocketSettings.ReceiveOriginalDestAddress = true
 	}
+	if receiverConfig.AcceptProxyProtocol {
+		if mss.SocketSettings == nil {
+			mss.SocketSettings = &internet.SocketConfig{}
+		}
+		mss.SocketSettings.AcceptProxyProtocol = true
+	}
 
 	h.streamSettings = mss
 
@@ -124,6 +130,7 @@ func (h *DynamicInboundHandler) refresh() error {
 		nl := p.Network()
 		if net.HasNetwork(nl, net.Network_TCP) {
 			worker := &tcpWorker{
+				ctx:             core.ToContext(context.Background(), h.v),
 				tag:             h.tag,
 				address:         address,
 				port:            port,
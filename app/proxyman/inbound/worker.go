@@ -31,6 +31,7 @@ type worker interface {
 }
 
 type tcpWorker struct {
+	ctx             context.Context
 	address         net.Address
 	port            net.Port
 	proxy           proxy.Inbound
@@ -76,17 +77,20 @@ func (w *tcpWorker) callback(conn internet.Connection) {
 			})
 		}
 	}
-	ctx = session.ContextWithInbound(ctx, &session.Inbound{
+	// If PROXY protocol is accepted, RemoteAddr() reads the header and returns the client address in it.
+	inbound := &session.Inbound{
 		Source:  net.DestinationFromAddr(conn.RemoteAddr()),
 		Gateway: net.TCPDestination(w.address, w.port),
 		Tag:     w.tag,
-	})
+	}
+	ctx = session.ContextWithInbound(ctx, inbound)
 	content := new(session.Content)
 	if w.sniffingConfig != nil {
 		content.SniffingRequest.Enabled = w.sniffingConfig.Enabled
 		content.SniffingRequest.OverrideDestinationForProtocol = w.sniffingConfig.DestinationOverride
 	}
 	ctx = session.ContextWithContent(ctx, content)
+	rawConn := conn
 	if w.uplinkCounter != nil || w.downlinkCounter != nil {
 		conn = &internet.StatCouterConnection{
 			Connection: conn,
@@ -98,6 +102,14 @@ func (w *tcpWorker) callback(conn internet.Connection) {
 		newError("connection ends").Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
 	cancel()
+	if inbound.ResetOnClose {
+		if lc, ok := internet.UnwrapConnection(rawConn).(interface{ SetLinger(int) error }); ok {
+			// Linger of 0 discards unsent data and sends RST on close.
+			lc.SetLinger(0) // nolint: errcheck
+		} else {
+			newError("resetOnClose is not supported by the transport of this inbound").AtDebug().WriteToLog(session.ExportIDToError(ctx))
+		}
+	}
 	if err := conn.Close(); err != nil {
 		newError("failed to close connection").Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
@@ -108,8 +120,7 @@ func (w *tcpWorker) Proxy() proxy.Inbound {
 }
 
 func (w *tcpWorker) Start() error {
-	ctx := context.Background()
-	hub, err := internet.ListenTCP(ctx, w.address, w.port, w.stream, func(conn internet.Connection) {
+	hub, err := internet.ListenTCP(w.ctx, w.address, w.port, w.stream, func(conn internet.Connection) {
 		go w.callback(conn)
 	})
 	if err != nil {
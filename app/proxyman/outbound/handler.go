@@ -9,6 +9,7 @@ import (
 	"v2ray.com/core/common/mux"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/dns"
 	"v2ray.com/core/features/outbound"
 	"v2ray.com/core/proxy"
 	"v2ray.com/core/transport"
@@ -19,11 +20,14 @@ import (
 
 // Handler is an implements of outbound.Handler.
 type Handler struct {
+	v               *core.Instance
 	tag             string
 	senderSettings  *proxyman.SenderConfig
+	sourcePool      *SourcePool
 	streamSettings  *internet.MemoryStreamConfig
 	proxy           proxy.Outbound
 	outboundManager outbound.Manager
+	dns             dns.Client
 	mux             *mux.ClientManager
 }
 
@@ -31,9 +35,15 @@ type Handler struct {
 func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbound.Handler, error) {
 	v := core.MustFromContext(ctx)
 	h := &Handler{
+		v:               v,
 		tag:             config.Tag,
 		outboundManager: v.GetFeature(outbound.ManagerType()).(outbound.Manager),
 	}
+	if err := core.RequireFeatures(ctx, func(d dns.Client) {
+		h.dns = d
+	}); err != nil {
+		return nil, err
+	}
 
 	if config.SenderSettings != nil {
 		senderSettings, err := config.SenderSettings.GetInstance()
@@ -48,6 +58,13 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 				return nil, newError("failed to parse stream settings").Base(err).AtWarning()
 			}
 			h.streamSettings = mss
+			if s.ViaPool != nil {
+				pool, err := NewSourcePool(s.ViaPool)
+				if err != nil {
+					return nil, newError("failed to create send through pool").Base(err).AtWarning()
+				}
+				h.sourcePool = pool
+			}
 		default:
 			return nil, newError("settings is not SenderConfig")
 		}
@@ -118,6 +135,9 @@ func (h *Handler) Dispatch(ctx context.Context, link *transport.Link) {
 
 // Address implements internet.Dialer.
 func (h *Handler) Address() net.Address {
+	if h.sourcePool != nil {
+		return h.sourcePool.Address()
+	}
 	if h.senderSettings == nil || h.senderSettings.Via == nil {
 		return nil
 	}
@@ -154,17 +174,24 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (internet.Conn
 			newError("failed to get outbound handler with tag: ", tag).AtWarning().WriteToLog(session.ExportIDToError(ctx))
 		}
 
-		if h.senderSettings.Via != nil {
+		if h.sourcePool != nil || h.senderSettings.Via != nil {
 			outbound := session.OutboundFromContext(ctx)
 			if outbound == nil {
 				outbound = new(session.Outbound)
 				ctx = session.ContextWithOutbound(ctx, outbound)
 			}
-			outbound.Gateway = h.senderSettings.Via.AsAddress()
+			if h.sourcePool != nil {
+				outbound.Gateway = h.sourcePool.Pick(ctx, dest)
+			} else {
+				outbound.Gateway = h.senderSettings.Via.AsAddress()
+			}
 		}
 	}
 
-	return internet.Dial(ctx, dest, h.streamSettings)
+	if h.dns != nil {
+		ctx = internet.ContextWithDNSClient(ctx, h.dns)
+	}
+	return internet.Dial(core.ToContext(ctx, h.v), dest, h.streamSettings)
 }
 
 // GetOutbound implements proxy.GetOutbound.
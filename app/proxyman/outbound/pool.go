@@ -0,0 +1,149 @@
+package outbound
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync/atomic"
+
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+)
+
+type poolEntry struct {
+	ip     net.IP
+	prefix uint32
+}
+
+// host returns the IP in this entry, with host bits taken from n. The network and broadcast
+// addresses of IPv4 CIDRs are never returned.
+func (e *poolEntry) host(n []byte) net.IP {
+	if len(e.ip) == net.IPv4len && e.prefix < 31 {
+		size := uint64(1)<<(32-e.prefix) - 2
+		h := make([]byte, net.IPv4len)
+		binary.BigEndian.PutUint32(h, uint32(uint64(binary.BigEndian.Uint32(n))%size+1))
+		n = h
+	}
+
+	ip := make(net.IP, len(e.ip))
+	copy(ip, e.ip)
+	for i := range ip {
+		fixed := int(e.prefix) - i*8
+		if fixed >= 8 {
+			continue
+		}
+		var mask byte = 0xff
+		if fixed > 0 {
+			mask >>= uint(fixed)
+		}
+		ip[i] = ip[i]&^mask | n[i]&mask
+	}
+	return ip
+}
+
+// poolGroup is a list of entries that share a round robin counter. It is always allocated on its
+// own, so that counter is 64-bit aligned for atomic operations.
+type poolGroup struct {
+	counter uint64
+	entries []poolEntry
+}
+
+// SourcePool picks source IPs for outbound connections.
+type SourcePool struct {
+	all      *poolGroup
+	ipv4     *poolGroup
+	ipv6     *poolGroup
+	strategy proxyman.SendThroughPool_Strategy
+}
+
+// NewSourcePool creates a new SourcePool from its config.
+func NewSourcePool(config *proxyman.SendThroughPool) (*SourcePool, error) {
+	p := &SourcePool{
+		all:      new(poolGroup),
+		ipv4:     new(poolGroup),
+		ipv6:     new(poolGroup),
+		strategy: config.Strategy,
+	}
+	for _, addr := range config.Address {
+		a := addr.AsAddress()
+		if a == nil || !a.Family().IsIP() {
+			return nil, newError("invalid address in send through pool: ", a)
+		}
+		ip := a.IP()
+		p.add(poolEntry{ip: ip, prefix: uint32(len(ip) * 8)})
+	}
+	for _, cidr := range config.Cidr {
+		if (len(cidr.Ip) != net.IPv4len && len(cidr.Ip) != net.IPv6len) || cidr.Prefix > uint32(len(cidr.Ip)*8) {
+			return nil, newError("invalid CIDR in send through pool: ", net.IP(cidr.Ip), "/", cidr.Prefix)
+		}
+		p.add(poolEntry{ip: net.IP(cidr.Ip), prefix: cidr.Prefix})
+	}
+	if len(p.all.entries) == 0 {
+		return nil, newError("empty send through pool")
+	}
+	return p, nil
+}
+
+func (p *SourcePool) add(e poolEntry) {
+	p.all.entries = append(p.all.entries, e)
+	if len(e.ip) == net.IPv4len {
+		p.ipv4.entries = append(p.ipv4.entries, e)
+	} else {
+		p.ipv6.entries = append(p.ipv6.entries, e)
+	}
+}
+
+// Address returns an address that represents the address family of the pool, or nil if the pool
+// contains both IPv4 and IPv6 addresses.
+func (p *SourcePool) Address() net.Address {
+	if len(p.ipv4.entries) > 0 && len(p.ipv6.entries) > 0 {
+		return nil
+	}
+	return net.IPAddress(p.all.entries[0].ip)
+}
+
+func (p *SourcePool) group(dest net.Destination) *poolGroup {
+	if dest.Address == nil {
+		return p.all
+	}
+	switch {
+	case dest.Address.Family().IsIPv4():
+		return p.ipv4
+	case dest.Address.Family().IsIPv6():
+		return p.ipv6
+	default:
+		return p.all
+	}
+}
+
+// Pick returns a source IP for the connection to dest in the given context. Only IPs of the same
+// address family as dest are picked. It returns nil if the pool has no such IP.
+func (p *SourcePool) Pick(ctx context.Context, dest net.Destination) net.Address {
+	g := p.group(dest)
+	if len(g.entries) == 0 {
+		return nil
+	}
+
+	switch p.strategy {
+	case proxyman.SendThroughPool_RoundRobin:
+		c := atomic.AddUint64(&g.counter, 1) - 1
+		e := &g.entries[c%uint64(len(g.entries))]
+		n := make([]byte, net.IPv6len)
+		binary.BigEndian.PutUint64(n[8:], c/uint64(len(g.entries)))
+		return net.IPAddress(e.host(n[len(n)-len(e.ip):]))
+	case proxyman.SendThroughPool_UserSticky:
+		if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.User != nil && len(inbound.User.Email) > 0 {
+			h := sha256.Sum256([]byte(inbound.User.Email))
+			e := &g.entries[binary.BigEndian.Uint64(h[:8])%uint64(len(g.entries))]
+			return net.IPAddress(e.host(h[8:]))
+		}
+	}
+
+	n := make([]byte, 8+net.IPv6len)
+	common.Must2(rand.Read(n))
+	e := &g.entries[binary.BigEndian.Uint64(n[:8])%uint64(len(g.entries))]
+	return net.IPAddress(e.host(n[8:]))
+}
@@ -0,0 +1,247 @@
+package outbound_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core/app/proxyman"
+	. "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/transport/internet"
+)
+
+func TestSourcePoolRoundRobin(t *testing.T) {
+	pool, err := NewSourcePool(&proxyman.SendThroughPool{
+		Address: []*net.IPOrDomain{
+			net.NewIPOrDomain(net.ParseAddress("127.0.0.2")),
+		},
+		Cidr: []*proxyman.CIDR{
+			{Ip: []byte{127, 0, 1, 0}, Prefix: 30},
+		},
+		Strategy: proxyman.SendThroughPool_RoundRobin,
+	})
+	common.Must(err)
+
+	dest := net.TCPDestination(net.LocalHostIP, 80)
+	expected := []string{"127.0.0.2", "127.0.1.1", "127.0.0.2", "127.0.1.2", "127.0.0.2", "127.0.1.1"}
+	for i, e := range expected {
+		if a := pool.Pick(context.Background(), dest).String(); a != e {
+			t.Error("pick #", i, ": got ", a, ", want ", e)
+		}
+	}
+}
+
+func TestSourcePoolRandom(t *testing.T) {
+	cidr := &net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(64, 128)}
+	pool, err := NewSourcePool(&proxyman.SendThroughPool{
+		Cidr: []*proxyman.CIDR{
+			{Ip: cidr.IP, Prefix: 64},
+		},
+	})
+	common.Must(err)
+
+	if pool.Address() == nil || !pool.Address().Family().IsIPv6() {
+		t.Error("unexpected pool address: ", pool.Address())
+	}
+
+	picked := make(map[string]bool)
+	for i := 0; i < 16; i++ {
+		a := pool.Pick(context.Background(), net.TCPDestination(net.LocalHostIPv6, 80))
+		if !cidr.Contains(a.IP()) {
+			t.Error("picked address ", a, " not in ", cidr)
+		}
+		picked[a.String()] = true
+	}
+	if len(picked) < 2 {
+		t.Error("random pool always picks the same address")
+	}
+}
+
+func TestSourcePoolUserSticky(t *testing.T) {
+	pool, err := NewSourcePool(&proxyman.SendThroughPool{
+		Cidr: []*proxyman.CIDR{
+			{Ip: []byte{127, 0, 0, 0}, Prefix: 8},
+		},
+		Strategy: proxyman.SendThroughPool_UserSticky,
+	})
+	common.Must(err)
+
+	userContext := func(email string) context.Context {
+		return session.ContextWithInbound(context.Background(), &session.Inbound{
+			User: &protocol.MemoryUser{Email: email},
+		})
+	}
+
+	dest := net.TCPDestination(net.LocalHostIP, 80)
+	a := pool.Pick(userContext("a@v2ray.com"), dest)
+	for i := 0; i < 8; i++ {
+		if b := pool.Pick(userContext("a@v2ray.com"), dest); b != a {
+			t.Error("user a picks ", b, ", want ", a)
+		}
+	}
+	if b := pool.Pick(userContext("b@v2ray.com"), dest); b == a {
+		t.Error("user a and b pick the same address ", a)
+	}
+}
+
+func TestSourcePoolMixedFamilies(t *testing.T) {
+	for _, strategy := range []proxyman.SendThroughPool_Strategy{
+		proxyman.SendThroughPool_Random,
+		proxyman.SendThroughPool_RoundRobin,
+		proxyman.SendThroughPool_UserSticky,
+	} {
+		pool, err := NewSourcePool(&proxyman.SendThroughPool{
+			Address: []*net.IPOrDomain{
+				net.NewIPOrDomain(net.ParseAddress("127.0.0.2")),
+				net.NewIPOrDomain(net.ParseAddress("2001:db8::2")),
+			},
+			Cidr: []*proxyman.CIDR{
+				{Ip: []byte{127, 0, 1, 0}, Prefix: 24},
+				{Ip: net.ParseIP("2001:db8:1::"), Prefix: 64},
+			},
+			Strategy: strategy,
+		})
+		common.Must(err)
+
+		if a := pool.Address(); a != nil {
+			t.Error("unexpected address of mixed pool: ", a)
+		}
+
+		ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+			User: &protocol.MemoryUser{Email: "a@v2ray.com"},
+		})
+		for i := 0; i < 16; i++ {
+			if a := pool.Pick(ctx, net.TCPDestination(net.ParseAddress("1.1.1.1"), 80)); !a.Family().IsIPv4() {
+				t.Error("strategy ", strategy, ": picked ", a, " for IPv4 destination")
+			}
+			if a := pool.Pick(ctx, net.TCPDestination(net.ParseAddress("2606:4700::1111"), 80)); !a.Family().IsIPv6() {
+				t.Error("strategy ", strategy, ": picked ", a, " for IPv6 destination")
+			}
+			if a := pool.Pick(ctx, net.TCPDestination(net.DomainAddress("v2ray.com"), 80)); a == nil {
+				t.Error("strategy ", strategy, ": picked nothing for domain destination")
+			}
+		}
+	}
+}
+
+func TestSourcePoolMissingFamily(t *testing.T) {
+	pool, err := NewSourcePool(&proxyman.SendThroughPool{
+		Cidr: []*proxyman.CIDR{
+			{Ip: []byte{127, 0, 0, 0}, Prefix: 8},
+		},
+	})
+	common.Must(err)
+
+	if a := pool.Pick(context.Background(), net.TCPDestination(net.LocalHostIPv6, 80)); a != nil {
+		t.Error("picked ", a, " for IPv6 destination from IPv4 pool")
+	}
+}
+
+func TestSourcePoolSkipsNetworkAndBroadcast(t *testing.T) {
+	testCases := []struct {
+		cidr     *proxyman.CIDR
+		expected []string
+	}{
+		{
+			cidr:     &proxyman.CIDR{Ip: []byte{10, 0, 0, 4}, Prefix: 30},
+			expected: []string{"10.0.0.5", "10.0.0.6"},
+		},
+		{
+			cidr:     &proxyman.CIDR{Ip: []byte{10, 0, 0, 4}, Prefix: 31},
+			expected: []string{"10.0.0.4", "10.0.0.5"},
+		},
+		{
+			cidr:     &proxyman.CIDR{Ip: []byte{10, 0, 0, 4}, Prefix: 32},
+			expected: []string{"10.0.0.4"},
+		},
+	}
+
+	dest := net.TCPDestination(net.LocalHostIP, 80)
+	for _, tc := range testCases {
+		pool, err := NewSourcePool(&proxyman.SendThroughPool{
+			Cidr:     []*proxyman.CIDR{tc.cidr},
+			Strategy: proxyman.SendThroughPool_RoundRobin,
+		})
+		common.Must(err)
+
+		for i := 0; i < 2*len(tc.expected); i++ {
+			e := tc.expected[i%len(tc.expected)]
+			if a := pool.Pick(context.Background(), dest).String(); a != e {
+				t.Error("/", tc.cidr.Prefix, " pick #", i, ": got ", a, ", want ", e)
+			}
+		}
+
+		random, err := NewSourcePool(&proxyman.SendThroughPool{
+			Cidr: []*proxyman.CIDR{tc.cidr},
+		})
+		common.Must(err)
+		for i := 0; i < 64; i++ {
+			a := random.Pick(context.Background(), dest).String()
+			found := false
+			for _, e := range tc.expected {
+				if a == e {
+					found = true
+				}
+			}
+			if !found {
+				t.Error("/", tc.cidr.Prefix, ": random pick ", a, " is not a host address")
+			}
+		}
+	}
+}
+
+func TestSourcePoolInvalid(t *testing.T) {
+	testCases := []*proxyman.SendThroughPool{
+		{},
+		{Address: []*net.IPOrDomain{net.NewIPOrDomain(net.DomainAddress("v2ray.com"))}},
+		{Cidr: []*proxyman.CIDR{{Ip: []byte{127, 0, 0, 0}, Prefix: 33}}},
+	}
+	for _, tc := range testCases {
+		if _, err := NewSourcePool(tc); err == nil {
+			t.Error("expect error for pool ", tc)
+		}
+	}
+}
+
+func TestSourcePoolDial(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+
+	sources := make(chan net.Address, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			sources <- net.DestinationFromAddr(conn.RemoteAddr()).Address
+			conn.Close()
+		}
+	}()
+
+	pool, err := NewSourcePool(&proxyman.SendThroughPool{
+		Cidr: []*proxyman.CIDR{
+			{Ip: []byte{127, 0, 0, 8}, Prefix: 29},
+		},
+		Strategy: proxyman.SendThroughPool_RoundRobin,
+	})
+	common.Must(err)
+
+	dest := net.DestinationFromAddr(listener.Addr())
+	for i := 0; i < 8; i++ {
+		ctx := context.Background()
+		gateway := pool.Pick(ctx, dest)
+		ctx = session.ContextWithOutbound(ctx, &session.Outbound{Gateway: gateway})
+		conn, err := internet.DialSystem(ctx, dest, nil)
+		common.Must(err)
+		conn.Close()
+
+		if source := <-sources; source != gateway {
+			t.Error("connection from ", source, ", want ", gateway)
+		}
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/mux"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/common/task"
+	"v2ray.com/core/common/uuid"
 	"v2ray.com/core/features/routing"
 	"v2ray.com/core/transport"
 	"v2ray.com/core/transport/pipe"
@@ -21,6 +24,7 @@ type Bridge struct {
 	dispatcher  routing.Dispatcher
 	tag         string
 	domain      string
+	id          string
 	workers     []*BridgeWorker
 	monitorTask *task.Periodic
 }
@@ -34,10 +38,12 @@ func NewBridge(config *BridgeConfig, dispatcher routing.Dispatcher) (*Bridge, er
 		return nil, newError("bridge domain is empty")
 	}
 
+	id := uuid.New()
 	b := &Bridge{
 		dispatcher: dispatcher,
 		tag:        config.Tag,
 		domain:     config.Domain,
+		id:         id.String(),
 	}
 	b.monitorTask = &task.Periodic{
 		Execute:  b.monitor,
@@ -79,6 +85,7 @@ func (b *Bridge) monitor() error {
 			newError("failed to create bridge worker").Base(err).AtWarning().WriteToLog()
 			return nil
 		}
+		worker.bridgeID = b.id
 		b.workers = append(b.workers, worker)
 	}
 
@@ -95,6 +102,7 @@ func (b *Bridge) Close() error {
 
 type BridgeWorker struct {
 	tag        string
+	bridgeID   string
 	worker     *mux.ServerWorker
 	dispatcher routing.Dispatcher
 	state      Control_State
@@ -165,7 +173,20 @@ func (w *BridgeWorker) handleInternalConn(link transport.Link) {
 				if ctl.State != w.state {
 					w.state = ctl.State
 				}
+				if ctl.Reply {
+					reply := &Control{
+						State:    w.state,
+						BridgeId: w.bridgeID,
+					}
+					reply.FillInRandom()
+					msg, err := proto.Marshal(reply)
+					common.Must(err)
+					if err := link.Writer.WriteMultiBuffer(buf.MergeBytes(nil, msg)); err != nil {
+						newError("failed to reply to portal").Base(err).WriteToLog()
+					}
+				}
 			}
+			buf.ReleaseMulti(mb)
 		}
 	}()
 }
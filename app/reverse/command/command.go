@@ -0,0 +1,65 @@
+// +build !confonly
+
+package command
+
+//go:generate errorgen
+
+import (
+	"context"
+
+	grpc "google.golang.org/grpc"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/reverse"
+	"v2ray.com/core/common"
+)
+
+type ReverseServer struct {
+	V *core.Instance
+}
+
+// ListBridges implements ReverseService.
+func (s *ReverseServer) ListBridges(ctx context.Context, request *ListBridgesRequest) (*ListBridgesResponse, error) {
+	r, ok := s.V.GetFeature((*reverse.Reverse)(nil)).(*reverse.Reverse)
+	if !ok {
+		return nil, newError("unable to get reverse instance")
+	}
+
+	response := &ListBridgesResponse{}
+	for _, p := range r.Portals() {
+		if len(request.PortalTag) > 0 && p.Tag() != request.PortalTag {
+			continue
+		}
+		for _, b := range p.Bridges() {
+			bridge := &Bridge{
+				PortalTag:      p.Tag(),
+				Domain:         p.Domain(),
+				Id:             b.ID,
+				Workers:        uint32(b.Workers),
+				ActiveSessions: b.ActiveSessions,
+			}
+			if b.Source.IsValid() {
+				bridge.Source = b.Source.NetAddr()
+			}
+			response.Bridge = append(response.Bridge, bridge)
+		}
+	}
+	return response, nil
+}
+
+type service struct {
+	v *core.Instance
+}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterReverseServiceServer(server, &ReverseServer{
+		V: s.v,
+	})
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := core.MustFromContext(ctx)
+		return &service{v: s}, nil
+	}))
+}
@@ -0,0 +1,61 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/inbound"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/app/reverse"
+	. "v2ray.com/core/app/reverse/command"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/serial"
+)
+
+func TestListBridgesEmpty(t *testing.T) {
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&reverse.Config{
+				PortalConfig: []*reverse.PortalConfig{
+					{Tag: "portal", Domain: "test.v2ray.com"},
+				},
+			}),
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	server := &ReverseServer{
+		V: v,
+	}
+	resp, err := server.ListBridges(context.Background(), &ListBridgesRequest{})
+	common.Must(err)
+	if len(resp.Bridge) != 0 {
+		t.Error("unexpected bridges: ", resp.Bridge)
+	}
+}
+
+func TestListBridgesNoReverse(t *testing.T) {
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+	})
+	common.Must(err)
+
+	server := &ReverseServer{
+		V: v,
+	}
+	if _, err := server.ListBridges(context.Background(), &ListBridgesRequest{}); err == nil {
+		t.Error("expect error when reverse is not configured")
+	}
+}
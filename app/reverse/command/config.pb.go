@@ -0,0 +1,329 @@
+package command
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Config struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Config) Reset()         { *m = Config{} }
+func (m *Config) String() string { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()    {}
+func (*Config) Descriptor() ([]byte, []int) {
+	return fileDescriptor_692ce398fcb82954, []int{0}
+}
+
+func (m *Config) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Config.Unmarshal(m, b)
+}
+func (m *Config) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Config.Marshal(b, m, deterministic)
+}
+func (m *Config) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Config.Merge(m, src)
+}
+func (m *Config) XXX_Size() int {
+	return xxx_messageInfo_Config.Size(m)
+}
+func (m *Config) XXX_DiscardUnknown() {
+	xxx_messageInfo_Config.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Config proto.InternalMessageInfo
+
+type ListBridgesRequest struct {
+	// Tag of the portal. Empty for all portals.
+	PortalTag            string   `protobuf:"bytes,1,opt,name=portal_tag,json=portalTag,proto3" json:"portal_tag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListBridgesRequest) Reset()         { *m = ListBridgesRequest{} }
+func (m *ListBridgesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListBridgesRequest) ProtoMessage()    {}
+func (*ListBridgesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_692ce398fcb82954, []int{1}
+}
+
+func (m *ListBridgesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBridgesRequest.Unmarshal(m, b)
+}
+func (m *ListBridgesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBridgesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListBridgesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBridgesRequest.Merge(m, src)
+}
+func (m *ListBridgesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListBridgesRequest.Size(m)
+}
+func (m *ListBridgesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBridgesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBridgesRequest proto.InternalMessageInfo
+
+func (m *ListBridgesRequest) GetPortalTag() string {
+	if m != nil {
+		return m.PortalTag
+	}
+	return ""
+}
+
+type Bridge struct {
+	PortalTag string `protobuf:"bytes,1,opt,name=portal_tag,json=portalTag,proto3" json:"portal_tag,omitempty"`
+	Domain    string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// ID of the bridge. Empty if the bridge doesn't report its ID.
+	Id string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	// Address that the bridge connects from.
+	Source               string   `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Workers              uint32   `protobuf:"varint,5,opt,name=workers,proto3" json:"workers,omitempty"`
+	ActiveSessions       uint32   `protobuf:"varint,6,opt,name=active_sessions,json=activeSessions,proto3" json:"active_sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Bridge) Reset()         { *m = Bridge{} }
+func (m *Bridge) String() string { return proto.CompactTextString(m) }
+func (*Bridge) ProtoMessage()    {}
+func (*Bridge) Descriptor() ([]byte, []int) {
+	return fileDescriptor_692ce398fcb82954, []int{2}
+}
+
+func (m *Bridge) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Bridge.Unmarshal(m, b)
+}
+func (m *Bridge) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Bridge.Marshal(b, m, deterministic)
+}
+func (m *Bridge) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Bridge.Merge(m, src)
+}
+func (m *Bridge) XXX_Size() int {
+	return xxx_messageInfo_Bridge.Size(m)
+}
+func (m *Bridge) XXX_DiscardUnknown() {
+	xxx_messageInfo_Bridge.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Bridge proto.InternalMessageInfo
+
+func (m *Bridge) GetPortalTag() string {
+	if m != nil {
+		return m.PortalTag
+	}
+	return ""
+}
+
+func (m *Bridge) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *Bridge) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Bridge) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *Bridge) GetWorkers() uint32 {
+	if m != nil {
+		return m.Workers
+	}
+	return 0
+}
+
+func (m *Bridge) GetActiveSessions() uint32 {
+	if m != nil {
+		return m.ActiveSessions
+	}
+	return 0
+}
+
+type ListBridgesResponse struct {
+	Bridge               []*Bridge `protobuf:"bytes,1,rep,name=bridge,proto3" json:"bridge,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListBridgesResponse) Reset()         { *m = ListBridgesResponse{} }
+func (m *ListBridgesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListBridgesResponse) ProtoMessage()    {}
+func (*ListBridgesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_692ce398fcb82954, []int{3}
+}
+
+func (m *ListBridgesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBridgesResponse.Unmarshal(m, b)
+}
+func (m *ListBridgesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBridgesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListBridgesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBridgesResponse.Merge(m, src)
+}
+func (m *ListBridgesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListBridgesResponse.Size(m)
+}
+func (m *ListBridgesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBridgesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBridgesResponse proto.InternalMessageInfo
+
+func (m *ListBridgesResponse) GetBridge() []*Bridge {
+	if m != nil {
+		return m.Bridge
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.reverse.command.Config")
+	proto.RegisterType((*ListBridgesRequest)(nil), "v2ray.core.app.reverse.command.ListBridgesRequest")
+	proto.RegisterType((*Bridge)(nil), "v2ray.core.app.reverse.command.Bridge")
+	proto.RegisterType((*ListBridgesResponse)(nil), "v2ray.core.app.reverse.command.ListBridgesResponse")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/app/reverse/command/config.proto", fileDescriptor_692ce398fcb82954)
+}
+
+var fileDescriptor_692ce398fcb82954 = []byte{
+	// 334 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0x41, 0x4b, 0x2b, 0x31,
+	0x14, 0x85, 0xdf, 0x4c, 0xdf, 0x9b, 0xbe, 0xde, 0x62, 0x85, 0x08, 0x12, 0x04, 0x4b, 0x99, 0x85,
+	0x76, 0x95, 0x81, 0xe9, 0x5e, 0xb0, 0xdd, 0xba, 0x28, 0x53, 0x75, 0xe1, 0xa6, 0xa4, 0x99, 0xeb,
+	0x10, 0x74, 0x26, 0x31, 0x99, 0x8e, 0xf6, 0x2f, 0xf8, 0x53, 0xfa, 0x2b, 0xa5, 0x93, 0x14, 0x14,
+	0xc1, 0xe2, 0x2e, 0xe7, 0xe4, 0x3b, 0x37, 0x9c, 0x4b, 0x20, 0x69, 0x52, 0xc3, 0x37, 0x4c, 0xa8,
+	0x32, 0x11, 0xca, 0x60, 0xc2, 0xb5, 0x4e, 0x0c, 0x36, 0x68, 0x2c, 0x26, 0x42, 0x95, 0x25, 0xaf,
+	0xf2, 0x44, 0xa8, 0xea, 0x51, 0x16, 0x4c, 0x1b, 0x55, 0x2b, 0x32, 0xdc, 0x07, 0x0c, 0x32, 0xae,
+	0x35, 0xf3, 0x30, 0xf3, 0x70, 0xfc, 0x1f, 0xa2, 0x59, 0xcb, 0xc7, 0x13, 0x20, 0x37, 0xd2, 0xd6,
+	0x53, 0x23, 0xf3, 0x02, 0x6d, 0x86, 0x2f, 0x6b, 0xb4, 0x35, 0x39, 0x07, 0xd0, 0xca, 0xd4, 0xfc,
+	0x79, 0x59, 0xf3, 0x82, 0x06, 0xa3, 0x60, 0xdc, 0xcb, 0x7a, 0xce, 0xb9, 0xe5, 0x45, 0xbc, 0x0d,
+	0x20, 0x72, 0x89, 0x03, 0x24, 0x39, 0x85, 0x28, 0x57, 0x25, 0x97, 0x15, 0x0d, 0xdb, 0x2b, 0xaf,
+	0xc8, 0x00, 0x42, 0x99, 0xd3, 0x4e, 0xeb, 0x85, 0x32, 0xdf, 0x71, 0x56, 0xad, 0x8d, 0x40, 0xfa,
+	0xd7, 0x71, 0x4e, 0x11, 0x0a, 0xdd, 0x57, 0x65, 0x9e, 0xd0, 0x58, 0xfa, 0x6f, 0x14, 0x8c, 0x8f,
+	0xb2, 0xbd, 0x24, 0x97, 0x70, 0xcc, 0x45, 0x2d, 0x1b, 0x5c, 0x5a, 0xb4, 0x56, 0xaa, 0xca, 0xd2,
+	0xa8, 0x25, 0x06, 0xce, 0x5e, 0x78, 0x37, 0xbe, 0x83, 0x93, 0x2f, 0x0d, 0xad, 0x56, 0x95, 0x45,
+	0x72, 0x05, 0xd1, 0xaa, 0xb5, 0x68, 0x30, 0xea, 0x8c, 0xfb, 0xe9, 0x05, 0xfb, 0x79, 0x67, 0xcc,
+	0x0d, 0xc8, 0x7c, 0x2a, 0x7d, 0x0f, 0x60, 0x90, 0x39, 0x64, 0x81, 0xa6, 0x91, 0x02, 0xc9, 0x1b,
+	0xf4, 0x3f, 0xbd, 0x44, 0xd2, 0x43, 0x13, 0xbf, 0x2f, 0xfe, 0x6c, 0xf2, 0xab, 0x8c, 0xab, 0x12,
+	0xff, 0x99, 0xce, 0x21, 0x16, 0xaa, 0x3c, 0x90, 0x9d, 0x07, 0x0f, 0x5d, 0x7f, 0xdc, 0x86, 0xc3,
+	0xfb, 0x34, 0xe3, 0x1b, 0x36, 0xdb, 0xb1, 0xd7, 0x5a, 0x33, 0x5f, 0x85, 0xcd, 0x1c, 0xb0, 0x8a,
+	0xda, 0x8f, 0x34, 0xf9, 0x18, 0x00, 0xa6, 0xb4, 0xfb, 0xfe, 0x7b, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ReverseServiceClient is the client API for ReverseService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ReverseServiceClient interface {
+	ListBridges(ctx context.Context, in *ListBridgesRequest, opts ...grpc.CallOption) (*ListBridgesResponse, error)
+}
+
+type reverseServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewReverseServiceClient(cc *grpc.ClientConn) ReverseServiceClient {
+	return &reverseServiceClient{cc}
+}
+
+func (c *reverseServiceClient) ListBridges(ctx context.Context, in *ListBridgesRequest, opts ...grpc.CallOption) (*ListBridgesResponse, error) {
+	out := new(ListBridgesResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.reverse.command.ReverseService/ListBridges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReverseServiceServer is the server API for ReverseService service.
+type ReverseServiceServer interface {
+	ListBridges(context.Context, *ListBridgesRequest) (*ListBridgesResponse, error)
+}
+
+// UnimplementedReverseServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedReverseServiceServer struct {
+}
+
+func (*UnimplementedReverseServiceServer) ListBridges(ctx context.Context, req *ListBridgesRequest) (*ListBridgesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBridges not implemented")
+}
+
+func RegisterReverseServiceServer(s *grpc.Server, srv ReverseServiceServer) {
+	s.RegisterService(&_ReverseService_serviceDesc, srv)
+}
+
+func _ReverseService_ListBridges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBridgesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReverseServiceServer).ListBridges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.reverse.command.ReverseService/ListBridges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReverseServiceServer).ListBridges(ctx, req.(*ListBridgesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ReverseService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v2ray.core.app.reverse.command.ReverseService",
+	HandlerType: (*ReverseServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBridges",
+			Handler:    _ReverseService_ListBridges_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v2ray.com/core/app/reverse/command/config.proto",
+}
@@ -43,11 +43,16 @@ func (Control_State) EnumDescriptor() ([]byte, []int) {
 }
 
 type Control struct {
-	State                Control_State `protobuf:"varint,1,opt,name=state,proto3,enum=v2ray.core.app.reverse.Control_State" json:"state,omitempty"`
-	Random               []byte        `protobuf:"bytes,99,opt,name=random,proto3" json:"random,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	State Control_State `protobuf:"varint,1,opt,name=state,proto3,enum=v2ray.core.app.reverse.Control_State" json:"state,omitempty"`
+	// Whether the bridge should reply to this message. Set by portals that
+	// monitor the health of bridges.
+	Reply bool `protobuf:"varint,2,opt,name=reply,proto3" json:"reply,omitempty"`
+	// ID of the bridge. Set in replies from bridges.
+	BridgeId             string   `protobuf:"bytes,3,opt,name=bridge_id,json=bridgeId,proto3" json:"bridge_id,omitempty"`
+	Random               []byte   `protobuf:"bytes,99,opt,name=random,proto3" json:"random,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Control) Reset()         { *m = Control{} }
@@ -82,6 +87,20 @@ func (m *Control) GetState() Control_State {
 	return Control_ACTIVE
 }
 
+func (m *Control) GetReply() bool {
+	if m != nil {
+		return m.Reply
+	}
+	return false
+}
+
+func (m *Control) GetBridgeId() string {
+	if m != nil {
+		return m.BridgeId
+	}
+	return ""
+}
+
 func (m *Control) GetRandom() []byte {
 	if m != nil {
 		return m.Random
@@ -243,25 +262,27 @@ func init() {
 }
 
 var fileDescriptor_829a0eeb60380cbc = []byte{
-	// 310 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x91, 0xc1, 0x4b, 0xfb, 0x30,
-	0x14, 0xc7, 0x7f, 0xd9, 0x58, 0xc7, 0xde, 0xaf, 0x93, 0x91, 0xc3, 0xe8, 0x41, 0x64, 0x0c, 0xc5,
-	0x9d, 0x52, 0xa8, 0x17, 0xc1, 0xd3, 0xd6, 0x79, 0xe8, 0x45, 0x46, 0x94, 0x1d, 0xbc, 0x48, 0xd6,
-	0xc5, 0x52, 0x58, 0xfb, 0x42, 0x16, 0x86, 0xbd, 0xf8, 0xa7, 0xf8, 0x07, 0xf8, 0x57, 0x4a, 0xd3,
-	0x14, 0x7a, 0x50, 0xc1, 0xdb, 0x7b, 0xc9, 0xe7, 0xf3, 0xf2, 0x4d, 0x02, 0xd7, 0xa7, 0x48, 0x8b,
-	0x8a, 0xa5, 0x58, 0x84, 0x29, 0x6a, 0x19, 0x0a, 0xa5, 0x42, 0x2d, 0x4f, 0x52, 0x1f, 0x65, 0x98,
-	0x62, 0xf9, 0x9a, 0x67, 0x4c, 0x69, 0x34, 0x48, 0xa7, 0x2d, 0xa8, 0x25, 0x13, 0x4a, 0x31, 0x07,
-	0xcd, 0xdf, 0x61, 0x18, 0x63, 0x69, 0x34, 0x1e, 0xe8, 0x1d, 0x0c, 0x8e, 0x46, 0x18, 0x19, 0x90,
-	0x19, 0x59, 0x9c, 0x45, 0x57, 0xec, 0x7b, 0x85, 0x39, 0x9e, 0x3d, 0xd6, 0x30, 0x6f, 0x1c, 0x3a,
-	0x05, 0x4f, 0x8b, 0x72, 0x8f, 0x45, 0x90, 0xce, 0xc8, 0xc2, 0xe7, 0xae, 0x9b, 0x5f, 0xc0, 0xc0,
-	0x72, 0x14, 0xc0, 0x5b, 0xc6, 0x4f, 0xc9, 0xf6, 0x7e, 0xf2, 0x8f, 0x8e, 0x60, 0xb0, 0xe6, 0xcb,
-	0xe4, 0x61, 0x42, 0xe6, 0xb7, 0xe0, 0xaf, 0x74, 0xbe, 0xcf, 0x64, 0x6c, 0xd3, 0xd2, 0x09, 0xf4,
-	0x8d, 0xc8, 0x6c, 0x84, 0x11, 0xaf, 0xcb, 0x7a, 0xf2, 0x1e, 0x0b, 0x91, 0x97, 0x41, 0xcf, 0x2e,
-	0xba, 0xae, 0x36, 0x37, 0xa8, 0x8d, 0x38, 0xfc, 0xd9, 0xfc, 0x20, 0xe0, 0x39, 0x29, 0x81, 0xf1,
-	0xce, 0x1e, 0xff, 0xd2, 0xbc, 0x56, 0x40, 0x66, 0xfd, 0xc5, 0xff, 0xe8, 0xf2, 0xa7, 0xbb, 0x77,
-	0xb3, 0x72, 0x7f, 0xd7, 0x4d, 0x9e, 0xc0, 0x58, 0xd9, 0x3c, 0xed, 0xa8, 0xde, 0xef, 0xa3, 0xba,
-	0xe1, 0xb9, 0xaf, 0x3a, 0xdd, 0x6a, 0x0d, 0xe7, 0x29, 0x16, 0x5d, 0x51, 0x69, 0x7c, 0xab, 0x5a,
-	0x75, 0x43, 0x9e, 0x87, 0xae, 0xfc, 0xec, 0x05, 0xdb, 0x88, 0x8b, 0x8a, 0xc5, 0x35, 0xb5, 0xb1,
-	0x14, 0x6f, 0xb6, 0x76, 0x9e, 0xfd, 0xf9, 0x9b, 0xaf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xb7, 0x83,
-	0x30, 0xdb, 0x24, 0x02, 0x00, 0x00,
+	// 337 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0xc1, 0x4a, 0xf3, 0x40,
+	0x14, 0x85, 0xff, 0x69, 0x49, 0xda, 0xdc, 0x3f, 0x95, 0x32, 0x48, 0x09, 0x28, 0x12, 0x8a, 0x62,
+	0x56, 0x13, 0x88, 0x1b, 0xc1, 0x55, 0x9b, 0xba, 0xc8, 0x46, 0xca, 0x28, 0x5d, 0xb8, 0x91, 0x69,
+	0x32, 0x96, 0x40, 0x93, 0x19, 0xa6, 0xa1, 0x98, 0x97, 0xf1, 0x01, 0xdc, 0xf9, 0x86, 0x92, 0xc9,
+	0x04, 0xb2, 0x50, 0xc1, 0xdd, 0x3d, 0xc9, 0xf9, 0x0e, 0xe7, 0x5e, 0x06, 0xae, 0x8f, 0x91, 0x62,
+	0x35, 0x49, 0x45, 0x11, 0xa6, 0x42, 0xf1, 0x90, 0x49, 0x19, 0x2a, 0x7e, 0xe4, 0xea, 0xc0, 0xc3,
+	0x54, 0x94, 0xaf, 0xf9, 0x8e, 0x48, 0x25, 0x2a, 0x81, 0x67, 0x9d, 0x51, 0x71, 0xc2, 0xa4, 0x24,
+	0xc6, 0x34, 0xff, 0x44, 0x30, 0x8a, 0x45, 0x59, 0x29, 0xb1, 0xc7, 0x77, 0x60, 0x1d, 0x2a, 0x56,
+	0x71, 0x0f, 0xf9, 0x28, 0x38, 0x89, 0xae, 0xc8, 0xf7, 0x0c, 0x31, 0x7e, 0xf2, 0xd8, 0x98, 0x69,
+	0xcb, 0xe0, 0x53, 0xb0, 0x14, 0x97, 0xfb, 0xda, 0x1b, 0xf8, 0x28, 0x18, 0xd3, 0x56, 0xe0, 0x33,
+	0x70, 0xb6, 0x2a, 0xcf, 0x76, 0xfc, 0x25, 0xcf, 0xbc, 0xa1, 0x8f, 0x02, 0x87, 0x8e, 0xdb, 0x0f,
+	0x49, 0x86, 0x67, 0x60, 0x2b, 0x56, 0x66, 0xa2, 0xf0, 0x52, 0x1f, 0x05, 0x2e, 0x35, 0x6a, 0x7e,
+	0x01, 0x96, 0x8e, 0xc6, 0x00, 0xf6, 0x22, 0x7e, 0x4a, 0x36, 0xf7, 0xd3, 0x7f, 0xd8, 0x01, 0x6b,
+	0x45, 0x17, 0xc9, 0xc3, 0x14, 0xcd, 0x6f, 0xc1, 0x5d, 0xea, 0x8c, 0x58, 0x6f, 0x88, 0xa7, 0x30,
+	0xac, 0xd8, 0x4e, 0xb7, 0x76, 0x68, 0x33, 0x36, 0xc9, 0x99, 0x28, 0x58, 0x5e, 0xea, 0x36, 0x0e,
+	0x35, 0xaa, 0x21, 0xd7, 0x42, 0x55, 0x6c, 0xff, 0x67, 0xf2, 0x1d, 0x81, 0x6d, 0xa0, 0x04, 0x26,
+	0x66, 0xa7, 0xf6, 0xc2, 0x1e, 0xf2, 0x87, 0xc1, 0xff, 0xe8, 0xf2, 0xa7, 0x73, 0xf5, 0xbb, 0x52,
+	0x77, 0xdb, 0x6f, 0x9e, 0xc0, 0x44, 0xea, 0x3e, 0x5d, 0xd4, 0xe0, 0xf7, 0xa8, 0x7e, 0x79, 0xea,
+	0xca, 0x9e, 0x5a, 0xae, 0xe0, 0x3c, 0x15, 0x45, 0x1f, 0x94, 0x4a, 0xbc, 0xd5, 0x1d, 0xba, 0x46,
+	0xcf, 0x23, 0x33, 0x7e, 0x0c, 0xbc, 0x4d, 0x44, 0x59, 0x4d, 0xe2, 0xc6, 0xb5, 0xd6, 0x2e, 0xda,
+	0xfe, 0xda, 0xda, 0xfa, 0xb5, 0xdc, 0x7c, 0x0d, 0x00, 0x7f, 0xbc, 0xff, 0x0c, 0x58, 0x02, 0x00,
+	0x00,
 }
@@ -0,0 +1,110 @@
+package reverse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/mux"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/transport"
+	"v2ray.com/core/transport/pipe"
+)
+
+func newTestPortalWorker(t *testing.T, bridgeID string, sessions int) *PortalWorker {
+	reader, writer := pipe.New(pipe.WithoutSizeLimit())
+	client, err := mux.NewClientWorker(transport.Link{Reader: reader, Writer: writer}, mux.ClientStrategy{})
+	common.Must(err)
+
+	w, err := NewPortalWorker(client)
+	common.Must(err)
+	w.bridgeID = bridgeID
+	w.lastReply = time.Now()
+
+	for i := 0; i < sessions; i++ {
+		addSession(t, w)
+	}
+	return w
+}
+
+func addSession(t *testing.T, w *PortalWorker) {
+	r, _ := pipe.New(pipe.WithoutSizeLimit())
+	_, dw := pipe.New(pipe.WithoutSizeLimit())
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+		Target: net.TCPDestination(net.DomainAddress("www.v2ray.com"), 80),
+	})
+	if !w.client.Dispatch(ctx, &transport.Link{Reader: r, Writer: dw}) {
+		t.Fatal("failed to dispatch session")
+	}
+}
+
+func TestStaticPickerBalanceBridges(t *testing.T) {
+	picker, err := NewStaticMuxPicker()
+	common.Must(err)
+
+	a := newTestPortalWorker(t, "a", 3)
+	b1 := newTestPortalWorker(t, "b", 2)
+	b2 := newTestPortalWorker(t, "b", 0)
+	picker.AddWorker(a)
+	picker.AddWorker(b1)
+	picker.AddWorker(b2)
+
+	// Bridge a has 4 sessions including the control connection, and bridge b has 4 in total.
+	// Break the tie with one more session on b.
+	addSession(t, b2)
+	if w, _ := picker.PickAvailable(); w != a.client {
+		t.Error("expect worker of bridge a")
+	}
+
+	addSession(t, a)
+	addSession(t, a)
+	if w, _ := picker.PickAvailable(); w != b2.client {
+		t.Error("expect least loaded worker of bridge b")
+	}
+
+	bridges := picker.Bridges()
+	if len(bridges) != 2 {
+		t.Fatal("unexpected bridges: ", bridges)
+	}
+	for _, bridge := range bridges {
+		switch bridge.ID {
+		case "a":
+			if bridge.Workers != 1 || bridge.ActiveSessions != 6 {
+				t.Error("unexpected state of bridge a: ", bridge)
+			}
+		case "b":
+			if bridge.Workers != 2 || bridge.ActiveSessions != 5 {
+				t.Error("unexpected state of bridge b: ", bridge)
+			}
+		default:
+			t.Error("unexpected bridge: ", bridge)
+		}
+	}
+}
+
+func TestStaticPickerSkipUnresponsive(t *testing.T) {
+	picker, err := NewStaticMuxPicker()
+	common.Must(err)
+
+	a := newTestPortalWorker(t, "a", 0)
+	b := newTestPortalWorker(t, "b", 4)
+	picker.AddWorker(a)
+	picker.AddWorker(b)
+
+	a.access.Lock()
+	a.lastReply = time.Now().Add(-heartbeatTimeout)
+	a.access.Unlock()
+
+	if w, _ := picker.PickAvailable(); w != b.client {
+		t.Error("expect worker of responsive bridge")
+	}
+
+	if err := a.heartbeat(); err == nil {
+		t.Error("expect heartbeat to fail for unresponsive bridge")
+	}
+	if !a.Closed() {
+		t.Error("unresponsive worker is not closed")
+	}
+}
@@ -4,6 +4,7 @@ package reverse
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -79,6 +80,9 @@ func (p *Portal) HandleConnection(ctx context.Context, link *transport.Link) err
 		if err != nil {
 			return newError("failed to create portal worker").Base(err)
 		}
+		if inbound := session.InboundFromContext(ctx); inbound != nil {
+			worker.source = inbound.Source
+		}
 
 		p.picker.AddWorker(worker)
 		return nil
@@ -87,6 +91,33 @@ func (p *Portal) HandleConnection(ctx context.Context, link *transport.Link) err
 	return p.client.Dispatch(ctx, link)
 }
 
+// BridgeInfo is the state of a bridge connected to a portal.
+type BridgeInfo struct {
+	// ID of the bridge. Empty if the bridge doesn't report its ID.
+	ID string
+	// Source is the address that the bridge connects from.
+	Source net.Destination
+	// Workers is the number of mux connections from the bridge.
+	Workers int
+	// ActiveSessions is the number of active sessions through the bridge.
+	ActiveSessions uint32
+}
+
+// Bridges returns the bridges currently connected to this portal.
+func (p *Portal) Bridges() []BridgeInfo {
+	return p.picker.Bridges()
+}
+
+// Tag returns the tag of this portal.
+func (p *Portal) Tag() string {
+	return p.tag
+}
+
+// Domain returns the domain of this portal.
+func (p *Portal) Domain() string {
+	return p.domain
+}
+
 type Outbound struct {
 	portal *Portal
 	tag    string
@@ -111,6 +142,8 @@ func (o *Outbound) Close() error {
 	return nil
 }
 
+// StaticMuxPicker picks mux workers from the connected bridges. It balances sessions across bridges
+// first, and then across workers of the same bridge.
 type StaticMuxPicker struct {
 	access  sync.Mutex
 	workers []*PortalWorker
@@ -145,40 +178,61 @@ func (p *StaticMuxPicker) cleanup() error {
 	return nil
 }
 
-func (p *StaticMuxPicker) PickAvailable() (*mux.ClientWorker, error) {
-	p.access.Lock()
-	defer p.access.Unlock()
+type bridgeLoad struct {
+	sessions uint32
+	best     *PortalWorker
+}
 
-	if len(p.workers) == 0 {
-		return nil, newError("empty worker list")
+// pick returns the least loaded worker of the least loaded bridge.
+func (p *StaticMuxPicker) pick(allowDraining bool) *PortalWorker {
+	now := time.Now()
+	loads := make(map[string]*bridgeLoad)
+	for _, w := range p.workers {
+		if w.Closed() || !w.healthy(now) || (w.draining && !allowDraining) {
+			continue
+		}
+		key := w.bridgeKey()
+		load := loads[key]
+		if load == nil {
+			load = new(bridgeLoad)
+			loads[key] = load
+		}
+		conns := w.client.ActiveConnections()
+		load.sessions += conns
+		if !w.IsFull() && (load.best == nil || conns < load.best.client.ActiveConnections()) {
+			load.best = w
+		}
 	}
 
-	var minIdx int = -1
-	var minConn uint32 = 9999
-	for i, w := range p.workers {
-		if w.draining {
+	var picked *bridgeLoad
+	for _, load := range loads {
+		if load.best == nil {
 			continue
 		}
-		if w.client.ActiveConnections() < minConn {
-			minConn = w.client.ActiveConnections()
-			minIdx = i
+		if picked == nil || load.sessions < picked.sessions {
+			picked = load
 		}
 	}
+	if picked == nil {
+		return nil
+	}
+	return picked.best
+}
 
-	if minIdx == -1 {
-		for i, w := range p.workers {
-			if w.IsFull() {
-				continue
-			}
-			if w.client.ActiveConnections() < minConn {
-				minConn = w.client.ActiveConnections()
-				minIdx = i
-			}
-		}
+func (p *StaticMuxPicker) PickAvailable() (*mux.ClientWorker, error) {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if len(p.workers) == 0 {
+		return nil, newError("empty worker list")
 	}
 
-	if minIdx != -1 {
-		return p.workers[minIdx].client, nil
+	w := p.pick(false)
+	if w == nil {
+		w = p.pick(true)
+	}
+	if w != nil {
+		return w.client, nil
 	}
 
 	return nil, newError("no mux client worker available")
@@ -191,12 +245,50 @@ func (p *StaticMuxPicker) AddWorker(worker *PortalWorker) {
 	p.workers = append(p.workers, worker)
 }
 
+// Bridges returns the state of bridges, grouped from active workers.
+func (p *StaticMuxPicker) Bridges() []BridgeInfo {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	var bridges []BridgeInfo
+	index := make(map[string]int)
+	for _, w := range p.workers {
+		if w.Closed() {
+			continue
+		}
+		key := w.bridgeKey()
+		i, found := index[key]
+		if !found {
+			i = len(bridges)
+			index[key] = i
+			bridges = append(bridges, BridgeInfo{
+				ID:     w.BridgeID(),
+				Source: w.source,
+			})
+		}
+		bridges[i].Workers++
+		bridges[i].ActiveSessions += w.client.ActiveConnections()
+	}
+	return bridges
+}
+
+const (
+	heartbeatInterval = time.Second * 2
+	// Workers are evicted if the bridge doesn't reply to heartbeats in this duration.
+	heartbeatTimeout = heartbeatInterval * 3
+)
+
 type PortalWorker struct {
 	client   *mux.ClientWorker
 	control  *task.Periodic
 	writer   buf.Writer
 	reader   buf.Reader
 	draining bool
+	source   net.Destination
+
+	access    sync.Mutex
+	bridgeID  string
+	lastReply time.Time
 }
 
 func NewPortalWorker(client *mux.ClientWorker) (*PortalWorker, error) {
@@ -222,12 +314,59 @@ func NewPortalWorker(client *mux.ClientWorker) (*PortalWorker, error) {
 	}
 	w.control = &task.Periodic{
 		Execute:  w.heartbeat,
-		Interval: time.Second * 2,
+		Interval: heartbeatInterval,
 	}
 	w.control.Start()
+	go w.readReplies(downlinkReader)
 	return w, nil
 }
 
+func (w *PortalWorker) readReplies(reader buf.Reader) {
+	for {
+		mb, err := reader.ReadMultiBuffer()
+		if err != nil {
+			return
+		}
+		for _, b := range mb {
+			var ctl Control
+			if err := proto.Unmarshal(b.Bytes(), &ctl); err != nil {
+				newError("failed to parse proto message").Base(err).WriteToLog()
+				continue
+			}
+			w.access.Lock()
+			w.bridgeID = ctl.BridgeId
+			w.lastReply = time.Now()
+			w.access.Unlock()
+		}
+		buf.ReleaseMulti(mb)
+	}
+}
+
+// BridgeID returns the ID of the bridge that this worker connects to. Empty if not known.
+func (w *PortalWorker) BridgeID() string {
+	w.access.Lock()
+	defer w.access.Unlock()
+
+	return w.bridgeID
+}
+
+// bridgeKey identifies the bridge of this worker. Each worker of bridges that don't report their IDs
+// is treated as a bridge on its own.
+func (w *PortalWorker) bridgeKey() string {
+	if id := w.BridgeID(); len(id) > 0 {
+		return id
+	}
+	return fmt.Sprintf("%p", w)
+}
+
+// healthy returns false if the bridge has replied to heartbeats, but stops replying.
+func (w *PortalWorker) healthy(now time.Time) bool {
+	w.access.Lock()
+	defer w.access.Unlock()
+
+	return w.lastReply.IsZero() || now.Sub(w.lastReply) < heartbeatTimeout
+}
+
 func (w *PortalWorker) heartbeat() error {
 	if w.client.Closed() {
 		return newError("client worker stopped")
@@ -237,12 +376,24 @@ func (w *PortalWorker) heartbeat() error {
 		return newError("already disposed")
 	}
 
-	msg := &Control{}
+	if !w.healthy(time.Now()) {
+		newError("evicting unresponsive bridge worker ", w.bridgeKey()).AtWarning().WriteToLog()
+		common.Close(w.writer)
+		common.Interrupt(w.reader)
+		w.writer = nil
+		common.Must(w.client.Close())
+		return newError("bridge unresponsive")
+	}
+
+	msg := &Control{
+		Reply: true,
+	}
 	msg.FillInRandom()
 
 	if w.client.TotalConnections() > 256 {
 		w.draining = true
 		msg.State = Control_DRAIN
+		msg.Reply = false
 
 		defer func() {
 			common.Close(w.writer)
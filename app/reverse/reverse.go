@@ -68,6 +68,11 @@ func (r *Reverse) Type() interface{} {
 	return (*Reverse)(nil)
 }
 
+// Portals returns all portals of this instance.
+func (r *Reverse) Portals() []*Portal {
+	return r.portals
+}
+
 func (r *Reverse) Start() error {
 	for _, b := range r.bridges {
 		if err := b.Start(); err != nil {
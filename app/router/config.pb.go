@@ -484,11 +484,14 @@ type RoutingRule struct {
 	// List of CIDRs for source IP address matching.
 	SourceCidr []*CIDR `protobuf:"bytes,6,rep,name=source_cidr,json=sourceCidr,proto3" json:"source_cidr,omitempty"` // Deprecated: Do not use.
 	// List of GeoIPs for source IP address matching. If this entry exists, the source_cidr above will have no effect.
-	SourceGeoip          []*GeoIP `protobuf:"bytes,11,rep,name=source_geoip,json=sourceGeoip,proto3" json:"source_geoip,omitempty"`
-	UserEmail            []string `protobuf:"bytes,7,rep,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
-	InboundTag           []string `protobuf:"bytes,8,rep,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
-	Protocol             []string `protobuf:"bytes,9,rep,name=protocol,proto3" json:"protocol,omitempty"`
-	Attributes           string   `protobuf:"bytes,15,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	SourceGeoip []*GeoIP `protobuf:"bytes,11,rep,name=source_geoip,json=sourceGeoip,proto3" json:"source_geoip,omitempty"`
+	UserEmail   []string `protobuf:"bytes,7,rep,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	InboundTag  []string `protobuf:"bytes,8,rep,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	Protocol    []string `protobuf:"bytes,9,rep,name=protocol,proto3" json:"protocol,omitempty"`
+	Attributes  string   `protobuf:"bytes,15,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	// Tag of this rule. It is recorded in the session of the connections that
+	// match this rule.
+	RuleTag              string   `protobuf:"bytes,16,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -651,6 +654,13 @@ func (m *RoutingRule) GetAttributes() string {
 	return ""
 }
 
+func (m *RoutingRule) GetRuleTag() string {
+	if m != nil {
+		return m.RuleTag
+	}
+	return ""
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*RoutingRule) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -781,62 +791,63 @@ func init() {
 }
 
 var fileDescriptor_6b1608360690c5fc = []byte{
-	// 910 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x5f, 0x8f, 0xdb, 0x44,
-	0x10, 0x3f, 0xdb, 0x49, 0x1a, 0x8f, 0x93, 0xd4, 0xac, 0x28, 0x32, 0x07, 0xd7, 0x0b, 0x56, 0xa1,
-	0x91, 0x40, 0x8e, 0x94, 0x02, 0x0f, 0x08, 0x54, 0x2e, 0xb9, 0x72, 0x17, 0x01, 0xc7, 0x69, 0xaf,
-	0xed, 0x03, 0x3c, 0x44, 0x8e, 0xb3, 0x67, 0xac, 0x3a, 0xbb, 0xab, 0xf5, 0xba, 0x34, 0x5f, 0x09,
-	0x89, 0xcf, 0xc0, 0x47, 0x03, 0xed, 0x9f, 0x24, 0x17, 0x68, 0x8e, 0x13, 0x6f, 0x3b, 0xb3, 0xbf,
-	0xf9, 0xed, 0x6f, 0x67, 0x76, 0x66, 0xe1, 0x93, 0xd7, 0x23, 0x91, 0xae, 0x92, 0x8c, 0x2d, 0x87,
-	0x19, 0x13, 0x64, 0x98, 0x72, 0x3e, 0x14, 0xac, 0x96, 0x44, 0x0c, 0x33, 0x46, 0xaf, 0x8b, 0x3c,
-	0xe1, 0x82, 0x49, 0x86, 0x1e, 0xac, 0x71, 0x82, 0x24, 0x29, 0xe7, 0x89, 0xc1, 0x1c, 0x3e, 0xfa,
-	0x47, 0x78, 0xc6, 0x96, 0x4b, 0x46, 0x87, 0x94, 0xc8, 0x21, 0x67, 0x42, 0x9a, 0xe0, 0xc3, 0xc7,
-	0xfb, 0x51, 0x94, 0xc8, 0xdf, 0x98, 0x78, 0x65, 0x80, 0xf1, 0x9f, 0x2e, 0xb4, 0x4e, 0xd9, 0x32,
-	0x2d, 0x28, 0xfa, 0x12, 0x1a, 0x72, 0xc5, 0x49, 0xe4, 0xf4, 0x9d, 0x41, 0x6f, 0x14, 0x27, 0x6f,
-	0x3d, 0x3f, 0x31, 0xe0, 0xe4, 0xf9, 0x8a, 0x13, 0xac, 0xf1, 0xe8, 0x5d, 0x68, 0xbe, 0x4e, 0xcb,
-	0x9a, 0x44, 0x6e, 0xdf, 0x19, 0xf8, 0xd8, 0x18, 0xe8, 0x19, 0xf8, 0xa9, 0x94, 0xa2, 0x98, 0xd7,
-	0x92, 0x44, 0x5e, 0xdf, 0x1b, 0x04, 0xa3, 0xc7, 0xb7, 0x53, 0x9e, 0xac, 0xe1, 0x78, 0x1b, 0x79,
-	0x58, 0x82, 0xbf, 0xf1, 0xa3, 0x10, 0xbc, 0x57, 0x64, 0xa5, 0x05, 0xfa, 0x58, 0x2d, 0xd1, 0x31,
-	0xc0, 0x9c, 0xb1, 0x72, 0xb6, 0x15, 0xd0, 0x3e, 0x3f, 0xc0, 0xbe, 0xf2, 0xbd, 0xd4, 0x32, 0x8e,
-	0xc0, 0x2f, 0xa8, 0xb4, 0xfb, 0x5e, 0xdf, 0x19, 0x78, 0xe7, 0x07, 0xb8, 0x5d, 0x50, 0xa9, 0xb7,
-	0xc7, 0x5d, 0x08, 0xd4, 0x1d, 0x16, 0x06, 0x10, 0x8f, 0xa0, 0xa1, 0x2e, 0x86, 0x7c, 0x68, 0x5e,
-	0x96, 0x69, 0x41, 0xc3, 0x03, 0xb5, 0xc4, 0x24, 0x27, 0x6f, 0x42, 0x07, 0xc1, 0x3a, 0x55, 0xa1,
-	0x8b, 0xda, 0xd0, 0xf8, 0xae, 0x2e, 0xcb, 0xd0, 0x8b, 0x13, 0x68, 0x4c, 0xa6, 0xa7, 0x18, 0xf5,
-	0xc0, 0x2d, 0xb8, 0xd6, 0xd6, 0xc1, 0x6e, 0xc1, 0xd1, 0x7b, 0xd0, 0xe2, 0x82, 0x5c, 0x17, 0x6f,
-	0xb4, 0xac, 0x2e, 0xb6, 0x56, 0xfc, 0x0b, 0x34, 0xcf, 0x08, 0x9b, 0x5e, 0xa2, 0x8f, 0xa0, 0x93,
-	0xb1, 0x9a, 0x4a, 0xb1, 0x9a, 0x65, 0x6c, 0x41, 0xec, 0xb5, 0x02, 0xeb, 0x9b, 0xb0, 0x05, 0x41,
-	0x43, 0x68, 0x64, 0xc5, 0x42, 0x44, 0xae, 0xce, 0xdf, 0x07, 0x7b, 0xf2, 0xa7, 0x8e, 0xc7, 0x1a,
-	0x18, 0x3f, 0x05, 0x5f, 0x93, 0xff, 0x50, 0x54, 0x12, 0x8d, 0xa0, 0x49, 0x14, 0x55, 0xe4, 0xe8,
-	0xf0, 0x0f, 0xf7, 0x84, 0xeb, 0x00, 0x6c, 0xa0, 0x71, 0x06, 0xf7, 0xce, 0x08, 0xbb, 0x2a, 0x24,
-	0xb9, 0x8b, 0xbe, 0x2f, 0xa0, 0xb5, 0xd0, 0x19, 0xb1, 0x0a, 0x8f, 0x6e, 0xad, 0x30, 0xb6, 0xe0,
-	0x78, 0x02, 0x81, 0x3d, 0x44, 0xeb, 0xfc, 0x7c, 0x57, 0xe7, 0xc3, 0xfd, 0x3a, 0x55, 0xc8, 0x5a,
-	0xe9, 0x5f, 0x4d, 0x08, 0x30, 0xab, 0x65, 0x41, 0x73, 0x5c, 0x97, 0x04, 0x21, 0xf0, 0x64, 0x9a,
-	0x1b, 0x95, 0xe7, 0x07, 0x58, 0x19, 0xe8, 0x63, 0xe8, 0xce, 0xd3, 0x32, 0xa5, 0x59, 0x41, 0xf3,
-	0x99, 0xda, 0xed, 0xd8, 0xdd, 0xce, 0xc6, 0xfd, 0x3c, 0xcd, 0xff, 0xe7, 0x35, 0xd0, 0x13, 0x5b,
-	0x1d, 0xef, 0x3f, 0xab, 0x33, 0x76, 0x23, 0xc7, 0x54, 0x48, 0x15, 0x25, 0x27, 0xac, 0xe0, 0x11,
-	0xdc, 0xa5, 0x28, 0x1a, 0x8a, 0x26, 0x00, 0xaa, 0xb7, 0x67, 0x22, 0xa5, 0x39, 0x89, 0x1a, 0x7d,
-	0x67, 0x10, 0x8c, 0xfa, 0x37, 0x03, 0x4d, 0x7b, 0x27, 0x94, 0xc8, 0xe4, 0x92, 0x09, 0x89, 0x15,
-	0x4e, 0x9f, 0xe9, 0xf3, 0xb5, 0x89, 0xbe, 0x06, 0x6d, 0xcc, 0xca, 0xa2, 0x92, 0x51, 0x4f, 0x73,
-	0x1c, 0xdf, 0xc2, 0xa1, 0x2a, 0x83, 0xdb, 0xdc, 0xae, 0xd0, 0x14, 0x3a, 0x76, 0x70, 0x18, 0x82,
-	0xa6, 0x26, 0x88, 0xf7, 0x10, 0x5c, 0x18, 0xa8, 0x8a, 0xd4, 0x32, 0x02, 0xba, 0x75, 0xa0, 0xaf,
-	0xa0, 0x6d, 0xcd, 0x2a, 0xea, 0xf6, 0xbd, 0x41, 0x6f, 0xb7, 0xe2, 0xff, 0xa6, 0xc1, 0x1b, 0x3c,
-	0xfa, 0x16, 0x82, 0x8a, 0xd5, 0x22, 0x23, 0x33, 0x9d, 0xf9, 0xd6, 0xdd, 0x32, 0x0f, 0x26, 0x66,
-	0xa2, 0xf2, 0xff, 0x14, 0x3a, 0x96, 0xc1, 0x94, 0x21, 0xb8, 0x43, 0x19, 0xec, 0x99, 0x67, 0xba,
-	0x18, 0x47, 0x00, 0x75, 0x45, 0xc4, 0x8c, 0x2c, 0xd3, 0xa2, 0x8c, 0xee, 0xf5, 0xbd, 0x81, 0x8f,
-	0x7d, 0xe5, 0x79, 0xa6, 0x1c, 0xe8, 0x18, 0x82, 0x82, 0xce, 0x59, 0x4d, 0x17, 0xfa, 0xc1, 0xb5,
-	0xf5, 0x3e, 0x58, 0x97, 0x7a, 0x6c, 0x87, 0xd0, 0xd6, 0xa3, 0x37, 0x63, 0x65, 0xe4, 0xeb, 0xdd,
-	0x8d, 0x8d, 0x1e, 0x02, 0x6c, 0x46, 0x5f, 0x15, 0xdd, 0xd7, 0x0d, 0x77, 0xc3, 0x33, 0xee, 0x00,
-	0xc8, 0x54, 0xe4, 0x44, 0x2a, 0xee, 0xf8, 0x02, 0xba, 0xe3, 0xf5, 0x33, 0xd6, 0x2d, 0x10, 0xde,
-	0x68, 0x01, 0xd3, 0x00, 0x9f, 0xc2, 0x3b, 0xac, 0x96, 0x46, 0x4e, 0x45, 0x4a, 0x92, 0x49, 0x66,
-	0xa6, 0x89, 0x8f, 0xc3, 0xf5, 0xc6, 0x95, 0xf5, 0xc7, 0x7f, 0xb8, 0xd0, 0x9a, 0xe8, 0x2f, 0x08,
-	0xbd, 0x80, 0xfb, 0xe6, 0x91, 0xcf, 0x2a, 0x29, 0x52, 0x49, 0xf2, 0x95, 0xfd, 0x16, 0x3e, 0xdb,
-	0x97, 0x6b, 0xf3, 0x75, 0x99, 0x0e, 0xb9, 0xb2, 0x31, 0xb8, 0xb7, 0xd8, 0xb1, 0xd5, 0x17, 0x23,
-	0xea, 0x92, 0xd8, 0x36, 0xdb, 0xf7, 0xc5, 0xdc, 0xe8, 0x6a, 0xac, 0xf1, 0xe8, 0x7b, 0xe8, 0x6d,
-	0xfb, 0x58, 0x33, 0x98, 0x9e, 0x7b, 0xb4, 0x87, 0x61, 0x27, 0x2d, 0x78, 0x3b, 0x03, 0x94, 0x19,
-	0x9f, 0x41, 0x6f, 0x57, 0xa6, 0x1a, 0xe6, 0x27, 0xd5, 0xb4, 0x32, 0xd3, 0xfe, 0x45, 0x45, 0xa6,
-	0x3c, 0x74, 0x50, 0x08, 0x9d, 0x29, 0x9f, 0x5e, 0x5f, 0x30, 0xfa, 0x63, 0x2a, 0xb3, 0x5f, 0x43,
-	0x17, 0xf5, 0x00, 0xa6, 0xfc, 0x27, 0x7a, 0x4a, 0x96, 0x29, 0x5d, 0x84, 0xde, 0xf8, 0x1b, 0x78,
-	0x3f, 0x63, 0xcb, 0xb7, 0x4b, 0xb8, 0x74, 0x7e, 0x6e, 0x99, 0xd5, 0xef, 0xee, 0x83, 0x97, 0x23,
-	0x9c, 0xae, 0x92, 0x89, 0x42, 0x9c, 0x70, 0xae, 0xef, 0x47, 0xc4, 0xbc, 0xa5, 0xcb, 0xfe, 0xe4,
-	0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0x7b, 0x3e, 0x38, 0x1b, 0x11, 0x08, 0x00, 0x00,
+	// 921 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xef, 0x6e, 0xe3, 0x44,
+	0x10, 0xaf, 0xed, 0x24, 0x17, 0x8f, 0x93, 0x9c, 0x59, 0x71, 0xc8, 0x57, 0x68, 0x1b, 0xac, 0x83,
+	0x8b, 0x04, 0x72, 0xa4, 0x1c, 0xf0, 0x01, 0x81, 0x8e, 0x26, 0x3d, 0xda, 0x08, 0x28, 0xd5, 0xb6,
+	0x77, 0x1f, 0xe0, 0x43, 0xe4, 0x38, 0x5b, 0x63, 0x9d, 0xb3, 0xbb, 0x5a, 0xaf, 0x8f, 0xcb, 0xab,
+	0xf0, 0x08, 0x48, 0x3c, 0x03, 0xaf, 0x86, 0xf6, 0x4f, 0xd2, 0x14, 0x5d, 0x4a, 0xc5, 0xb7, 0x9d,
+	0x99, 0xdf, 0xfc, 0xf6, 0xb7, 0x33, 0x3b, 0xbb, 0xf0, 0xe9, 0x9b, 0x91, 0x48, 0x57, 0x49, 0xc6,
+	0x96, 0xc3, 0x8c, 0x09, 0x32, 0x4c, 0x39, 0x1f, 0x0a, 0x56, 0x4b, 0x22, 0x86, 0x19, 0xa3, 0xd7,
+	0x45, 0x9e, 0x70, 0xc1, 0x24, 0x43, 0x8f, 0xd6, 0x38, 0x41, 0x92, 0x94, 0xf3, 0xc4, 0x60, 0xf6,
+	0x9f, 0xfc, 0x2b, 0x3d, 0x63, 0xcb, 0x25, 0xa3, 0x43, 0x4a, 0xe4, 0x90, 0x33, 0x21, 0x4d, 0xf2,
+	0xfe, 0xd3, 0xdd, 0x28, 0x4a, 0xe4, 0xef, 0x4c, 0xbc, 0x36, 0xc0, 0xf8, 0x6f, 0x17, 0x5a, 0x27,
+	0x6c, 0x99, 0x16, 0x14, 0x7d, 0x05, 0x0d, 0xb9, 0xe2, 0x24, 0x72, 0xfa, 0xce, 0xa0, 0x37, 0x8a,
+	0x93, 0x77, 0xee, 0x9f, 0x18, 0x70, 0x72, 0xb5, 0xe2, 0x04, 0x6b, 0x3c, 0x7a, 0x1f, 0x9a, 0x6f,
+	0xd2, 0xb2, 0x26, 0x91, 0xdb, 0x77, 0x06, 0x3e, 0x36, 0x06, 0x7a, 0x01, 0x7e, 0x2a, 0xa5, 0x28,
+	0xe6, 0xb5, 0x24, 0x91, 0xd7, 0xf7, 0x06, 0xc1, 0xe8, 0xe9, 0xdd, 0x94, 0xc7, 0x6b, 0x38, 0xbe,
+	0xc9, 0xdc, 0x2f, 0xc1, 0xdf, 0xf8, 0x51, 0x08, 0xde, 0x6b, 0xb2, 0xd2, 0x02, 0x7d, 0xac, 0x96,
+	0xe8, 0x08, 0x60, 0xce, 0x58, 0x39, 0xbb, 0x11, 0xd0, 0x3e, 0xdb, 0xc3, 0xbe, 0xf2, 0xbd, 0xd2,
+	0x32, 0x0e, 0xc0, 0x2f, 0xa8, 0xb4, 0x71, 0xaf, 0xef, 0x0c, 0xbc, 0xb3, 0x3d, 0xdc, 0x2e, 0xa8,
+	0xd4, 0xe1, 0x71, 0x17, 0x02, 0x75, 0x86, 0x85, 0x01, 0xc4, 0x23, 0x68, 0xa8, 0x83, 0x21, 0x1f,
+	0x9a, 0x17, 0x65, 0x5a, 0xd0, 0x70, 0x4f, 0x2d, 0x31, 0xc9, 0xc9, 0xdb, 0xd0, 0x41, 0xb0, 0x2e,
+	0x55, 0xe8, 0xa2, 0x36, 0x34, 0xbe, 0xaf, 0xcb, 0x32, 0xf4, 0xe2, 0x04, 0x1a, 0x93, 0xe9, 0x09,
+	0x46, 0x3d, 0x70, 0x0b, 0xae, 0xb5, 0x75, 0xb0, 0x5b, 0x70, 0xf4, 0x01, 0xb4, 0xb8, 0x20, 0xd7,
+	0xc5, 0x5b, 0x2d, 0xab, 0x8b, 0xad, 0x15, 0xff, 0x0a, 0xcd, 0x53, 0xc2, 0xa6, 0x17, 0xe8, 0x63,
+	0xe8, 0x64, 0xac, 0xa6, 0x52, 0xac, 0x66, 0x19, 0x5b, 0x10, 0x7b, 0xac, 0xc0, 0xfa, 0x26, 0x6c,
+	0x41, 0xd0, 0x10, 0x1a, 0x59, 0xb1, 0x10, 0x91, 0xab, 0xeb, 0xf7, 0xe1, 0x8e, 0xfa, 0xa9, 0xed,
+	0xb1, 0x06, 0xc6, 0xcf, 0xc1, 0xd7, 0xe4, 0x3f, 0x16, 0x95, 0x44, 0x23, 0x68, 0x12, 0x45, 0x15,
+	0x39, 0x3a, 0xfd, 0xa3, 0x1d, 0xe9, 0x3a, 0x01, 0x1b, 0x68, 0x9c, 0xc1, 0x83, 0x53, 0xc2, 0x2e,
+	0x0b, 0x49, 0xee, 0xa3, 0xef, 0x4b, 0x68, 0x2d, 0x74, 0x45, 0xac, 0xc2, 0x83, 0x3b, 0x3b, 0x8c,
+	0x2d, 0x38, 0x9e, 0x40, 0x60, 0x37, 0xd1, 0x3a, 0xbf, 0xb8, 0xad, 0xf3, 0x70, 0xb7, 0x4e, 0x95,
+	0xb2, 0x56, 0xfa, 0x47, 0x0b, 0x02, 0xcc, 0x6a, 0x59, 0xd0, 0x1c, 0xd7, 0x25, 0x41, 0x08, 0x3c,
+	0x99, 0xe6, 0x46, 0xe5, 0xd9, 0x1e, 0x56, 0x06, 0xfa, 0x04, 0xba, 0xf3, 0xb4, 0x4c, 0x69, 0x56,
+	0xd0, 0x7c, 0xa6, 0xa2, 0x1d, 0x1b, 0xed, 0x6c, 0xdc, 0x57, 0x69, 0xfe, 0x3f, 0x8f, 0x81, 0x9e,
+	0xd9, 0xee, 0x78, 0xff, 0xd9, 0x9d, 0xb1, 0x1b, 0x39, 0xa6, 0x43, 0xaa, 0x29, 0x39, 0x61, 0x05,
+	0x8f, 0xe0, 0x3e, 0x4d, 0xd1, 0x50, 0x34, 0x01, 0x50, 0xb3, 0x3d, 0x13, 0x29, 0xcd, 0x49, 0xd4,
+	0xe8, 0x3b, 0x83, 0x60, 0xd4, 0xdf, 0x4e, 0x34, 0xe3, 0x9d, 0x50, 0x22, 0x93, 0x0b, 0x26, 0x24,
+	0x56, 0x38, 0xbd, 0xa7, 0xcf, 0xd7, 0x26, 0xfa, 0x06, 0xb4, 0x31, 0x2b, 0x8b, 0x4a, 0x46, 0x3d,
+	0xcd, 0x71, 0x74, 0x07, 0x87, 0xea, 0x0c, 0x6e, 0x73, 0xbb, 0x42, 0x53, 0xe8, 0xd8, 0x87, 0xc3,
+	0x10, 0x34, 0x35, 0x41, 0xbc, 0x83, 0xe0, 0xdc, 0x40, 0x55, 0xa6, 0x96, 0x11, 0xd0, 0x1b, 0x07,
+	0xfa, 0x1a, 0xda, 0xd6, 0xac, 0xa2, 0x6e, 0xdf, 0x1b, 0xf4, 0x46, 0x87, 0x77, 0xd3, 0xe0, 0x0d,
+	0x1e, 0x7d, 0x07, 0x41, 0xc5, 0x6a, 0x91, 0x91, 0x99, 0xae, 0x7c, 0xeb, 0x7e, 0x95, 0x07, 0x93,
+	0x33, 0x51, 0xf5, 0x7f, 0x0e, 0x1d, 0xcb, 0x60, 0xda, 0x10, 0xdc, 0xa3, 0x0d, 0x76, 0xcf, 0x53,
+	0xdd, 0x8c, 0x03, 0x80, 0xba, 0x22, 0x62, 0x46, 0x96, 0x69, 0x51, 0x46, 0x0f, 0xfa, 0xde, 0xc0,
+	0xc7, 0xbe, 0xf2, 0xbc, 0x50, 0x0e, 0x74, 0x04, 0x41, 0x41, 0xe7, 0xac, 0xa6, 0x0b, 0x7d, 0xe1,
+	0xda, 0x3a, 0x0e, 0xd6, 0xa5, 0x2e, 0xdb, 0x3e, 0xb4, 0xf5, 0xd3, 0x9b, 0xb1, 0x32, 0xf2, 0x75,
+	0x74, 0x63, 0xa3, 0x43, 0x80, 0xcd, 0xd3, 0x57, 0x45, 0x0f, 0xf5, 0xc0, 0x6d, 0x79, 0xd0, 0x63,
+	0x68, 0x8b, 0xba, 0x24, 0x9a, 0x39, 0xd4, 0xd1, 0x07, 0xca, 0xbe, 0x4a, 0xf3, 0x71, 0x07, 0x40,
+	0xa6, 0x22, 0x27, 0x52, 0x05, 0xe3, 0x73, 0xe8, 0x8e, 0xd7, 0x37, 0x5c, 0x4f, 0x47, 0xb8, 0x35,
+	0x1d, 0x66, 0x36, 0x3e, 0x83, 0xf7, 0x58, 0x2d, 0x8d, 0xd2, 0x8a, 0x94, 0x24, 0x93, 0xcc, 0x3c,
+	0x34, 0x3e, 0x0e, 0xd7, 0x81, 0x4b, 0xeb, 0x8f, 0xff, 0x72, 0xa1, 0x35, 0xd1, 0xbf, 0x13, 0x7a,
+	0x09, 0x0f, 0xcd, 0xfd, 0x9f, 0x55, 0x52, 0xa4, 0x92, 0xe4, 0x2b, 0xfb, 0x63, 0x7c, 0xbe, 0xab,
+	0x0d, 0x3a, 0xcf, 0x0e, 0xcf, 0xa5, 0xcd, 0xc1, 0xbd, 0xc5, 0x2d, 0x5b, 0xfd, 0x3e, 0xea, 0x28,
+	0x76, 0x02, 0x77, 0xfd, 0x3e, 0x5b, 0x03, 0x8f, 0x35, 0x1e, 0xfd, 0x00, 0xbd, 0x9b, 0x11, 0xd7,
+	0x0c, 0x66, 0x1c, 0x9f, 0xec, 0x60, 0xb8, 0x55, 0x16, 0xdc, 0x9d, 0x6f, 0x9b, 0xf1, 0x29, 0xf4,
+	0x6e, 0xcb, 0x54, 0xef, 0xfc, 0x71, 0x35, 0xad, 0xcc, 0x47, 0xf0, 0xb2, 0x22, 0x53, 0x1e, 0x3a,
+	0x28, 0x84, 0xce, 0x94, 0x4f, 0xaf, 0xcf, 0x19, 0xfd, 0x29, 0x95, 0xd9, 0x6f, 0xa1, 0x8b, 0x7a,
+	0x00, 0x53, 0xfe, 0x33, 0x3d, 0x21, 0xcb, 0x94, 0x2e, 0x42, 0x6f, 0xfc, 0x2d, 0x3c, 0xce, 0xd8,
+	0xf2, 0xdd, 0x12, 0x2e, 0x9c, 0x5f, 0x5a, 0x66, 0xf5, 0xa7, 0xfb, 0xe8, 0xd5, 0x08, 0xa7, 0xab,
+	0x64, 0xa2, 0x10, 0xc7, 0x9c, 0xeb, 0xf3, 0x11, 0x31, 0x6f, 0xe9, 0x1b, 0xf1, 0xec, 0x9f, 0x01,
+	0x00, 0x47, 0xff, 0x24, 0xdf, 0x2c, 0x08, 0x00, 0x00,
 }
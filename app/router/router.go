@@ -59,6 +59,7 @@ func (r *Router) Init(config *Config, d dns.Client, ohm outbound.Manager) error
 		rr := &Rule{
 			Condition: cond,
 			Tag:       rule.GetTag(),
+			RuleTag:   rule.RuleTag,
 		}
 		btag := rule.GetBalancingTag()
 		if len(btag) > 0 {
@@ -79,6 +80,9 @@ func (r *Router) PickRoute(ctx context.Context) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if outbound := session.OutboundFromContext(ctx); outbound != nil {
+		outbound.RuleTag = rule.RuleTag
+	}
 	return rule.GetTag()
 }
 
@@ -51,6 +51,38 @@ func TestSimpleRouter(t *testing.T) {
 	}
 }
 
+func TestRuleTag(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "test",
+				},
+				Networks: []net.Network{net.Network_TCP},
+				RuleTag:  "tcp",
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDns := mocks.NewDNSClient(mockCtl)
+
+	r := new(Router)
+	common.Must(r.Init(config, mockDns, nil))
+
+	outbound := &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)}
+	tag, err := r.PickRoute(session.ContextWithOutbound(context.Background(), outbound))
+	common.Must(err)
+	if tag != "test" {
+		t.Error("expect tag 'test', bug actually ", tag)
+	}
+	if outbound.RuleTag != "tcp" {
+		t.Error("expect rule tag 'tcp', but actually ", outbound.RuleTag)
+	}
+}
+
 func TestSimpleBalancer(t *testing.T) {
 	config := &Config{
 		Rule: []*RoutingRule{
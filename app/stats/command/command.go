@@ -104,6 +104,62 @@ func (s *statsServer) GetSysStats(ctx context.Context, request *SysStatsRequest)
 	return response, nil
 }
 
+func (s *statsServer) GetUserQuota(ctx context.Context, request *GetUserQuotaRequest) (*GetUserQuotaResponse, error) {
+	manager, ok := s.stats.(*stats.Manager)
+	if !ok {
+		return nil, newError("GetUserQuota only works its own stats.Manager.")
+	}
+
+	quota, found := manager.GetUserQuota(request.Email)
+	if !found && s.stats.GetCounter(feature_stats.UserUplinkCounterName(request.Email)) == nil &&
+		s.stats.GetCounter(feature_stats.UserDownlinkCounterName(request.Email)) == nil {
+		return nil, newError("user ", request.Email, " not found.")
+	}
+
+	usage := manager.UserUsage(request.Email, request.Reset_)
+	return &GetUserQuotaResponse{
+		Quota: &UserQuota{
+			Email:    request.Email,
+			Quota:    quota.Quota,
+			Expiry:   quota.Expiry,
+			Usage:    usage,
+			Exceeded: quota.Quota > 0 && usage >= quota.Quota,
+			Expired:  quota.Expiry > 0 && time.Now().Unix() >= quota.Expiry,
+		},
+	}, nil
+}
+
+func (s *statsServer) QueryUserIPs(ctx context.Context, request *QueryUserIPsRequest) (*QueryUserIPsResponse, error) {
+	matcher, err := strmatcher.Substr.New(request.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, ok := s.stats.(*stats.Manager)
+	if !ok {
+		return nil, newError("QueryUserIPs only works its own stats.Manager.")
+	}
+
+	response := &QueryUserIPsResponse{}
+	manager.VisitUserIPs(func(email string, ips []stats.UserIP) bool {
+		if matcher.Match(email) {
+			user := &UserIPs{
+				Email: email,
+			}
+			for _, ip := range ips {
+				user.Ip = append(user.Ip, &UserIP{
+					Ip:       ip.IP,
+					LastSeen: ip.LastSeen.Unix(),
+				})
+			}
+			response.User = append(response.User, user)
+		}
+		return true
+	})
+
+	return response, nil
+}
+
 type service struct {
 	statsManager feature_stats.Manager
 }
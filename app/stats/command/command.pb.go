@@ -1,6 +1,3 @@
-// Code generated by protoc-gen-go. DO NOT EDIT.
-// source: v2ray.com/core/app/stats/command/command.proto
-
 package command
 
 import (
@@ -387,6 +384,350 @@ func (m *SysStatsResponse) GetUptime() uint32 {
 	return 0
 }
 
+type GetUserQuotaRequest struct {
+	// Email of the user.
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// Whether or not to reset traffic counters of the user after fetching its usage.
+	Reset_               bool     `protobuf:"varint,2,opt,name=reset,proto3" json:"reset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetUserQuotaRequest) Reset()         { *m = GetUserQuotaRequest{} }
+func (m *GetUserQuotaRequest) String() string { return proto.CompactTextString(m) }
+func (*GetUserQuotaRequest) ProtoMessage()    {}
+func (*GetUserQuotaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{7}
+}
+
+func (m *GetUserQuotaRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUserQuotaRequest.Unmarshal(m, b)
+}
+func (m *GetUserQuotaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUserQuotaRequest.Marshal(b, m, deterministic)
+}
+func (m *GetUserQuotaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUserQuotaRequest.Merge(m, src)
+}
+func (m *GetUserQuotaRequest) XXX_Size() int {
+	return xxx_messageInfo_GetUserQuotaRequest.Size(m)
+}
+func (m *GetUserQuotaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUserQuotaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUserQuotaRequest proto.InternalMessageInfo
+
+func (m *GetUserQuotaRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *GetUserQuotaRequest) GetReset_() bool {
+	if m != nil {
+		return m.Reset_
+	}
+	return false
+}
+
+type UserQuota struct {
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// Traffic quota in bytes. 0 for unlimited.
+	Quota int64 `protobuf:"varint,2,opt,name=quota,proto3" json:"quota,omitempty"`
+	// Expiry time in Unix seconds. 0 for never.
+	Expiry int64 `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	// Total traffic of the user on uplink and downlink, in bytes.
+	Usage                int64    `protobuf:"varint,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	Exceeded             bool     `protobuf:"varint,5,opt,name=exceeded,proto3" json:"exceeded,omitempty"`
+	Expired              bool     `protobuf:"varint,6,opt,name=expired,proto3" json:"expired,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserQuota) Reset()         { *m = UserQuota{} }
+func (m *UserQuota) String() string { return proto.CompactTextString(m) }
+func (*UserQuota) ProtoMessage()    {}
+func (*UserQuota) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{8}
+}
+
+func (m *UserQuota) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserQuota.Unmarshal(m, b)
+}
+func (m *UserQuota) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserQuota.Marshal(b, m, deterministic)
+}
+func (m *UserQuota) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserQuota.Merge(m, src)
+}
+func (m *UserQuota) XXX_Size() int {
+	return xxx_messageInfo_UserQuota.Size(m)
+}
+func (m *UserQuota) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserQuota.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserQuota proto.InternalMessageInfo
+
+func (m *UserQuota) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *UserQuota) GetQuota() int64 {
+	if m != nil {
+		return m.Quota
+	}
+	return 0
+}
+
+func (m *UserQuota) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *UserQuota) GetUsage() int64 {
+	if m != nil {
+		return m.Usage
+	}
+	return 0
+}
+
+func (m *UserQuota) GetExceeded() bool {
+	if m != nil {
+		return m.Exceeded
+	}
+	return false
+}
+
+func (m *UserQuota) GetExpired() bool {
+	if m != nil {
+		return m.Expired
+	}
+	return false
+}
+
+type GetUserQuotaResponse struct {
+	Quota                *UserQuota `protobuf:"bytes,1,opt,name=quota,proto3" json:"quota,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetUserQuotaResponse) Reset()         { *m = GetUserQuotaResponse{} }
+func (m *GetUserQuotaResponse) String() string { return proto.CompactTextString(m) }
+func (*GetUserQuotaResponse) ProtoMessage()    {}
+func (*GetUserQuotaResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{9}
+}
+
+func (m *GetUserQuotaResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUserQuotaResponse.Unmarshal(m, b)
+}
+func (m *GetUserQuotaResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUserQuotaResponse.Marshal(b, m, deterministic)
+}
+func (m *GetUserQuotaResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUserQuotaResponse.Merge(m, src)
+}
+func (m *GetUserQuotaResponse) XXX_Size() int {
+	return xxx_messageInfo_GetUserQuotaResponse.Size(m)
+}
+func (m *GetUserQuotaResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUserQuotaResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUserQuotaResponse proto.InternalMessageInfo
+
+func (m *GetUserQuotaResponse) GetQuota() *UserQuota {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+type QueryUserIPsRequest struct {
+	// Substring of user emails to match. Empty for all users.
+	Pattern              string   `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryUserIPsRequest) Reset()         { *m = QueryUserIPsRequest{} }
+func (m *QueryUserIPsRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryUserIPsRequest) ProtoMessage()    {}
+func (*QueryUserIPsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{10}
+}
+
+func (m *QueryUserIPsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryUserIPsRequest.Unmarshal(m, b)
+}
+func (m *QueryUserIPsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryUserIPsRequest.Marshal(b, m, deterministic)
+}
+func (m *QueryUserIPsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryUserIPsRequest.Merge(m, src)
+}
+func (m *QueryUserIPsRequest) XXX_Size() int {
+	return xxx_messageInfo_QueryUserIPsRequest.Size(m)
+}
+func (m *QueryUserIPsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryUserIPsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryUserIPsRequest proto.InternalMessageInfo
+
+func (m *QueryUserIPsRequest) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+type UserIP struct {
+	Ip string `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Last time the IP was seen, in Unix seconds.
+	LastSeen             int64    `protobuf:"varint,2,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserIP) Reset()         { *m = UserIP{} }
+func (m *UserIP) String() string { return proto.CompactTextString(m) }
+func (*UserIP) ProtoMessage()    {}
+func (*UserIP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{11}
+}
+
+func (m *UserIP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserIP.Unmarshal(m, b)
+}
+func (m *UserIP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserIP.Marshal(b, m, deterministic)
+}
+func (m *UserIP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserIP.Merge(m, src)
+}
+func (m *UserIP) XXX_Size() int {
+	return xxx_messageInfo_UserIP.Size(m)
+}
+func (m *UserIP) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserIP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserIP proto.InternalMessageInfo
+
+func (m *UserIP) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *UserIP) GetLastSeen() int64 {
+	if m != nil {
+		return m.LastSeen
+	}
+	return 0
+}
+
+type UserIPs struct {
+	Email                string    `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Ip                   []*UserIP `protobuf:"bytes,2,rep,name=ip,proto3" json:"ip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *UserIPs) Reset()         { *m = UserIPs{} }
+func (m *UserIPs) String() string { return proto.CompactTextString(m) }
+func (*UserIPs) ProtoMessage()    {}
+func (*UserIPs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{12}
+}
+
+func (m *UserIPs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserIPs.Unmarshal(m, b)
+}
+func (m *UserIPs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserIPs.Marshal(b, m, deterministic)
+}
+func (m *UserIPs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserIPs.Merge(m, src)
+}
+func (m *UserIPs) XXX_Size() int {
+	return xxx_messageInfo_UserIPs.Size(m)
+}
+func (m *UserIPs) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserIPs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserIPs proto.InternalMessageInfo
+
+func (m *UserIPs) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *UserIPs) GetIp() []*UserIP {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+type QueryUserIPsResponse struct {
+	User                 []*UserIPs `protobuf:"bytes,1,rep,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *QueryUserIPsResponse) Reset()         { *m = QueryUserIPsResponse{} }
+func (m *QueryUserIPsResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryUserIPsResponse) ProtoMessage()    {}
+func (*QueryUserIPsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{13}
+}
+
+func (m *QueryUserIPsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryUserIPsResponse.Unmarshal(m, b)
+}
+func (m *QueryUserIPsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryUserIPsResponse.Marshal(b, m, deterministic)
+}
+func (m *QueryUserIPsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryUserIPsResponse.Merge(m, src)
+}
+func (m *QueryUserIPsResponse) XXX_Size() int {
+	return xxx_messageInfo_QueryUserIPsResponse.Size(m)
+}
+func (m *QueryUserIPsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryUserIPsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryUserIPsResponse proto.InternalMessageInfo
+
+func (m *QueryUserIPsResponse) GetUser() []*UserIPs {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
 type Config struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -397,7 +738,7 @@ func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c902411c4948f26b, []int{7}
+	return fileDescriptor_c902411c4948f26b, []int{14}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -426,6 +767,13 @@ func init() {
 	proto.RegisterType((*QueryStatsResponse)(nil), "v2ray.core.app.stats.command.QueryStatsResponse")
 	proto.RegisterType((*SysStatsRequest)(nil), "v2ray.core.app.stats.command.SysStatsRequest")
 	proto.RegisterType((*SysStatsResponse)(nil), "v2ray.core.app.stats.command.SysStatsResponse")
+	proto.RegisterType((*GetUserQuotaRequest)(nil), "v2ray.core.app.stats.command.GetUserQuotaRequest")
+	proto.RegisterType((*UserQuota)(nil), "v2ray.core.app.stats.command.UserQuota")
+	proto.RegisterType((*GetUserQuotaResponse)(nil), "v2ray.core.app.stats.command.GetUserQuotaResponse")
+	proto.RegisterType((*QueryUserIPsRequest)(nil), "v2ray.core.app.stats.command.QueryUserIPsRequest")
+	proto.RegisterType((*UserIP)(nil), "v2ray.core.app.stats.command.UserIP")
+	proto.RegisterType((*UserIPs)(nil), "v2ray.core.app.stats.command.UserIPs")
+	proto.RegisterType((*QueryUserIPsResponse)(nil), "v2ray.core.app.stats.command.QueryUserIPsResponse")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.stats.command.Config")
 }
 
@@ -434,38 +782,52 @@ func init() {
 }
 
 var fileDescriptor_c902411c4948f26b = []byte{
-	// 488 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x5d, 0x6f, 0xd3, 0x30,
-	0x14, 0x5d, 0xdb, 0xac, 0xed, 0x6e, 0x37, 0xad, 0xb3, 0x10, 0xb2, 0xa6, 0x09, 0x45, 0x7e, 0xea,
-	0x0b, 0xce, 0x54, 0x24, 0x5e, 0x78, 0x1a, 0x91, 0x98, 0x84, 0xca, 0x18, 0x29, 0xf0, 0xc0, 0x9b,
-	0x17, 0x2e, 0x28, 0xd0, 0xc4, 0x9e, 0xed, 0x54, 0xca, 0xdf, 0xe1, 0x91, 0x1f, 0xc5, 0x6f, 0x41,
-	0x76, 0x12, 0xfa, 0x31, 0xad, 0x1b, 0x4f, 0xf5, 0x39, 0xf7, 0x9e, 0xdc, 0x73, 0xed, 0xa3, 0x02,
-	0x5f, 0x4e, 0xb5, 0xa8, 0x78, 0x2a, 0xf3, 0x28, 0x95, 0x1a, 0x23, 0xa1, 0x54, 0x64, 0xac, 0xb0,
-	0x26, 0x4a, 0x65, 0x9e, 0x8b, 0xe2, 0x6b, 0xfb, 0xcb, 0x95, 0x96, 0x56, 0x92, 0xb3, 0xb6, 0x5f,
-	0x23, 0x17, 0x4a, 0x71, 0xdf, 0xcb, 0x9b, 0x1e, 0xf6, 0x0a, 0x8e, 0x2f, 0xd1, 0xce, 0x1d, 0x97,
-	0xe0, 0x6d, 0x89, 0xc6, 0x12, 0x02, 0x41, 0x21, 0x72, 0xa4, 0x9d, 0xb0, 0x33, 0x39, 0x48, 0xfc,
-	0x99, 0x3c, 0x81, 0x7d, 0x8d, 0x06, 0x2d, 0xed, 0x86, 0x9d, 0xc9, 0x30, 0xa9, 0x01, 0x3b, 0x87,
-	0xc0, 0x29, 0xef, 0x53, 0x2c, 0xc5, 0xa2, 0x44, 0xaf, 0xe8, 0x25, 0x35, 0x60, 0x6f, 0x61, 0xbc,
-	0x1a, 0x67, 0x94, 0x2c, 0x0c, 0x92, 0x97, 0x10, 0x38, 0x4f, 0x5e, 0x3d, 0x9a, 0x32, 0xbe, 0xcb,
-	0x2f, 0x77, 0xd2, 0xc4, 0xf7, 0xb3, 0x18, 0x4e, 0x3e, 0x94, 0xa8, 0xab, 0x0d, 0xf3, 0x14, 0x06,
-	0x4a, 0x58, 0x8b, 0xba, 0x68, 0xdc, 0xb4, 0xf0, 0x9e, 0x15, 0x66, 0x40, 0xd6, 0x3f, 0x72, 0xc7,
-	0x52, 0xef, 0xbf, 0x2c, 0x9d, 0xc0, 0xf1, 0xbc, 0x32, 0xeb, 0x86, 0xd8, 0xaf, 0x2e, 0x8c, 0x57,
-	0x5c, 0xf3, 0x7d, 0x06, 0x87, 0x57, 0x65, 0x7e, 0x29, 0xb5, 0x2c, 0x6d, 0x56, 0xd4, 0x17, 0x77,
-	0x94, 0x6c, 0x70, 0xce, 0xaf, 0xc3, 0xb1, 0xf7, 0x7b, 0x94, 0xd4, 0xc0, 0xb1, 0x17, 0x8b, 0x85,
-	0x4c, 0x69, 0x2f, 0xec, 0x4c, 0x82, 0xa4, 0x06, 0xe4, 0x19, 0xc0, 0x47, 0x69, 0xc5, 0xa2, 0x2e,
-	0x05, 0xbe, 0xb4, 0xc6, 0x90, 0x31, 0xf4, 0xe6, 0x95, 0xa1, 0xfb, 0xbe, 0xe0, 0x8e, 0xee, 0x9e,
-	0xde, 0x09, 0x57, 0x33, 0xb4, 0xef, 0xd9, 0x16, 0xba, 0x09, 0x6f, 0x34, 0xa2, 0xa1, 0x83, 0x7a,
-	0x82, 0x07, 0x24, 0x84, 0xd1, 0x2c, 0x5b, 0xe2, 0xfb, 0x9b, 0x1f, 0x98, 0x5a, 0x43, 0x87, 0xbe,
-	0xb6, 0x4e, 0xb9, 0x9d, 0xae, 0x45, 0x69, 0xd0, 0x8f, 0xbd, 0x32, 0xf4, 0xc0, 0xb7, 0x6c, 0x70,
-	0xe4, 0x29, 0xf4, 0x3f, 0x29, 0x9b, 0xe5, 0x48, 0xc1, 0x2f, 0xd5, 0x20, 0x36, 0x84, 0x7e, 0x2c,
-	0x8b, 0x6f, 0xd9, 0xf7, 0xe9, 0x9f, 0x2e, 0x1c, 0xfa, 0xbb, 0x9a, 0xa3, 0x5e, 0x66, 0x29, 0x92,
-	0x9f, 0x30, 0x6c, 0x13, 0x43, 0x9e, 0xef, 0x7e, 0x88, 0xad, 0x20, 0x9f, 0xf2, 0xc7, 0xb6, 0xd7,
-	0xaf, 0xc2, 0xf6, 0xc8, 0x2d, 0xc0, 0x2a, 0x0d, 0x24, 0xda, 0xad, 0xbf, 0x13, 0xbe, 0xd3, 0xf3,
-	0xc7, 0x0b, 0xfe, 0x8d, 0x2c, 0x60, 0xe4, 0x8c, 0x34, 0x09, 0x79, 0x68, 0xc5, 0xad, 0x74, 0x3d,
-	0xb4, 0xe2, 0x76, 0xf0, 0xd8, 0xde, 0xeb, 0x19, 0x84, 0xa9, 0xcc, 0x77, 0xca, 0xae, 0x3b, 0x5f,
-	0x06, 0xcd, 0xf1, 0x77, 0xf7, 0xec, 0xf3, 0x34, 0x11, 0x15, 0x8f, 0x5d, 0xe7, 0x85, 0x52, 0x3e,
-	0xed, 0x86, 0xc7, 0x75, 0xf9, 0xa6, 0xef, 0xff, 0x63, 0x5e, 0xfc, 0x0d, 0x00, 0x00, 0xff, 0xff,
-	0x25, 0xa8, 0x5c, 0x1b, 0x95, 0x04, 0x00, 0x00,
+	// 712 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x4b, 0x6f, 0xd3, 0x40,
+	0x10, 0x26, 0x8f, 0x26, 0xce, 0xb4, 0xa5, 0xed, 0x36, 0x42, 0x56, 0xa8, 0x50, 0xb4, 0x02, 0xd1,
+	0x0b, 0x4e, 0x09, 0x0f, 0x09, 0x21, 0x0e, 0x25, 0x12, 0x55, 0x51, 0x29, 0xe9, 0x86, 0x70, 0xe0,
+	0x82, 0xb6, 0xce, 0x50, 0x19, 0xe2, 0x47, 0xbd, 0xeb, 0xd0, 0xfc, 0x16, 0x6e, 0x1c, 0xf9, 0x35,
+	0xfc, 0x24, 0xb4, 0xbb, 0x76, 0xe2, 0xb4, 0xe4, 0xd1, 0x53, 0xfc, 0xcd, 0xcc, 0xb7, 0xf3, 0xcd,
+	0xec, 0xcc, 0x06, 0x9c, 0x51, 0x3b, 0xe6, 0x63, 0xc7, 0x0d, 0xfd, 0x96, 0x1b, 0xc6, 0xd8, 0xe2,
+	0x51, 0xd4, 0x12, 0x92, 0x4b, 0xd1, 0x72, 0x43, 0xdf, 0xe7, 0xc1, 0x20, 0xfb, 0x75, 0xa2, 0x38,
+	0x94, 0x21, 0xd9, 0xcb, 0xe2, 0x63, 0x74, 0x78, 0x14, 0x39, 0x3a, 0xd6, 0x49, 0x63, 0xe8, 0x6b,
+	0xd8, 0x3a, 0x42, 0xd9, 0x53, 0x36, 0x86, 0x97, 0x09, 0x0a, 0x49, 0x08, 0x94, 0x03, 0xee, 0xa3,
+	0x5d, 0x68, 0x16, 0xf6, 0x6b, 0x4c, 0x7f, 0x93, 0x3a, 0xac, 0xc5, 0x28, 0x50, 0xda, 0xc5, 0x66,
+	0x61, 0xdf, 0x62, 0x06, 0xd0, 0x03, 0x28, 0x2b, 0xe6, 0x3c, 0xc6, 0x88, 0x0f, 0x13, 0xd4, 0x8c,
+	0x12, 0x33, 0x80, 0xbe, 0x87, 0xed, 0x69, 0x3a, 0x11, 0x85, 0x81, 0x40, 0xf2, 0x12, 0xca, 0x4a,
+	0x93, 0x66, 0xaf, 0xb7, 0xa9, 0xb3, 0x48, 0xaf, 0xa3, 0xa8, 0x4c, 0xc7, 0xd3, 0x0e, 0xec, 0x9c,
+	0x25, 0x18, 0x8f, 0x67, 0xc4, 0xdb, 0x50, 0x8d, 0xb8, 0x94, 0x18, 0x07, 0xa9, 0x9a, 0x0c, 0xce,
+	0x29, 0xe1, 0x04, 0x48, 0xfe, 0x90, 0x1b, 0x92, 0x4a, 0xb7, 0x92, 0xb4, 0x03, 0x5b, 0xbd, 0xb1,
+	0xc8, 0x0b, 0xa2, 0xbf, 0x8b, 0xb0, 0x3d, 0xb5, 0xa5, 0xe7, 0x53, 0xd8, 0x38, 0x4d, 0xfc, 0xa3,
+	0x30, 0x0e, 0x13, 0xe9, 0x05, 0xa6, 0x71, 0x9b, 0x6c, 0xc6, 0xa6, 0xf4, 0x2a, 0xdc, 0xd1, 0x7a,
+	0x37, 0x99, 0x01, 0xca, 0x7a, 0x38, 0x1c, 0x86, 0xae, 0x5d, 0x6a, 0x16, 0xf6, 0xcb, 0xcc, 0x00,
+	0xf2, 0x00, 0xe0, 0x53, 0x28, 0xf9, 0xd0, 0xb8, 0xca, 0xda, 0x95, 0xb3, 0x90, 0x6d, 0x28, 0xf5,
+	0xc6, 0xc2, 0x5e, 0xd3, 0x0e, 0xf5, 0xa9, 0xfa, 0xf4, 0x81, 0x2b, 0x9f, 0xb0, 0x2b, 0xda, 0x9a,
+	0x41, 0x95, 0xe1, 0x5d, 0x8c, 0x28, 0xec, 0xaa, 0xc9, 0xa0, 0x01, 0x69, 0xc2, 0xfa, 0x89, 0x37,
+	0xc2, 0x8f, 0xe7, 0xdf, 0xd1, 0x95, 0xc2, 0xb6, 0xb4, 0x2f, 0x6f, 0x52, 0x35, 0x75, 0x79, 0x22,
+	0x50, 0xa7, 0x3d, 0x15, 0x76, 0x4d, 0x87, 0xcc, 0xd8, 0xc8, 0x3d, 0xa8, 0xf4, 0x23, 0xe9, 0xf9,
+	0x68, 0x83, 0x2e, 0x2a, 0x45, 0xf4, 0x10, 0x76, 0x8f, 0x50, 0xf6, 0x05, 0xc6, 0x67, 0x49, 0x28,
+	0x79, 0x76, 0x99, 0x75, 0x58, 0x43, 0x9f, 0x7b, 0xc3, 0xf4, 0x2a, 0x0d, 0x98, 0x73, 0x91, 0xbf,
+	0x0a, 0x50, 0x9b, 0x1c, 0x30, 0x9f, 0x79, 0xa9, 0xdc, 0xd9, 0x4c, 0x6a, 0xa0, 0x44, 0xe1, 0x55,
+	0xe4, 0xc5, 0x63, 0xdd, 0xd3, 0x12, 0x4b, 0x91, 0x8a, 0x4e, 0x04, 0xbf, 0x40, 0xdd, 0xcf, 0x12,
+	0x33, 0x80, 0x34, 0xc0, 0xc2, 0x2b, 0x17, 0x71, 0x80, 0x03, 0xdd, 0x4f, 0x8b, 0x4d, 0xb0, 0x6a,
+	0xaa, 0xe6, 0xe2, 0x40, 0x37, 0xd5, 0x62, 0x19, 0xa4, 0x7d, 0xa8, 0xcf, 0x16, 0x98, 0x0e, 0xc2,
+	0x9b, 0x4c, 0x91, 0x19, 0xfe, 0xc7, 0x8b, 0x27, 0x6d, 0xca, 0x37, 0x2c, 0xda, 0x82, 0x5d, 0x3d,
+	0xbd, 0xca, 0x71, 0xdc, 0x5d, 0xbe, 0x04, 0xf4, 0x05, 0x54, 0x4c, 0x2c, 0xb9, 0x0b, 0x45, 0x2f,
+	0x4a, 0xdd, 0x45, 0x2f, 0x22, 0xf7, 0xa1, 0x36, 0xe4, 0x42, 0x7e, 0x15, 0x88, 0x41, 0xda, 0x1f,
+	0x4b, 0x19, 0x7a, 0x88, 0x01, 0xed, 0x43, 0x35, 0x4d, 0x31, 0xa7, 0xb3, 0xcf, 0xf5, 0x69, 0x45,
+	0xbd, 0x2e, 0x0f, 0x97, 0x17, 0x71, 0xdc, 0x55, 0x39, 0xe9, 0x19, 0xd4, 0x67, 0xe5, 0xa7, 0x5d,
+	0x79, 0x05, 0xe5, 0x44, 0x60, 0x9c, 0xae, 0xdf, 0xa3, 0x55, 0xce, 0x13, 0x4c, 0x53, 0xa8, 0x05,
+	0x95, 0x4e, 0x18, 0x7c, 0xf3, 0x2e, 0xda, 0x7f, 0xcb, 0xb0, 0xa1, 0xb7, 0xae, 0x87, 0xf1, 0xc8,
+	0x73, 0x91, 0xfc, 0x00, 0x2b, 0x7b, 0x7b, 0xc8, 0x93, 0xc5, 0x67, 0x5e, 0x7b, 0x12, 0x1b, 0xce,
+	0xaa, 0xe1, 0xa6, 0x00, 0x7a, 0x87, 0x5c, 0x02, 0x4c, 0xdf, 0x15, 0xd2, 0x5a, 0xcc, 0xbf, 0xf1,
+	0x8c, 0x35, 0x0e, 0x56, 0x27, 0x4c, 0x52, 0x06, 0xb0, 0xae, 0x84, 0x8c, 0xc5, 0x4a, 0x25, 0x5e,
+	0x7b, 0xa7, 0x1a, 0xce, 0xaa, 0xe1, 0x93, 0x7c, 0x3f, 0x61, 0x23, 0x3f, 0xd3, 0xe4, 0xe9, 0xd2,
+	0x26, 0x5d, 0x5f, 0xf0, 0x46, 0xfb, 0x36, 0x94, 0x7c, 0xe2, 0xfc, 0xd8, 0x2c, 0x4b, 0xfc, 0x9f,
+	0x0d, 0x69, 0xb4, 0x6f, 0x43, 0xc9, 0x12, 0xbf, 0x3d, 0x81, 0xa6, 0x1b, 0xfa, 0x0b, 0xa9, 0xdd,
+	0xc2, 0x97, 0x6a, 0xfa, 0xf9, 0xa7, 0xb8, 0xf7, 0xb9, 0xcd, 0xf8, 0xd8, 0xe9, 0xa8, 0xc8, 0xc3,
+	0x28, 0xd2, 0xff, 0x14, 0xc2, 0xe9, 0x18, 0xf7, 0x79, 0x45, 0xff, 0x3f, 0x3f, 0xfb, 0x37, 0x00,
+	0xbe, 0x2e, 0x8c, 0xa5, 0xd1, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -483,6 +845,8 @@ type StatsServiceClient interface {
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
 	QueryStats(ctx context.Context, in *QueryStatsRequest, opts ...grpc.CallOption) (*QueryStatsResponse, error)
 	GetSysStats(ctx context.Context, in *SysStatsRequest, opts ...grpc.CallOption) (*SysStatsResponse, error)
+	GetUserQuota(ctx context.Context, in *GetUserQuotaRequest, opts ...grpc.CallOption) (*GetUserQuotaResponse, error)
+	QueryUserIPs(ctx context.Context, in *QueryUserIPsRequest, opts ...grpc.CallOption) (*QueryUserIPsResponse, error)
 }
 
 type statsServiceClient struct {
@@ -520,11 +884,31 @@ func (c *statsServiceClient) GetSysStats(ctx context.Context, in *SysStatsReques
 	return out, nil
 }
 
+func (c *statsServiceClient) GetUserQuota(ctx context.Context, in *GetUserQuotaRequest, opts ...grpc.CallOption) (*GetUserQuotaResponse, error) {
+	out := new(GetUserQuotaResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.stats.command.StatsService/GetUserQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *statsServiceClient) QueryUserIPs(ctx context.Context, in *QueryUserIPsRequest, opts ...grpc.CallOption) (*QueryUserIPsResponse, error) {
+	out := new(QueryUserIPsResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.stats.command.StatsService/QueryUserIPs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StatsServiceServer is the server API for StatsService service.
 type StatsServiceServer interface {
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
 	QueryStats(context.Context, *QueryStatsRequest) (*QueryStatsResponse, error)
 	GetSysStats(context.Context, *SysStatsRequest) (*SysStatsResponse, error)
+	GetUserQuota(context.Context, *GetUserQuotaRequest) (*GetUserQuotaResponse, error)
+	QueryUserIPs(context.Context, *QueryUserIPsRequest) (*QueryUserIPsResponse, error)
 }
 
 // UnimplementedStatsServiceServer can be embedded to have forward compatible implementations.
@@ -540,6 +924,12 @@ func (*UnimplementedStatsServiceServer) QueryStats(ctx context.Context, req *Que
 func (*UnimplementedStatsServiceServer) GetSysStats(ctx context.Context, req *SysStatsRequest) (*SysStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSysStats not implemented")
 }
+func (*UnimplementedStatsServiceServer) GetUserQuota(ctx context.Context, req *GetUserQuotaRequest) (*GetUserQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserQuota not implemented")
+}
+func (*UnimplementedStatsServiceServer) QueryUserIPs(ctx context.Context, req *QueryUserIPsRequest) (*QueryUserIPsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryUserIPs not implemented")
+}
 
 func RegisterStatsServiceServer(s *grpc.Server, srv StatsServiceServer) {
 	s.RegisterService(&_StatsService_serviceDesc, srv)
@@ -599,6 +989,42 @@ func _StatsService_GetSysStats_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StatsService_GetUserQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatsServiceServer).GetUserQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.stats.command.StatsService/GetUserQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatsServiceServer).GetUserQuota(ctx, req.(*GetUserQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StatsService_QueryUserIPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryUserIPsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatsServiceServer).QueryUserIPs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.stats.command.StatsService/QueryUserIPs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatsServiceServer).QueryUserIPs(ctx, req.(*QueryUserIPsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _StatsService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v2ray.core.app.stats.command.StatsService",
 	HandlerType: (*StatsServiceServer)(nil),
@@ -615,6 +1041,14 @@ var _StatsService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetSysStats",
 			Handler:    _StatsService_GetSysStats_Handler,
 		},
+		{
+			MethodName: "GetUserQuota",
+			Handler:    _StatsService_GetUserQuota_Handler,
+		},
+		{
+			MethodName: "QueryUserIPs",
+			Handler:    _StatsService_QueryUserIPs_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v2ray.com/core/app/stats/command/command.proto",
@@ -3,6 +3,7 @@ package command_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -10,6 +11,7 @@ import (
 	"v2ray.com/core/app/stats"
 	. "v2ray.com/core/app/stats/command"
 	"v2ray.com/core/common"
+	feature_stats "v2ray.com/core/features/stats"
 )
 
 func TestGetStats(t *testing.T) {
@@ -89,3 +91,53 @@ func TestQueryStats(t *testing.T) {
 		t.Error(r)
 	}
 }
+
+func TestGetUserQuota(t *testing.T) {
+	m, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	uplink, err := m.RegisterCounter(feature_stats.UserUplinkCounterName("test@v2ray.com"))
+	common.Must(err)
+	uplink.Set(60)
+	downlink, err := m.RegisterCounter(feature_stats.UserDownlinkCounterName("test@v2ray.com"))
+	common.Must(err)
+	downlink.Set(40)
+	m.SetUserQuota("test@v2ray.com", feature_stats.UserQuota{Quota: 100})
+
+	s := NewStatsServer(m)
+
+	if _, err := s.GetUserQuota(context.Background(), &GetUserQuotaRequest{Email: "unknown@v2ray.com"}); err == nil {
+		t.Error("nil error for unknown user")
+	}
+
+	resp, err := s.GetUserQuota(context.Background(), &GetUserQuotaRequest{
+		Email:  "test@v2ray.com",
+		Reset_: true,
+	})
+	common.Must(err)
+	if r := cmp.Diff(resp.Quota, &UserQuota{Email: "test@v2ray.com", Quota: 100, Usage: 100, Exceeded: true}); r != "" {
+		t.Error(r)
+	}
+
+	resp, err = s.GetUserQuota(context.Background(), &GetUserQuotaRequest{Email: "test@v2ray.com"})
+	common.Must(err)
+	if r := cmp.Diff(resp.Quota, &UserQuota{Email: "test@v2ray.com", Quota: 100}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestQueryUserIPs(t *testing.T) {
+	m, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	m.AddUserIP("test@v2ray.com", "1.1.1.1", 1, time.Minute)
+	m.AddUserIP("other@v2ray.com", "2.2.2.2", 1, time.Minute)
+
+	s := NewStatsServer(m)
+	resp, err := s.QueryUserIPs(context.Background(), &QueryUserIPsRequest{
+		Pattern: "test",
+	})
+	common.Must(err)
+	if len(resp.User) != 1 || resp.User[0].Email != "test@v2ray.com" || len(resp.User[0].Ip) != 1 || resp.User[0].Ip[0].Ip != "1.1.1.1" {
+		t.Error("unexpected response: ", resp)
+	}
+}
@@ -0,0 +1,86 @@
+// +build !confonly
+
+package stats
+
+import (
+	"sort"
+	"time"
+)
+
+// UserIP is a source IP of a user, with the last time it was seen.
+type UserIP struct {
+	IP       string
+	LastSeen time.Time
+}
+
+type userIPs struct {
+	window time.Duration
+	ips    map[string]time.Time
+}
+
+func (u *userIPs) removeExpired(now time.Time) {
+	for ip, lastSeen := range u.ips {
+		if now.Sub(lastSeen) > u.window {
+			delete(u.ips, ip)
+		}
+	}
+}
+
+// AddUserIP implements stats.UserIPManager.
+func (m *Manager) AddUserIP(email string, ip string, maxIPs uint32, window time.Duration) bool {
+	m.ipAccess.Lock()
+	defer m.ipAccess.Unlock()
+
+	now := time.Now()
+	u, found := m.userIPs[email]
+	if !found {
+		u = &userIPs{
+			ips: make(map[string]time.Time),
+		}
+		m.userIPs[email] = u
+	}
+	u.window = window
+	u.removeExpired(now)
+
+	if _, found := u.ips[ip]; !found && maxIPs > 0 && uint32(len(u.ips)) >= maxIPs {
+		return false
+	}
+	u.ips[ip] = now
+	return true
+}
+
+// RefreshUserIP implements stats.UserIPManager.
+func (m *Manager) RefreshUserIP(email string, ip string) {
+	m.ipAccess.Lock()
+	defer m.ipAccess.Unlock()
+
+	if u, found := m.userIPs[email]; found {
+		u.ips[ip] = time.Now()
+	}
+}
+
+// VisitUserIPs calls visitor with active source IPs of each user, sorted by the last time seen.
+func (m *Manager) VisitUserIPs(visitor func(string, []UserIP) bool) {
+	m.ipAccess.Lock()
+	defer m.ipAccess.Unlock()
+
+	now := time.Now()
+	for email, u := range m.userIPs {
+		u.removeExpired(now)
+		if len(u.ips) == 0 {
+			delete(m.userIPs, email)
+			continue
+		}
+
+		ips := make([]UserIP, 0, len(u.ips))
+		for ip, lastSeen := range u.ips {
+			ips = append(ips, UserIP{IP: ip, LastSeen: lastSeen})
+		}
+		sort.Slice(ips, func(i, j int) bool {
+			return ips[i].LastSeen.After(ips[j].LastSeen)
+		})
+		if !visitor(email, ips) {
+			break
+		}
+	}
+}
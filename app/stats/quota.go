@@ -0,0 +1,42 @@
+// +build !confonly
+
+package stats
+
+import (
+	"v2ray.com/core/features/stats"
+)
+
+// SetUserQuota implements stats.QuotaManager.
+func (m *Manager) SetUserQuota(email string, quota stats.UserQuota) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	m.quotas[email] = quota
+}
+
+// GetUserQuota implements stats.QuotaManager.
+func (m *Manager) GetUserQuota(email string) (stats.UserQuota, bool) {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	quota, found := m.quotas[email]
+	return quota, found
+}
+
+// UserUsage returns the total traffic of the given user on uplink and downlink. If reset is true,
+// the traffic counters of the user are reset to 0.
+func (m *Manager) UserUsage(email string, reset bool) int64 {
+	var usage int64
+	for _, name := range []string{stats.UserUplinkCounterName(email), stats.UserDownlinkCounterName(email)} {
+		c := m.GetCounter(name)
+		if c == nil {
+			continue
+		}
+		if reset {
+			usage += c.Set(0)
+		} else {
+			usage += c.Value()
+		}
+	}
+	return usage
+}
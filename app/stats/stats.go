@@ -36,11 +36,17 @@ func (c *Counter) Add(delta int64) int64 {
 type Manager struct {
 	access   sync.RWMutex
 	counters map[string]*Counter
+	quotas   map[string]stats.UserQuota
+
+	ipAccess sync.Mutex
+	userIPs  map[string]*userIPs
 }
 
 func NewManager(ctx context.Context, config *Config) (*Manager, error) {
 	m := &Manager{
 		counters: make(map[string]*Counter),
+		quotas:   make(map[string]stats.UserQuota),
+		userIPs:  make(map[string]*userIPs),
 	}
 
 	return m, nil
@@ -63,6 +69,18 @@ func (m *Manager) RegisterCounter(name string) (stats.Counter, error) {
 	return c, nil
 }
 
+// UnregisterCounter implements stats.CounterUnregisterer.
+func (m *Manager) UnregisterCounter(name string) error {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if _, found := m.counters[name]; found {
+		newError("remove counter ", name).AtDebug().WriteToLog()
+		delete(m.counters, name)
+	}
+	return nil
+}
+
 func (m *Manager) GetCounter(name string) stats.Counter {
 	m.access.RLock()
 	defer m.access.RUnlock()
@@ -3,14 +3,19 @@ package stats_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "v2ray.com/core/app/stats"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/features/stats"
 )
 
 func TestInternface(t *testing.T) {
 	_ = (stats.Manager)(new(Manager))
+	_ = (stats.QuotaManager)(new(Manager))
+	_ = (stats.UserIPManager)(new(Manager))
+	_ = (stats.CounterUnregisterer)(new(Manager))
 }
 
 func TestStatsCounter(t *testing.T) {
@@ -33,3 +38,77 @@ func TestStatsCounter(t *testing.T) {
 		t.Fatal("unexpected Value() return: ", v, ", wanted ", 0)
 	}
 }
+
+func TestUnregisterCounter(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{})
+	common.Must(err)
+
+	common.Must2(m.RegisterCounter("test.counter"))
+	common.Must(m.UnregisterCounter("test.counter"))
+	if c := m.GetCounter("test.counter"); c != nil {
+		t.Error("counter should be unregistered")
+	}
+	common.Must2(m.RegisterCounter("test.counter"))
+}
+
+func TestRegisterUserQuota(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{})
+	common.Must(err)
+
+	stats.RegisterUserQuota(m, &protocol.MemoryUser{Email: "test", Quota: 100, Expiry: 200})
+	stats.RegisterUserQuota(m, &protocol.MemoryUser{Email: "unlimited"})
+
+	if quota, found := m.GetUserQuota("test"); !found || quota != (stats.UserQuota{Quota: 100, Expiry: 200}) {
+		t.Error("unexpected quota: ", quota)
+	}
+	if _, found := m.GetUserQuota("unlimited"); found {
+		t.Error("user without quota should not be recorded")
+	}
+}
+
+func TestUserIPs(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{})
+	common.Must(err)
+
+	for _, ip := range []string{"1.1.1.1", "2.2.2.2", "1.1.1.1"} {
+		if !m.AddUserIP("test", ip, 2, time.Minute) {
+			t.Error("IP ", ip, " should be accepted")
+		}
+	}
+	if m.AddUserIP("test", "3.3.3.3", 2, time.Minute) {
+		t.Error("IP over limit should be rejected")
+	}
+	if !m.AddUserIP("test", "3.3.3.3", 2, 0) {
+		t.Error("IP should be accepted after other IPs expire")
+	}
+
+	m.VisitUserIPs(func(email string, ips []UserIP) bool {
+		if email != "test" || len(ips) != 1 || ips[0].IP != "3.3.3.3" {
+			t.Error("unexpected IPs of ", email, ": ", ips)
+		}
+		return true
+	})
+}
+
+func TestRefreshUserIP(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{})
+	common.Must(err)
+
+	const window = time.Millisecond * 100
+	if !m.AddUserIP("test", "1.1.1.1", 1, window) {
+		t.Fatal("first IP should be accepted")
+	}
+	// The IP of an active connection stays active beyond the window.
+	for i := 0; i < 4; i++ {
+		time.Sleep(window / 2)
+		m.RefreshUserIP("test", "1.1.1.1")
+	}
+	if m.AddUserIP("test", "2.2.2.2", 1, window) {
+		t.Error("IP over limit should be rejected while the other IP is in use")
+	}
+
+	time.Sleep(window * 2)
+	if !m.AddUserIP("test", "2.2.2.2", 1, window) {
+		t.Error("IP should be accepted after the other IP expires")
+	}
+}
@@ -206,6 +206,11 @@ func (m *ClientWorker) Closed() bool {
 	return m.done.Done()
 }
 
+// Close closes the worker, as well as all its sessions and the underlying link.
+func (m *ClientWorker) Close() error {
+	return m.done.Close()
+}
+
 func (m *ClientWorker) monitor() {
 	timer := time.NewTicker(time.Second * 16)
 	defer timer.Stop()
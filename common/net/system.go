@@ -59,3 +59,5 @@ var ResolveUnixAddr = net.ResolveUnixAddr
 var ResolveUDPAddr = net.ResolveUDPAddr
 
 type Resolver = net.Resolver
+
+var DefaultResolver = net.DefaultResolver
@@ -0,0 +1,255 @@
+// Package proxyproto implements version 1 and 2 of the PROXY protocol.
+// See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt
+package proxyproto
+
+//go:generate errorgen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+)
+
+const (
+	// maxV1Length is the max length of a version 1 header, including the trailing CRLF.
+	maxV1Length = 107
+
+	v2CommandLocal = 0x20
+	v2CommandProxy = 0x21
+
+	v2FamilyUnspec = 0x00
+	v2FamilyTCP4   = 0x11
+	v2FamilyUDP4   = 0x12
+	v2FamilyTCP6   = 0x21
+	v2FamilyUDP6   = 0x22
+)
+
+var (
+	v1Signature = []byte("PROXY ")
+	v2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+)
+
+// Header is a PROXY protocol header.
+type Header struct {
+	// Version is either 1 or 2.
+	Version byte
+	// Source is the address of the original client. It is invalid if the header doesn't carry address information.
+	Source net.Destination
+	// Destination is the address that the original client connected to.
+	Destination net.Destination
+}
+
+// ReadHeader reads a PROXY protocol header of either version from the reader.
+// It reads no more bytes than the header, so the reader can be used for the payload afterwards.
+func ReadHeader(reader io.Reader) (*Header, error) {
+	b := make([]byte, len(v1Signature))
+	if _, err := io.ReadFull(reader, b); err != nil {
+		return nil, newError("failed to read PROXY protocol signature").Base(err)
+	}
+	if bytes.Equal(b, v1Signature) {
+		return readV1(reader)
+	}
+
+	b = append(b, make([]byte, len(v2Signature)-len(b))...)
+	if _, err := io.ReadFull(reader, b[len(v1Signature):]); err != nil {
+		return nil, newError("failed to read PROXY protocol signature").Base(err)
+	}
+	if bytes.Equal(b, v2Signature) {
+		return readV2(reader)
+	}
+
+	return nil, newError("not a PROXY protocol header")
+}
+
+func readV1(reader io.Reader) (*Header, error) {
+	line := make([]byte, 0, maxV1Length)
+	b := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(reader, b); err != nil {
+			return nil, newError("failed to read PROXY protocol v1 header").Base(err)
+		}
+		line = append(line, b[0])
+		if b[0] == '\n' {
+			break
+		}
+		if len(line)+len(v1Signature) >= maxV1Length {
+			return nil, newError("PROXY protocol v1 header too long")
+		}
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, newError("PROXY protocol v1 header not terminated by CRLF")
+	}
+
+	header := &Header{Version: 1}
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	switch fields[0] {
+	case "UNKNOWN":
+		return header, nil
+	case "TCP4", "TCP6":
+	default:
+		return nil, newError("unknown protocol in PROXY protocol v1 header: ", fields[0])
+	}
+	if len(fields) != 5 {
+		return nil, newError("invalid PROXY protocol v1 header: ", string(line))
+	}
+
+	srcIP := net.ParseIP(fields[1])
+	dstIP := net.ParseIP(fields[2])
+	if srcIP == nil || dstIP == nil {
+		return nil, newError("invalid address in PROXY protocol v1 header: ", string(line))
+	}
+	srcPort, err := net.PortFromString(fields[3])
+	if err != nil {
+		return nil, newError("invalid source port in PROXY protocol v1 header").Base(err)
+	}
+	dstPort, err := net.PortFromString(fields[4])
+	if err != nil {
+		return nil, newError("invalid destination port in PROXY protocol v1 header").Base(err)
+	}
+
+	header.Source = net.TCPDestination(net.IPAddress(srcIP), srcPort)
+	header.Destination = net.TCPDestination(net.IPAddress(dstIP), dstPort)
+	return header, nil
+}
+
+func readV2(reader io.Reader) (*Header, error) {
+	b := make([]byte, 4)
+	if _, err := io.ReadFull(reader, b); err != nil {
+		return nil, newError("failed to read PROXY protocol v2 header").Base(err)
+	}
+	command := b[0]
+	family := b[1]
+	length := binary.BigEndian.Uint16(b[2:])
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, newError("failed to read PROXY protocol v2 addresses").Base(err)
+	}
+
+	header := &Header{Version: 2}
+	switch command {
+	case v2CommandLocal:
+		return header, nil
+	case v2CommandProxy:
+	default:
+		return nil, newError("unknown command in PROXY protocol v2 header: ", command)
+	}
+
+	var ipLen int
+	var network net.Network
+	switch family {
+	case v2FamilyTCP4:
+		ipLen, network = net.IPv4len, net.Network_TCP
+	case v2FamilyUDP4:
+		ipLen, network = net.IPv4len, net.Network_UDP
+	case v2FamilyTCP6:
+		ipLen, network = net.IPv6len, net.Network_TCP
+	case v2FamilyUDP6:
+		ipLen, network = net.IPv6len, net.Network_UDP
+	default:
+		// Unix sockets and unspecified families carry no usable address.
+		return header, nil
+	}
+	if len(payload) < ipLen*2+4 {
+		return nil, newError("PROXY protocol v2 addresses too short")
+	}
+
+	header.Source = net.Destination{
+		Network: network,
+		Address: net.IPAddress(payload[:ipLen]),
+		Port:    net.PortFromBytes(payload[ipLen*2 : ipLen*2+2]),
+	}
+	header.Destination = net.Destination{
+		Network: network,
+		Address: net.IPAddress(payload[ipLen : ipLen*2]),
+		Port:    net.PortFromBytes(payload[ipLen*2+2 : ipLen*2+4]),
+	}
+	return header, nil
+}
+
+// WriteHeader writes a PROXY protocol header of the given version, carrying the given source and destination.
+// If the addresses are not both IPv4 or IPv6, the header is written without address information.
+func WriteHeader(writer io.Writer, version byte, source net.Destination, destination net.Destination) error {
+	b := buf.New()
+	defer b.Release()
+
+	switch version {
+	case 1:
+		writeV1(b, source, destination)
+	case 2:
+		writeV2(b, source, destination)
+	default:
+		return newError("unknown PROXY protocol version: ", version)
+	}
+
+	return buf.WriteAllBytes(writer, b.Bytes())
+}
+
+func addressFamily(source net.Destination, destination net.Destination) net.AddressFamily {
+	if !source.IsValid() || !destination.IsValid() || source.Address == nil || destination.Address == nil {
+		return net.AddressFamilyDomain
+	}
+	srcFamily := source.Address.Family()
+	dstFamily := destination.Address.Family()
+	if srcFamily.IsIP() && srcFamily == dstFamily {
+		return srcFamily
+	}
+	return net.AddressFamilyDomain
+}
+
+func writeV1(b *buf.Buffer, source net.Destination, destination net.Destination) {
+	common.Must2(b.Write(v1Signature))
+	switch addressFamily(source, destination) {
+	case net.AddressFamilyIPv4:
+		common.Must2(b.WriteString("TCP4 "))
+	case net.AddressFamilyIPv6:
+		common.Must2(b.WriteString("TCP6 "))
+	default:
+		common.Must2(b.WriteString("UNKNOWN\r\n"))
+		return
+	}
+	common.Must2(b.WriteString(source.Address.IP().String()))
+	common.Must2(b.WriteString(" "))
+	common.Must2(b.WriteString(destination.Address.IP().String()))
+	common.Must2(b.WriteString(" "))
+	common.Must2(b.WriteString(source.Port.String()))
+	common.Must2(b.WriteString(" "))
+	common.Must2(b.WriteString(destination.Port.String()))
+	common.Must2(b.WriteString("\r\n"))
+}
+
+func writeV2(b *buf.Buffer, source net.Destination, destination net.Destination) {
+	common.Must2(b.Write(v2Signature))
+
+	var family byte
+	switch addressFamily(source, destination) {
+	case net.AddressFamilyIPv4:
+		family = v2FamilyTCP4
+		if source.Network == net.Network_UDP {
+			family = v2FamilyUDP4
+		}
+	case net.AddressFamilyIPv6:
+		family = v2FamilyTCP6
+		if source.Network == net.Network_UDP {
+			family = v2FamilyUDP6
+		}
+	default:
+		common.Must2(b.Write([]byte{v2CommandLocal, v2FamilyUnspec, 0, 0}))
+		return
+	}
+
+	srcIP := source.Address.IP()
+	dstIP := destination.Address.IP()
+	length := uint16(len(srcIP)*2 + 4)
+	common.Must2(b.Write([]byte{v2CommandProxy, family, byte(length >> 8), byte(length)}))
+	common.Must2(b.Write(srcIP))
+	common.Must2(b.Write(dstIP))
+	common.Must2(serial.WriteUint16(b, source.Port.Value()))
+	common.Must2(serial.WriteUint16(b, destination.Port.Value()))
+}
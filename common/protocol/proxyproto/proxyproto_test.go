@@ -0,0 +1,96 @@
+package proxyproto_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	. "v2ray.com/core/common/protocol/proxyproto"
+)
+
+func TestHeaderRoundTrip(t *testing.T) {
+	testCases := []struct {
+		version     byte
+		source      net.Destination
+		destination net.Destination
+	}{
+		{
+			version:     1,
+			source:      net.TCPDestination(net.ParseAddress("1.2.3.4"), 1234),
+			destination: net.TCPDestination(net.ParseAddress("5.6.7.8"), 443),
+		},
+		{
+			version:     1,
+			source:      net.TCPDestination(net.ParseAddress("2001:db8::1"), 1234),
+			destination: net.TCPDestination(net.ParseAddress("2001:db8::2"), 443),
+		},
+		{
+			version:     2,
+			source:      net.TCPDestination(net.ParseAddress("1.2.3.4"), 1234),
+			destination: net.TCPDestination(net.ParseAddress("5.6.7.8"), 443),
+		},
+		{
+			version:     2,
+			source:      net.TCPDestination(net.ParseAddress("2001:db8::1"), 1234),
+			destination: net.TCPDestination(net.ParseAddress("2001:db8::2"), 443),
+		},
+	}
+
+	for _, tc := range testCases {
+		buffer := bytes.NewBuffer(nil)
+		common.Must(WriteHeader(buffer, tc.version, tc.source, tc.destination))
+		common.Must2(buffer.WriteString("payload"))
+
+		header, err := ReadHeader(buffer)
+		common.Must(err)
+		if header.Version != tc.version {
+			t.Error("expect version ", tc.version, " but got ", header.Version)
+		}
+		if r := cmp.Diff(header.Source, tc.source); r != "" {
+			t.Error(r)
+		}
+		if r := cmp.Diff(header.Destination, tc.destination); r != "" {
+			t.Error(r)
+		}
+
+		payload, err := ioutil.ReadAll(buffer)
+		common.Must(err)
+		if string(payload) != "payload" {
+			t.Error("unexpected payload: ", string(payload))
+		}
+	}
+}
+
+func TestUnknownAddress(t *testing.T) {
+	for _, version := range []byte{1, 2} {
+		buffer := bytes.NewBuffer(nil)
+		common.Must(WriteHeader(buffer, version, net.Destination{}, net.Destination{}))
+
+		header, err := ReadHeader(buffer)
+		common.Must(err)
+		if header.Source.IsValid() {
+			t.Error("expect invalid source, but got ", header.Source)
+		}
+		if buffer.Len() != 0 {
+			t.Error("unread header bytes: ", buffer.Len())
+		}
+	}
+}
+
+func TestInvalidHeader(t *testing.T) {
+	inputs := []string{
+		"GET / HTTP/1.1\r\n\r\n",
+		"PROXY TCP4 1.2.3.4\r\n",
+		"PROXY TCP4 1.2.3.4 5.6.7.8 1234 443\n",
+	}
+
+	for _, input := range inputs {
+		if _, err := ReadHeader(bytes.NewBufferString(input)); err == nil {
+			t.Error("expect error for input: ", input)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package protocol
 
+import "time"
+
 func (u *User) GetTypedAccount() (Account, error) {
 	if u.GetAccount() == nil {
 		return nil, newError("Account missing").AtWarning()
@@ -27,6 +29,8 @@ func (u *User) ToMemoryUser() (*MemoryUser, error) {
 		Account: account,
 		Email:   u.Email,
 		Level:   u.Level,
+		Quota:   u.Quota,
+		Expiry:  u.Expiry,
 	}, nil
 }
 
@@ -36,4 +40,13 @@ type MemoryUser struct {
 	Account Account
 	Email   string
 	Level   uint32
+	// Quota is the traffic quota in bytes. 0 for unlimited.
+	Quota int64
+	// Expiry is the expiry time in Unix seconds. 0 for never.
+	Expiry int64
+}
+
+// IsExpired returns true if the user has an expiry time that has passed at the given time.
+func (u *MemoryUser) IsExpired(now time.Time) bool {
+	return u.Expiry > 0 && now.Unix() >= u.Expiry
 }
@@ -23,10 +23,14 @@ type User struct {
 	Level uint32 `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
 	Email string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
 	// Protocol specific account information. Must be the account proto in one of the proxies.
-	Account              *serial.TypedMessage `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Account *serial.TypedMessage `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	// Traffic quota of the user in bytes, counted on both uplink and downlink. 0 for unlimited.
+	Quota int64 `protobuf:"varint,4,opt,name=quota,proto3" json:"quota,omitempty"`
+	// Expiry time of the user in Unix seconds. 0 for never.
+	Expiry               int64    `protobuf:"varint,5,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *User) Reset()         { *m = User{} }
@@ -75,6 +79,20 @@ func (m *User) GetAccount() *serial.TypedMessage {
 	return nil
 }
 
+func (m *User) GetQuota() int64 {
+	if m != nil {
+		return m.Quota
+	}
+	return 0
+}
+
+func (m *User) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*User)(nil), "v2ray.core.common.protocol.User")
 }
@@ -84,19 +102,21 @@ func init() {
 }
 
 var fileDescriptor_9da52c16030369bd = []byte{
-	// 218 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x2c, 0x33, 0x2a, 0x4a,
-	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x4f, 0xce, 0xcf, 0xcd, 0xcd,
-	0xcf, 0xd3, 0x2f, 0x28, 0xca, 0x2f, 0xc9, 0x4f, 0xce, 0xcf, 0xd1, 0x2f, 0x2d, 0x4e, 0x2d, 0xd2,
-	0x03, 0xf3, 0x84, 0xa4, 0x60, 0x4a, 0x8b, 0x52, 0xf5, 0x20, 0xca, 0xf4, 0x60, 0xca, 0xa4, 0x0c,
-	0xb0, 0x1b, 0x53, 0x9c, 0x5a, 0x94, 0x99, 0x98, 0xa3, 0x5f, 0x52, 0x59, 0x90, 0x9a, 0x12, 0x9f,
-	0x9b, 0x5a, 0x5c, 0x9c, 0x98, 0x9e, 0x0a, 0xd1, 0xa4, 0x54, 0xc2, 0xc5, 0x12, 0x5a, 0x9c, 0x5a,
-	0x24, 0x24, 0xc2, 0xc5, 0x9a, 0x93, 0x5a, 0x96, 0x9a, 0x23, 0xc1, 0xa8, 0xc0, 0xa8, 0xc1, 0x1b,
-	0x04, 0xe1, 0x80, 0x44, 0x53, 0x73, 0x13, 0x33, 0x73, 0x24, 0x98, 0x14, 0x18, 0x35, 0x38, 0x83,
-	0x20, 0x1c, 0x21, 0x07, 0x2e, 0xf6, 0xc4, 0xe4, 0xe4, 0xfc, 0xd2, 0xbc, 0x12, 0x09, 0x66, 0x05,
-	0x46, 0x0d, 0x6e, 0x23, 0x35, 0x3d, 0x4c, 0x37, 0x41, 0xec, 0xd4, 0x0b, 0x01, 0xd9, 0xe9, 0x0b,
-	0xb1, 0x32, 0x08, 0xa6, 0xcd, 0xc9, 0x93, 0x4b, 0x2e, 0x39, 0x3f, 0x57, 0x0f, 0xb7, 0x4f, 0x02,
-	0x18, 0xa3, 0x38, 0x60, 0xec, 0x55, 0x4c, 0x52, 0x61, 0x46, 0x41, 0x89, 0x95, 0x7a, 0xce, 0x20,
-	0x85, 0xce, 0x10, 0x85, 0x01, 0x50, 0xc9, 0x24, 0x36, 0xb0, 0x32, 0x63, 0x40, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x02, 0xf5, 0x15, 0xcc, 0x42, 0x01, 0x00, 0x00,
+	// 243 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x8f, 0xbf, 0x4a, 0xc5, 0x30,
+	0x14, 0xc6, 0xc9, 0xfd, 0xa7, 0x46, 0x5c, 0x8a, 0x48, 0xe8, 0x20, 0xc5, 0x41, 0xea, 0x92, 0x48,
+	0x7d, 0x01, 0xf1, 0x4e, 0x0e, 0xc2, 0x25, 0xa8, 0x83, 0x8b, 0xc4, 0x78, 0x90, 0x42, 0x72, 0x4f,
+	0x4d, 0xda, 0x8b, 0x7d, 0x1d, 0x47, 0x9f, 0x52, 0xda, 0xd3, 0x4c, 0x7a, 0xb7, 0xfc, 0x4e, 0x7e,
+	0xe7, 0xfb, 0x12, 0x7e, 0xb5, 0xab, 0x82, 0xe9, 0xa5, 0x45, 0xaf, 0x2c, 0x06, 0x50, 0x16, 0xbd,
+	0xc7, 0xad, 0x6a, 0x02, 0xb6, 0x68, 0xd1, 0xa9, 0x2e, 0x42, 0x90, 0x23, 0x65, 0x79, 0x52, 0x03,
+	0x48, 0xd2, 0x64, 0xd2, 0xf2, 0xeb, 0xff, 0x63, 0x22, 0x84, 0xda, 0x38, 0xd5, 0xf6, 0x0d, 0xbc,
+	0xbf, 0x7a, 0x88, 0xd1, 0x7c, 0x00, 0x2d, 0x5d, 0x7c, 0x33, 0xbe, 0x78, 0x8a, 0x10, 0xb2, 0x53,
+	0xbe, 0x74, 0xb0, 0x03, 0x27, 0x58, 0xc1, 0xca, 0x13, 0x4d, 0x30, 0x4c, 0xc1, 0x9b, 0xda, 0x89,
+	0x59, 0xc1, 0xca, 0x23, 0x4d, 0x90, 0xdd, 0xf2, 0x03, 0x63, 0x2d, 0x76, 0xdb, 0x56, 0xcc, 0x0b,
+	0x56, 0x1e, 0x57, 0x97, 0xf2, 0xef, 0xa3, 0xa8, 0x54, 0x3e, 0x0e, 0xa5, 0x0f, 0xd4, 0xa9, 0xd3,
+	0xda, 0x90, 0xfb, 0xd9, 0x61, 0x6b, 0xc4, 0xa2, 0x60, 0xe5, 0x5c, 0x13, 0x64, 0x67, 0x7c, 0x05,
+	0x5f, 0x4d, 0x1d, 0x7a, 0xb1, 0x1c, 0xc7, 0x13, 0xdd, 0xdd, 0xf3, 0x73, 0x8b, 0x5e, 0xee, 0xff,
+	0xf8, 0x86, 0xbd, 0x1c, 0xa6, 0xf3, 0xcf, 0x2c, 0x7f, 0xae, 0xb4, 0xe9, 0xe5, 0x7a, 0x10, 0xd7,
+	0x24, 0x6e, 0xa6, 0xcb, 0xb7, 0xd5, 0xa8, 0xdd, 0xfc, 0x0e, 0x00, 0xf0, 0xdd, 0x8f, 0x53, 0x71,
+	0x01, 0x00, 0x00,
 }
@@ -43,6 +43,10 @@ type Inbound struct {
 	Tag string
 	// User is the user that authencates for the inbound. May be nil if the protocol allows anounymous traffic.
 	User *protocol.MemoryUser
+	// Loopback is whether the connection is dispatched again by a loopback outbound.
+	Loopback bool
+	// ResetOnClose is whether to close the inbound connection with a TCP RST instead of a FIN, if supported.
+	ResetOnClose bool
 }
 
 // Outbound is the metadata of an outbound connection.
@@ -53,6 +57,8 @@ type Outbound struct {
 	Gateway net.Address
 	// ResolvedIPs is the resolved IP addresses, if the Targe is a domain address.
 	ResolvedIPs []net.IP
+	// RuleTag is the tag of the routing rule that picks the outbound. May be empty.
+	RuleTag string
 }
 
 type SniffingRequest struct {
@@ -18,6 +18,14 @@ func FromContext(ctx context.Context) *Instance {
 	return nil
 }
 
+// ToContext returns a context derived from ctx that carries the given Instance.
+func ToContext(ctx context.Context, v *Instance) context.Context {
+	if FromContext(ctx) != v {
+		ctx = context.WithValue(ctx, v2rayKey, v)
+	}
+	return ctx
+}
+
 // MustFromContext returns an Instance from the given context, or panics if not present.
 func MustFromContext(ctx context.Context) *Instance {
 	v := FromContext(ctx)
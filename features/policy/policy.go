@@ -35,6 +35,14 @@ type Buffer struct {
 	PerConnection int32
 }
 
+// IPLimit contains settings for limiting source IPs of a user.
+type IPLimit struct {
+	// Maximum number of distinct source IPs per user. 0 for unlimited.
+	MaxIPs uint32
+	// Sliding window in which a source IP is considered active, after its last connection ends.
+	Window time.Duration
+}
+
 // SystemStats contains stat policy settings on system level.
 type SystemStats struct {
 	// Whether or not to enable stat counter for uplink traffic in inbound handlers.
@@ -54,6 +62,7 @@ type Session struct {
 	Timeouts Timeout // Timeout settings
 	Stats    Stats
 	Buffer   Buffer
+	IPLimit  IPLimit
 }
 
 // Manager is a feature that provides Policy for the given user by its id or level.
@@ -123,6 +132,10 @@ func SessionDefault() Session {
 			UserDownlink: false,
 		},
 		Buffer: defaultBufferPolicy(),
+		IPLimit: IPLimit{
+			MaxIPs: 0,
+			Window: time.Second * 300,
+		},
 	}
 }
 
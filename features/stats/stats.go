@@ -2,7 +2,12 @@ package stats
 
 //go:generate errorgen
 
-import "v2ray.com/core/features"
+import (
+	"time"
+
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/features"
+)
 
 // Counter is the interface for stats counters.
 //
@@ -28,6 +33,84 @@ type Manager interface {
 	GetCounter(string) Counter
 }
 
+// CounterUnregisterer is implemented by Managers that can remove counters.
+type CounterUnregisterer interface {
+	// UnregisterCounter unregisters a counter from the manager by its identifier.
+	UnregisterCounter(string) error
+}
+
+// UnregisterCounter unregisters a counter from the manager, if the manager supports it.
+func UnregisterCounter(m Manager, name string) error {
+	if u, ok := m.(CounterUnregisterer); ok {
+		return u.UnregisterCounter(name)
+	}
+	return nil
+}
+
+// UserQuota is the traffic quota and expiry time of a user.
+type UserQuota struct {
+	// Quota is the traffic quota in bytes. 0 for unlimited.
+	Quota int64
+	// Expiry is the expiry time in Unix seconds. 0 for never.
+	Expiry int64
+}
+
+// QuotaManager is implemented by Managers that keep track of user quotas.
+type QuotaManager interface {
+	// SetUserQuota records the quota of the given user, so that it can be queried later.
+	SetUserQuota(email string, quota UserQuota)
+	// GetUserQuota returns the quota of the given user, if recorded.
+	GetUserQuota(email string) (UserQuota, bool)
+}
+
+// RegisterUserQuota records the quota of the user in the manager when the user is loaded. Traffic quotas are
+// enforced with the traffic counters of the manager, so a warning is logged if the manager doesn't support them.
+func RegisterUserQuota(m Manager, user *protocol.MemoryUser) {
+	if user.Quota <= 0 && user.Expiry <= 0 {
+		return
+	}
+	qm, ok := m.(QuotaManager)
+	if !ok || len(user.Email) == 0 {
+		if user.Quota > 0 {
+			newError("traffic quota of user ", user.Email, " is not enforced, which requires an email and the stats app").AtWarning().WriteToLog()
+		}
+		return
+	}
+	qm.SetUserQuota(user.Email, UserQuota{
+		Quota:  user.Quota,
+		Expiry: user.Expiry,
+	})
+}
+
+// CheckUserQuota returns an error if the user has expired, or has used up its traffic quota according to the
+// traffic counters in the manager.
+func CheckUserQuota(m Manager, user *protocol.MemoryUser) error {
+	if user.IsExpired(time.Now()) {
+		return newError("user ", user.Email, " has expired")
+	}
+	if user.Quota > 0 {
+		var usage int64
+		for _, name := range []string{UserUplinkCounterName(user.Email), UserDownlinkCounterName(user.Email)} {
+			if c := m.GetCounter(name); c != nil {
+				usage += c.Value()
+			}
+		}
+		if usage >= user.Quota {
+			return newError("user ", user.Email, " has exceeded quota")
+		}
+	}
+	return nil
+}
+
+// UserIPManager is implemented by Managers that keep track of source IPs of users.
+type UserIPManager interface {
+	// AddUserIP records a source IP of the given user, and returns false if the user already has
+	// maxIPs other active IPs within the given window.
+	AddUserIP(email string, ip string, maxIPs uint32, window time.Duration) bool
+	// RefreshUserIP updates the last time a source IP of the given user is seen, as it is still in use.
+	RefreshUserIP(email string, ip string)
+}
+
 // GetOrRegisterCounter tries to get the StatCounter first. If not exist, it then tries to create a new counter.
 func GetOrRegisterCounter(m Manager, name string) (Counter, error) {
 	counter := m.GetCounter(name)
@@ -38,6 +121,16 @@ func GetOrRegisterCounter(m Manager, name string) (Counter, error) {
 	return m.RegisterCounter(name)
 }
 
+// UserUplinkCounterName returns the name of the uplink traffic counter of the given user.
+func UserUplinkCounterName(email string) string {
+	return "user>>>" + email + ">>>traffic>>>uplink"
+}
+
+// UserDownlinkCounterName returns the name of the downlink traffic counter of the given user.
+func UserDownlinkCounterName(email string) string {
+	return "user>>>" + email + ">>>traffic>>>downlink"
+}
+
 // ManagerType returns the type of Manager interface. Can be used to implement common.HasType.
 //
 // v2ray:api:stable
@@ -6,6 +6,7 @@ import (
 	"v2ray.com/core/app/commander"
 	loggerservice "v2ray.com/core/app/log/command"
 	handlerservice "v2ray.com/core/app/proxyman/command"
+	reverseservice "v2ray.com/core/app/reverse/command"
 	statsservice "v2ray.com/core/app/stats/command"
 	"v2ray.com/core/common/serial"
 )
@@ -29,6 +30,8 @@ func (c *ApiConfig) Build() (*commander.Config, error) {
 			services = append(services, serial.ToTypedMessage(&loggerservice.Config{}))
 		case "statsservice":
 			services = append(services, serial.ToTypedMessage(&statsservice.Config{}))
+		case "reverseservice":
+			services = append(services, serial.ToTypedMessage(&reverseservice.Config{}))
 		}
 	}
 
@@ -15,18 +15,67 @@ func (*NoneResponse) Build() (proto.Message, error) {
 	return new(blackhole.NoneResponse), nil
 }
 
-type HttpResponse struct{}
+type HttpResponse struct {
+	StatusCode  uint32 `json:"statusCode"`
+	ContentType string `json:"contentType"`
+	Body        string `json:"body"`
+}
+
+func (v *HttpResponse) Build() (proto.Message, error) {
+	if v.StatusCode != 0 && (v.StatusCode < 100 || v.StatusCode > 999) {
+		return nil, newError("invalid HTTP status code: ", v.StatusCode)
+	}
+	return &blackhole.HTTPResponse{
+		StatusCode:  v.StatusCode,
+		ContentType: v.ContentType,
+		Body:        v.Body,
+	}, nil
+}
+
+type TLSAlertResponse struct {
+	Alert uint32 `json:"alert"`
+}
+
+func (v *TLSAlertResponse) Build() (proto.Message, error) {
+	if v.Alert > 255 {
+		return nil, newError("invalid TLS alert: ", v.Alert)
+	}
+	return &blackhole.TLSAlertResponse{
+		Alert: v.Alert,
+	}, nil
+}
 
-func (*HttpResponse) Build() (proto.Message, error) {
-	return new(blackhole.HTTPResponse), nil
+type ResetResponse struct{}
+
+func (*ResetResponse) Build() (proto.Message, error) {
+	return new(blackhole.ResetResponse), nil
+}
+
+type TarpitResponse struct {
+	Delay uint32 `json:"delay"`
+}
+
+func (v *TarpitResponse) Build() (proto.Message, error) {
+	return &blackhole.TarpitResponse{
+		Delay: v.Delay,
+	}, nil
+}
+
+type DropResponse struct{}
+
+func (*DropResponse) Build() (proto.Message, error) {
+	return new(blackhole.DropResponse), nil
 }
 
 type BlackholeConfig struct {
 	Response json.RawMessage `json:"response"`
+	Stats    bool            `json:"stats"`
 }
 
 func (v *BlackholeConfig) Build() (proto.Message, error) {
-	config := new(blackhole.Config)
+	config := &blackhole.Config{
+		Stats: v.Stats,
+	}
 	if v.Response != nil {
 		response, _, err := configLoader.Load(v.Response)
 		if err != nil {
@@ -45,8 +94,12 @@ func (v *BlackholeConfig) Build() (proto.Message, error) {
 var (
 	configLoader = NewJSONConfigLoader(
 		ConfigCreatorCache{
-			"none": func() interface{} { return new(NoneResponse) },
-			"http": func() interface{} { return new(HttpResponse) },
+			"none":   func() interface{} { return new(NoneResponse) },
+			"http":   func() interface{} { return new(HttpResponse) },
+			"tls":    func() interface{} { return new(TLSAlertResponse) },
+			"reset":  func() interface{} { return new(ResetResponse) },
+			"tarpit": func() interface{} { return new(TarpitResponse) },
+			"drop":   func() interface{} { return new(DropResponse) },
 		},
 		"type",
 		"")
@@ -25,6 +25,76 @@ func TestHTTPResponseJSON(t *testing.T) {
 				Response: serial.ToTypedMessage(&blackhole.HTTPResponse{}),
 			},
 		},
+		{
+			Input: `{
+				"response": {
+					"type": "http",
+					"statusCode": 204,
+					"contentType": "text/plain",
+					"body": "blocked"
+				},
+				"stats": true
+			}`,
+			Parser: loadJSON(creator),
+			Output: &blackhole.Config{
+				Response: serial.ToTypedMessage(&blackhole.HTTPResponse{
+					StatusCode:  204,
+					ContentType: "text/plain",
+					Body:        "blocked",
+				}),
+				Stats: true,
+			},
+		},
+		{
+			Input: `{
+				"response": {
+					"type": "tls",
+					"alert": 49
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &blackhole.Config{
+				Response: serial.ToTypedMessage(&blackhole.TLSAlertResponse{
+					Alert: 49,
+				}),
+			},
+		},
+		{
+			Input: `{
+				"response": {
+					"type": "reset"
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &blackhole.Config{
+				Response: serial.ToTypedMessage(&blackhole.ResetResponse{}),
+			},
+		},
+		{
+			Input: `{
+				"response": {
+					"type": "tarpit",
+					"delay": 30
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &blackhole.Config{
+				Response: serial.ToTypedMessage(&blackhole.TarpitResponse{
+					Delay: 30,
+				}),
+			},
+		},
+		{
+			Input: `{
+				"response": {
+					"type": "drop"
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &blackhole.Config{
+				Response: serial.ToTypedMessage(&blackhole.DropResponse{}),
+			},
+		},
 		{
 			Input:  `{}`,
 			Parser: loadJSON(creator),
@@ -5,13 +5,34 @@ import (
 	"v2ray.com/core/proxy/dokodemo"
 )
 
+type DokodemoPortMapping struct {
+	Listen *PortRange `json:"listen"`
+	Host   *Address   `json:"address"`
+	Port   uint16     `json:"port"`
+}
+
+func (v *DokodemoPortMapping) Build() (*dokodemo.PortMapping, error) {
+	if v.Listen == nil {
+		return nil, newError("listen ports not specified in port map")
+	}
+	mapping := &dokodemo.PortMapping{
+		Listen: v.Listen.Build(),
+		Port:   uint32(v.Port),
+	}
+	if v.Host != nil {
+		mapping.Address = v.Host.Build()
+	}
+	return mapping, nil
+}
+
 type DokodemoConfig struct {
-	Host         *Address     `json:"address"`
-	PortValue    uint16       `json:"port"`
-	NetworkList  *NetworkList `json:"network"`
-	TimeoutValue uint32       `json:"timeout"`
-	Redirect     bool         `json:"followRedirect"`
-	UserLevel    uint32       `json:"userLevel"`
+	Host         *Address               `json:"address"`
+	PortValue    uint16                 `json:"port"`
+	NetworkList  *NetworkList           `json:"network"`
+	TimeoutValue uint32                 `json:"timeout"`
+	Redirect     bool                   `json:"followRedirect"`
+	UserLevel    uint32                 `json:"userLevel"`
+	PortMap      []*DokodemoPortMapping `json:"portMap"`
 }
 
 func (v *DokodemoConfig) Build() (proto.Message, error) {
@@ -24,5 +45,12 @@ func (v *DokodemoConfig) Build() (proto.Message, error) {
 	config.Timeout = v.TimeoutValue
 	config.FollowRedirect = v.Redirect
 	config.UserLevel = v.UserLevel
+	for _, m := range v.PortMap {
+		mapping, err := m.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.PortMap = append(config.PortMap, mapping)
+	}
 	return config, nil
 }
@@ -37,5 +37,40 @@ func TestDokodemoConfig(t *testing.T) {
 				UserLevel:      1,
 			},
 		},
+		{
+			Input: `{
+				"address": "10.0.0.1",
+				"port": 80,
+				"network": "tcp,udp",
+				"portMap": [
+					{"listen": "5000-5009", "address": "10.0.0.2", "port": 6000},
+					{"listen": 7000}
+				]
+			}`,
+			Parser: loadJSON(creator),
+			Output: &dokodemo.Config{
+				Address: &net.IPOrDomain{
+					Address: &net.IPOrDomain_Ip{
+						Ip: []byte{10, 0, 0, 1},
+					},
+				},
+				Port:     80,
+				Networks: []net.Network{net.Network_TCP, net.Network_UDP},
+				PortMap: []*dokodemo.PortMapping{
+					{
+						Listen: &net.PortRange{From: 5000, To: 5009},
+						Address: &net.IPOrDomain{
+							Address: &net.IPOrDomain_Ip{
+								Ip: []byte{10, 0, 0, 2},
+							},
+						},
+						Port: 6000,
+					},
+					{
+						Listen: &net.PortRange{From: 7000, To: 7000},
+					},
+				},
+			},
+		},
 	})
 }
@@ -0,0 +1,50 @@
+package conf
+
+import (
+	"strings"
+
+	"v2ray.com/core/proxy/fallback"
+)
+
+type FallbackConfig struct {
+	Alpn string `json:"alpn"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Dest string `json:"dest"`
+}
+
+func (c *FallbackConfig) Build() (*fallback.Fallback, error) {
+	switch strings.ToLower(c.Type) {
+	case "", "tcp":
+		c.Type = "tcp"
+	case "unix":
+		c.Type = "unix"
+	default:
+		return nil, newError("unknown fallback type: ", c.Type)
+	}
+	if len(c.Dest) == 0 {
+		return nil, newError("fallback destination is not specified")
+	}
+	if len(c.Path) > 0 && c.Path[0] != '/' {
+		return nil, newError("fallback path must start with '/': ", c.Path)
+	}
+
+	return &fallback.Fallback{
+		Alpn: c.Alpn,
+		Path: c.Path,
+		Type: c.Type,
+		Dest: c.Dest,
+	}, nil
+}
+
+func buildFallbacks(configs []*FallbackConfig) ([]*fallback.Fallback, error) {
+	var fallbacks []*fallback.Fallback
+	for _, fc := range configs {
+		f, err := fc.Build()
+		if err != nil {
+			return nil, err
+		}
+		fallbacks = append(fallbacks, f)
+	}
+	return fallbacks, nil
+}
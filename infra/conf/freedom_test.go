@@ -20,7 +20,8 @@ func TestFreedomConfig(t *testing.T) {
 				"domainStrategy": "AsIs",
 				"timeout": 10,
 				"redirect": "127.0.0.1:3366",
-				"userLevel": 1
+				"userLevel": 1,
+				"proxyProtocol": 2
 			}`,
 			Parser: loadJSON(creator),
 			Output: &freedom.Config{
@@ -36,7 +37,8 @@ func TestFreedomConfig(t *testing.T) {
 						Port: 3366,
 					},
 				},
-				UserLevel: 1,
+				UserLevel:     1,
+				ProxyProtocol: 2,
 			},
 		},
 	})
@@ -0,0 +1,19 @@
+package conf
+
+import (
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/proxy/loopback"
+)
+
+type LoopbackConfig struct {
+	InboundTag string `json:"inboundTag"`
+}
+
+func (c *LoopbackConfig) Build() (proto.Message, error) {
+	if len(c.InboundTag) == 0 {
+		return nil, newError("inbound tag of loopback is not specified")
+	}
+	return &loopback.Config{
+		InboundTag: c.InboundTag,
+	}, nil
+}
@@ -0,0 +1,26 @@
+package conf_test
+
+import (
+	"testing"
+
+	. "v2ray.com/core/infra/conf"
+	"v2ray.com/core/proxy/loopback"
+)
+
+func TestLoopbackConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(LoopbackConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"inboundTag": "in-loop"
+			}`,
+			Parser: loadJSON(creator),
+			Output: &loopback.Config{
+				InboundTag: "in-loop",
+			},
+		},
+	})
+}
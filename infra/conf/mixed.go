@@ -0,0 +1,40 @@
+package conf
+
+import (
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/proxy/mixed"
+)
+
+type MixedAccount struct {
+	Username string `json:"user"`
+	Password string `json:"pass"`
+}
+
+type MixedServerConfig struct {
+	Accounts    []*MixedAccount `json:"accounts"`
+	UDP         bool            `json:"udp"`
+	Host        *Address        `json:"ip"`
+	Transparent bool            `json:"allowTransparent"`
+	UserLevel   uint32          `json:"userLevel"`
+}
+
+func (c *MixedServerConfig) Build() (proto.Message, error) {
+	config := &mixed.ServerConfig{
+		UdpEnabled:       c.UDP,
+		AllowTransparent: c.Transparent,
+		UserLevel:        c.UserLevel,
+	}
+
+	if len(c.Accounts) > 0 {
+		config.Accounts = make(map[string]string, len(c.Accounts))
+		for _, account := range c.Accounts {
+			config.Accounts[account.Username] = account.Password
+		}
+	}
+
+	if c.Host != nil {
+		config.Address = c.Host.Build()
+	}
+
+	return config, nil
+}
@@ -0,0 +1,46 @@
+package conf_test
+
+import (
+	"testing"
+
+	"v2ray.com/core/common/net"
+	. "v2ray.com/core/infra/conf"
+	"v2ray.com/core/proxy/mixed"
+)
+
+func TestMixedServerConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(MixedServerConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"accounts": [
+					{
+						"user": "my-username",
+						"pass": "my-password"
+					}
+				],
+				"udp": true,
+				"ip": "127.0.0.1",
+				"allowTransparent": true,
+				"userLevel": 1
+			}`,
+			Parser: loadJSON(creator),
+			Output: &mixed.ServerConfig{
+				Accounts: map[string]string{
+					"my-username": "my-password",
+				},
+				Address: &net.IPOrDomain{
+					Address: &net.IPOrDomain_Ip{
+						Ip: []byte{127, 0, 0, 1},
+					},
+				},
+				UdpEnabled:       true,
+				AllowTransparent: true,
+				UserLevel:        1,
+			},
+		},
+	})
+}
@@ -17,16 +17,20 @@ type MTProtoAccount struct {
 
 // Build implements Buildable
 func (a *MTProtoAccount) Build() (*mtproto.Account, error) {
-	if len(a.Secret) != 32 {
-		return nil, newError("MTProto secret must have 32 chars")
+	if len(a.Secret) < 32 {
+		return nil, newError("MTProto secret must have at least 32 chars")
 	}
 	secret, err := hex.DecodeString(a.Secret)
 	if err != nil {
 		return nil, newError("failed to decode secret: ", a.Secret).Base(err)
 	}
-	return &mtproto.Account{
+	account := &mtproto.Account{
 		Secret: secret,
-	}, nil
+	}
+	if err := account.Validate(); err != nil {
+		return nil, newError("invalid MTProto secret: ", a.Secret).Base(err)
+	}
+	return account, nil
 }
 
 type MTProtoServerConfig struct {
@@ -60,10 +64,37 @@ func (c *MTProtoServerConfig) Build() (proto.Message, error) {
 	return config, nil
 }
 
+type MTProtoServerTarget struct {
+	Address *Address `json:"address"`
+	Port    uint16   `json:"port"`
+	Secret  string   `json:"secret"`
+}
+
 type MTProtoClientConfig struct {
+	Server *MTProtoServerTarget `json:"server"`
 }
 
 func (c *MTProtoClientConfig) Build() (proto.Message, error) {
 	config := new(mtproto.ClientConfig)
+	if c.Server == nil {
+		return config, nil
+	}
+
+	if c.Server.Address == nil {
+		return nil, newError("address is not set in MTProto server")
+	}
+	account, err := (&MTProtoAccount{Secret: c.Server.Secret}).Build()
+	if err != nil {
+		return nil, newError("failed to parse MTProto server secret").Base(err)
+	}
+	config.Server = &protocol.ServerEndpoint{
+		Address: c.Server.Address.Build(),
+		Port:    uint32(c.Server.Port),
+		User: []*protocol.User{
+			{
+				Account: serial.ToTypedMessage(account),
+			},
+		},
+	}
 	return config, nil
 }
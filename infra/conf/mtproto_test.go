@@ -3,6 +3,7 @@ package conf_test
 import (
 	"testing"
 
+	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
 	. "v2ray.com/core/infra/conf"
@@ -38,3 +39,56 @@ func TestMTProtoServerConfig(t *testing.T) {
 		},
 	})
 }
+
+func TestMTProtoClientConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(MTProtoClientConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input:  `{}`,
+			Parser: loadJSON(creator),
+			Output: &mtproto.ClientConfig{},
+		},
+		{
+			Input: `{
+				"server": {
+					"address": "127.0.0.1",
+					"port": 443,
+					"secret": "eeb0cbcef5a486d9636472ac27f8e11a9d7777772e76327261792e636f6d"
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &mtproto.ClientConfig{
+				Server: &protocol.ServerEndpoint{
+					Address: &net.IPOrDomain{
+						Address: &net.IPOrDomain_Ip{
+							Ip: []byte{127, 0, 0, 1},
+						},
+					},
+					Port: 443,
+					User: []*protocol.User{
+						{
+							Account: serial.ToTypedMessage(&mtproto.Account{
+								Secret: append([]byte{0xee, 176, 203, 206, 245, 164, 134, 217, 99, 100, 114, 172, 39, 248, 225, 26, 157}, "www.v2ray.com"...),
+							}),
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestMTProtoInvalidSecret(t *testing.T) {
+	for _, secret := range []string{
+		"b0cbcef5a486d9636472ac27f8e11a",
+		"b0cbcef5a486d9636472ac27f8e11a9d00",
+		"ddb0cbcef5a486d9636472ac27f8e11a9d00",
+	} {
+		if _, err := (&MTProtoAccount{Secret: secret}).Build(); err == nil {
+			t.Error("expect error for secret ", secret)
+		}
+	}
+}
@@ -12,6 +12,8 @@ type Policy struct {
 	StatsUserUplink   bool    `json:"statsUserUplink"`
 	StatsUserDownlink bool    `json:"statsUserDownlink"`
 	BufferSize        *int32  `json:"bufferSize"`
+	IPLimit           uint32  `json:"ipLimit"`
+	IPLimitWindow     *uint32 `json:"ipLimitWindow"`
 }
 
 func (t *Policy) Build() (*policy.Policy, error) {
@@ -47,6 +49,15 @@ func (t *Policy) Build() (*policy.Policy, error) {
 		}
 	}
 
+	if t.IPLimit > 0 {
+		p.IpLimit = &policy.Policy_IPLimit{
+			MaxIps: t.IPLimit,
+		}
+		if t.IPLimitWindow != nil {
+			p.IpLimit.Window = &policy.Second{Value: *t.IPLimitWindow}
+		}
+	}
+
 	return p, nil
 }
 
@@ -38,3 +38,19 @@ func TestBufferSize(t *testing.T) {
 		}
 	}
 }
+
+func TestIPLimit(t *testing.T) {
+	window := uint32(60)
+	pConf := Policy{
+		IPLimit:       2,
+		IPLimitWindow: &window,
+	}
+	p, err := pConf.Build()
+	common.Must(err)
+	if p.IpLimit.MaxIps != 2 {
+		t.Error("expected IP limit 2 but got ", p.IpLimit.MaxIps)
+	}
+	if p.IpLimit.Window.Value != 60 {
+		t.Error("expected IP limit window 60 but got ", p.IpLimit.Window.Value)
+	}
+}
@@ -92,6 +92,7 @@ type RouterRule struct {
 	Type        string `json:"type"`
 	OutboundTag string `json:"outboundTag"`
 	BalancerTag string `json:"balancerTag"`
+	RuleTag     string `json:"ruleTag"`
 }
 
 func ParseIP(s string) (*router.CIDR, error) {
@@ -371,7 +372,9 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		return nil, err
 	}
 
-	rule := new(router.RoutingRule)
+	rule := &router.RoutingRule{
+		RuleTag: rawFieldRule.RuleTag,
+	}
 	if len(rawFieldRule.OutboundTag) > 0 {
 		rule.TargetTag = &router.RoutingRule_Tag{
 			Tag: rawFieldRule.OutboundTag,
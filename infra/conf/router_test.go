@@ -35,7 +35,8 @@ func TestRouterConfig(t *testing.T) {
 								"baidu.com",
 								"qq.com"
 							],
-							"outboundTag": "direct"
+							"outboundTag": "direct",
+							"ruleTag": "cn"
 						},
 						{
 							"type": "field",
@@ -86,6 +87,7 @@ func TestRouterConfig(t *testing.T) {
 						TargetTag: &router.RoutingRule_Tag{
 							Tag: "direct",
 						},
+						RuleTag: "cn",
 					},
 					{
 						Geoip: []*router.GeoIP{
@@ -32,13 +32,16 @@ func cipherFromString(c string) shadowsocks.CipherType {
 }
 
 type ShadowsocksServerConfig struct {
-	Cipher      string       `json:"method"`
-	Password    string       `json:"password"`
-	UDP         bool         `json:"udp"`
-	Level       byte         `json:"level"`
-	Email       string       `json:"email"`
-	OTA         *bool        `json:"ota"`
-	NetworkList *NetworkList `json:"network"`
+	Cipher      string            `json:"method"`
+	Password    string            `json:"password"`
+	UDP         bool              `json:"udp"`
+	Level       byte              `json:"level"`
+	Email       string            `json:"email"`
+	Quota       int64             `json:"quota"`
+	Expiry      int64             `json:"expiry"`
+	OTA         *bool             `json:"ota"`
+	NetworkList *NetworkList      `json:"network"`
+	Fallbacks   []*FallbackConfig `json:"fallbacks"`
 }
 
 func (v *ShadowsocksServerConfig) Build() (proto.Message, error) {
@@ -68,9 +71,17 @@ func (v *ShadowsocksServerConfig) Build() (proto.Message, error) {
 	config.User = &protocol.User{
 		Email:   v.Email,
 		Level:   uint32(v.Level),
+		Quota:   v.Quota,
+		Expiry:  v.Expiry,
 		Account: serial.ToTypedMessage(account),
 	}
 
+	fallbacks, err := buildFallbacks(v.Fallbacks)
+	if err != nil {
+		return nil, newError("invalid Shadowsocks fallback").Base(err)
+	}
+	config.Fallback = fallbacks
+
 	return config, nil
 }
 
@@ -32,5 +32,45 @@ func TestShadowsocksServerConfigParsing(t *testing.T) {
 				Network: []net.Network{net.Network_TCP},
 			},
 		},
+		{
+			Input: `{
+				"method": "aes-256-gcm",
+				"password": "v2ray-password",
+				"email": "love@v2ray.com",
+				"quota": 1073741824
+			}`,
+			Parser: loadJSON(creator),
+			Output: &shadowsocks.ServerConfig{
+				User: &protocol.User{
+					Email: "love@v2ray.com",
+					Quota: 1073741824,
+					Account: serial.ToTypedMessage(&shadowsocks.Account{
+						CipherType: shadowsocks.CipherType_AES_256_GCM,
+						Password:   "v2ray-password",
+					}),
+				},
+				Network: []net.Network{net.Network_TCP},
+			},
+		},
+		{
+			Input: `{
+				"method": "aes-256-gcm",
+				"password": "v2ray-password",
+				"email": "love@v2ray.com",
+				"expiry": 1893456000
+			}`,
+			Parser: loadJSON(creator),
+			Output: &shadowsocks.ServerConfig{
+				User: &protocol.User{
+					Email:  "love@v2ray.com",
+					Expiry: 1893456000,
+					Account: serial.ToTypedMessage(&shadowsocks.Account{
+						CipherType: shadowsocks.CipherType_AES_256_GCM,
+						Password:   "v2ray-password",
+					}),
+				},
+				Network: []net.Network{net.Network_TCP},
+			},
+		},
 	})
 }
@@ -30,6 +30,8 @@ type SocksServerConfig struct {
 	AuthMethod string          `json:"auth"`
 	Accounts   []*SocksAccount `json:"accounts"`
 	UDP        bool            `json:"udp"`
+	UDPStrict  bool            `json:"udpStrictPort"`
+	Bind       bool            `json:"bind"`
 	Host       *Address        `json:"ip"`
 	Timeout    uint32          `json:"timeout"`
 	UserLevel  uint32          `json:"userLevel"`
@@ -55,6 +57,8 @@ func (v *SocksServerConfig) Build() (proto.Message, error) {
 	}
 
 	config.UdpEnabled = v.UDP
+	config.UdpStrictPort = v.UDPStrict
+	config.BindEnabled = v.Bind
 	if v.Host != nil {
 		config.Address = v.Host.Build()
 	}
@@ -46,6 +46,21 @@ func TestSocksInboundConfig(t *testing.T) {
 				UserLevel: 1,
 			},
 		},
+		{
+			Input: `{
+				"auth": "noauth",
+				"udp": true,
+				"udpStrictPort": true,
+				"bind": true
+			}`,
+			Parser: loadJSON(creator),
+			Output: &socks.ServerConfig{
+				AuthType:      socks.AuthType_NO_AUTH,
+				UdpEnabled:    true,
+				UdpStrictPort: true,
+				BindEnabled:   true,
+			},
+		},
 	})
 }
 
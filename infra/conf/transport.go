@@ -13,6 +13,7 @@ type TransportConfig struct {
 	HTTPConfig *HTTPConfig         `json:"httpSettings"`
 	DSConfig   *DomainSocketConfig `json:"dsSettings"`
 	QUICConfig *QUICConfig         `json:"quicSettings"`
+	GRPCConfig *GRPCConfig         `json:"grpcSettings"`
 }
 
 // Build implements Buildable.
@@ -85,5 +86,16 @@ func (c *TransportConfig) Build() (*transport.Config, error) {
 		})
 	}
 
+	if c.GRPCConfig != nil {
+		gs, err := c.GRPCConfig.Build()
+		if err != nil {
+			return nil, newError("Failed to build gRPC config.").Base(err)
+		}
+		config.TransportSettings = append(config.TransportSettings, &internet.TransportConfig{
+			ProtocolName: "grpc",
+			Settings:     serial.ToTypedMessage(gs),
+		})
+	}
+
 	return config, nil
 }
@@ -2,6 +2,7 @@ package conf
 
 import (
 	"encoding/json"
+	"sort"
 	"strings"
 
 	"github.com/golang/protobuf/proto"
@@ -10,6 +11,7 @@ import (
 	"v2ray.com/core/common/serial"
 	"v2ray.com/core/transport/internet"
 	"v2ray.com/core/transport/internet/domainsocket"
+	"v2ray.com/core/transport/internet/grpc"
 	"v2ray.com/core/transport/internet/http"
 	"v2ray.com/core/transport/internet/kcp"
 	"v2ray.com/core/transport/internet/quic"
@@ -34,15 +36,23 @@ var (
 	}, "type", "")
 )
 
+type KCPFECConfig struct {
+	DataShards   uint32 `json:"dataShards"`
+	ParityShards uint32 `json:"parityShards"`
+}
+
 type KCPConfig struct {
-	Mtu             *uint32         `json:"mtu"`
-	Tti             *uint32         `json:"tti"`
-	UpCap           *uint32         `json:"uplinkCapacity"`
-	DownCap         *uint32         `json:"downlinkCapacity"`
-	Congestion      *bool           `json:"congestion"`
-	ReadBufferSize  *uint32         `json:"readBufferSize"`
-	WriteBufferSize *uint32         `json:"writeBufferSize"`
-	HeaderConfig    json.RawMessage `json:"header"`
+	Mtu               *uint32         `json:"mtu"`
+	Tti               *uint32         `json:"tti"`
+	UpCap             *uint32         `json:"uplinkCapacity"`
+	DownCap           *uint32         `json:"downlinkCapacity"`
+	Congestion        *bool           `json:"congestion"`
+	ReadBufferSize    *uint32         `json:"readBufferSize"`
+	WriteBufferSize   *uint32         `json:"writeBufferSize"`
+	HeaderConfig      json.RawMessage `json:"header"`
+	FEC               *KCPFECConfig   `json:"fec"`
+	CongestionControl string          `json:"congestionControl"`
+	Stats             bool            `json:"stats"`
 }
 
 // Build implements Buildable.
@@ -72,6 +82,18 @@ func (c *KCPConfig) Build() (proto.Message, error) {
 	if c.Congestion != nil {
 		config.Congestion = *c.Congestion
 	}
+	switch strings.ToLower(c.CongestionControl) {
+	case "", "loss":
+		config.CongestionControl = kcp.CongestionControl_LossBased
+	case "bbr":
+		config.CongestionControl = kcp.CongestionControl_BBR
+	default:
+		return nil, newError("unknown mKCP congestion control: ", c.CongestionControl).AtError()
+	}
+	if c.CongestionControl != "" && c.Congestion == nil {
+		config.Congestion = true
+	}
+	config.Stats = c.Stats
 	if c.ReadBufferSize != nil {
 		size := *c.ReadBufferSize
 		if size > 0 {
@@ -99,6 +121,16 @@ func (c *KCPConfig) Build() (proto.Message, error) {
 		}
 		config.HeaderConfig = serial.ToTypedMessage(ts)
 	}
+	if c.FEC != nil {
+		data, parity := c.FEC.DataShards, c.FEC.ParityShards
+		if data == 0 || parity == 0 || data+parity > 256 {
+			return nil, newError("invalid mKCP FEC shards: ", data, "/", parity).AtError()
+		}
+		config.Fec = &kcp.FEC{
+			DataShards:   data,
+			ParityShards: parity,
+		}
+	}
 
 	return config, nil
 }
@@ -126,9 +158,13 @@ func (c *TCPConfig) Build() (proto.Message, error) {
 }
 
 type WebSocketConfig struct {
-	Path    string            `json:"path"`
-	Path2   string            `json:"Path"` // The key was misspelled. For backward compatibility, we have to keep track the old key.
-	Headers map[string]string `json:"headers"`
+	Path                string            `json:"path"`
+	Path2               string            `json:"Path"` // The key was misspelled. For backward compatibility, we have to keep track the old key.
+	Headers             map[string]string `json:"headers"`
+	MaxEarlyData        int32             `json:"maxEarlyData"`
+	EarlyDataHeaderName string            `json:"earlyDataHeaderName"`
+	Host                *StringList       `json:"host"`
+	Fallback            string            `json:"fallback"`
 }
 
 // Build implements Buildable.
@@ -145,37 +181,73 @@ func (c *WebSocketConfig) Build() (proto.Message, error) {
 		})
 	}
 
+	if c.MaxEarlyData < 0 {
+		return nil, newError("invalid max early data: ", c.MaxEarlyData)
+	}
+
 	config := &websocket.Config{
-		Path:   path,
-		Header: header,
+		Path:                path,
+		Header:              header,
+		MaxEarlyData:        c.MaxEarlyData,
+		EarlyDataHeaderName: c.EarlyDataHeaderName,
+		Fallback:            c.Fallback,
+	}
+	if c.Host != nil {
+		config.Host = []string(*c.Host)
 	}
 	return config, nil
 }
 
 type HTTPConfig struct {
-	Host *StringList `json:"host"`
-	Path string      `json:"path"`
+	Host       *StringList       `json:"host"`
+	Path       string            `json:"path"`
+	Method     string            `json:"method"`
+	Headers    map[string]string `json:"headers"`
+	RandomPath bool              `json:"randomPath"`
 }
 
 func (c *HTTPConfig) Build() (proto.Message, error) {
 	config := &http.Config{
-		Path: c.Path,
+		Path:       c.Path,
+		Method:     c.Method,
+		RandomPath: c.RandomPath,
 	}
 	if c.Host != nil {
 		config.Host = []string(*c.Host)
 	}
+	keys := make([]string, 0, len(c.Headers))
+	for key := range c.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		config.Header = append(config.Header, &http.Header{
+			Key:   key,
+			Value: c.Headers[key],
+		})
+	}
 	return config, nil
 }
 
 type QUICConfig struct {
-	Header   json.RawMessage `json:"header"`
-	Security string          `json:"security"`
-	Key      string          `json:"key"`
+	Header                  json.RawMessage `json:"header"`
+	Security                string          `json:"security"`
+	Key                     string          `json:"key"`
+	IdleTimeout             uint32          `json:"idleTimeout"`
+	MaxConcurrentStreams    uint32          `json:"maxConcurrentStreams"`
+	StreamReceiveWindow     uint64          `json:"streamReceiveWindow"`
+	ConnectionReceiveWindow uint64          `json:"connectionReceiveWindow"`
+	ConnectionPoolSize      uint32          `json:"connectionPoolSize"`
 }
 
 func (c *QUICConfig) Build() (proto.Message, error) {
 	config := &quic.Config{
-		Key: c.Key,
+		Key:                     c.Key,
+		IdleTimeout:             c.IdleTimeout,
+		MaxConcurrentStreams:    c.MaxConcurrentStreams,
+		StreamReceiveWindow:     c.StreamReceiveWindow,
+		ConnectionReceiveWindow: c.ConnectionReceiveWindow,
+		ConnectionPoolSize:      c.ConnectionPoolSize,
 	}
 
 	if len(c.Header) > 0 {
@@ -219,12 +291,25 @@ func (c *DomainSocketConfig) Build() (proto.Message, error) {
 	}, nil
 }
 
+type GRPCConfig struct {
+	ServiceName string `json:"serviceName"`
+}
+
+// Build implements Buildable.
+func (c *GRPCConfig) Build() (proto.Message, error) {
+	return &grpc.Config{
+		ServiceName: c.ServiceName,
+	}, nil
+}
+
 type TLSCertConfig struct {
-	CertFile string   `json:"certificateFile"`
-	CertStr  []string `json:"certificate"`
-	KeyFile  string   `json:"keyFile"`
-	KeyStr   []string `json:"key"`
-	Usage    string   `json:"usage"`
+	CertFile  string   `json:"certificateFile"`
+	CertStr   []string `json:"certificate"`
+	KeyFile   string   `json:"keyFile"`
+	KeyStr    []string `json:"key"`
+	Usage     string   `json:"usage"`
+	OCSPFile  string   `json:"ocspResponseFile"`
+	OCSPFetch bool     `json:"ocspFetch"`
 }
 
 func readFileOrString(f string, s []string) ([]byte, error) {
@@ -265,9 +350,43 @@ func (c *TLSCertConfig) Build() (*tls.Certificate, error) {
 		certificate.Usage = tls.Certificate_ENCIPHERMENT
 	}
 
+	// Certificates in files are loaded by path, so that they are reloaded when the files change.
+	if certificate.Usage == tls.Certificate_ENCIPHERMENT && len(c.CertFile) > 0 && len(c.KeyFile) > 0 {
+		certificate.Certificate = nil
+		certificate.Key = nil
+		certificate.CertificatePath = c.CertFile
+		certificate.KeyPath = c.KeyFile
+		certificate.OcspResponsePath = c.OCSPFile
+		certificate.OcspFetch = c.OCSPFetch
+	} else if len(c.OCSPFile) > 0 || c.OCSPFetch {
+		return nil, newError("OCSP stapling requires certificateFile and keyFile for encipherment")
+	}
+
 	return certificate, nil
 }
 
+type ACMEConfig struct {
+	Domains         *StringList `json:"domains"`
+	Email           string      `json:"email"`
+	Directory       string      `json:"directory"`
+	Storage         string      `json:"storage"`
+	RenewBeforeDays uint32      `json:"renewBeforeDays"`
+}
+
+// Build implements Buildable.
+func (c *ACMEConfig) Build() (*tls.AcmeConfig, error) {
+	if c.Domains == nil || len(*c.Domains) == 0 {
+		return nil, newError("ACME requires at least one domain")
+	}
+	return &tls.AcmeConfig{
+		Domain:          []string(*c.Domains),
+		Email:           c.Email,
+		DirectoryUrl:    c.Directory,
+		StoragePath:     c.Storage,
+		RenewBeforeDays: c.RenewBeforeDays,
+	}, nil
+}
+
 type TLSConfig struct {
 	Insecure         bool             `json:"allowInsecure"`
 	InsecureCiphers  bool             `json:"allowInsecureCiphers"`
@@ -275,6 +394,8 @@ type TLSConfig struct {
 	ServerName       string           `json:"serverName"`
 	ALPN             *StringList      `json:"alpn"`
 	DiableSystemRoot bool             `json:"disableSystemRoot"`
+	Fingerprint      string           `json:"fingerprint"`
+	ACME             *ACMEConfig      `json:"acme"`
 }
 
 // Build implements Buildable.
@@ -298,6 +419,19 @@ func (c *TLSConfig) Build() (proto.Message, error) {
 		config.NextProtocol = []string(*c.ALPN)
 	}
 	config.DisableSystemRoot = c.DiableSystemRoot
+	switch fingerprint := strings.ToLower(c.Fingerprint); fingerprint {
+	case "", "chrome", "firefox", "safari", "ios", "randomized", "golang":
+		config.Fingerprint = fingerprint
+	default:
+		return nil, newError("unknown TLS fingerprint: ", c.Fingerprint).AtError()
+	}
+	if c.ACME != nil {
+		acme, err := c.ACME.Build()
+		if err != nil {
+			return nil, newError("failed to build ACME config").Base(err)
+		}
+		config.Acme = acme
+	}
 	return config, nil
 }
 
@@ -318,15 +452,33 @@ func (p TransportProtocol) Build() (string, error) {
 		return "domainsocket", nil
 	case "quic":
 		return "quic", nil
+	case "grpc", "gun":
+		return "grpc", nil
 	default:
 		return "", newError("Config: unknown transport protocol: ", p)
 	}
 }
 
+type HappyEyeballsConfig struct {
+	PreferIPv4 bool   `json:"preferIPv4"`
+	TryDelayMs uint32 `json:"tryDelayMs"`
+	Interleave uint32 `json:"interleave"`
+}
+
+func (c *HappyEyeballsConfig) Build() *internet.HappyEyeballsConfig {
+	return &internet.HappyEyeballsConfig{
+		PreferIpv4: c.PreferIPv4,
+		TryDelayMs: c.TryDelayMs,
+		Interleave: c.Interleave,
+	}
+}
+
 type SocketConfig struct {
-	Mark   int32  `json:"mark"`
-	TFO    *bool  `json:"tcpFastOpen"`
-	TProxy string `json:"tproxy"`
+	Mark          int32                `json:"mark"`
+	TFO           *bool                `json:"tcpFastOpen"`
+	TProxy        string               `json:"tproxy"`
+	ProxyProtocol bool                 `json:"acceptProxyProtocol"`
+	HappyEyeballs *HappyEyeballsConfig `json:"happyEyeballs"`
 }
 
 func (c *SocketConfig) Build() (*internet.SocketConfig, error) {
@@ -348,11 +500,16 @@ func (c *SocketConfig) Build() (*internet.SocketConfig, error) {
 		tproxy = internet.SocketConfig_Off
 	}
 
-	return &internet.SocketConfig{
-		Mark:   c.Mark,
-		Tfo:    tfoSettings,
-		Tproxy: tproxy,
-	}, nil
+	config := &internet.SocketConfig{
+		Mark:                c.Mark,
+		Tfo:                 tfoSettings,
+		Tproxy:              tproxy,
+		AcceptProxyProtocol: c.ProxyProtocol,
+	}
+	if c.HappyEyeballs != nil {
+		config.HappyEyeballs = c.HappyEyeballs.Build()
+	}
+	return config, nil
 }
 
 type StreamConfig struct {
@@ -365,6 +522,7 @@ type StreamConfig struct {
 	HTTPSettings   *HTTPConfig         `json:"httpSettings"`
 	DSSettings     *DomainSocketConfig `json:"dsSettings"`
 	QUICSettings   *QUICConfig         `json:"quicSettings"`
+	GRPCSettings   *GRPCConfig         `json:"grpcSettings"`
 	SocketSettings *SocketConfig       `json:"sockopt"`
 }
 
@@ -453,6 +611,16 @@ func (c *StreamConfig) Build() (*internet.StreamConfig, error) {
 			Settings:     serial.ToTypedMessage(qs),
 		})
 	}
+	if c.GRPCSettings != nil {
+		gs, err := c.GRPCSettings.Build()
+		if err != nil {
+			return nil, newError("failed to build gRPC config").Base(err)
+		}
+		config.TransportSettings = append(config.TransportSettings, &internet.TransportConfig{
+			ProtocolName: "grpc",
+			Settings:     serial.ToTypedMessage(gs),
+		})
+	}
 	if c.SocketSettings != nil {
 		ss, err := c.SocketSettings.Build()
 		if err != nil {
@@ -2,20 +2,27 @@ package conf_test
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/common"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
 	. "v2ray.com/core/infra/conf"
 	"v2ray.com/core/transport"
 	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/grpc"
 	"v2ray.com/core/transport/internet/headers/http"
 	"v2ray.com/core/transport/internet/headers/noop"
 	"v2ray.com/core/transport/internet/headers/tls"
+	httptransport "v2ray.com/core/transport/internet/http"
 	"v2ray.com/core/transport/internet/kcp"
 	"v2ray.com/core/transport/internet/quic"
 	"v2ray.com/core/transport/internet/tcp"
+	v2tls "v2ray.com/core/transport/internet/tls"
 	"v2ray.com/core/transport/internet/websocket"
 )
 
@@ -42,7 +49,122 @@ func TestSocketConfig(t *testing.T) {
 				Tfo:  internet.SocketConfig_Enable,
 			},
 		},
+		{
+			Input: `{
+				"happyEyeballs": {
+					"preferIPv4": true,
+					"tryDelayMs": 100,
+					"interleave": 2
+				}
+			}`,
+			Parser: createParser(),
+			Output: &internet.SocketConfig{
+				HappyEyeballs: &internet.HappyEyeballsConfig{
+					PreferIpv4: true,
+					TryDelayMs: 100,
+					Interleave: 2,
+				},
+			},
+		},
+	})
+}
+
+func TestTLSConfig(t *testing.T) {
+	createParser := func() func(string) (proto.Message, error) {
+		return func(s string) (proto.Message, error) {
+			config := new(TLSConfig)
+			if err := json.Unmarshal([]byte(s), config); err != nil {
+				return nil, err
+			}
+			return config.Build()
+		}
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"serverName": "v2ray.com",
+				"alpn": ["h2", "http/1.1"],
+				"fingerprint": "Chrome"
+			}`,
+			Parser: createParser(),
+			Output: &v2tls.Config{
+				Certificate:  []*v2tls.Certificate{},
+				ServerName:   "v2ray.com",
+				NextProtocol: []string{"h2", "http/1.1"},
+				Fingerprint:  "chrome",
+			},
+		},
+		{
+			Input: `{
+				"acme": {
+					"domains": ["v2ray.com", "www.v2ray.com"],
+					"email": "admin@v2ray.com",
+					"directory": "https://localhost:14000/dir",
+					"storage": "/var/lib/v2ray/acme",
+					"renewBeforeDays": 15
+				}
+			}`,
+			Parser: createParser(),
+			Output: &v2tls.Config{
+				Certificate: []*v2tls.Certificate{},
+				Acme: &v2tls.AcmeConfig{
+					Domain:          []string{"v2ray.com", "www.v2ray.com"},
+					Email:           "admin@v2ray.com",
+					DirectoryUrl:    "https://localhost:14000/dir",
+					StoragePath:     "/var/lib/v2ray/acme",
+					RenewBeforeDays: 15,
+				},
+			},
+		},
 	})
+
+	if _, err := createParser()(`{"fingerprint": "netscape"}`); err == nil {
+		t.Error("expect error on unknown fingerprint")
+	}
+	if _, err := createParser()(`{"acme": {"email": "admin@v2ray.com"}}`); err == nil {
+		t.Error("expect error on ACME without domains")
+	}
+}
+
+func TestTLSCertConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-conf")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	common.Must(ioutil.WriteFile(certPath, []byte("cert"), 0644))
+	common.Must(ioutil.WriteFile(keyPath, []byte("key"), 0644))
+
+	parse := func(s string) (*v2tls.Certificate, error) {
+		config := new(TLSCertConfig)
+		common.Must(json.Unmarshal([]byte(s), config))
+		return config.Build()
+	}
+
+	certificate, err := parse(`{
+		"certificateFile": "` + certPath + `",
+		"keyFile": "` + keyPath + `",
+		"ocspResponseFile": "ocsp.der",
+		"ocspFetch": true
+	}`)
+	common.Must(err)
+	if !proto.Equal(certificate, &v2tls.Certificate{
+		CertificatePath:  certPath,
+		KeyPath:          keyPath,
+		OcspResponsePath: "ocsp.der",
+		OcspFetch:        true,
+	}) {
+		t.Error("unexpected certificate: ", certificate)
+	}
+
+	if _, err := parse(`{
+		"certificate": ["cert"],
+		"key": ["key"],
+		"ocspFetch": true
+	}`); err == nil {
+		t.Error("expect error on OCSP stapling without certificate files")
+	}
 }
 
 func TestTransportConfig(t *testing.T) {
@@ -82,16 +204,45 @@ func TestTransportConfig(t *testing.T) {
 					"mtu": 1200,
 					"header": {
 						"type": "none"
-					}
+					},
+					"fec": {
+						"dataShards": 10,
+						"parityShards": 3
+					},
+					"congestionControl": "bbr",
+					"stats": true
 				},
 				"wsSettings": {
-					"path": "/t"
+					"path": "/t",
+					"maxEarlyData": 2048,
+					"earlyDataHeaderName": "Sec-WebSocket-Protocol",
+					"host": ["a.v2ray.com", "b.v2ray.com"],
+					"fallback": "http://127.0.0.1:8080"
+				},
+				"httpSettings": {
+					"path": "/h2",
+					"method": "POST",
+					"headers": {
+						"User-Agent": "v2ray",
+						"X-Forwarded-For": "1.2.3.4",
+						"Accept": "*/*",
+						"Pragma": "no-cache"
+					},
+					"randomPath": true
 				},
 				"quicSettings": {
 					"key": "abcd",
+					"idleTimeout": 60,
+					"maxConcurrentStreams": 64,
+					"streamReceiveWindow": 1048576,
+					"connectionReceiveWindow": 4194304,
+					"connectionPoolSize": 4,
 					"header": {
 						"type": "dtls"
 					}
+				},
+				"grpcSettings": {
+					"serviceName": "v2ray.Tunnel"
 				}
 			}`,
 			Parser: createParser(),
@@ -142,26 +293,59 @@ func TestTransportConfig(t *testing.T) {
 					{
 						ProtocolName: "mkcp",
 						Settings: serial.ToTypedMessage(&kcp.Config{
-							Mtu:          &kcp.MTU{Value: 1200},
-							HeaderConfig: serial.ToTypedMessage(&noop.Config{}),
+							Mtu:               &kcp.MTU{Value: 1200},
+							HeaderConfig:      serial.ToTypedMessage(&noop.Config{}),
+							Fec:               &kcp.FEC{DataShards: 10, ParityShards: 3},
+							Congestion:        true,
+							CongestionControl: kcp.CongestionControl_BBR,
+							Stats:             true,
 						}),
 					},
 					{
 						ProtocolName: "websocket",
 						Settings: serial.ToTypedMessage(&websocket.Config{
-							Path: "/t",
+							Path:                "/t",
+							MaxEarlyData:        2048,
+							EarlyDataHeaderName: "Sec-WebSocket-Protocol",
+							Host:                []string{"a.v2ray.com", "b.v2ray.com"},
+							Fallback:            "http://127.0.0.1:8080",
+						}),
+					},
+					{
+						ProtocolName: "http",
+						Settings: serial.ToTypedMessage(&httptransport.Config{
+							Path:       "/h2",
+							Method:     "POST",
+							RandomPath: true,
+							Header: []*httptransport.Header{
+								{Key: "Accept", Value: "*/*"},
+								{Key: "Pragma", Value: "no-cache"},
+								{Key: "User-Agent", Value: "v2ray"},
+								{Key: "X-Forwarded-For", Value: "1.2.3.4"},
+							},
 						}),
 					},
 					{
 						ProtocolName: "quic",
 						Settings: serial.ToTypedMessage(&quic.Config{
-							Key: "abcd",
+							Key:                     "abcd",
+							IdleTimeout:             60,
+							MaxConcurrentStreams:    64,
+							StreamReceiveWindow:     1048576,
+							ConnectionReceiveWindow: 4194304,
+							ConnectionPoolSize:      4,
 							Security: &protocol.SecurityConfig{
 								Type: protocol.SecurityType_NONE,
 							},
 							Header: serial.ToTypedMessage(&tls.PacketConfig{}),
 						}),
 					},
+					{
+						ProtocolName: "grpc",
+						Settings: serial.ToTypedMessage(&grpc.Config{
+							ServiceName: "v2ray.Tunnel",
+						}),
+					},
 				},
 			},
 		},
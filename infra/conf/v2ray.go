@@ -15,6 +15,7 @@ var (
 	inboundConfigLoader = NewJSONConfigLoader(ConfigCreatorCache{
 		"dokodemo-door": func() interface{} { return new(DokodemoConfig) },
 		"http":          func() interface{} { return new(HttpServerConfig) },
+		"mixed":         func() interface{} { return new(MixedServerConfig) },
 		"shadowsocks":   func() interface{} { return new(ShadowsocksServerConfig) },
 		"socks":         func() interface{} { return new(SocksServerConfig) },
 		"vmess":         func() interface{} { return new(VMessInboundConfig) },
@@ -25,11 +26,13 @@ var (
 		"blackhole":   func() interface{} { return new(BlackholeConfig) },
 		"freedom":     func() interface{} { return new(FreedomConfig) },
 		"http":        func() interface{} { return new(HttpClientConfig) },
+		"loopback":    func() interface{} { return new(LoopbackConfig) },
 		"shadowsocks": func() interface{} { return new(ShadowsocksClientConfig) },
 		"vmess":       func() interface{} { return new(VMessOutboundConfig) },
 		"socks":       func() interface{} { return new(SocksClientConfig) },
 		"mtproto":     func() interface{} { return new(MTProtoClientConfig) },
 		"dns":         func() interface{} { return new(DnsOutboundConfig) },
+		"wireguard":   func() interface{} { return new(WireGuardConfig) },
 	}, "protocol", "settings")
 )
 
@@ -140,6 +143,7 @@ type InboundDetourConfig struct {
 	StreamSetting  *StreamConfig                  `json:"streamSettings"`
 	DomainOverride *StringList                    `json:"domainOverride"`
 	SniffingConfig *SniffingConfig                `json:"sniffing"`
+	ProxyProtocol  bool                           `json:"acceptProxyProtocol"`
 }
 
 // Build implements Buildable.
@@ -194,6 +198,7 @@ func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 		}
 		receiverSettings.DomainOverride = kp
 	}
+	receiverSettings.AcceptProxyProtocol = c.ProxyProtocol
 
 	settings := []byte("{}")
 	if c.Settings != nil {
@@ -218,14 +223,56 @@ func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 	}, nil
 }
 
+type SendThroughPoolConfig struct {
+	Addresses []*Address `json:"addresses"`
+	CIDR      []string   `json:"cidr"`
+	Strategy  string     `json:"strategy"`
+}
+
+// Build implements Buildable.
+func (c *SendThroughPoolConfig) Build() (*proxyman.SendThroughPool, error) {
+	config := new(proxyman.SendThroughPool)
+	switch strings.ToLower(c.Strategy) {
+	case "", "random":
+		config.Strategy = proxyman.SendThroughPool_Random
+	case "roundrobin":
+		config.Strategy = proxyman.SendThroughPool_RoundRobin
+	case "usersticky":
+		config.Strategy = proxyman.SendThroughPool_UserSticky
+	default:
+		return nil, newError("unknown send through strategy: ", c.Strategy)
+	}
+	for _, address := range c.Addresses {
+		if !address.Family().IsIP() {
+			return nil, newError("unable to send through: " + address.String())
+		}
+		config.Address = append(config.Address, address.Build())
+	}
+	for _, s := range c.CIDR {
+		cidr, err := ParseIP(s)
+		if err != nil {
+			return nil, newError("invalid CIDR to send through: ", s).Base(err)
+		}
+		config.Cidr = append(config.Cidr, &proxyman.CIDR{
+			Ip:     cidr.Ip,
+			Prefix: cidr.Prefix,
+		})
+	}
+	if len(config.Address) == 0 && len(config.Cidr) == 0 {
+		return nil, newError("empty send through pool")
+	}
+	return config, nil
+}
+
 type OutboundDetourConfig struct {
-	Protocol      string           `json:"protocol"`
-	SendThrough   *Address         `json:"sendThrough"`
-	Tag           string           `json:"tag"`
-	Settings      *json.RawMessage `json:"settings"`
-	StreamSetting *StreamConfig    `json:"streamSettings"`
-	ProxySettings *ProxyConfig     `json:"proxySettings"`
-	MuxSettings   *MuxConfig       `json:"mux"`
+	Protocol        string                 `json:"protocol"`
+	SendThrough     *Address               `json:"sendThrough"`
+	SendThroughPool *SendThroughPoolConfig `json:"sendThroughPool"`
+	Tag             string                 `json:"tag"`
+	Settings        *json.RawMessage       `json:"settings"`
+	StreamSetting   *StreamConfig          `json:"streamSettings"`
+	ProxySettings   *ProxyConfig           `json:"proxySettings"`
+	MuxSettings     *MuxConfig             `json:"mux"`
 }
 
 // Build implements Buildable.
@@ -240,6 +287,14 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 		senderSettings.Via = address.Build()
 	}
 
+	if c.SendThroughPool != nil {
+		pool, err := c.SendThroughPool.Build()
+		if err != nil {
+			return nil, err
+		}
+		senderSettings.ViaPool = pool
+	}
+
 	if c.StreamSetting != nil {
 		ss, err := c.StreamSetting.Build()
 		if err != nil {
@@ -369,3 +369,69 @@ func TestMuxConfig_Build(t *testing.T) {
 		})
 	}
 }
+
+func TestSendThroughPoolConfig_Build(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields string
+		want   *proxyman.SendThroughPool
+	}{
+		{"addresses", `{"addresses": ["127.0.0.2", "127.0.0.3"], "strategy": "roundRobin"}`, &proxyman.SendThroughPool{
+			Address: []*net.IPOrDomain{
+				net.NewIPOrDomain(net.IPAddress([]byte{127, 0, 0, 2})),
+				net.NewIPOrDomain(net.IPAddress([]byte{127, 0, 0, 3})),
+			},
+			Strategy: proxyman.SendThroughPool_RoundRobin,
+		}},
+		{"cidr", `{"cidr": ["2001:db8::/64"], "strategy": "userSticky"}`, &proxyman.SendThroughPool{
+			Cidr: []*proxyman.CIDR{
+				{
+					Ip:     []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+					Prefix: 64,
+				},
+			},
+			Strategy: proxyman.SendThroughPool_UserSticky,
+		}},
+		{"domain", `{"addresses": ["v2ray.com"]}`, nil},
+		{"empty", `{"strategy": "random"}`, nil},
+		{"unknown strategy", `{"addresses": ["127.0.0.2"], "strategy": "first"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &SendThroughPoolConfig{}
+			common.Must(json.Unmarshal([]byte(tt.fields), c))
+			got, err := c.Build()
+			if tt.want == nil {
+				if err == nil {
+					t.Error("expect error, but got ", got)
+				}
+				return
+			}
+			common.Must(err)
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("SendThroughPoolConfig.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInboundDetourConfigProxyProtocol(t *testing.T) {
+	c := &InboundDetourConfig{}
+	common.Must(json.Unmarshal([]byte(`{
+		"protocol": "dokodemo-door",
+		"port": 1080,
+		"acceptProxyProtocol": true,
+		"settings": {
+			"address": "127.0.0.1",
+			"port": 80
+		}
+	}`), c))
+	config, err := c.Build()
+	common.Must(err)
+
+	receiverSettings, err := config.ReceiverSettings.GetInstance()
+	common.Must(err)
+	if !receiverSettings.(*proxyman.ReceiverConfig).AcceptProxyProtocol {
+		t.Error("acceptProxyProtocol is not set in receiver settings")
+	}
+}
@@ -14,9 +14,10 @@ import (
 )
 
 type VMessAccount struct {
-	ID       string `json:"id"`
-	AlterIds uint16 `json:"alterId"`
-	Security string `json:"security"`
+	ID         string `json:"id"`
+	AlterIds   uint16 `json:"alterId"`
+	Security   string `json:"security"`
+	AEADHeader bool   `json:"aeadHeader"`
 }
 
 // Build implements Buildable
@@ -40,6 +41,7 @@ func (a *VMessAccount) Build() *vmess.Account {
 		SecuritySettings: &protocol.SecurityConfig{
 			Type: st,
 		},
+		AeadHeader: a.AEADHeader,
 	}
 }
 
@@ -75,17 +77,20 @@ func (c *VMessDefaultConfig) Build() *inbound.DefaultConfig {
 }
 
 type VMessInboundConfig struct {
-	Users        []json.RawMessage   `json:"clients"`
-	Features     *FeaturesConfig     `json:"features"`
-	Defaults     *VMessDefaultConfig `json:"default"`
-	DetourConfig *VMessDetourConfig  `json:"detour"`
-	SecureOnly   bool                `json:"disableInsecureEncryption"`
+	Users         []json.RawMessage   `json:"clients"`
+	Features      *FeaturesConfig     `json:"features"`
+	Defaults      *VMessDefaultConfig `json:"default"`
+	DetourConfig  *VMessDetourConfig  `json:"detour"`
+	SecureOnly    bool                `json:"disableInsecureEncryption"`
+	Fallbacks     []*FallbackConfig   `json:"fallbacks"`
+	DisableLegacy bool                `json:"disableLegacyHeader"`
 }
 
 // Build implements Buildable
 func (c *VMessInboundConfig) Build() (proto.Message, error) {
 	config := &inbound.Config{
 		SecureEncryptionOnly: c.SecureOnly,
+		DisableLegacyHeader:  c.DisableLegacy,
 	}
 
 	if c.Defaults != nil {
@@ -98,6 +103,12 @@ func (c *VMessInboundConfig) Build() (proto.Message, error) {
 		config.Detour = c.Features.Detour.Build()
 	}
 
+	fallbacks, err := buildFallbacks(c.Fallbacks)
+	if err != nil {
+		return nil, newError("invalid VMess fallback").Base(err)
+	}
+	config.Fallback = fallbacks
+
 	config.User = make([]*protocol.User, len(c.Users))
 	for idx, rawData := range c.Users {
 		user := new(protocol.User)
@@ -7,6 +7,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
 	. "v2ray.com/core/infra/conf"
+	"v2ray.com/core/proxy/fallback"
 	"v2ray.com/core/proxy/vmess"
 	"v2ray.com/core/proxy/vmess/inbound"
 	"v2ray.com/core/proxy/vmess/outbound"
@@ -27,7 +28,8 @@ func TestVMessOutbound(t *testing.T) {
 						{
 							"id": "e641f5ad-9397-41e3-bf1a-e8740dfed019",
 							"email": "love@v2ray.com",
-							"level": 255
+							"level": 255,
+							"aeadHeader": true
 						}
 					]
 				}]
@@ -52,6 +54,7 @@ func TestVMessOutbound(t *testing.T) {
 									SecuritySettings: &protocol.SecurityConfig{
 										Type: protocol.SecurityType_AUTO,
 									},
+									AeadHeader: true,
 								}),
 							},
 						},
@@ -76,7 +79,9 @@ func TestVMessInbound(t *testing.T) {
 						"level": 0,
 						"alterId": 16,
 						"email": "love@v2ray.com",
-						"security": "aes-128-gcm"
+						"security": "aes-128-gcm",
+						"quota": 1073741824,
+						"expiry": 1893456000
 					}
 				],
 				"default": {
@@ -86,14 +91,28 @@ func TestVMessInbound(t *testing.T) {
 				"detour": {
 					"to": "tag_to_detour"
 				},
-				"disableInsecureEncryption": true
+				"disableInsecureEncryption": true,
+				"disableLegacyHeader": true,
+				"fallbacks": [
+					{
+						"alpn": "h2",
+						"dest": "/run/web.sock",
+						"type": "unix"
+					},
+					{
+						"path": "/api",
+						"dest": "127.0.0.1:8080"
+					}
+				]
 			}`,
 			Parser: loadJSON(creator),
 			Output: &inbound.Config{
 				User: []*protocol.User{
 					{
-						Level: 0,
-						Email: "love@v2ray.com",
+						Level:  0,
+						Email:  "love@v2ray.com",
+						Quota:  1073741824,
+						Expiry: 1893456000,
 						Account: serial.ToTypedMessage(&vmess.Account{
 							Id:      "27848739-7e62-4138-9fd3-098a63964b6b",
 							AlterId: 16,
@@ -111,6 +130,19 @@ func TestVMessInbound(t *testing.T) {
 					To: "tag_to_detour",
 				},
 				SecureEncryptionOnly: true,
+				DisableLegacyHeader:  true,
+				Fallback: []*fallback.Fallback{
+					{
+						Alpn: "h2",
+						Type: "unix",
+						Dest: "/run/web.sock",
+					},
+					{
+						Path: "/api",
+						Type: "tcp",
+						Dest: "127.0.0.1:8080",
+					},
+				},
 			},
 		},
 	})
@@ -0,0 +1,135 @@
+package conf
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy/wireguard"
+)
+
+type WireGuardPeerConfig struct {
+	PublicKey    string   `json:"publicKey"`
+	PreSharedKey string   `json:"preSharedKey"`
+	Endpoint     string   `json:"endpoint"`
+	KeepAlive    uint32   `json:"keepAlive"`
+	AllowedIPs   []string `json:"allowedIPs"`
+}
+
+func (c *WireGuardPeerConfig) Build() (*wireguard.PeerConfig, error) {
+	config := &wireguard.PeerConfig{
+		KeepAlive: c.KeepAlive,
+	}
+
+	var err error
+	if config.PublicKey, err = parseWireGuardKey(c.PublicKey); err != nil {
+		return nil, newError("invalid public key of WireGuard peer").Base(err)
+	}
+	if len(c.PreSharedKey) > 0 {
+		if config.PreSharedKey, err = parseWireGuardKey(c.PreSharedKey); err != nil {
+			return nil, newError("invalid pre-shared key of WireGuard peer").Base(err)
+		}
+	}
+
+	if len(c.Endpoint) > 0 {
+		host, port, err := net.SplitHostPort(c.Endpoint)
+		if err != nil {
+			return nil, newError("invalid endpoint of WireGuard peer: ", c.Endpoint).Base(err)
+		}
+		portValue, err := strconv.ParseUint(port, 10, 16)
+		if err != nil || portValue == 0 {
+			return nil, newError("invalid port of WireGuard peer: ", port)
+		}
+		config.Address = net.NewIPOrDomain(net.ParseAddress(host))
+		config.Port = uint32(portValue)
+	}
+
+	for _, ip := range c.AllowedIPs {
+		cidr, err := ParseIP(ip)
+		if err != nil {
+			return nil, newError("invalid allowed IPs of WireGuard peer").Base(err)
+		}
+		config.AllowedIps = append(config.AllowedIps, &wireguard.CIDR{
+			Ip:     cidr.Ip,
+			Prefix: cidr.Prefix,
+		})
+	}
+
+	return config, nil
+}
+
+type WireGuardConfig struct {
+	SecretKey string                 `json:"secretKey"`
+	Address   []string               `json:"address"`
+	Peers     []*WireGuardPeerConfig `json:"peers"`
+	MTU       uint32                 `json:"mtu"`
+	Reserved  []uint32               `json:"reserved"`
+	UserLevel uint32                 `json:"userLevel"`
+}
+
+func (c *WireGuardConfig) Build() (proto.Message, error) {
+	config := &wireguard.Config{
+		Mtu:       c.MTU,
+		UserLevel: c.UserLevel,
+	}
+
+	var err error
+	if config.SecretKey, err = parseWireGuardKey(c.SecretKey); err != nil {
+		return nil, newError("invalid secret key of WireGuard").Base(err)
+	}
+
+	if len(c.Address) == 0 {
+		return nil, newError("address of WireGuard is not specified")
+	}
+	for _, addr := range c.Address {
+		// Network mask of the interface address is irrelevant.
+		cidr, err := ParseIP(addr)
+		if err != nil {
+			return nil, newError("invalid address of WireGuard").Base(err)
+		}
+		config.Address = append(config.Address, cidr.Ip)
+	}
+
+	if len(c.Peers) == 0 {
+		return nil, newError("peers of WireGuard are not specified")
+	}
+	for _, peer := range c.Peers {
+		p, err := peer.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Peer = append(config.Peer, p)
+	}
+
+	if c.MTU != 0 && c.MTU < 1280 {
+		return nil, newError("MTU of WireGuard must be at least 1280")
+	}
+	if len(c.Reserved) > 3 {
+		return nil, newError("WireGuard supports at most 3 reserved bytes")
+	}
+	for _, b := range c.Reserved {
+		if b > 255 {
+			return nil, newError("invalid reserved byte of WireGuard: ", b)
+		}
+		config.Reserved = append(config.Reserved, byte(b))
+	}
+
+	return config, nil
+}
+
+// parseWireGuardKey decodes a key in base64, as used by wg(8), or in hex.
+func parseWireGuardKey(s string) ([]byte, error) {
+	if len(s) == 64 {
+		return hex.DecodeString(s)
+	}
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, newError("key length must be 32 bytes")
+	}
+	return key, nil
+}
@@ -0,0 +1,64 @@
+package conf_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"v2ray.com/core/common/net"
+	. "v2ray.com/core/infra/conf"
+	"v2ray.com/core/proxy/wireguard"
+)
+
+func TestWireGuardConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(WireGuardConfig)
+	}
+
+	secretKey := make([]byte, 32)
+	publicKey := make([]byte, 32)
+	for i := range secretKey {
+		secretKey[i] = byte(i)
+		publicKey[i] = byte(255 - i)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"secretKey": "` + base64.StdEncoding.EncodeToString(secretKey) + `",
+				"address": ["10.0.0.2/32", "fd00::2"],
+				"peers": [{
+					"publicKey": "` + base64.StdEncoding.EncodeToString(publicKey) + `",
+					"endpoint": "[2001:db8::1]:51820",
+					"keepAlive": 25,
+					"allowedIPs": ["0.0.0.0/0", "::/0"]
+				}],
+				"mtu": 1280,
+				"reserved": [1, 2, 3],
+				"userLevel": 1
+			}`,
+			Parser: loadJSON(creator),
+			Output: &wireguard.Config{
+				SecretKey: secretKey,
+				Address: [][]byte{
+					{10, 0, 0, 2},
+					net.ParseIP("fd00::2"),
+				},
+				Peer: []*wireguard.PeerConfig{
+					{
+						PublicKey: publicKey,
+						Address:   net.NewIPOrDomain(net.ParseAddress("2001:db8::1")),
+						Port:      51820,
+						KeepAlive: 25,
+						AllowedIps: []*wireguard.CIDR{
+							{Ip: []byte{0, 0, 0, 0}, Prefix: 0},
+							{Ip: make([]byte, 16), Prefix: 0},
+						},
+					},
+				},
+				Mtu:       1280,
+				Reserved:  []byte{1, 2, 3},
+				UserLevel: 1,
+			},
+		},
+	})
+}
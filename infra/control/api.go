@@ -12,6 +12,7 @@ import (
 	"google.golang.org/grpc"
 
 	logService "v2ray.com/core/app/log/command"
+	reverseService "v2ray.com/core/app/reverse/command"
 	statsService "v2ray.com/core/app/stats/command"
 	"v2ray.com/core/common"
 )
@@ -30,6 +31,7 @@ func (c *ApiCommand) Description() Description {
 			"Call an API in an V2Ray process.",
 			"The following methods are currently supported:",
 			"\tLoggerService.RestartLogger",
+			"\tReverseService.ListBridges",
 			"\tStatsService.GetStats",
 			"\tStatsService.QueryStats",
 			"API calls in this command have a timeout to the server of 3 seconds.",
@@ -38,6 +40,7 @@ func (c *ApiCommand) Description() Description {
 			"v2ctl api --server=127.0.0.1:8080 StatsService.QueryStats 'pattern: \"\" reset: false'",
 			"v2ctl api --server=127.0.0.1:8080 StatsService.GetStats 'name: \"inbound>>>statin>>>traffic>>>downlink\" reset: false'",
 			"v2ctl api --server=127.0.0.1:8080 StatsService.GetSysStats ''",
+			"v2ctl api --server=127.0.0.1:8080 ReverseService.ListBridges 'portal_tag: \"portal\"'",
 		},
 	}
 }
@@ -93,8 +96,9 @@ func getServiceMethod(s string) (string, string) {
 type serviceHandler func(ctx context.Context, conn *grpc.ClientConn, method string, request string) (string, error)
 
 var serivceHandlerMap = map[string]serviceHandler{
-	"statsservice":  callStatsService,
-	"loggerservice": callLogService,
+	"statsservice":   callStatsService,
+	"loggerservice":  callLogService,
+	"reverseservice": callReverseService,
 }
 
 func callLogService(ctx context.Context, conn *grpc.ClientConn, method string, request string) (string, error) {
@@ -116,6 +120,25 @@ func callLogService(ctx context.Context, conn *grpc.ClientConn, method string, r
 	}
 }
 
+func callReverseService(ctx context.Context, conn *grpc.ClientConn, method string, request string) (string, error) {
+	client := reverseService.NewReverseServiceClient(conn)
+
+	switch strings.ToLower(method) {
+	case "listbridges":
+		r := &reverseService.ListBridgesRequest{}
+		if err := proto.UnmarshalText(request, r); err != nil {
+			return "", err
+		}
+		resp, err := client.ListBridges(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		return proto.MarshalTextString(resp), nil
+	default:
+		return "", errors.New("Unknown method: " + method)
+	}
+}
+
 func callStatsService(ctx context.Context, conn *grpc.ClientConn, method string, request string) (string, error) {
 	client := statsService.NewStatsServiceClient(conn)
 
@@ -12,6 +12,7 @@ import (
 	_ "v2ray.com/core/app/commander"
 	_ "v2ray.com/core/app/log/command"
 	_ "v2ray.com/core/app/proxyman/command"
+	_ "v2ray.com/core/app/reverse/command"
 	_ "v2ray.com/core/app/stats/command"
 
 	// Other optional features.
@@ -28,14 +29,18 @@ import (
 	_ "v2ray.com/core/proxy/dokodemo"
 	_ "v2ray.com/core/proxy/freedom"
 	_ "v2ray.com/core/proxy/http"
+	_ "v2ray.com/core/proxy/loopback"
+	_ "v2ray.com/core/proxy/mixed"
 	_ "v2ray.com/core/proxy/mtproto"
 	_ "v2ray.com/core/proxy/shadowsocks"
 	_ "v2ray.com/core/proxy/socks"
 	_ "v2ray.com/core/proxy/vmess/inbound"
 	_ "v2ray.com/core/proxy/vmess/outbound"
+	_ "v2ray.com/core/proxy/wireguard"
 
 	// Transports
 	_ "v2ray.com/core/transport/internet/domainsocket"
+	_ "v2ray.com/core/transport/internet/grpc"
 	_ "v2ray.com/core/transport/internet/http"
 	_ "v2ray.com/core/transport/internet/kcp"
 	_ "v2ray.com/core/transport/internet/quic"
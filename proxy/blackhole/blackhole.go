@@ -10,14 +10,19 @@ import (
 	"context"
 	"time"
 
+	"v2ray.com/core"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/stats"
 	"v2ray.com/core/transport"
 	"v2ray.com/core/transport/internet"
 )
 
 // Handler is an outbound connection that silently swallow the entire payload.
 type Handler struct {
-	response ResponseConfig
+	response     ResponseConfig
+	statsManager stats.Manager
 }
 
 // New creates a new blackhole handler.
@@ -26,13 +31,65 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Handler{
+	h := &Handler{
 		response: response,
-	}, nil
+	}
+	if config.Stats {
+		if v := core.FromContext(ctx); v != nil {
+			h.statsManager = v.GetFeature(stats.ManagerType()).(stats.Manager)
+		}
+	}
+	return h, nil
+}
+
+// BlockedCounterName returns the name of the counter of connections blocked for the given inbound and routing rule.
+func BlockedCounterName(inboundTag string, ruleTag string) string {
+	return "inbound>>>" + inboundTag + ">>>blocked>>>" + ruleTag
+}
+
+func (h *Handler) countBlocked(ctx context.Context) {
+	var inboundTag, ruleTag string
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		inboundTag = inbound.Tag
+	}
+	if outbound := session.OutboundFromContext(ctx); outbound != nil {
+		ruleTag = outbound.RuleTag
+	}
+	c, err := stats.GetOrRegisterCounter(h.statsManager, BlockedCounterName(inboundTag, ruleTag))
+	if err != nil {
+		newError("failed to get blocked counter").Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		return
+	}
+	c.Add(1)
 }
 
 // Process implements OutboundHandler.Dispatch().
 func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer internet.Dialer) error {
+	if h.statsManager != nil {
+		h.countBlocked(ctx)
+	}
+
+	switch r := h.response.(type) {
+	case *ResetResponse:
+		if inbound := session.InboundFromContext(ctx); inbound != nil {
+			inbound.ResetOnClose = true
+		}
+	case *TarpitResponse:
+		delay := time.Duration(r.Delay) * time.Second
+		if delay == 0 {
+			delay = time.Minute
+		}
+		go buf.Copy(link.Reader, buf.Discard) // nolint: errcheck
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	case *DropResponse:
+		if err := buf.Copy(link.Reader, buf.Discard); err != nil {
+			newError("connection ends").Base(err).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+		}
+	}
+
 	nBytes := h.response.WriteTo(link.Writer)
 	if nBytes > 0 {
 		// Sleep a little here to make sure the response is sent to client.
@@ -3,10 +3,15 @@ package blackhole_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"v2ray.com/core"
+	"v2ray.com/core/app/stats"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/serial"
+	"v2ray.com/core/common/session"
+	feature_stats "v2ray.com/core/features/stats"
 	"v2ray.com/core/proxy/blackhole"
 	"v2ray.com/core/transport"
 	"v2ray.com/core/transport/pipe"
@@ -38,3 +43,65 @@ func TestBlackholeHTTPResponse(t *testing.T) {
 		t.Error("expect http response, but nothing")
 	}
 }
+
+func TestBlackholeReset(t *testing.T) {
+	handler, err := blackhole.New(context.Background(), &blackhole.Config{
+		Response: serial.ToTypedMessage(&blackhole.ResetResponse{}),
+	})
+	common.Must(err)
+
+	inbound := &session.Inbound{}
+	ctx := session.ContextWithInbound(context.Background(), inbound)
+	reader, writer := pipe.New(pipe.WithoutSizeLimit())
+	common.Must(handler.Process(ctx, &transport.Link{Reader: reader, Writer: writer}, nil))
+
+	if !inbound.ResetOnClose {
+		t.Error("inbound is not closed with reset")
+	}
+}
+
+func TestBlackholeTarpit(t *testing.T) {
+	handler, err := blackhole.New(context.Background(), &blackhole.Config{
+		Response: serial.ToTypedMessage(&blackhole.TarpitResponse{Delay: 1}),
+	})
+	common.Must(err)
+
+	uplinkReader, uplinkWriter := pipe.New(pipe.WithoutSizeLimit())
+	downlinkReader, downlinkWriter := pipe.New(pipe.WithoutSizeLimit())
+	common.Must(uplinkWriter.WriteMultiBuffer(buf.MergeBytes(nil, []byte("request"))))
+
+	start := time.Now()
+	common.Must(handler.Process(context.Background(), &transport.Link{Reader: uplinkReader, Writer: downlinkWriter}, nil))
+	if d := time.Since(start); d < time.Second {
+		t.Error("tarpit closes connection too early: ", d)
+	}
+	if _, err := downlinkReader.ReadMultiBuffer(); err == nil {
+		t.Error("expect connection closed without response")
+	}
+}
+
+func TestBlackholeStats(t *testing.T) {
+	server, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&stats.Config{}),
+		},
+	})
+	common.Must(err)
+
+	obj, err := core.CreateObject(server, &blackhole.Config{Stats: true})
+	common.Must(err)
+	handler := obj.(*blackhole.Handler)
+
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{Tag: "in"})
+	ctx = session.ContextWithOutbound(ctx, &session.Outbound{RuleTag: "ads"})
+	for i := 0; i < 2; i++ {
+		reader, writer := pipe.New(pipe.WithoutSizeLimit())
+		common.Must(handler.Process(ctx, &transport.Link{Reader: reader, Writer: writer}, nil))
+	}
+
+	statsManager := server.GetFeature(feature_stats.ManagerType()).(feature_stats.Manager)
+	counter := statsManager.GetCounter(blackhole.BlockedCounterName("in", "ads"))
+	if counter == nil || counter.Value() != 2 {
+		t.Error("unexpected blocked counter: ", counter)
+	}
+}
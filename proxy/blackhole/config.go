@@ -1,6 +1,9 @@
 package blackhole
 
 import (
+	"fmt"
+	"net/http"
+
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 )
@@ -25,14 +28,49 @@ type ResponseConfig interface {
 func (*NoneResponse) WriteTo(buf.Writer) int32 { return 0 }
 
 // WriteTo implements ResponseConfig.WriteTo().
-func (*HTTPResponse) WriteTo(writer buf.Writer) int32 {
+func (r *HTTPResponse) WriteTo(writer buf.Writer) int32 {
+	var mb buf.MultiBuffer
+	if r.StatusCode == 0 && len(r.Body) == 0 && len(r.ContentType) == 0 {
+		mb = buf.MergeBytes(mb, []byte(http403response))
+	} else {
+		statusCode := int(r.StatusCode)
+		if statusCode == 0 {
+			statusCode = http.StatusForbidden
+		}
+		header := fmt.Sprintf("HTTP/1.1 %d %s\r\nConnection: close\r\nCache-Control: max-age=3600, public\r\n", statusCode, http.StatusText(statusCode))
+		if len(r.ContentType) > 0 {
+			header += "Content-Type: " + r.ContentType + "\r\n"
+		}
+		header += fmt.Sprintf("Content-Length: %d\r\n\r\n", len(r.Body))
+		mb = buf.MergeBytes(mb, []byte(header+r.Body))
+	}
+	n := mb.Len()
+	writer.WriteMultiBuffer(mb)
+	return n
+}
+
+// WriteTo implements ResponseConfig.WriteTo().
+func (r *TLSAlertResponse) WriteTo(writer buf.Writer) int32 {
+	alert := byte(r.Alert)
+	if alert == 0 {
+		alert = 40 // handshake_failure
+	}
 	b := buf.New()
-	common.Must2(b.WriteString(http403response))
-	n := b.Len()
+	// Alert record of TLS 1.2 (also used by TLS 1.3 before handshake), level fatal.
+	common.Must2(b.Write([]byte{0x15, 0x03, 0x03, 0x00, 0x02, 0x02, alert}))
 	writer.WriteMultiBuffer(buf.MultiBuffer{b})
-	return n
+	return 7
 }
 
+// WriteTo implements ResponseConfig.WriteTo().
+func (*ResetResponse) WriteTo(buf.Writer) int32 { return 0 }
+
+// WriteTo implements ResponseConfig.WriteTo().
+func (*TarpitResponse) WriteTo(buf.Writer) int32 { return 0 }
+
+// WriteTo implements ResponseConfig.WriteTo().
+func (*DropResponse) WriteTo(buf.Writer) int32 { return 0 }
+
 // GetInternalResponse converts response settings from proto to internal data structure.
 func (c *Config) GetInternalResponse() (ResponseConfig, error) {
 	if c.GetResponse() == nil {
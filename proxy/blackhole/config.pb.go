@@ -50,6 +50,10 @@ func (m *NoneResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_NoneResponse proto.InternalMessageInfo
 
 type HTTPResponse struct {
+	// Status code of the response. 403 if not set.
+	StatusCode           uint32   `protobuf:"varint,1,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	ContentType          string   `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Body                 string   `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -80,18 +84,193 @@ func (m *HTTPResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_HTTPResponse proto.InternalMessageInfo
 
+func (m *HTTPResponse) GetStatusCode() uint32 {
+	if m != nil {
+		return m.StatusCode
+	}
+	return 0
+}
+
+func (m *HTTPResponse) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *HTTPResponse) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+// TLSAlertResponse sends a fatal TLS alert, so that TLS clients fail the
+// handshake at once.
+type TLSAlertResponse struct {
+	// Alert description, as defined in RFC 8446 section 6. 40
+	// (handshake_failure) if not set.
+	Alert                uint32   `protobuf:"varint,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TLSAlertResponse) Reset()         { *m = TLSAlertResponse{} }
+func (m *TLSAlertResponse) String() string { return proto.CompactTextString(m) }
+func (*TLSAlertResponse) ProtoMessage()    {}
+func (*TLSAlertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8c8b37c8ae1bdfea, []int{2}
+}
+
+func (m *TLSAlertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TLSAlertResponse.Unmarshal(m, b)
+}
+func (m *TLSAlertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TLSAlertResponse.Marshal(b, m, deterministic)
+}
+func (m *TLSAlertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TLSAlertResponse.Merge(m, src)
+}
+func (m *TLSAlertResponse) XXX_Size() int {
+	return xxx_messageInfo_TLSAlertResponse.Size(m)
+}
+func (m *TLSAlertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TLSAlertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TLSAlertResponse proto.InternalMessageInfo
+
+func (m *TLSAlertResponse) GetAlert() uint32 {
+	if m != nil {
+		return m.Alert
+	}
+	return 0
+}
+
+// ResetResponse closes the inbound TCP connection with a RST instead of a FIN.
+type ResetResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResetResponse) Reset()         { *m = ResetResponse{} }
+func (m *ResetResponse) String() string { return proto.CompactTextString(m) }
+func (*ResetResponse) ProtoMessage()    {}
+func (*ResetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8c8b37c8ae1bdfea, []int{3}
+}
+
+func (m *ResetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetResponse.Unmarshal(m, b)
+}
+func (m *ResetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetResponse.Marshal(b, m, deterministic)
+}
+func (m *ResetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetResponse.Merge(m, src)
+}
+func (m *ResetResponse) XXX_Size() int {
+	return xxx_messageInfo_ResetResponse.Size(m)
+}
+func (m *ResetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetResponse proto.InternalMessageInfo
+
+// TarpitResponse holds the connection open without responding, and closes it
+// after the delay.
+type TarpitResponse struct {
+	// Delay in seconds. 60 if not set.
+	Delay                uint32   `protobuf:"varint,1,opt,name=delay,proto3" json:"delay,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TarpitResponse) Reset()         { *m = TarpitResponse{} }
+func (m *TarpitResponse) String() string { return proto.CompactTextString(m) }
+func (*TarpitResponse) ProtoMessage()    {}
+func (*TarpitResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8c8b37c8ae1bdfea, []int{4}
+}
+
+func (m *TarpitResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TarpitResponse.Unmarshal(m, b)
+}
+func (m *TarpitResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TarpitResponse.Marshal(b, m, deterministic)
+}
+func (m *TarpitResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TarpitResponse.Merge(m, src)
+}
+func (m *TarpitResponse) XXX_Size() int {
+	return xxx_messageInfo_TarpitResponse.Size(m)
+}
+func (m *TarpitResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TarpitResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TarpitResponse proto.InternalMessageInfo
+
+func (m *TarpitResponse) GetDelay() uint32 {
+	if m != nil {
+		return m.Delay
+	}
+	return 0
+}
+
+// DropResponse discards the payload and never responds, until the connection
+// times out. For UDP, this looks like packets dropped by a firewall.
+type DropResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DropResponse) Reset()         { *m = DropResponse{} }
+func (m *DropResponse) String() string { return proto.CompactTextString(m) }
+func (*DropResponse) ProtoMessage()    {}
+func (*DropResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8c8b37c8ae1bdfea, []int{5}
+}
+
+func (m *DropResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropResponse.Unmarshal(m, b)
+}
+func (m *DropResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropResponse.Marshal(b, m, deterministic)
+}
+func (m *DropResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropResponse.Merge(m, src)
+}
+func (m *DropResponse) XXX_Size() int {
+	return xxx_messageInfo_DropResponse.Size(m)
+}
+func (m *DropResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DropResponse proto.InternalMessageInfo
+
 type Config struct {
-	Response             *serial.TypedMessage `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Response *serial.TypedMessage `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	// Whether to count blocked connections in the stats counter
+	// "inbound>>>[inbound tag]>>>blocked>>>[rule tag]". Rule tag is the tag of
+	// the routing rule that picks this outbound.
+	Stats                bool     `protobuf:"varint,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8c8b37c8ae1bdfea, []int{2}
+	return fileDescriptor_8c8b37c8ae1bdfea, []int{6}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -119,9 +298,20 @@ func (m *Config) GetResponse() *serial.TypedMessage {
 	return nil
 }
 
+func (m *Config) GetStats() bool {
+	if m != nil {
+		return m.Stats
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*NoneResponse)(nil), "v2ray.core.proxy.blackhole.NoneResponse")
 	proto.RegisterType((*HTTPResponse)(nil), "v2ray.core.proxy.blackhole.HTTPResponse")
+	proto.RegisterType((*TLSAlertResponse)(nil), "v2ray.core.proxy.blackhole.TLSAlertResponse")
+	proto.RegisterType((*ResetResponse)(nil), "v2ray.core.proxy.blackhole.ResetResponse")
+	proto.RegisterType((*TarpitResponse)(nil), "v2ray.core.proxy.blackhole.TarpitResponse")
+	proto.RegisterType((*DropResponse)(nil), "v2ray.core.proxy.blackhole.DropResponse")
 	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.blackhole.Config")
 }
 
@@ -130,19 +320,27 @@ func init() {
 }
 
 var fileDescriptor_8c8b37c8ae1bdfea = []byte{
-	// 217 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x2e, 0x33, 0x2a, 0x4a,
-	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x2f, 0x28, 0xca, 0xaf, 0xa8,
-	0xd4, 0x4f, 0xca, 0x49, 0x4c, 0xce, 0xce, 0xc8, 0xcf, 0x49, 0xd5, 0x4f, 0xce, 0xcf, 0x4b, 0xcb,
-	0x4c, 0xd7, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x92, 0x82, 0x29, 0x2e, 0x4a, 0xd5, 0x03, 0x2b,
-	0xd4, 0x83, 0x2b, 0x94, 0x32, 0x40, 0x33, 0x28, 0x39, 0x3f, 0x37, 0x37, 0x3f, 0x4f, 0xbf, 0x38,
-	0xb5, 0x28, 0x33, 0x31, 0x47, 0xbf, 0xa4, 0xb2, 0x20, 0x35, 0x25, 0x3e, 0x37, 0xb5, 0xb8, 0x38,
-	0x31, 0x3d, 0x15, 0x62, 0x9a, 0x12, 0x1f, 0x17, 0x8f, 0x5f, 0x7e, 0x5e, 0x6a, 0x50, 0x6a, 0x71,
-	0x41, 0x7e, 0x5e, 0x71, 0x2a, 0x88, 0xef, 0x11, 0x12, 0x12, 0x00, 0xe7, 0xfb, 0x70, 0xb1, 0x39,
-	0x83, 0x6d, 0x17, 0x72, 0xe2, 0xe2, 0x28, 0x82, 0x8a, 0x4a, 0x30, 0x2a, 0x30, 0x6a, 0x70, 0x1b,
-	0xa9, 0xe9, 0x21, 0x39, 0x05, 0x62, 0x95, 0x1e, 0xc4, 0x2a, 0xbd, 0x10, 0x90, 0x55, 0xbe, 0x10,
-	0x9b, 0x82, 0xe0, 0xfa, 0x9c, 0xbc, 0xb8, 0xe4, 0x92, 0xf3, 0x73, 0xf5, 0x70, 0xfb, 0x20, 0x80,
-	0x31, 0x8a, 0x13, 0xce, 0x59, 0xc5, 0x24, 0x15, 0x66, 0x14, 0x94, 0x58, 0xa9, 0xe7, 0x0c, 0x52,
-	0x19, 0x00, 0x56, 0xe9, 0x04, 0x93, 0x4c, 0x62, 0x03, 0x7b, 0xc0, 0x18, 0x10, 0x00, 0x00, 0xff,
-	0xff, 0xb6, 0x3c, 0xef, 0x4f, 0x3d, 0x01, 0x00, 0x00,
+	// 337 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x51, 0x4d, 0x4b, 0xeb, 0x40,
+	0x14, 0x25, 0x7d, 0xef, 0x95, 0xf6, 0xf6, 0xe3, 0x49, 0x70, 0x51, 0xba, 0xd0, 0x9a, 0x45, 0x29,
+	0x08, 0x13, 0xa9, 0xbf, 0xc0, 0xd6, 0x85, 0x88, 0x4a, 0x89, 0xc1, 0x85, 0x9b, 0x32, 0x99, 0xdc,
+	0xd6, 0x62, 0x92, 0x1b, 0x66, 0x46, 0x71, 0xfe, 0x92, 0xbf, 0x52, 0x32, 0xd3, 0x84, 0x2a, 0xb8,
+	0x9b, 0x73, 0xe6, 0x9c, 0x7b, 0x39, 0xe7, 0xc2, 0xf9, 0xfb, 0x5c, 0x72, 0xc3, 0x04, 0xe5, 0xa1,
+	0x20, 0x89, 0x61, 0x29, 0xe9, 0xc3, 0x84, 0x49, 0xc6, 0xc5, 0xeb, 0x0b, 0x65, 0x18, 0x0a, 0x2a,
+	0x36, 0xbb, 0x2d, 0x2b, 0x25, 0x69, 0xf2, 0xc7, 0xb5, 0x58, 0x22, 0xb3, 0x42, 0xd6, 0x08, 0xc7,
+	0x17, 0x3f, 0x06, 0x09, 0xca, 0x73, 0x2a, 0x42, 0x85, 0x72, 0xc7, 0xb3, 0x50, 0x9b, 0x12, 0xd3,
+	0x75, 0x8e, 0x4a, 0xf1, 0x2d, 0xba, 0x69, 0xc1, 0x10, 0xfa, 0x0f, 0x54, 0x60, 0x84, 0xaa, 0xa4,
+	0x42, 0x61, 0xb0, 0x81, 0xfe, 0x4d, 0x1c, 0xaf, 0x6a, 0xec, 0x9f, 0x42, 0x4f, 0x69, 0xae, 0xdf,
+	0xd4, 0x5a, 0x50, 0x8a, 0x23, 0x6f, 0xe2, 0xcd, 0x06, 0x11, 0x38, 0x6a, 0x49, 0x29, 0xfa, 0x67,
+	0xd0, 0x17, 0x54, 0x68, 0x2c, 0xf4, 0xba, 0x9a, 0x3f, 0x6a, 0x4d, 0xbc, 0x59, 0x37, 0xea, 0xed,
+	0xb9, 0xd8, 0x94, 0xe8, 0xfb, 0xf0, 0x37, 0xa1, 0xd4, 0x8c, 0xfe, 0xd8, 0x2f, 0xfb, 0x0e, 0x66,
+	0x70, 0x14, 0xdf, 0x3d, 0x5e, 0x65, 0x28, 0x75, 0xb3, 0xeb, 0x18, 0xfe, 0xf1, 0x8a, 0xd8, 0x6f,
+	0x71, 0x20, 0xf8, 0x0f, 0x83, 0x08, 0x15, 0x36, 0xb2, 0x60, 0x0a, 0xc3, 0x98, 0xcb, 0x72, 0xf7,
+	0xcd, 0x98, 0x62, 0xc6, 0x4d, 0x6d, 0xb4, 0xa0, 0x8a, 0x76, 0x2d, 0xa9, 0x6c, 0x7c, 0x09, 0xb4,
+	0x97, 0xb6, 0x48, 0x7f, 0x01, 0x1d, 0xb9, 0x67, 0xad, 0xa5, 0x37, 0x9f, 0xb2, 0x83, 0x56, 0x5d,
+	0x6b, 0xcc, 0xb5, 0xc6, 0xaa, 0x08, 0xe9, 0xbd, 0x2b, 0x2d, 0xea, 0xc8, 0x83, 0x9d, 0x55, 0x0b,
+	0xca, 0x06, 0xee, 0x44, 0x0e, 0x2c, 0x6e, 0xe1, 0x44, 0x50, 0xce, 0x7e, 0x3f, 0xd1, 0xca, 0x7b,
+	0xee, 0x36, 0xe0, 0xb3, 0x35, 0x7e, 0x9a, 0x47, 0xdc, 0xb0, 0x65, 0xa5, 0x5c, 0x59, 0xe5, 0xa2,
+	0xfe, 0x4c, 0xda, 0xf6, 0x42, 0x97, 0x5f, 0x03, 0x00, 0xa9, 0xc1, 0xa0, 0x8e, 0x1e, 0x02, 0x00,
+	0x00,
 }
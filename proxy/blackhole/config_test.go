@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	. "v2ray.com/core/proxy/blackhole"
@@ -23,3 +25,36 @@ func TestHTTPResponse(t *testing.T) {
 		t.Error("expected status code 403, but got ", response.StatusCode)
 	}
 }
+
+func TestCustomHTTPResponse(t *testing.T) {
+	buffer := buf.New()
+
+	httpResponse := &HTTPResponse{
+		StatusCode:  204,
+		ContentType: "text/plain",
+		Body:        "blocked",
+	}
+	httpResponse.WriteTo(buf.NewWriter(buffer))
+
+	reader := bufio.NewReader(buffer)
+	response, err := http.ReadResponse(reader, nil)
+	common.Must(err)
+	if response.StatusCode != 204 {
+		t.Error("expected status code 204, but got ", response.StatusCode)
+	}
+	if ct := response.Header.Get("Content-Type"); ct != "text/plain" {
+		t.Error("unexpected content type: ", ct)
+	}
+}
+
+func TestTLSAlertResponse(t *testing.T) {
+	buffer := buf.New()
+
+	alertResponse := new(TLSAlertResponse)
+	if n := alertResponse.WriteTo(buf.NewWriter(buffer)); n != 7 {
+		t.Error("unexpected response length: ", n)
+	}
+	if r := cmp.Diff(buffer.Bytes(), []byte{0x15, 0x03, 0x03, 0x00, 0x02, 0x02, 40}); r != "" {
+		t.Error(r)
+	}
+}
@@ -12,3 +12,32 @@ func (v *Config) GetPredefinedAddress() net.Address {
 	}
 	return addr
 }
+
+// GetTarget returns the target address and port for connections on the given listen port.
+// Returned address is nil if no mapping nor predefined address applies.
+func (v *Config) GetTarget(listenPort net.Port) (net.Address, net.Port) {
+	for _, m := range v.PortMap {
+		if m.Listen == nil || !m.Listen.Contains(listenPort) {
+			continue
+		}
+		address := m.Address.AsAddress()
+		if address == nil {
+			address = v.GetPredefinedAddress()
+		}
+		if m.Port == 0 {
+			return address, listenPort
+		}
+		return address, net.Port(m.Port + uint32(listenPort) - m.Listen.From)
+	}
+	return v.GetPredefinedAddress(), net.Port(v.Port)
+}
+
+func (m *PortMapping) validate() error {
+	if m.Listen == nil || m.Listen.From == 0 || m.Listen.From > m.Listen.To || m.Listen.To > 65535 {
+		return newError("invalid listen ports in port map: ", m.Listen)
+	}
+	if m.Port > 0 && m.Port+m.Listen.To-m.Listen.From > 65535 {
+		return newError("target ports out of range in port map: ", m.Port, " for listen ports ", m.Listen.From, "-", m.Listen.To)
+	}
+	return nil
+}
@@ -18,6 +18,66 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// PortMapping forwards connections on a range of listen ports to a target.
+type PortMapping struct {
+	// Listen ports that this mapping applies to.
+	Listen *net.PortRange `protobuf:"bytes,1,opt,name=listen,proto3" json:"listen,omitempty"`
+	// Target address. If not set, the address in Config is used.
+	Address *net.IPOrDomain `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// Target port of the first listen port. Following listen ports are mapped
+	// with the same offset. If 0, the listen port is used as target port.
+	Port                 uint32   `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PortMapping) Reset()         { *m = PortMapping{} }
+func (m *PortMapping) String() string { return proto.CompactTextString(m) }
+func (*PortMapping) ProtoMessage()    {}
+func (*PortMapping) Descriptor() ([]byte, []int) {
+	return fileDescriptor_de04411d7254f312, []int{0}
+}
+
+func (m *PortMapping) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PortMapping.Unmarshal(m, b)
+}
+func (m *PortMapping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PortMapping.Marshal(b, m, deterministic)
+}
+func (m *PortMapping) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PortMapping.Merge(m, src)
+}
+func (m *PortMapping) XXX_Size() int {
+	return xxx_messageInfo_PortMapping.Size(m)
+}
+func (m *PortMapping) XXX_DiscardUnknown() {
+	xxx_messageInfo_PortMapping.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PortMapping proto.InternalMessageInfo
+
+func (m *PortMapping) GetListen() *net.PortRange {
+	if m != nil {
+		return m.Listen
+	}
+	return nil
+}
+
+func (m *PortMapping) GetAddress() *net.IPOrDomain {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *PortMapping) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
 type Config struct {
 	Address *net.IPOrDomain `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
 	Port    uint32          `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
@@ -25,20 +85,23 @@ type Config struct {
 	// Deprecated. Use networks.
 	NetworkList *net.NetworkList `protobuf:"bytes,3,opt,name=network_list,json=networkList,proto3" json:"network_list,omitempty"` // Deprecated: Do not use.
 	// List of networks that the Dokodemo accepts.
-	Networks             []net.Network `protobuf:"varint,7,rep,packed,name=networks,proto3,enum=v2ray.core.common.net.Network" json:"networks,omitempty"`
-	Timeout              uint32        `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"` // Deprecated: Do not use.
-	FollowRedirect       bool          `protobuf:"varint,5,opt,name=follow_redirect,json=followRedirect,proto3" json:"follow_redirect,omitempty"`
-	UserLevel            uint32        `protobuf:"varint,6,opt,name=user_level,json=userLevel,proto3" json:"user_level,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Networks       []net.Network `protobuf:"varint,7,rep,packed,name=networks,proto3,enum=v2ray.core.common.net.Network" json:"networks,omitempty"`
+	Timeout        uint32        `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"` // Deprecated: Do not use.
+	FollowRedirect bool          `protobuf:"varint,5,opt,name=follow_redirect,json=followRedirect,proto3" json:"follow_redirect,omitempty"`
+	UserLevel      uint32        `protobuf:"varint,6,opt,name=user_level,json=userLevel,proto3" json:"user_level,omitempty"`
+	// Per listen port targets. The first mapping that matches the listen port
+	// is used. Connections on other ports go to address and port above.
+	PortMap              []*PortMapping `protobuf:"bytes,8,rep,name=port_map,json=portMap,proto3" json:"port_map,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_de04411d7254f312, []int{0}
+	return fileDescriptor_de04411d7254f312, []int{1}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -110,7 +173,15 @@ func (m *Config) GetUserLevel() uint32 {
 	return 0
 }
 
+func (m *Config) GetPortMap() []*PortMapping {
+	if m != nil {
+		return m.PortMap
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterType((*PortMapping)(nil), "v2ray.core.proxy.dokodemo.PortMapping")
 	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.dokodemo.Config")
 }
 
@@ -119,26 +190,31 @@ func init() {
 }
 
 var fileDescriptor_de04411d7254f312 = []byte{
-	// 331 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0x41, 0x4f, 0xea, 0x40,
-	0x10, 0xc7, 0xd3, 0xc2, 0x03, 0xde, 0xf2, 0x1e, 0x26, 0x7b, 0x5a, 0x8c, 0x98, 0xca, 0x85, 0xc6,
-	0xc3, 0x36, 0xa9, 0x37, 0xbd, 0x01, 0x89, 0x21, 0x21, 0x4a, 0xf6, 0xe0, 0xc1, 0x0b, 0xa9, 0xed,
-	0x60, 0x1a, 0xba, 0x3b, 0x64, 0xbb, 0x80, 0x7c, 0x25, 0x3f, 0x88, 0x9f, 0xcb, 0x74, 0xdb, 0xaa,
-	0x31, 0x81, 0xdb, 0xec, 0x7f, 0x7f, 0xfb, 0x9b, 0xd9, 0x0c, 0xb9, 0xde, 0x85, 0x3a, 0x3a, 0xf0,
-	0x18, 0x65, 0x10, 0xa3, 0x86, 0x60, 0xa3, 0xf1, 0xed, 0x10, 0x24, 0xb8, 0xc6, 0x04, 0x24, 0x06,
-	0x31, 0xaa, 0x55, 0xfa, 0xca, 0x37, 0x1a, 0x0d, 0xd2, 0x7e, 0xcd, 0x6a, 0xe0, 0x96, 0xe3, 0x35,
-	0x77, 0x3e, 0xfa, 0xa5, 0x89, 0x51, 0x4a, 0x54, 0x81, 0x02, 0x13, 0x44, 0x49, 0xa2, 0x21, 0xcf,
-	0x4b, 0xc7, 0x29, 0x50, 0x81, 0xd9, 0xa3, 0x5e, 0x97, 0xe0, 0xf0, 0xc3, 0x25, 0xad, 0x89, 0xed,
-	0x4e, 0xef, 0x48, 0xbb, 0x92, 0x30, 0xc7, 0x73, 0xfc, 0x6e, 0x78, 0xc5, 0x7f, 0x4c, 0x52, 0x1a,
-	0xb8, 0x02, 0xc3, 0x67, 0x8b, 0x47, 0x3d, 0x45, 0x19, 0xa5, 0x4a, 0xd4, 0x2f, 0x28, 0x25, 0xcd,
-	0x0d, 0x6a, 0xc3, 0x5c, 0xcf, 0xf1, 0xff, 0x0b, 0x5b, 0xd3, 0x19, 0xf9, 0x57, 0x35, 0x5b, 0x66,
-	0x69, 0x6e, 0x58, 0xc3, 0x5a, 0x87, 0x47, 0xac, 0x0f, 0x25, 0x3a, 0x4f, 0x73, 0x33, 0x76, 0x99,
-	0x23, 0xba, 0xea, 0x3b, 0xa0, 0xb7, 0xa4, 0x53, 0x1d, 0x73, 0xd6, 0xf6, 0x1a, 0x7e, 0x2f, 0xbc,
-	0x3c, 0xad, 0x11, 0x5f, 0x3c, 0xbd, 0x20, 0x6d, 0x93, 0x4a, 0xc0, 0xad, 0x61, 0xcd, 0x62, 0x3a,
-	0x6b, 0xaf, 0x23, 0x3a, 0x22, 0x67, 0x2b, 0xcc, 0x32, 0xdc, 0x2f, 0x35, 0x24, 0xa9, 0x86, 0xd8,
-	0xb0, 0x3f, 0x9e, 0xe3, 0x77, 0x44, 0xaf, 0x8c, 0x45, 0x95, 0xd2, 0x01, 0x21, 0xdb, 0x1c, 0xf4,
-	0x32, 0x83, 0x1d, 0x64, 0xac, 0x65, 0xff, 0xf9, 0xb7, 0x48, 0xe6, 0x45, 0x30, 0xbe, 0x27, 0x83,
-	0x18, 0x25, 0x3f, 0xba, 0xbb, 0x85, 0xf3, 0xdc, 0xa9, 0xeb, 0x77, 0xb7, 0xff, 0x14, 0x8a, 0xe8,
-	0xc0, 0x27, 0x05, 0xb7, 0xb0, 0xdc, 0xb4, 0xba, 0x7b, 0x69, 0xd9, 0xc5, 0xdc, 0x7c, 0x06, 0x00,
-	0x00, 0xff, 0xff, 0xec, 0xf1, 0xbb, 0x96, 0x33, 0x02, 0x00, 0x00,
+	// 406 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xc1, 0x8a, 0xd5, 0x30,
+	0x18, 0x85, 0x49, 0x3b, 0xb6, 0x35, 0xd5, 0x11, 0xb2, 0xca, 0x88, 0x23, 0xf5, 0x22, 0x4e, 0x71,
+	0x91, 0x42, 0xdd, 0x88, 0xae, 0x9c, 0x19, 0x90, 0x81, 0x51, 0x4b, 0x16, 0x2e, 0xdc, 0x94, 0xda,
+	0x66, 0x2e, 0x65, 0x9a, 0xfc, 0x25, 0xcd, 0xcc, 0xf5, 0x3e, 0x82, 0x6f, 0xe0, 0x33, 0xf8, 0x94,
+	0x92, 0xb4, 0xf5, 0x5e, 0x84, 0xde, 0x85, 0xbb, 0xe4, 0xf4, 0x3b, 0xa7, 0x27, 0x3f, 0x3f, 0x7e,
+	0x7d, 0x9f, 0xeb, 0x6a, 0xcb, 0x6a, 0x90, 0x59, 0x0d, 0x5a, 0x64, 0xbd, 0x86, 0x1f, 0xdb, 0xac,
+	0x81, 0x5b, 0x68, 0x84, 0x84, 0xac, 0x06, 0x75, 0xd3, 0xae, 0x59, 0xaf, 0xc1, 0x00, 0x39, 0x99,
+	0x59, 0x2d, 0x98, 0xe3, 0xd8, 0xcc, 0x3d, 0x3d, 0xfb, 0x27, 0xa6, 0x06, 0x29, 0x41, 0x65, 0x4a,
+	0x98, 0xac, 0x6a, 0x1a, 0x2d, 0x86, 0x61, 0xcc, 0x38, 0x04, 0x2a, 0x61, 0x36, 0xa0, 0x6f, 0x27,
+	0xf0, 0xe5, 0x32, 0xd8, 0x83, 0x36, 0x23, 0xb5, 0xfa, 0x85, 0x70, 0x5c, 0x80, 0x36, 0x9f, 0xaa,
+	0xbe, 0x6f, 0xd5, 0x9a, 0xbc, 0xc5, 0x41, 0xd7, 0x0e, 0x46, 0x28, 0x8a, 0x12, 0x94, 0xc6, 0x79,
+	0xc2, 0xf6, 0x3a, 0x8f, 0x11, 0x4c, 0x09, 0xc3, 0xac, 0x87, 0x57, 0x6a, 0x2d, 0xf8, 0xc4, 0x93,
+	0xf7, 0x38, 0x9c, 0x9a, 0x52, 0xcf, 0x59, 0x5f, 0x2c, 0x58, 0xaf, 0x8a, 0x2f, 0xfa, 0x12, 0x64,
+	0xd5, 0x2a, 0x3e, 0x3b, 0x08, 0xc1, 0x47, 0xb6, 0x14, 0xf5, 0x13, 0x94, 0x3e, 0xe6, 0xee, 0xbc,
+	0xfa, 0xe9, 0xe3, 0xe0, 0xc2, 0x8d, 0x6f, 0x3f, 0x1b, 0xfd, 0x77, 0xb6, 0xb7, 0xcb, 0x26, 0x57,
+	0xf8, 0xd1, 0x34, 0xad, 0xd2, 0xd6, 0x77, 0xff, 0x8d, 0xf3, 0xd5, 0x42, 0xea, 0xe7, 0x11, 0xbd,
+	0x6e, 0x07, 0x73, 0xee, 0x51, 0xc4, 0x63, 0xb5, 0x13, 0xc8, 0x3b, 0x1c, 0x4d, 0xd7, 0x81, 0x86,
+	0x89, 0x9f, 0x1e, 0xe7, 0xcf, 0x0f, 0xc7, 0xf0, 0xbf, 0x3c, 0x79, 0x86, 0x43, 0xd3, 0x4a, 0x01,
+	0x77, 0x86, 0x1e, 0xd9, 0x76, 0x2e, 0x7d, 0x96, 0xc8, 0x19, 0x7e, 0x72, 0x03, 0x5d, 0x07, 0x9b,
+	0x52, 0x8b, 0xa6, 0xd5, 0xa2, 0x36, 0xf4, 0x41, 0x82, 0xd2, 0x88, 0x1f, 0x8f, 0x32, 0x9f, 0x54,
+	0x72, 0x8a, 0xf1, 0xdd, 0x20, 0x74, 0xd9, 0x89, 0x7b, 0xd1, 0xd1, 0xc0, 0xbd, 0xf3, 0xa1, 0x55,
+	0xae, 0xad, 0x40, 0x3e, 0xe0, 0xc8, 0x3e, 0xba, 0x94, 0x55, 0x4f, 0xa3, 0xc4, 0x4f, 0xe3, 0xfc,
+	0x15, 0x5b, 0xdc, 0x44, 0xb6, 0xb7, 0x0d, 0x3c, 0xec, 0xc7, 0xcb, 0xf9, 0x47, 0x7c, 0x5a, 0x83,
+	0x5c, 0x76, 0x15, 0xe8, 0x5b, 0x34, 0x9f, 0x7f, 0x7b, 0x27, 0x5f, 0x73, 0x5e, 0x6d, 0xd9, 0x85,
+	0xe5, 0x0a, 0xc7, 0x5d, 0x4e, 0xdf, 0xbe, 0x07, 0x6e, 0xed, 0xde, 0xfc, 0x19, 0x00, 0x8e, 0x42,
+	0xdd, 0x98, 0x37, 0x03, 0x00, 0x00,
 }
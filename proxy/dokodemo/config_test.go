@@ -0,0 +1,42 @@
+package dokodemo_test
+
+import (
+	"testing"
+
+	"v2ray.com/core/common/net"
+	. "v2ray.com/core/proxy/dokodemo"
+)
+
+func TestConfigGetTarget(t *testing.T) {
+	config := &Config{
+		Address: net.NewIPOrDomain(net.LocalHostIP),
+		Port:    80,
+		PortMap: []*PortMapping{
+			{
+				Listen:  &net.PortRange{From: 5000, To: 5009},
+				Address: net.NewIPOrDomain(net.DomainAddress("v2ray.com")),
+				Port:    6000,
+			},
+			{
+				Listen: &net.PortRange{From: 7000, To: 7010},
+			},
+		},
+	}
+
+	testCases := []struct {
+		listen  net.Port
+		address net.Address
+		port    net.Port
+	}{
+		{listen: 5000, address: net.DomainAddress("v2ray.com"), port: 6000},
+		{listen: 5009, address: net.DomainAddress("v2ray.com"), port: 6009},
+		{listen: 7005, address: net.LocalHostIP, port: 7005},
+		{listen: 8000, address: net.LocalHostIP, port: 80},
+	}
+	for _, tc := range testCases {
+		address, port := config.GetTarget(tc.listen)
+		if address != tc.address || port != tc.port {
+			t.Error("listen port ", tc.listen, ": got ", address, ":", port, ", want ", tc.address, ":", tc.port)
+		}
+	}
+}
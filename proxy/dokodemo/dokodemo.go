@@ -44,6 +44,11 @@ func (d *DokodemoDoor) Init(config *Config, pm policy.Manager) error {
 	if (config.NetworkList == nil || len(config.NetworkList.Network) == 0) && len(config.Networks) == 0 {
 		return newError("no network specified")
 	}
+	for _, m := range config.PortMap {
+		if err := m.validate(); err != nil {
+			return err
+		}
+	}
 	d.config = config
 	d.address = config.GetPredefinedAddress()
 	d.port = net.Port(config.Port)
@@ -82,6 +87,11 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 		Address: d.address,
 		Port:    d.port,
 	}
+	if len(d.config.PortMap) > 0 {
+		if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.Gateway.Port != 0 {
+			dest.Address, dest.Port = d.config.GetTarget(inbound.Gateway.Port)
+		}
+	}
 
 	destinationOverridden := false
 	if d.config.FollowRedirect {
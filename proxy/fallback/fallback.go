@@ -0,0 +1,5 @@
+// Package fallback forwards connections that fail the protocol handshake of an inbound to another destination,
+// so that the inbound can't be distinguished from the real service behind it.
+package fallback
+
+//go:generate errorgen
@@ -0,0 +1,60 @@
+package fallback_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	. "v2ray.com/core/proxy/fallback"
+)
+
+func TestRecorder(t *testing.T) {
+	content := []byte("GET /path HTTP/1.1\r\nHost: example.com\r\n\r\n")
+	recorder := NewRecorder(bytes.NewReader(content))
+
+	b := make([]byte, 10)
+	common.Must2(io.ReadFull(recorder, b))
+
+	mb := recorder.Content()
+	defer buf.ReleaseMulti(mb)
+	if mb.String() != string(content[:10]) {
+		t.Error("unexpected recorded content: ", mb.String())
+	}
+
+	common.Must2(io.ReadFull(recorder, b))
+	if mb.Len() != 10 {
+		t.Error("recorder should stop after content is taken")
+	}
+}
+
+func TestPick(t *testing.T) {
+	fallbacks := []*Fallback{
+		{Alpn: "h2", Dest: "h2"},
+		{Path: "/ws", Dest: "ws"},
+		{Dest: "default"},
+	}
+
+	testCases := []struct {
+		alpn string
+		path string
+		dest string
+	}{
+		{alpn: "h2", path: "/ws", dest: "h2"},
+		{alpn: "http/1.1", path: "/ws/abc", dest: "ws"},
+		{alpn: "", path: "/", dest: "default"},
+		{alpn: "", path: "", dest: "default"},
+	}
+
+	for _, tc := range testCases {
+		f := Pick(fallbacks, tc.alpn, tc.path)
+		if f == nil || f.Dest != tc.dest {
+			t.Error("expect ", tc.dest, " for (", tc.alpn, ", ", tc.path, "), but got ", f)
+		}
+	}
+
+	if f := Pick(fallbacks[:2], "", "/"); f != nil {
+		t.Error("expect no fallback, but got ", f)
+	}
+}
@@ -0,0 +1,46 @@
+// +build !confonly
+
+package fallback
+
+import (
+	"io"
+
+	"v2ray.com/core/common/buf"
+)
+
+// Recorder is an io.Reader that keeps a copy of all content read through it, until Stop() is called.
+type Recorder struct {
+	reader  io.Reader
+	content buf.MultiBuffer
+	stopped bool
+}
+
+// NewRecorder creates a new Recorder on the given reader.
+func NewRecorder(reader io.Reader) *Recorder {
+	return &Recorder{
+		reader: reader,
+	}
+}
+
+// Read implements io.Reader.
+func (r *Recorder) Read(b []byte) (int, error) {
+	n, err := r.reader.Read(b)
+	if n > 0 && !r.stopped {
+		r.content = buf.MergeBytes(r.content, b[:n])
+	}
+	return n, err
+}
+
+// Stop stops recording and releases all recorded content.
+func (r *Recorder) Stop() {
+	r.stopped = true
+	r.content = buf.ReleaseMulti(r.content)
+}
+
+// Content stops recording and returns all content read so far. The caller takes ownership of the returned MultiBuffer.
+func (r *Recorder) Content() buf.MultiBuffer {
+	r.stopped = true
+	mb := r.content
+	r.content = nil
+	return mb
+}
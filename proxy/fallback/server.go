@@ -0,0 +1,123 @@
+// +build !confonly
+
+package fallback
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"strings"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/transport/internet"
+)
+
+type tlsConnection interface {
+	ConnectionState() tls.ConnectionState
+}
+
+func negotiatedProtocol(conn internet.Connection) string {
+	if statConn, ok := conn.(*internet.StatCouterConnection); ok {
+		conn = statConn.Connection
+	}
+	if tlsConn, ok := conn.(tlsConnection); ok {
+		return tlsConn.ConnectionState().NegotiatedProtocol
+	}
+	return ""
+}
+
+// requestPath returns the path in the request line, if the content starts with an HTTP request.
+func requestPath(mb buf.MultiBuffer) string {
+	if mb.IsEmpty() {
+		return ""
+	}
+	b := mb[0].Bytes()
+	if idx := bytes.IndexByte(b, '\n'); idx >= 0 {
+		b = b[:idx]
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) != 3 || !strings.HasPrefix(fields[2], "HTTP/") || !strings.HasPrefix(fields[1], "/") {
+		return ""
+	}
+	return fields[1]
+}
+
+// Pick returns the first Fallback that matches the given ALPN and HTTP path, or nil if none matches.
+func Pick(fallbacks []*Fallback, alpn string, path string) *Fallback {
+	for _, f := range fallbacks {
+		if len(f.Alpn) > 0 && f.Alpn != alpn {
+			continue
+		}
+		if len(f.Path) > 0 && !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		return f
+	}
+	return nil
+}
+
+func (f *Fallback) network() string {
+	if len(f.Type) == 0 {
+		return "tcp"
+	}
+	return f.Type
+}
+
+// Serve forwards the content recorded during handshake, as well as the rest of the connection, to a matching Fallback.
+func Serve(ctx context.Context, fallbacks []*Fallback, conn internet.Connection, recorder *Recorder, sessionPolicy policy.Session) error {
+	content := recorder.Content()
+	f := Pick(fallbacks, negotiatedProtocol(conn), requestPath(content))
+	if f == nil {
+		buf.ReleaseMulti(content)
+		return newError("no fallback matches connection from ", conn.RemoteAddr())
+	}
+
+	newError("fallback to ", f.network(), ":", f.Dest).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+
+	var dialer net.Dialer
+	target, err := dialer.DialContext(ctx, f.network(), f.Dest)
+	if err != nil {
+		buf.ReleaseMulti(content)
+		return newError("failed to dial fallback ", f.Dest).Base(err)
+	}
+	defer target.Close() // nolint: errcheck
+
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
+
+	requestDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
+
+		writer := buf.NewWriter(target)
+		if err := writer.WriteMultiBuffer(content); err != nil {
+			return newError("failed to write handshake content to fallback").Base(err)
+		}
+		if err := buf.Copy(buf.NewReader(conn), writer, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transport request to fallback").Base(err)
+		}
+		if cw, ok := target.(interface{ CloseWrite() error }); ok {
+			cw.CloseWrite() // nolint: errcheck
+		}
+		return nil
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
+
+		if err := buf.Copy(buf.NewReader(target), buf.NewWriter(conn), buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transport response from fallback").Base(err)
+		}
+		return nil
+	}
+
+	if err := task.Run(ctx, requestDone, responseDone); err != nil {
+		return newError("fallback connection ends").Base(err)
+	}
+
+	return nil
+}
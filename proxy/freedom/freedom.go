@@ -13,6 +13,7 @@ import (
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/dice"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/proxyproto"
 	"v2ray.com/core/common/retry"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/common/signal"
@@ -59,7 +60,7 @@ func (h *Handler) policy() policy.Session {
 	return p
 }
 
-func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Address) net.Address {
+func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Address) []net.IP {
 	var lookupFunc func(string) ([]net.IP, error) = h.dns.LookupIP
 
 	if h.config.DomainStrategy == Config_USE_IP4 || (localAddr != nil && localAddr.Family().IsIPv4()) {
@@ -76,10 +77,7 @@ func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Ad
 	if err != nil {
 		newError("failed to get IP address for domain ", domain).Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
-	if len(ips) == 0 {
-		return nil
-	}
-	return net.IPAddress(ips[dice.Roll(len(ips))])
+	return ips
 }
 
 func isValidAddress(addr *net.IPOrDomain) bool {
@@ -114,20 +112,26 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 
 	var conn internet.Connection
 	err := retry.ExponentialBackoff(5, 100).On(func() error {
+		dialCtx := ctx
 		dialDest := destination
 		if h.config.useIP() && dialDest.Address.Family().IsDomain() {
-			ip := h.resolveIP(ctx, dialDest.Address.Domain(), dialer.Address())
-			if ip != nil {
+			ips := h.resolveIP(ctx, dialDest.Address.Domain(), dialer.Address())
+			if len(ips) > 0 {
 				dialDest = net.Destination{
 					Network: dialDest.Network,
-					Address: ip,
+					Address: net.IPAddress(ips[dice.Roll(len(ips))]),
 					Port:    dialDest.Port,
 				}
 				newError("dialing to to ", dialDest).WriteToLog(session.ExportIDToError(ctx))
+
+				// Let the system dialer race across all the IPs if happy eyeballs is enabled.
+				dialOutbound := *outbound
+				dialOutbound.ResolvedIPs = ips
+				dialCtx = session.ContextWithOutbound(ctx, &dialOutbound)
 			}
 		}
 
-		rawConn, err := dialer.Dial(ctx, dialDest)
+		rawConn, err := dialer.Dial(dialCtx, dialDest)
 		if err != nil {
 			return err
 		}
@@ -139,6 +143,16 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 	}
 	defer conn.Close() // nolint: errcheck
 
+	if h.config.ProxyProtocol > 0 && destination.Network == net.Network_TCP {
+		var source net.Destination
+		if inbound := session.InboundFromContext(ctx); inbound != nil {
+			source = inbound.Source
+		}
+		if err := proxyproto.WriteHeader(conn, byte(h.config.ProxyProtocol), source, net.DestinationFromAddr(conn.RemoteAddr())); err != nil {
+			return newError("failed to write PROXY protocol header").Base(err)
+		}
+	}
+
 	plcy := h.policy()
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, plcy.Timeouts.ConnectionIdle)
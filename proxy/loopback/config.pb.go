@@ -0,0 +1,81 @@
+package loopback
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Config struct {
+	// Tag of the inbound that connections are dispatched from, when looping
+	// back into routing.
+	InboundTag           string   `protobuf:"bytes,1,opt,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Config) Reset()         { *m = Config{} }
+func (m *Config) String() string { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()    {}
+func (*Config) Descriptor() ([]byte, []int) {
+	return fileDescriptor_63e1be9bc724d93c, []int{0}
+}
+
+func (m *Config) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Config.Unmarshal(m, b)
+}
+func (m *Config) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Config.Marshal(b, m, deterministic)
+}
+func (m *Config) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Config.Merge(m, src)
+}
+func (m *Config) XXX_Size() int {
+	return xxx_messageInfo_Config.Size(m)
+}
+func (m *Config) XXX_DiscardUnknown() {
+	xxx_messageInfo_Config.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Config proto.InternalMessageInfo
+
+func (m *Config) GetInboundTag() string {
+	if m != nil {
+		return m.InboundTag
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.loopback.Config")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/proxy/loopback/config.proto", fileDescriptor_63e1be9bc724d93c)
+}
+
+var fileDescriptor_63e1be9bc724d93c = []byte{
+	// 151 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x2a, 0x33, 0x2a, 0x4a,
+	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x2f, 0x28, 0xca, 0xaf, 0xa8,
+	0xd4, 0xcf, 0xc9, 0xcf, 0x2f, 0x48, 0x4a, 0x4c, 0xce, 0xd6, 0x4f, 0xce, 0xcf, 0x4b, 0xcb, 0x4c,
+	0xd7, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x92, 0x84, 0xa9, 0x2d, 0x4a, 0xd5, 0x03, 0xab, 0xd3,
+	0x83, 0xa9, 0x53, 0xd2, 0xe4, 0x62, 0x73, 0x06, 0x2b, 0x15, 0x92, 0xe7, 0xe2, 0xce, 0xcc, 0x4b,
+	0xca, 0x2f, 0xcd, 0x4b, 0x89, 0x2f, 0x49, 0x4c, 0x97, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0xe2,
+	0x82, 0x0a, 0x85, 0x24, 0xa6, 0x3b, 0xb9, 0x73, 0xc9, 0x26, 0xe7, 0xe7, 0xea, 0xe1, 0x34, 0x2b,
+	0x80, 0x31, 0x8a, 0x03, 0xc6, 0x5e, 0xc5, 0x24, 0x19, 0x66, 0x14, 0x94, 0x58, 0xa9, 0xe7, 0x0c,
+	0x52, 0x17, 0x00, 0x56, 0xe7, 0x03, 0x95, 0x4b, 0x62, 0x03, 0xbb, 0xca, 0x18, 0x30, 0x00, 0xb0,
+	0x6e, 0x97, 0x78, 0xc3, 0x00, 0x00, 0x00,
+}
@@ -0,0 +1,101 @@
+// +build !confonly
+
+// Package loopback is an outbound handler that dispatches connections back into routing, as if they come from another inbound.
+package loopback
+
+//go:generate errorgen
+
+import (
+	"context"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/transport"
+	"v2ray.com/core/transport/internet"
+)
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		h := new(Handler)
+		if err := core.RequireFeatures(ctx, func(d routing.Dispatcher) error {
+			return h.Init(config.(*Config), d)
+		}); err != nil {
+			return nil, err
+		}
+		return h, nil
+	}))
+}
+
+// Handler is an outbound connection handler that loops connections back into routing.
+type Handler struct {
+	inboundTag string
+	dispatcher routing.Dispatcher
+}
+
+// Init initializes the Handler with necessary parameters.
+func (h *Handler) Init(config *Config, d routing.Dispatcher) error {
+	h.inboundTag = config.InboundTag
+	h.dispatcher = d
+	return nil
+}
+
+// Process implements proxy.Outbound.
+func (h *Handler) Process(ctx context.Context, link *transport.Link, _ internet.Dialer) error {
+	outbound := session.OutboundFromContext(ctx)
+	if outbound == nil || !outbound.Target.IsValid() {
+		return newError("target not specified.")
+	}
+	destination := outbound.Target
+
+	newError("looping back to inbound [", h.inboundTag, "] for ", destination).WriteToLog(session.ExportIDToError(ctx))
+
+	inbound := &session.Inbound{
+		Tag:      h.inboundTag,
+		Loopback: true,
+	}
+	if original := session.InboundFromContext(ctx); original != nil {
+		inbound.Source = original.Source
+		inbound.Gateway = original.Gateway
+		inbound.User = original.User
+	}
+	ctx = session.ContextWithInbound(ctx, inbound)
+
+	// Keep the sniffed protocol and attributes, but don't sniff again, as the destination has already been overridden if necessary.
+	if content := session.ContentFromContext(ctx); content != nil {
+		ctx = session.ContextWithContent(ctx, &session.Content{
+			Protocol:   content.Protocol,
+			Attributes: content.Attributes,
+		})
+	}
+
+	loopLink, err := h.dispatcher.Dispatch(ctx, destination)
+	if err != nil {
+		return newError("failed to dispatch to ", destination).Base(err)
+	}
+
+	requestDone := func() error {
+		if err := buf.Copy(link.Reader, loopLink.Writer); err != nil {
+			return newError("failed to loop back request").Base(err)
+		}
+		return nil
+	}
+
+	responseDone := func() error {
+		if err := buf.Copy(loopLink.Reader, link.Writer); err != nil {
+			return newError("failed to loop back response").Base(err)
+		}
+		return nil
+	}
+
+	if err := task.Run(ctx, task.OnSuccess(requestDone, task.Close(loopLink.Writer)), task.OnSuccess(responseDone, task.Close(link.Writer))); err != nil {
+		common.Interrupt(loopLink.Reader)
+		common.Interrupt(loopLink.Writer)
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+// +build !confonly
+
+package mixed
+
+import (
+	"v2ray.com/core/proxy/http"
+	"v2ray.com/core/proxy/socks"
+)
+
+func (c *ServerConfig) socksConfig() *socks.ServerConfig {
+	config := &socks.ServerConfig{
+		AuthType:   socks.AuthType_NO_AUTH,
+		Accounts:   c.Accounts,
+		Address:    c.Address,
+		UdpEnabled: c.UdpEnabled,
+		UserLevel:  c.UserLevel,
+	}
+	if len(c.Accounts) > 0 {
+		config.AuthType = socks.AuthType_PASSWORD
+	}
+	return config
+}
+
+func (c *ServerConfig) httpConfig() *http.ServerConfig {
+	return &http.ServerConfig{
+		Accounts:         c.Accounts,
+		AllowTransparent: c.AllowTransparent,
+		UserLevel:        c.UserLevel,
+	}
+}
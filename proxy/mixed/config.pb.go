@@ -0,0 +1,130 @@
+package mixed
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+	net "v2ray.com/core/common/net"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// ServerConfig is the protobuf config for a server accepting both Socks and
+// HTTP proxy requests on the same port.
+type ServerConfig struct {
+	// Accounts shared by Socks 5 and HTTP. Authentication is required when
+	// this is not empty.
+	Accounts map[string]string `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Address of the Socks UDP relay.
+	Address              *net.IPOrDomain `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	UdpEnabled           bool            `protobuf:"varint,3,opt,name=udp_enabled,json=udpEnabled,proto3" json:"udp_enabled,omitempty"`
+	AllowTransparent     bool            `protobuf:"varint,4,opt,name=allow_transparent,json=allowTransparent,proto3" json:"allow_transparent,omitempty"`
+	UserLevel            uint32          `protobuf:"varint,5,opt,name=user_level,json=userLevel,proto3" json:"user_level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ServerConfig) Reset()         { *m = ServerConfig{} }
+func (m *ServerConfig) String() string { return proto.CompactTextString(m) }
+func (*ServerConfig) ProtoMessage()    {}
+func (*ServerConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_095c3d03904e5aed, []int{0}
+}
+
+func (m *ServerConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServerConfig.Unmarshal(m, b)
+}
+func (m *ServerConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServerConfig.Marshal(b, m, deterministic)
+}
+func (m *ServerConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServerConfig.Merge(m, src)
+}
+func (m *ServerConfig) XXX_Size() int {
+	return xxx_messageInfo_ServerConfig.Size(m)
+}
+func (m *ServerConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServerConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServerConfig proto.InternalMessageInfo
+
+func (m *ServerConfig) GetAccounts() map[string]string {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetAddress() *net.IPOrDomain {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetUdpEnabled() bool {
+	if m != nil {
+		return m.UdpEnabled
+	}
+	return false
+}
+
+func (m *ServerConfig) GetAllowTransparent() bool {
+	if m != nil {
+		return m.AllowTransparent
+	}
+	return false
+}
+
+func (m *ServerConfig) GetUserLevel() uint32 {
+	if m != nil {
+		return m.UserLevel
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ServerConfig)(nil), "v2ray.core.proxy.mixed.ServerConfig")
+	proto.RegisterMapType((map[string]string)(nil), "v2ray.core.proxy.mixed.ServerConfig.AccountsEntry")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/proxy/mixed/config.proto", fileDescriptor_095c3d03904e5aed)
+}
+
+var fileDescriptor_095c3d03904e5aed = []byte{
+	// 337 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0x5f, 0x4b, 0xf3, 0x30,
+	0x14, 0xc6, 0x49, 0xf7, 0xee, 0x75, 0xcb, 0x1c, 0xcc, 0x20, 0xa3, 0x0c, 0xc4, 0xea, 0x8d, 0x05,
+	0x21, 0x85, 0x7a, 0x23, 0x0e, 0x04, 0x9d, 0xbb, 0x10, 0xfc, 0x33, 0xa2, 0x78, 0xe1, 0xcd, 0xc8,
+	0xd2, 0xa3, 0x0c, 0xdb, 0xa4, 0xa4, 0x69, 0x5d, 0xbf, 0x92, 0xdf, 0xc3, 0xef, 0x25, 0x4d, 0x37,
+	0x9d, 0xa2, 0x77, 0x27, 0xcf, 0xf9, 0x3d, 0xcf, 0xc9, 0x49, 0xf0, 0x41, 0x11, 0x6a, 0x5e, 0x52,
+	0xa1, 0x92, 0x40, 0x28, 0x0d, 0x41, 0xaa, 0xd5, 0xa2, 0x0c, 0x92, 0xf9, 0x02, 0xa2, 0x40, 0x28,
+	0xf9, 0x34, 0x7f, 0xa6, 0xa9, 0x56, 0x46, 0x91, 0xfe, 0x0a, 0xd4, 0x40, 0x2d, 0x44, 0x2d, 0x34,
+	0xf8, 0x19, 0x20, 0x54, 0x92, 0x28, 0x19, 0x48, 0x30, 0x01, 0x8f, 0x22, 0x0d, 0x59, 0x56, 0x07,
+	0xec, 0xbf, 0x3b, 0x78, 0xf3, 0x0e, 0x74, 0x01, 0x7a, 0x64, 0x73, 0xc9, 0x0d, 0x6e, 0x71, 0x21,
+	0x54, 0x2e, 0x4d, 0xe6, 0x22, 0xaf, 0xe1, 0x77, 0xc2, 0x90, 0xfe, 0x3e, 0x84, 0xae, 0xfb, 0xe8,
+	0xd9, 0xd2, 0x34, 0x96, 0x46, 0x97, 0xec, 0x33, 0x83, 0x0c, 0xf1, 0xc6, 0x72, 0xa2, 0xeb, 0x78,
+	0xc8, 0xef, 0x84, 0x7b, 0xeb, 0x71, 0xf5, 0xbd, 0xa8, 0x04, 0x43, 0x2f, 0x27, 0xb7, 0xfa, 0x42,
+	0x25, 0x7c, 0x2e, 0xd9, 0xca, 0x41, 0x76, 0x71, 0x27, 0x8f, 0xd2, 0x29, 0x48, 0x3e, 0x8b, 0x21,
+	0x72, 0x1b, 0x1e, 0xf2, 0x5b, 0x0c, 0xe7, 0x51, 0x3a, 0xae, 0x15, 0x72, 0x88, 0xb7, 0x78, 0x1c,
+	0xab, 0xd7, 0xa9, 0xd1, 0x5c, 0x66, 0x29, 0xd7, 0x20, 0x8d, 0xfb, 0xcf, 0x62, 0x3d, 0xdb, 0xb8,
+	0xff, 0xd2, 0xc9, 0x0e, 0xc6, 0x79, 0x06, 0x7a, 0x1a, 0x43, 0x01, 0xb1, 0xdb, 0xf4, 0x90, 0xdf,
+	0x65, 0xed, 0x4a, 0xb9, 0xaa, 0x84, 0xc1, 0x10, 0x77, 0xbf, 0x2d, 0x41, 0x7a, 0xb8, 0xf1, 0x02,
+	0xa5, 0x8b, 0x3c, 0xe4, 0xb7, 0x59, 0x55, 0x92, 0x6d, 0xdc, 0x2c, 0x78, 0x9c, 0x83, 0x5d, 0xa5,
+	0xcd, 0xea, 0xc3, 0x89, 0x73, 0x8c, 0xce, 0x4f, 0xf1, 0x40, 0xa8, 0xe4, 0x8f, 0x97, 0x9a, 0xa0,
+	0xc7, 0xa6, 0x2d, 0xde, 0x9c, 0xfe, 0x43, 0xc8, 0x78, 0x49, 0x47, 0x15, 0x31, 0xb1, 0xc4, 0x75,
+	0xd5, 0x98, 0xfd, 0xb7, 0xdf, 0x71, 0xf4, 0x31, 0x00, 0x74, 0x9f, 0xe1, 0xf4, 0xfa, 0x01, 0x00,
+	0x00,
+}
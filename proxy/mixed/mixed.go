@@ -0,0 +1,4 @@
+// Package mixed provides an inbound proxy that serves Socks 4, 4a, 5 and HTTP proxy requests on a single port.
+package mixed
+
+//go:generate errorgen
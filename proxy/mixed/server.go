@@ -0,0 +1,102 @@
+// +build !confonly
+
+package mixed
+
+import (
+	"bufio"
+	"context"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/proxy/http"
+	"v2ray.com/core/proxy/socks"
+	"v2ray.com/core/transport/internet"
+)
+
+const (
+	socks4Version = 0x04
+	socks5Version = 0x05
+)
+
+// Server is an inbound handler that accepts both Socks and HTTP proxy requests.
+type Server struct {
+	config        *ServerConfig
+	policyManager policy.Manager
+	socks         *socks.Server
+	http          *http.Server
+}
+
+// NewServer creates a new mixed inbound handler.
+func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
+	socksServer, err := socks.NewServer(ctx, config.socksConfig())
+	if err != nil {
+		return nil, newError("failed to create Socks server").Base(err)
+	}
+	httpServer, err := http.NewServer(ctx, config.httpConfig())
+	if err != nil {
+		return nil, newError("failed to create HTTP server").Base(err)
+	}
+
+	v := core.MustFromContext(ctx)
+	s := &Server{
+		config:        config,
+		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
+		socks:         socksServer,
+		http:          httpServer,
+	}
+	return s, nil
+}
+
+// Network implements proxy.Inbound.
+func (s *Server) Network() []net.Network {
+	return s.socks.Network()
+}
+
+// Process implements proxy.Inbound.
+func (s *Server) Process(ctx context.Context, network net.Network, conn internet.Connection, dispatcher routing.Dispatcher) error {
+	if network != net.Network_TCP {
+		return s.socks.Process(ctx, network, conn, dispatcher)
+	}
+
+	plcy := s.policyManager.ForLevel(s.config.UserLevel)
+	if err := conn.SetReadDeadline(time.Now().Add(plcy.Timeouts.Handshake)); err != nil {
+		newError("failed to set read deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
+	}
+
+	bufferedConn := &bufferedConnection{
+		Connection: conn,
+		reader:     bufio.NewReader(conn),
+	}
+	version, err := bufferedConn.reader.Peek(1)
+	if err != nil {
+		return newError("failed to read first byte").Base(err)
+	}
+
+	switch version[0] {
+	case socks4Version, socks5Version:
+		return s.socks.Process(ctx, network, bufferedConn, dispatcher)
+	default:
+		return s.http.Process(ctx, network, bufferedConn, dispatcher)
+	}
+}
+
+// bufferedConnection is a connection whose first bytes have been peeked.
+type bufferedConnection struct {
+	internet.Connection
+	reader *bufio.Reader
+}
+
+func (c *bufferedConnection) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*ServerConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewServer(ctx, config.(*ServerConfig))
+	}))
+}
@@ -59,6 +59,14 @@ func (a *Authentication) DataCenterID() uint16 {
 	return uint16(x) - 1
 }
 
+// SetDataCenterID sets the ID of the data center to connect to, for the case of connecting through
+// another MTProto proxy.
+func (a *Authentication) SetDataCenterID(id uint16) {
+	x := int16(id) + 1
+	a.Header[60] = byte(x)
+	a.Header[61] = byte(x >> 8)
+}
+
 func (a *Authentication) ConnectionType() [4]byte {
 	var x [4]byte
 	copy(x[:], a.Header[56:60])
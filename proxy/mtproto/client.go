@@ -2,11 +2,13 @@ package mtproto
 
 import (
 	"context"
+	"io"
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/crypto"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/common/task"
 	"v2ray.com/core/transport"
@@ -14,10 +16,34 @@ import (
 )
 
 type Client struct {
+	server  *protocol.ServerSpec
+	account *Account
 }
 
 func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
-	return &Client{}, nil
+	if config.Server == nil {
+		return &Client{}, nil
+	}
+
+	server, err := protocol.NewServerSpecFromPB(*config.Server)
+	if err != nil {
+		return nil, newError("failed to get server spec").Base(err)
+	}
+	user := server.PickUser()
+	if user == nil {
+		return nil, newError("no user configured for MTProto server")
+	}
+	account, ok := user.Account.(*Account)
+	if !ok {
+		return nil, newError("not a MTProto account")
+	}
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
+	return &Client{
+		server:  server,
+		account: account,
+	}, nil
 }
 
 func (c *Client) Process(ctx context.Context, link *transport.Link, dialer internet.Dialer) error {
@@ -29,6 +55,9 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 	if dest.Network != net.Network_TCP {
 		return newError("not TCP traffic", dest)
 	}
+	if c.server != nil {
+		dest = c.server.Destination()
+	}
 
 	conn, err := dialer.Dial(ctx, dest)
 	if err != nil {
@@ -40,6 +69,24 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 	auth := NewAuthentication(sc)
 	defer putAuthenticationObject(auth)
 
+	var reader io.Reader = conn
+	var writer io.Writer = conn
+	if c.account != nil {
+		if c.account.Mode() == SecretModeSecure && !isPaddedConnectionType(sc.ConnectionType) {
+			return newError("secure mode requires padded intermediate connection, but got: ", sc.ConnectionType)
+		}
+		auth.SetDataCenterID(sc.DataCenterID)
+		auth.ApplySecret(c.account.Key())
+		if c.account.Mode() == SecretModeFakeTLS {
+			tlsConn, err := clientFakeTLSHandshake(conn, c.account)
+			if err != nil {
+				return newError("failed to complete fake TLS handshake").Base(err)
+			}
+			reader = tlsConn
+			writer = tlsConn
+		}
+	}
+
 	request := func() error {
 		encryptor := crypto.NewAesCTRStream(auth.EncodingKey[:], auth.EncodingNonce[:])
 
@@ -47,18 +94,18 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 		encryptor.XORKeyStream(header[:], auth.Header[:])
 		copy(header[:56], auth.Header[:])
 
-		if _, err := conn.Write(header[:]); err != nil {
+		if _, err := writer.Write(header[:]); err != nil {
 			return newError("failed to write auth header").Base(err)
 		}
 
-		connWriter := buf.NewWriter(crypto.NewCryptionWriter(encryptor, conn))
+		connWriter := buf.NewWriter(crypto.NewCryptionWriter(encryptor, writer))
 		return buf.Copy(link.Reader, connWriter)
 	}
 
 	response := func() error {
 		decryptor := crypto.NewAesCTRStream(auth.DecodingKey[:], auth.DecodingNonce[:])
 
-		connReader := buf.NewReader(crypto.NewCryptionReader(decryptor, conn))
+		connReader := buf.NewReader(crypto.NewCryptionReader(decryptor, reader))
 		return buf.Copy(connReader, link.Writer)
 	}
 
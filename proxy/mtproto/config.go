@@ -22,3 +22,54 @@ func (a *Account) Equals(another protocol.Account) bool {
 
 	return true
 }
+
+// SecretMode is the mode of an MTProto secret, indicated by its first byte.
+type SecretMode byte
+
+const (
+	// SecretModeSimple is the original obfuscated2 mode, with a 16-byte secret.
+	SecretModeSimple SecretMode = 0
+	// SecretModeSecure is the "dd" mode, in which clients must use padded intermediate transport.
+	SecretModeSecure SecretMode = 0xdd
+	// SecretModeFakeTLS is the "ee" mode, in which the connection is wrapped in fake TLS records.
+	SecretModeFakeTLS SecretMode = 0xee
+)
+
+const secretKeySize = 16
+
+// Mode returns the mode of the secret.
+func (a *Account) Mode() SecretMode {
+	switch {
+	case len(a.Secret) == secretKeySize+1 && a.Secret[0] == byte(SecretModeSecure):
+		return SecretModeSecure
+	case len(a.Secret) > secretKeySize+1 && a.Secret[0] == byte(SecretModeFakeTLS):
+		return SecretModeFakeTLS
+	default:
+		return SecretModeSimple
+	}
+}
+
+// Key returns the 16-byte key of the secret, without mode prefix and domain.
+func (a *Account) Key() []byte {
+	if a.Mode() == SecretModeSimple {
+		return a.Secret
+	}
+	return a.Secret[1 : secretKeySize+1]
+}
+
+// Domain returns the domain in the secret of fake TLS mode, which is used as server name in TLS
+// handshake.
+func (a *Account) Domain() string {
+	if a.Mode() != SecretModeFakeTLS {
+		return ""
+	}
+	return string(a.Secret[secretKeySize+1:])
+}
+
+// Validate returns an error if the secret is malformed.
+func (a *Account) Validate() error {
+	if len(a.Secret) == secretKeySize || a.Mode() != SecretModeSimple {
+		return nil
+	}
+	return newError("invalid MTProto secret of ", len(a.Secret), " bytes")
+}
@@ -19,6 +19,9 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type Account struct {
+	// Secret of the account. A 16-byte secret is used in obfuscated2 mode. A secret prefixed by 0xdd
+	// is used in secure mode, and a secret prefixed by 0xee and followed by a domain is used in fake
+	// TLS mode.
 	Secret               []byte   `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -99,9 +102,12 @@ func (m *ServerConfig) GetUser() []*protocol.User {
 }
 
 type ClientConfig struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// Server is an optional MTProto proxy to connect through. If not set, connections are made to
+	// Telegram data centers directly.
+	Server               *protocol.ServerEndpoint `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
 }
 
 func (m *ClientConfig) Reset()         { *m = ClientConfig{} }
@@ -129,6 +135,13 @@ func (m *ClientConfig) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ClientConfig proto.InternalMessageInfo
 
+func (m *ClientConfig) GetServer() *protocol.ServerEndpoint {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Account)(nil), "v2ray.core.proxy.mtproto.Account")
 	proto.RegisterType((*ServerConfig)(nil), "v2ray.core.proxy.mtproto.ServerConfig")
@@ -140,19 +153,21 @@ func init() {
 }
 
 var fileDescriptor_64514e21c693811b = []byte{
-	// 221 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x8f, 0xc1, 0x4a, 0xc4, 0x30,
-	0x10, 0x86, 0x89, 0xca, 0x2e, 0xc4, 0xe2, 0xa1, 0x07, 0x09, 0xe2, 0xa1, 0xf6, 0xb4, 0x5e, 0x26,
-	0x50, 0x7d, 0x01, 0xed, 0x5e, 0x85, 0xa5, 0xa2, 0x07, 0x6f, 0xeb, 0x30, 0xca, 0xc2, 0x26, 0x53,
-	0xa6, 0x69, 0xb1, 0xaf, 0xe4, 0x53, 0x4a, 0x93, 0x16, 0x44, 0xf0, 0x94, 0xfc, 0xfc, 0x1f, 0xdf,
-	0x9f, 0xe8, 0xdb, 0xa1, 0x92, 0xfd, 0x08, 0xc8, 0xce, 0x22, 0x0b, 0xd9, 0x56, 0xf8, 0x6b, 0xb4,
-	0x2e, 0xb4, 0xc2, 0x81, 0x2d, 0xb2, 0xff, 0x38, 0x7c, 0x42, 0x0c, 0xb9, 0x59, 0x50, 0x21, 0x88,
-	0x18, 0xcc, 0xd8, 0xd5, 0x5f, 0x09, 0xb2, 0x73, 0xec, 0x6d, 0x2c, 0x91, 0x8f, 0xb6, 0xef, 0x48,
-	0x92, 0xa4, 0xbc, 0xd1, 0xeb, 0x07, 0x44, 0xee, 0x7d, 0xc8, 0x2f, 0xf5, 0xaa, 0x23, 0x14, 0x0a,
-	0x46, 0x15, 0x6a, 0x93, 0x35, 0x73, 0x2a, 0xb7, 0x3a, 0x7b, 0x26, 0x19, 0x48, 0xea, 0xb8, 0x9e,
-	0xdf, 0xeb, 0xb3, 0x49, 0x60, 0x54, 0x71, 0xba, 0x39, 0xaf, 0x0a, 0xf8, 0xf5, 0x8c, 0x34, 0x04,
-	0xcb, 0x10, 0xbc, 0x74, 0x24, 0x4d, 0xa4, 0xcb, 0x0b, 0x9d, 0xd5, 0xc7, 0x03, 0xf9, 0x90, 0x2c,
-	0x8f, 0x5b, 0x7d, 0x8d, 0xec, 0xe0, 0xbf, 0x3f, 0xec, 0xd4, 0xdb, 0x7a, 0xbe, 0x7e, 0x9f, 0x98,
-	0xd7, 0xaa, 0xd9, 0x8f, 0x50, 0x4f, 0xd4, 0x2e, 0x52, 0x4f, 0xa9, 0x7a, 0x5f, 0xc5, 0xe3, 0xee,
-	0x27, 0x00, 0x00, 0xff, 0xff, 0x54, 0x23, 0xa0, 0xae, 0x37, 0x01, 0x00, 0x00,
+	// 255 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x90, 0x41, 0x4b, 0xf3, 0x40,
+	0x10, 0x86, 0xc9, 0xf7, 0x49, 0x0b, 0xdb, 0x9c, 0x72, 0x90, 0x50, 0x3c, 0xc4, 0x9c, 0xaa, 0x87,
+	0x5d, 0x89, 0xfe, 0x01, 0x9b, 0x7a, 0x14, 0xca, 0x8a, 0x1e, 0xbc, 0x48, 0x1d, 0x47, 0x09, 0x74,
+	0x77, 0xc2, 0xec, 0xb6, 0x98, 0xbf, 0xe4, 0xaf, 0x94, 0xec, 0x6e, 0x41, 0x04, 0xf5, 0x94, 0x0c,
+	0xf3, 0xbc, 0xef, 0x33, 0xac, 0x38, 0xdb, 0x37, 0xbc, 0x19, 0x24, 0x90, 0x51, 0x40, 0x8c, 0xaa,
+	0x67, 0x7a, 0x1f, 0x94, 0xf1, 0x3d, 0x93, 0x27, 0x05, 0x64, 0x5f, 0xbb, 0x37, 0x19, 0x86, 0xa2,
+	0x3c, 0xa0, 0x8c, 0x32, 0x60, 0x32, 0x61, 0xf3, 0xef, 0x25, 0x40, 0xc6, 0x90, 0x55, 0x61, 0x09,
+	0xb4, 0x55, 0x3b, 0x87, 0x1c, 0x4b, 0xe6, 0x17, 0x7f, 0xa0, 0x0e, 0x79, 0x8f, 0xfc, 0xe4, 0x7a,
+	0x84, 0x98, 0xa8, 0x4f, 0xc5, 0xf4, 0x1a, 0x80, 0x76, 0xd6, 0x17, 0xc7, 0x62, 0xe2, 0x10, 0x18,
+	0x7d, 0x99, 0x55, 0xd9, 0x22, 0xd7, 0x69, 0xaa, 0x57, 0x22, 0xbf, 0x0b, 0xb9, 0x36, 0xdc, 0x5b,
+	0x5c, 0x89, 0xa3, 0x51, 0x59, 0x66, 0xd5, 0xff, 0xc5, 0xac, 0xa9, 0xe4, 0x97, 0xc3, 0xa3, 0x4f,
+	0x1e, 0x7c, 0xf2, 0xde, 0x21, 0xeb, 0x40, 0xd7, 0x5a, 0xe4, 0xed, 0xb6, 0x43, 0xeb, 0x53, 0xcb,
+	0x72, 0xb4, 0x8d, 0xad, 0xc1, 0x36, 0x6b, 0xce, 0x7f, 0xeb, 0x89, 0xfe, 0x1b, 0xfb, 0xd2, 0x53,
+	0x67, 0xbd, 0x4e, 0xc9, 0xe5, 0x4a, 0x9c, 0x00, 0x19, 0xf9, 0xd3, 0xcb, 0xad, 0xb3, 0xc7, 0x69,
+	0xfa, 0xfd, 0xf8, 0x57, 0x3e, 0x34, 0x7a, 0x33, 0xc8, 0x76, 0xa4, 0xd6, 0x81, 0xba, 0x8d, 0xab,
+	0xe7, 0x49, 0xf8, 0x5c, 0x7e, 0x0e, 0x00, 0x8e, 0xf7, 0x53, 0x0c, 0xad, 0x01, 0x00, 0x00,
 }
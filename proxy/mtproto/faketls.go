@@ -0,0 +1,379 @@
+package mtproto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/dice"
+)
+
+const (
+	tlsRecordTypeChangeCipherSpec = 0x14
+	tlsRecordTypeHandshake        = 0x16
+	tlsRecordTypeApplicationData  = 0x17
+
+	tlsRecordHeaderSize  = 5
+	tlsMaxRecordPayload  = 1 << 14
+	tlsMaxRecordReadSize = tlsMaxRecordPayload + 2048
+
+	tlsClientHelloSize = 517
+	tlsRandomOffset    = tlsRecordHeaderSize + 4 + 2
+	tlsRandomSize      = 32
+
+	fakeTLSMaxTimeSkew = 2 * time.Minute
+)
+
+var tlsChangeCipherSpec = []byte{tlsRecordTypeChangeCipherSpec, 0x03, 0x03, 0x00, 0x01, 0x01}
+
+func appendUint16(b []byte, v int) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint24(b []byte, v int) []byte {
+	return append(b, byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendTLSExtension(b []byte, extType uint16, data []byte) []byte {
+	b = appendUint16(b, int(extType))
+	b = appendUint16(b, len(data))
+	return append(b, data...)
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	common.Must2(rand.Read(b))
+	return b
+}
+
+// buildClientHello returns a TLS 1.3 ClientHello record for the given server name, with its
+// random field zeroed.
+func buildClientHello(domain string) []byte {
+	body := make([]byte, 0, tlsClientHelloSize)
+	body = append(body, 0x03, 0x03)
+	body = append(body, make([]byte, tlsRandomSize)...)
+	body = append(body, 32)
+	body = append(body, randomBytes(32)...)
+
+	cipherSuites := []byte{
+		0x13, 0x01, 0x13, 0x02, 0x13, 0x03, 0xc0, 0x2b, 0xc0, 0x2f, 0xc0, 0x2c, 0xc0, 0x30,
+		0xcc, 0xa9, 0xcc, 0xa8, 0xc0, 0x13, 0xc0, 0x14, 0x00, 0x9c, 0x00, 0x9d, 0x00, 0x2f, 0x00, 0x35,
+	}
+	body = appendUint16(body, len(cipherSuites))
+	body = append(body, cipherSuites...)
+	body = append(body, 0x01, 0x00)
+
+	var serverName []byte
+	serverName = appendUint16(serverName, len(domain)+3)
+	serverName = append(serverName, 0x00)
+	serverName = appendUint16(serverName, len(domain))
+	serverName = append(serverName, domain...)
+
+	var keyShare []byte
+	keyShare = appendUint16(keyShare, 36)
+	keyShare = append(keyShare, 0x00, 0x1d, 0x00, 0x20)
+	keyShare = append(keyShare, randomBytes(32)...)
+
+	var extensions []byte
+	extensions = appendTLSExtension(extensions, 0x0000, serverName)
+	extensions = appendTLSExtension(extensions, 0x0017, nil)
+	extensions = appendTLSExtension(extensions, 0xff01, []byte{0x00})
+	extensions = appendTLSExtension(extensions, 0x000a, []byte{0x00, 0x06, 0x00, 0x1d, 0x00, 0x17, 0x00, 0x18})
+	extensions = appendTLSExtension(extensions, 0x000b, []byte{0x01, 0x00})
+	extensions = appendTLSExtension(extensions, 0x0023, nil)
+	extensions = appendTLSExtension(extensions, 0x0010, []byte{0x00, 0x0c, 0x02, 'h', '2', 0x08, 'h', 't', 't', 'p', '/', '1', '.', '1'})
+	extensions = appendTLSExtension(extensions, 0x000d, []byte{0x00, 0x10, 0x04, 0x03, 0x08, 0x04, 0x04, 0x01, 0x05, 0x03, 0x08, 0x05, 0x05, 0x01, 0x08, 0x06, 0x06, 0x01})
+	extensions = appendTLSExtension(extensions, 0x0033, keyShare)
+	extensions = appendTLSExtension(extensions, 0x002d, []byte{0x01, 0x01})
+	extensions = appendTLSExtension(extensions, 0x002b, []byte{0x04, 0x03, 0x04, 0x03, 0x03})
+
+	// Pad the record to a fixed size, as browsers do.
+	size := tlsRecordHeaderSize + 4 + len(body) + 2 + len(extensions)
+	if padding := tlsClientHelloSize - size - 4; padding >= 0 {
+		extensions = appendTLSExtension(extensions, 0x0015, make([]byte, padding))
+	}
+	body = appendUint16(body, len(extensions))
+	body = append(body, extensions...)
+
+	record := make([]byte, 0, tlsRecordHeaderSize+4+len(body))
+	record = append(record, tlsRecordTypeHandshake, 0x03, 0x01)
+	record = appendUint16(record, len(body)+4)
+	record = append(record, 0x01)
+	record = appendUint24(record, len(body))
+	return append(record, body...)
+}
+
+// buildServerHello returns the server response to a ClientHello with the given session ID. The
+// response consists of a ServerHello, a ChangeCipherSpec and an ApplicationData record of random
+// size, with the random field of ServerHello zeroed.
+func buildServerHello(sessionID []byte) []byte {
+	body := make([]byte, 0, 128)
+	body = append(body, 0x03, 0x03)
+	body = append(body, make([]byte, tlsRandomSize)...)
+	body = append(body, byte(len(sessionID)))
+	body = append(body, sessionID...)
+	body = append(body, 0x13, 0x01, 0x00)
+
+	var keyShare []byte
+	keyShare = append(keyShare, 0x00, 0x1d, 0x00, 0x20)
+	keyShare = append(keyShare, randomBytes(32)...)
+
+	var extensions []byte
+	extensions = appendTLSExtension(extensions, 0x0033, keyShare)
+	extensions = appendTLSExtension(extensions, 0x002b, []byte{0x03, 0x04})
+	body = appendUint16(body, len(extensions))
+	body = append(body, extensions...)
+
+	appData := randomBytes(1024 + dice.Roll(3072))
+
+	response := make([]byte, 0, 2*tlsRecordHeaderSize+4+len(body)+len(tlsChangeCipherSpec)+len(appData))
+	response = append(response, tlsRecordTypeHandshake, 0x03, 0x03)
+	response = appendUint16(response, len(body)+4)
+	response = append(response, 0x02)
+	response = appendUint24(response, len(body))
+	response = append(response, body...)
+	response = append(response, tlsChangeCipherSpec...)
+	response = append(response, tlsRecordTypeApplicationData, 0x03, 0x03)
+	response = appendUint16(response, len(appData))
+	return append(response, appData...)
+}
+
+func fakeTLSDigest(key []byte, data ...[]byte) []byte {
+	h := hmac.New(sha256.New, key)
+	for _, d := range data {
+		h.Write(d) // nolint: errcheck
+	}
+	return h.Sum(nil)
+}
+
+// readTLSRecord reads a TLS record and returns its type and the whole record including header.
+func readTLSRecord(reader io.Reader, b []byte) (byte, []byte, error) {
+	if _, err := io.ReadFull(reader, b[:tlsRecordHeaderSize]); err != nil {
+		return 0, nil, err
+	}
+	if b[1] != 0x03 {
+		return 0, nil, newError("unexpected TLS version: ", b[1], ".", b[2])
+	}
+	size := int(binary.BigEndian.Uint16(b[3:5]))
+	if size > tlsMaxRecordReadSize || tlsRecordHeaderSize+size > len(b) {
+		return 0, nil, newError("TLS record too large: ", size)
+	}
+	if _, err := io.ReadFull(reader, b[tlsRecordHeaderSize:tlsRecordHeaderSize+size]); err != nil {
+		return 0, nil, err
+	}
+	return b[0], b[:tlsRecordHeaderSize+size], nil
+}
+
+// clientHello is a parsed ClientHello of fake TLS.
+type clientHello struct {
+	random     [tlsRandomSize]byte
+	sessionID  []byte
+	serverName string
+	timestamp  int64
+}
+
+// parseClientHello validates the given ClientHello record against the key, and parses it.
+func parseClientHello(record []byte, key []byte) (*clientHello, error) {
+	if len(record) < tlsRandomOffset+tlsRandomSize+1 || record[0] != tlsRecordTypeHandshake || record[tlsRecordHeaderSize] != 0x01 {
+		return nil, newError("not a ClientHello")
+	}
+
+	hello := new(clientHello)
+	copy(hello.random[:], record[tlsRandomOffset:])
+
+	zeroed := append([]byte(nil), record...)
+	copy(zeroed[tlsRandomOffset:tlsRandomOffset+tlsRandomSize], make([]byte, tlsRandomSize))
+	digest := fakeTLSDigest(key, zeroed)
+	for i := range digest {
+		digest[i] ^= hello.random[i]
+	}
+	if subtle.ConstantTimeCompare(digest[:28], make([]byte, 28)) != 1 {
+		return nil, newError("invalid ClientHello digest")
+	}
+	hello.timestamp = int64(binary.LittleEndian.Uint32(digest[28:]))
+
+	body := record[tlsRandomOffset+tlsRandomSize:]
+	sessionIDLen := int(body[0])
+	if len(body) < 1+sessionIDLen+2 {
+		return nil, newError("invalid session ID in ClientHello")
+	}
+	hello.sessionID = body[1 : 1+sessionIDLen]
+	body = body[1+sessionIDLen:]
+
+	cipherSuitesLen := int(binary.BigEndian.Uint16(body))
+	if len(body) < 2+cipherSuitesLen+1 {
+		return nil, newError("invalid cipher suites in ClientHello")
+	}
+	body = body[2+cipherSuitesLen:]
+	compressionLen := int(body[0])
+	if len(body) < 1+compressionLen+2 {
+		return nil, newError("invalid compression methods in ClientHello")
+	}
+	body = body[1+compressionLen:]
+
+	extensionsLen := int(binary.BigEndian.Uint16(body))
+	if len(body) < 2+extensionsLen {
+		return nil, newError("invalid extensions in ClientHello")
+	}
+	extensions := body[2 : 2+extensionsLen]
+	for len(extensions) >= 4 {
+		extType := binary.BigEndian.Uint16(extensions)
+		extLen := int(binary.BigEndian.Uint16(extensions[2:]))
+		if len(extensions) < 4+extLen {
+			return nil, newError("invalid extension in ClientHello")
+		}
+		data := extensions[4 : 4+extLen]
+		extensions = extensions[4+extLen:]
+
+		// server_name extension with a single host_name entry.
+		if extType == 0x0000 && len(data) >= 5 && data[2] == 0x00 {
+			nameLen := int(binary.BigEndian.Uint16(data[3:]))
+			if len(data) >= 5+nameLen {
+				hello.serverName = string(data[5 : 5+nameLen])
+			}
+		}
+	}
+
+	return hello, nil
+}
+
+// fakeTLSConn is a connection that carries data in TLS ApplicationData records after a fake TLS
+// handshake.
+type fakeTLSConn struct {
+	net.Conn
+	buffer  []byte
+	pending []byte
+}
+
+func newFakeTLSConn(conn net.Conn) *fakeTLSConn {
+	return &fakeTLSConn{
+		Conn:   conn,
+		buffer: make([]byte, tlsRecordHeaderSize+tlsMaxRecordReadSize),
+	}
+}
+
+func (c *fakeTLSConn) Read(b []byte) (int, error) {
+	for len(c.pending) == 0 {
+		recordType, record, err := readTLSRecord(c.Conn, c.buffer)
+		if err != nil {
+			return 0, err
+		}
+		switch recordType {
+		case tlsRecordTypeApplicationData:
+			c.pending = record[tlsRecordHeaderSize:]
+		case tlsRecordTypeChangeCipherSpec:
+		default:
+			return 0, newError("unexpected TLS record type: ", recordType)
+		}
+	}
+
+	n := copy(b, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+func (c *fakeTLSConn) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		size := len(b)
+		if size > tlsMaxRecordPayload {
+			size = tlsMaxRecordPayload
+		}
+		record := make([]byte, 0, tlsRecordHeaderSize+size)
+		record = append(record, tlsRecordTypeApplicationData, 0x03, 0x03)
+		record = appendUint16(record, size)
+		record = append(record, b[:size]...)
+		if _, err := c.Conn.Write(record); err != nil {
+			return written, err
+		}
+		written += size
+		b = b[size:]
+	}
+	return written, nil
+}
+
+// clientFakeTLSHandshake performs fake TLS handshake as client, with the key and server name in
+// the secret.
+func clientFakeTLSHandshake(conn net.Conn, account *Account) (*fakeTLSConn, error) {
+	key := account.Key()
+	hello := buildClientHello(account.Domain())
+	digest := fakeTLSDigest(key, hello)
+	var timestamp [4]byte
+	binary.LittleEndian.PutUint32(timestamp[:], uint32(time.Now().Unix()))
+	for i := range timestamp {
+		digest[28+i] ^= timestamp[i]
+	}
+	copy(hello[tlsRandomOffset:], digest)
+	if _, err := conn.Write(hello); err != nil {
+		return nil, newError("failed to write ClientHello").Base(err)
+	}
+
+	var response []byte
+	buffer := make([]byte, tlsRecordHeaderSize+tlsMaxRecordReadSize)
+	for _, expected := range []byte{tlsRecordTypeHandshake, tlsRecordTypeChangeCipherSpec, tlsRecordTypeApplicationData} {
+		recordType, record, err := readTLSRecord(conn, buffer)
+		if err != nil {
+			return nil, newError("failed to read ServerHello").Base(err)
+		}
+		if recordType != expected {
+			return nil, newError("unexpected TLS record type: ", recordType)
+		}
+		response = append(response, record...)
+	}
+	if len(response) < tlsRandomOffset+tlsRandomSize {
+		return nil, newError("invalid ServerHello")
+	}
+
+	var serverRandom [tlsRandomSize]byte
+	copy(serverRandom[:], response[tlsRandomOffset:])
+	copy(response[tlsRandomOffset:tlsRandomOffset+tlsRandomSize], make([]byte, tlsRandomSize))
+	if !hmac.Equal(serverRandom[:], fakeTLSDigest(key, hello[tlsRandomOffset:tlsRandomOffset+tlsRandomSize], response)) {
+		return nil, newError("invalid ServerHello digest")
+	}
+
+	if _, err := conn.Write(tlsChangeCipherSpec); err != nil {
+		return nil, newError("failed to write ChangeCipherSpec").Base(err)
+	}
+	return newFakeTLSConn(conn), nil
+}
+
+// readClientHello reads a ClientHello as fake TLS server, and validates it with the key and server
+// name in the secret.
+func readClientHello(conn net.Conn, account *Account) (*clientHello, error) {
+	buffer := make([]byte, tlsRecordHeaderSize+tlsMaxRecordReadSize)
+	recordType, record, err := readTLSRecord(conn, buffer)
+	if err != nil {
+		return nil, newError("failed to read ClientHello").Base(err)
+	}
+	if recordType != tlsRecordTypeHandshake {
+		return nil, newError("unexpected TLS record type: ", recordType)
+	}
+
+	hello, err := parseClientHello(record, account.Key())
+	if err != nil {
+		return nil, err
+	}
+	if domain := account.Domain(); hello.serverName != domain {
+		return nil, newError("unexpected server name: ", hello.serverName)
+	}
+	if skew := time.Since(time.Unix(hello.timestamp, 0)); skew > fakeTLSMaxTimeSkew || skew < -fakeTLSMaxTimeSkew {
+		return nil, newError("invalid timestamp in ClientHello: ", hello.timestamp)
+	}
+	return hello, nil
+}
+
+// writeServerHello responds to the given ClientHello as fake TLS server, and completes the handshake.
+func writeServerHello(conn net.Conn, account *Account, hello *clientHello) (*fakeTLSConn, error) {
+	response := buildServerHello(hello.sessionID)
+	copy(response[tlsRandomOffset:], fakeTLSDigest(account.Key(), hello.random[:], response))
+	if _, err := conn.Write(response); err != nil {
+		return nil, newError("failed to write ServerHello").Base(err)
+	}
+	return newFakeTLSConn(conn), nil
+}
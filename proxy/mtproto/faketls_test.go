@@ -0,0 +1,112 @@
+package mtproto
+
+import (
+	"crypto/rand"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+)
+
+func newFakeTLSAccount(domain string) *Account {
+	secret := make([]byte, 1+secretKeySize)
+	common.Must2(rand.Read(secret))
+	secret[0] = byte(SecretModeFakeTLS)
+	return &Account{Secret: append(secret, domain...)}
+}
+
+func TestAccountMode(t *testing.T) {
+	key := make([]byte, secretKeySize)
+	common.Must2(rand.Read(key))
+
+	testCases := []struct {
+		secret []byte
+		mode   SecretMode
+		domain string
+	}{
+		{secret: key, mode: SecretModeSimple},
+		{secret: append([]byte{0xdd}, key...), mode: SecretModeSecure},
+		{secret: append(append([]byte{0xee}, key...), "www.v2ray.com"...), mode: SecretModeFakeTLS, domain: "www.v2ray.com"},
+	}
+	for _, tc := range testCases {
+		a := &Account{Secret: tc.secret}
+		common.Must(a.Validate())
+		if a.Mode() != tc.mode {
+			t.Error("unexpected mode: ", a.Mode(), " want ", tc.mode)
+		}
+		if r := cmp.Diff(a.Key(), key); r != "" {
+			t.Error(r)
+		}
+		if a.Domain() != tc.domain {
+			t.Error("unexpected domain: ", a.Domain(), " want ", tc.domain)
+		}
+	}
+
+	if err := (&Account{Secret: append([]byte{0xdd}, key[:8]...)}).Validate(); err == nil {
+		t.Error("expect error for short secret")
+	}
+}
+
+func TestFakeTLSHandshake(t *testing.T) {
+	account := newFakeTLSAccount("www.v2ray.com")
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	payload := make([]byte, 40000)
+	common.Must2(rand.Read(payload))
+
+	errCh := make(chan error, 1)
+	go func() {
+		hello, err := readClientHello(serverConn, account)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		conn, err := writeServerHello(serverConn, account, hello)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		received := make([]byte, len(payload))
+		if _, err := io.ReadFull(conn, received); err != nil {
+			errCh <- err
+			return
+		}
+		_, err = conn.Write(received)
+		errCh <- err
+	}()
+
+	conn, err := clientFakeTLSHandshake(clientConn, account)
+	common.Must(err)
+	common.Must2(conn.Write(payload))
+	response := make([]byte, len(payload))
+	common.Must2(io.ReadFull(conn, response))
+	common.Must(<-errCh)
+
+	if r := cmp.Diff(response, payload); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestFakeTLSInvalidClientHello(t *testing.T) {
+	account := newFakeTLSAccount("www.v2ray.com")
+
+	testCases := []*Account{
+		newFakeTLSAccount("www.v2ray.com"),
+		{Secret: append(append([]byte(nil), account.Secret[:1+secretKeySize]...), "www.example.com"...)},
+	}
+	for _, clientAccount := range testCases {
+		clientConn, serverConn := net.Pipe()
+		go clientFakeTLSHandshake(clientConn, clientAccount) // nolint: errcheck
+
+		if _, err := readClientHello(serverConn, account); err == nil {
+			t.Error("expect error for ClientHello of secret ", clientAccount.Secret)
+		}
+		clientConn.Close()
+		serverConn.Close()
+	}
+}
@@ -5,6 +5,8 @@ package mtproto
 import (
 	"bytes"
 	"context"
+	"io"
+	"sync"
 	"time"
 
 	"v2ray.com/core"
@@ -35,6 +37,10 @@ type Server struct {
 	user    *protocol.User
 	account *Account
 	policy  policy.Manager
+
+	access      sync.Mutex
+	tlsRandoms  map[[tlsRandomSize]byte]time.Time
+	lastCleanup time.Time
 }
 
 func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
@@ -51,6 +57,9 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 	if !ok {
 		return nil, newError("not a MTProto account")
 	}
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
 
 	v := core.MustFromContext(ctx)
 
@@ -58,6 +67,9 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 		user:    user,
 		account: account,
 		policy:  v.GetFeature(policy.ManagerType()).(policy.Manager),
+
+		tlsRandoms:  make(map[[tlsRandomSize]byte]time.Time),
+		lastCleanup: time.Now(),
 	}, nil
 }
 
@@ -67,6 +79,7 @@ func (s *Server) Network() []net.Network {
 
 var ctype1 = []byte{0xef, 0xef, 0xef, 0xef}
 var ctype2 = []byte{0xee, 0xee, 0xee, 0xee}
+var ctype3 = []byte{0xdd, 0xdd, 0xdd, 0xdd}
 
 func isValidConnectionType(c [4]byte) bool {
 	if bytes.Equal(c[:], ctype1) {
@@ -75,16 +88,67 @@ func isValidConnectionType(c [4]byte) bool {
 	if bytes.Equal(c[:], ctype2) {
 		return true
 	}
+	if bytes.Equal(c[:], ctype3) {
+		return true
+	}
 	return false
 }
 
+// isPaddedConnectionType returns true if the connection type is padded intermediate, which is
+// required by secure mode.
+func isPaddedConnectionType(c [4]byte) bool {
+	return bytes.Equal(c[:], ctype3)
+}
+
+// checkTLSRandom records the random field of a ClientHello, and returns false if it has been seen
+// before.
+func (s *Server) checkTLSRandom(random [tlsRandomSize]byte) bool {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastCleanup) > fakeTLSMaxTimeSkew {
+		for r, expire := range s.tlsRandoms {
+			if expire.Before(now) {
+				delete(s.tlsRandoms, r)
+			}
+		}
+		s.lastCleanup = now
+	}
+
+	if _, found := s.tlsRandoms[random]; found {
+		return false
+	}
+	s.tlsRandoms[random] = now.Add(fakeTLSMaxTimeSkew * 2)
+	return true
+}
+
 func (s *Server) Process(ctx context.Context, network net.Network, conn internet.Connection, dispatcher routing.Dispatcher) error {
 	sPolicy := s.policy.ForLevel(s.user.Level)
 
 	if err := conn.SetDeadline(time.Now().Add(sPolicy.Timeouts.Handshake)); err != nil {
 		newError("failed to set deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
-	auth, err := ReadAuthentication(conn)
+
+	var reader io.Reader = conn
+	var writer io.Writer = conn
+	if s.account.Mode() == SecretModeFakeTLS {
+		hello, err := readClientHello(conn, s.account)
+		if err != nil {
+			return newError("failed to read fake TLS handshake").Base(err)
+		}
+		if !s.checkTLSRandom(hello.random) {
+			return newError("duplicated ClientHello, possibly under replay attack")
+		}
+		tlsConn, err := writeServerHello(conn, s.account, hello)
+		if err != nil {
+			return err
+		}
+		reader = tlsConn
+		writer = tlsConn
+	}
+
+	auth, err := ReadAuthentication(reader)
 	if err != nil {
 		return newError("failed to read authentication header").Base(err)
 	}
@@ -94,7 +158,7 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 		newError("failed to clear deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
 
-	auth.ApplySecret(s.account.Secret)
+	auth.ApplySecret(s.account.Key())
 
 	decryptor := crypto.NewAesCTRStream(auth.DecodingKey[:], auth.DecodingNonce[:])
 	decryptor.XORKeyStream(auth.Header[:], auth.Header[:])
@@ -103,6 +167,9 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 	if !isValidConnectionType(ct) {
 		return newError("invalid connection type: ", ct)
 	}
+	if s.account.Mode() == SecretModeSecure && !isPaddedConnectionType(ct) {
+		return newError("secure mode requires padded intermediate connection, but got: ", ct)
+	}
 
 	dcID := auth.DataCenterID()
 	if dcID >= uint16(len(dcList)) {
@@ -133,7 +200,7 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 	request := func() error {
 		defer timer.SetTimeout(sPolicy.Timeouts.DownlinkOnly)
 
-		reader := buf.NewReader(crypto.NewCryptionReader(decryptor, conn))
+		reader := buf.NewReader(crypto.NewCryptionReader(decryptor, reader))
 		return buf.Copy(reader, link.Writer, buf.UpdateActivity(timer))
 	}
 
@@ -141,7 +208,7 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 		defer timer.SetTimeout(sPolicy.Timeouts.UplinkOnly)
 
 		encryptor := crypto.NewAesCTRStream(auth.EncodingKey[:], auth.EncodingNonce[:])
-		writer := buf.NewWriter(crypto.NewCryptionWriter(encryptor, conn))
+		writer := buf.NewWriter(crypto.NewCryptionWriter(encryptor, writer))
 		return buf.Copy(link.Reader, writer, buf.UpdateActivity(timer))
 	}
 
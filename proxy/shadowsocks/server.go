@@ -4,6 +4,7 @@ package shadowsocks
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"v2ray.com/core"
@@ -18,6 +19,8 @@ import (
 	"v2ray.com/core/common/task"
 	"v2ray.com/core/features/policy"
 	"v2ray.com/core/features/routing"
+	"v2ray.com/core/features/stats"
+	"v2ray.com/core/proxy/fallback"
 	"v2ray.com/core/transport/internet"
 	"v2ray.com/core/transport/internet/udp"
 )
@@ -26,6 +29,7 @@ type Server struct {
 	config        ServerConfig
 	user          *protocol.MemoryUser
 	policyManager policy.Manager
+	statsManager  stats.Manager
 }
 
 // NewServer create a new Shadowsocks server.
@@ -44,7 +48,9 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 		config:        *config,
 		user:          mUser,
 		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
+		statsManager:  v.GetFeature(stats.ManagerType()).(stats.Manager),
 	}
+	stats.RegisterUserQuota(s.statsManager, mUser)
 
 	return s, nil
 }
@@ -132,6 +138,12 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 				continue
 			}
 
+			if err := stats.CheckUserQuota(s.statsManager, s.user); err != nil {
+				newError("dropping UDP packet from: ", inbound.Source).Base(err).WriteToLog(session.ExportIDToError(ctx))
+				payload.Release()
+				continue
+			}
+
 			dest := request.Destination()
 			if inbound.Source.IsValid() {
 				ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
@@ -156,7 +168,14 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 	sessionPolicy := s.policyManager.ForLevel(s.user.Level)
 	conn.SetReadDeadline(time.Now().Add(sessionPolicy.Timeouts.Handshake))
 
-	bufferedReader := buf.BufferedReader{Reader: buf.NewReader(conn)}
+	var rawReader io.Reader = conn
+	var recorder *fallback.Recorder
+	if len(s.config.Fallback) > 0 {
+		recorder = fallback.NewRecorder(conn)
+		rawReader = recorder
+	}
+
+	bufferedReader := buf.BufferedReader{Reader: buf.NewReader(rawReader)}
 	request, bodyReader, err := ReadTCPSession(s.user, &bufferedReader)
 	if err != nil {
 		log.Record(&log.AccessMessage{
@@ -165,10 +184,29 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 			Status: log.AccessRejected,
 			Reason: err,
 		})
+		if recorder != nil {
+			newError("falling back on failed handshake from: ", conn.RemoteAddr()).Base(err).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+			conn.SetReadDeadline(time.Time{})
+			return fallback.Serve(ctx, s.config.Fallback, conn, recorder, sessionPolicy)
+		}
 		return newError("failed to create request from: ", conn.RemoteAddr()).Base(err)
 	}
+	if recorder != nil {
+		recorder.Stop()
+	}
 	conn.SetReadDeadline(time.Time{})
 
+	if err := stats.CheckUserQuota(s.statsManager, s.user); err != nil {
+		log.Record(&log.AccessMessage{
+			From:   conn.RemoteAddr(),
+			To:     "",
+			Status: log.AccessRejected,
+			Reason: err,
+			Email:  s.user.Email,
+		})
+		return newError("rejecting request from ", conn.RemoteAddr()).Base(err)
+	}
+
 	inbound := session.InboundFromContext(ctx)
 	if inbound == nil {
 		panic("no inbound metadata")
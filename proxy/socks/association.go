@@ -0,0 +1,81 @@
+// +build !confonly
+
+package socks
+
+import (
+	"io"
+	"sync"
+
+	"v2ray.com/core/common/net"
+)
+
+// udpAssociation is a UDP association created by a UDP ASSOCIATE command. It lives as long as
+// the TCP connection that creates it.
+type udpAssociation struct {
+	ip net.IP
+	// port is the port that the client declares to send from. 0 for any port.
+	port  net.Port
+	conns map[io.Closer]bool
+}
+
+func (a *udpAssociation) accepts(source net.Destination) bool {
+	return source.Address.Family().IsIP() && a.ip.Equal(source.Address.IP()) && (a.port == 0 || a.port == source.Port)
+}
+
+type udpAssociations struct {
+	sync.Mutex
+	associations map[*udpAssociation]bool
+}
+
+// add registers an association for the client IP and the declared port.
+func (m *udpAssociations) add(ip net.Address, port net.Port) *udpAssociation {
+	m.Lock()
+	defer m.Unlock()
+
+	a := &udpAssociation{
+		ip:    ip.IP(),
+		port:  port,
+		conns: make(map[io.Closer]bool),
+	}
+	if m.associations == nil {
+		m.associations = make(map[*udpAssociation]bool)
+	}
+	m.associations[a] = true
+	return a
+}
+
+// remove unregisters the association, and closes all UDP connections attached to it.
+func (m *udpAssociations) remove(a *udpAssociation) {
+	m.Lock()
+	delete(m.associations, a)
+	conns := a.conns
+	a.conns = nil
+	m.Unlock()
+
+	for conn := range conns {
+		conn.Close() // nolint: errcheck
+	}
+}
+
+// attach finds the association that accepts the UDP source, and attaches the UDP connection to it.
+func (m *udpAssociations) attach(source net.Destination, conn io.Closer) *udpAssociation {
+	m.Lock()
+	defer m.Unlock()
+
+	for a := range m.associations {
+		if a.accepts(source) {
+			a.conns[conn] = true
+			return a
+		}
+	}
+	return nil
+}
+
+func (m *udpAssociations) detach(a *udpAssociation, conn io.Closer) {
+	m.Lock()
+	defer m.Unlock()
+
+	if a.conns != nil {
+		delete(a.conns, conn)
+	}
+}
@@ -97,15 +97,23 @@ func (m *Account) GetPassword() string {
 
 // ServerConfig is the protobuf config for Socks server.
 type ServerConfig struct {
-	AuthType             AuthType          `protobuf:"varint,1,opt,name=auth_type,json=authType,proto3,enum=v2ray.core.proxy.socks.AuthType" json:"auth_type,omitempty"`
-	Accounts             map[string]string `protobuf:"bytes,2,rep,name=accounts,proto3" json:"accounts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Address              *net.IPOrDomain   `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
-	UdpEnabled           bool              `protobuf:"varint,4,opt,name=udp_enabled,json=udpEnabled,proto3" json:"udp_enabled,omitempty"`
-	Timeout              uint32            `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"` // Deprecated: Do not use.
-	UserLevel            uint32            `protobuf:"varint,6,opt,name=user_level,json=userLevel,proto3" json:"user_level,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	AuthType   AuthType          `protobuf:"varint,1,opt,name=auth_type,json=authType,proto3,enum=v2ray.core.proxy.socks.AuthType" json:"auth_type,omitempty"`
+	Accounts   map[string]string `protobuf:"bytes,2,rep,name=accounts,proto3" json:"accounts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Address    *net.IPOrDomain   `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	UdpEnabled bool              `protobuf:"varint,4,opt,name=udp_enabled,json=udpEnabled,proto3" json:"udp_enabled,omitempty"`
+	Timeout    uint32            `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"` // Deprecated: Do not use.
+	UserLevel  uint32            `protobuf:"varint,6,opt,name=user_level,json=userLevel,proto3" json:"user_level,omitempty"`
+	// Whether to accept BIND command of SOCKS4 and SOCKS5. The server listens for
+	// the incoming connection on the address of the TCP connection from the client.
+	// BIND requests are routed, and only allowed if routed to a freedom outbound.
+	BindEnabled bool `protobuf:"varint,7,opt,name=bind_enabled,json=bindEnabled,proto3" json:"bind_enabled,omitempty"`
+	// Whether to accept UDP packets only from the port that the client declares
+	// in UDP ASSOCIATE command. Packets are always restricted to the IP of the
+	// client.
+	UdpStrictPort        bool     `protobuf:"varint,8,opt,name=udp_strict_port,json=udpStrictPort,proto3" json:"udp_strict_port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ServerConfig) Reset()         { *m = ServerConfig{} }
@@ -176,6 +184,20 @@ func (m *ServerConfig) GetUserLevel() uint32 {
 	return 0
 }
 
+func (m *ServerConfig) GetBindEnabled() bool {
+	if m != nil {
+		return m.BindEnabled
+	}
+	return false
+}
+
+func (m *ServerConfig) GetUdpStrictPort() bool {
+	if m != nil {
+		return m.UdpStrictPort
+	}
+	return false
+}
+
 // ClientConfig is the protobuf config for Socks client.
 type ClientConfig struct {
 	// Sever is a list of Socks server addresses.
@@ -230,35 +252,37 @@ func init() {
 }
 
 var fileDescriptor_e86958e2cebd3303 = []byte{
-	// 470 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x5d, 0x8b, 0xd3, 0x40,
-	0x14, 0x75, 0xb2, 0xb6, 0x4d, 0x6f, 0xbb, 0x52, 0x06, 0x59, 0x42, 0x51, 0x8c, 0x05, 0xb1, 0xec,
-	0xc3, 0x44, 0xe2, 0x8b, 0xb8, 0x28, 0xb4, 0xdd, 0x82, 0x82, 0x6c, 0xcb, 0x74, 0x55, 0xf0, 0x25,
-	0xcc, 0x4e, 0x46, 0x37, 0x6c, 0x32, 0x13, 0x66, 0x26, 0xd5, 0xfc, 0x25, 0xff, 0x9f, 0xef, 0x92,
-	0xaf, 0x65, 0x95, 0xee, 0xdb, 0xfd, 0x38, 0xf7, 0xcc, 0x3d, 0xe7, 0x0e, 0xbc, 0xdc, 0x87, 0x9a,
-	0x95, 0x84, 0xab, 0x2c, 0xe0, 0x4a, 0x8b, 0x20, 0xd7, 0xea, 0x57, 0x19, 0x18, 0xc5, 0x6f, 0x4c,
-	0xc0, 0x95, 0xfc, 0x9e, 0xfc, 0x20, 0xb9, 0x56, 0x56, 0xe1, 0x93, 0x0e, 0xa8, 0x05, 0xa9, 0x41,
-	0xa4, 0x06, 0x4d, 0xff, 0x27, 0xe0, 0x2a, 0xcb, 0x94, 0x0c, 0xa4, 0xb0, 0x01, 0x8b, 0x63, 0x2d,
-	0x8c, 0x69, 0x08, 0xa6, 0xaf, 0x0e, 0x03, 0xeb, 0x26, 0x57, 0x69, 0x60, 0x84, 0xde, 0x0b, 0x1d,
-	0x99, 0x5c, 0xf0, 0x66, 0x62, 0xb6, 0x80, 0xc1, 0x82, 0x73, 0x55, 0x48, 0x8b, 0xa7, 0xe0, 0x16,
-	0x46, 0x68, 0xc9, 0x32, 0xe1, 0x21, 0x1f, 0xcd, 0x87, 0xf4, 0x36, 0xaf, 0x7a, 0x39, 0x33, 0xe6,
-	0xa7, 0xd2, 0xb1, 0xe7, 0x34, 0xbd, 0x2e, 0x9f, 0xfd, 0x71, 0x60, 0xbc, 0xab, 0x89, 0x57, 0xb5,
-	0x18, 0xfc, 0x0e, 0x86, 0xac, 0xb0, 0xd7, 0x91, 0x2d, 0xf3, 0x86, 0xe9, 0x51, 0xe8, 0x93, 0xc3,
-	0xd2, 0xc8, 0xa2, 0xb0, 0xd7, 0x97, 0x65, 0x2e, 0xa8, 0xcb, 0xda, 0x08, 0x5f, 0x80, 0xcb, 0x9a,
-	0x95, 0x8c, 0xe7, 0xf8, 0x47, 0xf3, 0x51, 0x18, 0xde, 0x37, 0x7d, 0xf7, 0x59, 0xd2, 0xea, 0x30,
-	0x6b, 0x69, 0x75, 0x49, 0x6f, 0x39, 0xf0, 0x19, 0x0c, 0x5a, 0x97, 0xbc, 0x23, 0x1f, 0xcd, 0x47,
-	0xe1, 0xf3, 0xbb, 0x74, 0x8d, 0x45, 0x44, 0x0a, 0x4b, 0x3e, 0x6e, 0x37, 0xfa, 0x5c, 0x65, 0x2c,
-	0x91, 0xb4, 0x9b, 0xc0, 0xcf, 0x60, 0x54, 0xc4, 0x79, 0x24, 0x24, 0xbb, 0x4a, 0x45, 0xec, 0x3d,
-	0xf4, 0xd1, 0xdc, 0xa5, 0x50, 0xc4, 0xf9, 0xba, 0xa9, 0xe0, 0x27, 0x30, 0xb0, 0x49, 0x26, 0x54,
-	0x61, 0xbd, 0x9e, 0x8f, 0xe6, 0xc7, 0x4b, 0xc7, 0x43, 0xb4, 0x2b, 0xe1, 0xa7, 0x00, 0x95, 0x87,
-	0x51, 0x2a, 0xf6, 0x22, 0xf5, 0xfa, 0x15, 0x80, 0x0e, 0xab, 0xca, 0xa7, 0xaa, 0x30, 0x3d, 0x83,
-	0xe3, 0x7f, 0xb6, 0xc6, 0x13, 0x38, 0xba, 0x11, 0x65, 0x6b, 0x7f, 0x15, 0xe2, 0xc7, 0xd0, 0xdb,
-	0xb3, 0xb4, 0x10, 0xad, 0xed, 0x4d, 0xf2, 0xd6, 0x79, 0x83, 0x66, 0x14, 0xc6, 0xab, 0x34, 0x11,
-	0xd2, 0xb6, 0xb6, 0x2f, 0xa1, 0xdf, 0xdc, 0xd7, 0x43, 0xb5, 0x6b, 0xa7, 0x07, 0x64, 0x76, 0x3f,
-	0xa1, 0x75, 0x6e, 0x2d, 0xe3, 0x5c, 0x25, 0xd2, 0xd2, 0x76, 0xf2, 0xf4, 0x05, 0xb8, 0xdd, 0x45,
-	0xf0, 0x08, 0x06, 0x17, 0x9b, 0x68, 0xf1, 0xf9, 0xf2, 0xc3, 0xe4, 0x01, 0x1e, 0x83, 0xbb, 0x5d,
-	0xec, 0x76, 0x5f, 0x37, 0xf4, 0x7c, 0x82, 0x96, 0xef, 0x61, 0xca, 0x55, 0x76, 0xcf, 0x55, 0xb6,
-	0xe8, 0x5b, 0xaf, 0x0e, 0x7e, 0x3b, 0x27, 0x5f, 0x42, 0xca, 0x4a, 0xb2, 0xaa, 0x10, 0xdb, 0x1a,
-	0xb1, 0xab, 0x1a, 0x57, 0xfd, 0x7a, 0x8f, 0xd7, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x23, 0xac,
-	0x72, 0x71, 0x1a, 0x03, 0x00, 0x00,
+	// 507 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x51, 0x8b, 0xd3, 0x40,
+	0x10, 0x36, 0xad, 0x6d, 0xd3, 0x69, 0xab, 0x65, 0x91, 0x23, 0x14, 0xc5, 0x5c, 0x41, 0x2d, 0xf7,
+	0x90, 0x48, 0x7c, 0x11, 0x0f, 0x85, 0xb6, 0x57, 0x50, 0x90, 0x6b, 0x49, 0x4f, 0x05, 0x5f, 0xc2,
+	0x76, 0xb3, 0x7a, 0xe1, 0x92, 0xdd, 0x65, 0x77, 0x53, 0xcd, 0x5f, 0xf2, 0x57, 0xf8, 0xd3, 0x24,
+	0x9b, 0xa4, 0x9c, 0x47, 0xef, 0x6d, 0xe6, 0x9b, 0x6f, 0xbe, 0x9d, 0x99, 0x6f, 0xe1, 0xd5, 0x3e,
+	0x90, 0xb8, 0xf0, 0x08, 0xcf, 0x7c, 0xc2, 0x25, 0xf5, 0x85, 0xe4, 0xbf, 0x0b, 0x5f, 0x71, 0x72,
+	0xa3, 0x7c, 0xc2, 0xd9, 0x8f, 0xe4, 0xa7, 0x27, 0x24, 0xd7, 0x1c, 0x9d, 0x34, 0x44, 0x49, 0x3d,
+	0x43, 0xf2, 0x0c, 0x69, 0x72, 0x57, 0x80, 0xf0, 0x2c, 0xe3, 0xcc, 0x67, 0x54, 0xfb, 0x38, 0x8e,
+	0x25, 0x55, 0xaa, 0x12, 0x98, 0xbc, 0x3e, 0x4e, 0x34, 0x45, 0xc2, 0x53, 0x5f, 0x51, 0xb9, 0xa7,
+	0x32, 0x52, 0x82, 0x92, 0xaa, 0x63, 0x3a, 0x87, 0xde, 0x9c, 0x10, 0x9e, 0x33, 0x8d, 0x26, 0x60,
+	0xe7, 0x8a, 0x4a, 0x86, 0x33, 0xea, 0x58, 0xae, 0x35, 0xeb, 0x87, 0x87, 0xbc, 0xac, 0x09, 0xac,
+	0xd4, 0x2f, 0x2e, 0x63, 0xa7, 0x55, 0xd5, 0x9a, 0x7c, 0xfa, 0xb7, 0x0d, 0xc3, 0xad, 0x11, 0x5e,
+	0x9a, 0x65, 0xd0, 0x7b, 0xe8, 0xe3, 0x5c, 0x5f, 0x47, 0xba, 0x10, 0x95, 0xd2, 0xa3, 0xc0, 0xf5,
+	0x8e, 0xaf, 0xe6, 0xcd, 0x73, 0x7d, 0x7d, 0x55, 0x08, 0x1a, 0xda, 0xb8, 0x8e, 0xd0, 0x25, 0xd8,
+	0xb8, 0x1a, 0x49, 0x39, 0x2d, 0xb7, 0x3d, 0x1b, 0x04, 0xc1, 0x7d, 0xdd, 0xb7, 0x9f, 0xf5, 0xea,
+	0x3d, 0xd4, 0x8a, 0x69, 0x59, 0x84, 0x07, 0x0d, 0x74, 0x0e, 0xbd, 0xfa, 0x4a, 0x4e, 0xdb, 0xb5,
+	0x66, 0x83, 0xe0, 0xf4, 0xb6, 0x5c, 0x75, 0x22, 0x8f, 0x51, 0xed, 0x7d, 0xda, 0xac, 0xe5, 0x05,
+	0xcf, 0x70, 0xc2, 0xc2, 0xa6, 0x03, 0x3d, 0x87, 0x41, 0x1e, 0x8b, 0x88, 0x32, 0xbc, 0x4b, 0x69,
+	0xec, 0x3c, 0x74, 0xad, 0x99, 0x1d, 0x42, 0x1e, 0x8b, 0x55, 0x85, 0xa0, 0xa7, 0xd0, 0xd3, 0x49,
+	0x46, 0x79, 0xae, 0x9d, 0x8e, 0x6b, 0xcd, 0x46, 0x8b, 0x96, 0x63, 0x85, 0x0d, 0x84, 0x9e, 0x01,
+	0x94, 0x37, 0x8c, 0x52, 0xba, 0xa7, 0xa9, 0xd3, 0x2d, 0x09, 0x61, 0xbf, 0x44, 0x3e, 0x97, 0x00,
+	0x3a, 0x85, 0xe1, 0x2e, 0x61, 0xf1, 0x41, 0xbe, 0x67, 0xe4, 0x07, 0x25, 0xd6, 0xe8, 0xbf, 0x84,
+	0xc7, 0xe5, 0x00, 0x4a, 0xcb, 0x84, 0xe8, 0x48, 0x70, 0xa9, 0x1d, 0xdb, 0xb0, 0x46, 0x79, 0x2c,
+	0xb6, 0x06, 0xdd, 0x70, 0xa9, 0x27, 0xe7, 0x30, 0xfa, 0xef, 0x00, 0x68, 0x0c, 0xed, 0x1b, 0x5a,
+	0xd4, 0x4e, 0x96, 0x21, 0x7a, 0x02, 0x9d, 0x3d, 0x4e, 0x73, 0x5a, 0x3b, 0x58, 0x25, 0xef, 0x5a,
+	0x6f, 0xad, 0x69, 0x08, 0xc3, 0x65, 0x9a, 0x50, 0xa6, 0x6b, 0x07, 0x17, 0xd0, 0xad, 0xbe, 0x8a,
+	0x63, 0x19, 0x03, 0xce, 0x8e, 0x5c, 0xac, 0xf9, 0x54, 0xb5, 0x09, 0x2b, 0x16, 0x0b, 0x9e, 0x30,
+	0x1d, 0xd6, 0x9d, 0x67, 0x2f, 0xc0, 0x6e, 0xcc, 0x45, 0x03, 0xe8, 0x5d, 0xae, 0xa3, 0xf9, 0x97,
+	0xab, 0x8f, 0xe3, 0x07, 0x68, 0x08, 0xf6, 0x66, 0xbe, 0xdd, 0x7e, 0x5b, 0x87, 0x17, 0x63, 0x6b,
+	0xf1, 0x01, 0x26, 0x84, 0x67, 0xf7, 0x18, 0xbc, 0xb1, 0xbe, 0x77, 0x4c, 0xf0, 0xa7, 0x75, 0xf2,
+	0x35, 0x08, 0x71, 0xe1, 0x2d, 0x4b, 0xc6, 0xc6, 0x30, 0xb6, 0x65, 0x61, 0xd7, 0x35, 0x73, 0xbc,
+	0xf9, 0x37, 0x00, 0x98, 0xb1, 0x4e, 0x25, 0x65, 0x03, 0x00, 0x00,
 }
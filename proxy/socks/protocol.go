@@ -31,6 +31,7 @@ const (
 	authNoMatchingMethod = 0xFF
 
 	statusSuccess       = 0x00
+	statusServerFailure = 0x01
 	statusCmdNotSupport = 0x07
 )
 
@@ -43,6 +44,14 @@ var addrParser = protocol.NewAddressParser(
 type ServerSession struct {
 	config *ServerConfig
 	port   net.Port
+
+	// clientAddress is the address of the client. UDP associations are restricted to it.
+	clientAddress net.Address
+	associations  *udpAssociations
+	// association is the UDP association created in the handshake, if any.
+	association *udpAssociation
+	// bind is whether the request is a BIND command. The replies are not written in the handshake.
+	bind bool
 }
 
 func (s *ServerSession) handshake4(cmd byte, reader io.Reader, writer io.Writer) (*protocol.RequestHeader, error) {
@@ -88,6 +97,18 @@ func (s *ServerSession) handshake4(cmd byte, reader io.Reader, writer io.Writer)
 			return nil, err
 		}
 		return request, nil
+	case cmdTCPBind:
+		if !s.config.BindEnabled {
+			writeSocks4Response(writer, socks4RequestRejected, net.AnyIP, net.Port(0)) // nolint: errcheck
+			return nil, newError("TCP bind is not enabled.")
+		}
+		s.bind = true
+		return &protocol.RequestHeader{
+			Command: protocol.RequestCommandTCP,
+			Address: address,
+			Port:    port,
+			Version: socks4Version,
+		}, nil
 	default:
 		writeSocks4Response(writer, socks4RequestRejected, net.AnyIP, net.Port(0)) // nolint: errcheck
 		return nil, newError("unsupported command: ", cmd)
@@ -171,8 +192,12 @@ func (s *ServerSession) handshake5(nMethod byte, reader io.Reader, writer io.Wri
 		}
 		request.Command = protocol.RequestCommandUDP
 	case cmdTCPBind:
-		writeSocks5Response(writer, statusCmdNotSupport, net.AnyIP, net.Port(0)) // nolint: errcheck
-		return nil, newError("TCP bind is not supported.")
+		if !s.config.BindEnabled {
+			writeSocks5Response(writer, statusCmdNotSupport, net.AnyIP, net.Port(0)) // nolint: errcheck
+			return nil, newError("TCP bind is not enabled.")
+		}
+		request.Command = protocol.RequestCommandTCP
+		s.bind = true
 	default:
 		writeSocks5Response(writer, statusCmdNotSupport, net.AnyIP, net.Port(0)) // nolint: errcheck
 		return nil, newError("unknown command ", cmd)
@@ -187,6 +212,10 @@ func (s *ServerSession) handshake5(nMethod byte, reader io.Reader, writer io.Wri
 	request.Address = addr
 	request.Port = port
 
+	if s.bind {
+		return request, nil
+	}
+
 	responseAddress := net.AnyIP
 	responsePort := net.Port(1717)
 	if request.Command == protocol.RequestCommandUDP {
@@ -196,6 +225,15 @@ func (s *ServerSession) handshake5(nMethod byte, reader io.Reader, writer io.Wri
 		}
 		responseAddress = addr
 		responsePort = s.port
+
+		// Register the association before the response, as the client may send UDP packets right after it.
+		if s.associations != nil && s.clientAddress != nil && s.clientAddress.Family().IsIP() {
+			var declaredPort net.Port
+			if s.config.UdpStrictPort {
+				declaredPort = request.Port
+			}
+			s.association = s.associations.add(s.clientAddress, declaredPort)
+		}
 	}
 	if err := writeSocks5Response(writer, statusSuccess, responseAddress, responsePort); err != nil {
 		return nil, err
@@ -18,8 +18,12 @@ import (
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/common/task"
 	"v2ray.com/core/features"
+	"v2ray.com/core/features/outbound"
 	"v2ray.com/core/features/policy"
 	"v2ray.com/core/features/routing"
+	"v2ray.com/core/features/stats"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/proxy/freedom"
 	"v2ray.com/core/transport/internet"
 	"v2ray.com/core/transport/internet/udp"
 )
@@ -28,6 +32,10 @@ import (
 type Server struct {
 	config        *ServerConfig
 	policyManager policy.Manager
+	router        routing.Router
+	outbounds     outbound.Manager
+	stats         stats.Manager
+	associations  udpAssociations
 }
 
 // NewServer creates a new Server object.
@@ -37,6 +45,16 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 		config:        config,
 		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
 	}
+	if config.BindEnabled {
+		// BIND requests are routed like other requests, before anything listens for them.
+		if err := core.RequireFeatures(ctx, func(r routing.Router, om outbound.Manager, sm stats.Manager) {
+			s.router = r
+			s.outbounds = om
+			s.stats = sm
+		}); err != nil {
+			return nil, err
+		}
+	}
 	return s, nil
 }
 
@@ -91,12 +109,17 @@ func (s *Server) processTCP(ctx context.Context, conn internet.Connection, dispa
 	}
 
 	svrSession := &ServerSession{
-		config: s.config,
-		port:   inbound.Gateway.Port,
+		config:        s.config,
+		port:          inbound.Gateway.Port,
+		clientAddress: inbound.Source.Address,
+		associations:  &s.associations,
 	}
 
 	reader := &buf.BufferedReader{Reader: buf.NewReader(conn)}
 	request, err := svrSession.Handshake(reader, conn)
+	if svrSession.association != nil {
+		defer s.associations.remove(svrSession.association)
+	}
 	if err != nil {
 		if inbound != nil && inbound.Source.IsValid() {
 			log.Record(&log.AccessMessage{
@@ -116,6 +139,11 @@ func (s *Server) processTCP(ctx context.Context, conn internet.Connection, dispa
 		newError("failed to clear deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
 
+	if svrSession.bind {
+		newError("TCP Bind request for ", request.Destination()).WriteToLog(session.ExportIDToError(ctx))
+		return s.handleBind(ctx, request, reader, conn)
+	}
+
 	if request.Command == protocol.RequestCommandTCP {
 		dest := request.Destination()
 		newError("TCP Connect request to ", dest).WriteToLog(session.ExportIDToError(ctx))
@@ -139,11 +167,193 @@ func (s *Server) processTCP(ctx context.Context, conn internet.Connection, dispa
 }
 
 func (*Server) handleUDP(c io.Reader) error {
-	// The TCP connection closes after this method returns. We need to wait until
-	// the client closes it.
+	// The TCP connection closes after this method returns, and so does the UDP association.
+	// We need to wait until the client closes it.
 	return common.Error2(io.Copy(buf.DiscardBytes, c))
 }
 
+// handleBind listens for the incoming connection of a BIND command, and relays it to the client.
+//
+// The request is routed like the one of a CONNECT command, so routing rules can reject it. But BIND waits for the
+// peer to connect to this host, which none of the outbound protocols can ask a remote server to do. So BIND is only
+// allowed if routed to a freedom outbound, and the listener is opened on the local address of the client
+// connection. Traffic with the peer is counted in the stats of the user, as the policy of the user level asks.
+func (s *Server) handleBind(ctx context.Context, request *protocol.RequestHeader, reader io.Reader, conn internet.Connection) error {
+	reply := func(success bool, addr net.Address, port net.Port) error {
+		if request.Version == socks4Version {
+			code := byte(socks4RequestGranted)
+			if !success {
+				code = socks4RequestRejected
+			}
+			// Replies in SOCKS4 only carry IPv4 addresses. Clients use the address of the server for 0.0.0.0.
+			if !addr.Family().IsIPv4() {
+				addr = net.AnyIP
+			}
+			return writeSocks4Response(conn, code, addr, port)
+		}
+		status := byte(statusSuccess)
+		if !success {
+			status = statusServerFailure
+		}
+		return writeSocks5Response(conn, status, addr, port)
+	}
+
+	if err := s.routeBind(ctx, request.Destination()); err != nil {
+		reply(false, net.AnyIP, net.Port(0)) // nolint: errcheck
+		if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.Source.IsValid() {
+			log.Record(&log.AccessMessage{
+				From:   inbound.Source,
+				To:     request.Destination(),
+				Status: log.AccessRejected,
+				Reason: err,
+			})
+		}
+		return newError("BIND to ", request.Destination(), " is rejected").Base(err)
+	}
+
+	localAddr, ok := conn.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		reply(false, net.AnyIP, net.Port(0)) // nolint: errcheck
+		return newError("unable to bind on local address ", conn.LocalAddr())
+	}
+	listener, err := internet.ListenSystem(ctx, &net.TCPAddr{IP: localAddr.IP}, nil)
+	if err != nil {
+		reply(false, net.AnyIP, net.Port(0)) // nolint: errcheck
+		return newError("failed to listen for BIND").Base(err)
+	}
+	defer listener.Close()
+
+	bindAddr := net.DestinationFromAddr(listener.Addr())
+	if addr := s.config.Address.AsAddress(); addr != nil {
+		bindAddr.Address = addr
+	}
+	if err := reply(true, bindAddr.Address, bindAddr.Port); err != nil {
+		return err
+	}
+	newError("listening on ", listener.Addr(), " for BIND").WriteToLog(session.ExportIDToError(ctx))
+
+	plcy := s.policy()
+	peer, err := acceptBindPeer(ctx, listener, request.Address, plcy.Timeouts.ConnectionIdle)
+	if err != nil {
+		reply(false, net.AnyIP, net.Port(0)) // nolint: errcheck
+		return newError("failed to accept incoming connection for BIND").Base(err)
+	}
+	defer peer.Close()
+
+	peerAddr := net.DestinationFromAddr(peer.RemoteAddr())
+	peer = s.countBindTraffic(ctx, peer)
+	if err := reply(true, peerAddr.Address, peerAddr.Port); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, plcy.Timeouts.ConnectionIdle)
+
+	requestDone := func() error {
+		defer timer.SetTimeout(plcy.Timeouts.DownlinkOnly)
+		if err := buf.Copy(buf.NewReader(reader), buf.NewWriter(peer), buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transport all TCP request").Base(err)
+		}
+		return nil
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(plcy.Timeouts.UplinkOnly)
+		if err := buf.Copy(buf.NewReader(peer), buf.NewWriter(conn), buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transport all TCP response").Base(err)
+		}
+		return nil
+	}
+
+	if err := task.Run(ctx, task.OnSuccess(requestDone, task.Close(peer)), responseDone); err != nil {
+		return newError("connection ends").Base(err)
+	}
+	return nil
+}
+
+// routeBind picks the outbound of a BIND request to dest by the routing rules, and returns an error unless it is
+// a freedom outbound.
+func (s *Server) routeBind(ctx context.Context, dest net.Destination) error {
+	ctx = session.ContextWithOutbound(ctx, &session.Outbound{
+		Target: dest,
+	})
+
+	var handler outbound.Handler
+	if tag, err := s.router.PickRoute(ctx); err == nil {
+		if handler = s.outbounds.GetHandler(tag); handler == nil {
+			newError("non existing tag: ", tag).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		}
+	}
+	if handler == nil {
+		handler = s.outbounds.GetDefaultHandler()
+	}
+	if handler == nil {
+		return newError("default outbound handler not exist")
+	}
+
+	if h, ok := handler.(proxy.GetOutbound); ok {
+		if _, ok := h.GetOutbound().(*freedom.Handler); ok {
+			newError("taking detour [", handler.Tag(), "] for BIND to ", dest).WriteToLog(session.ExportIDToError(ctx))
+			return nil
+		}
+	}
+	return newError("BIND is routed to outbound [", handler.Tag(), "], which can't accept connections")
+}
+
+// countBindTraffic counts the traffic of the peer of a BIND command in the stats of the user, as if the peer was
+// the destination of a dispatched connection.
+func (s *Server) countBindTraffic(ctx context.Context, peer net.Conn) net.Conn {
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil || inbound.User == nil || len(inbound.User.Email) == 0 {
+		return peer
+	}
+	user := inbound.User
+	p := s.policyManager.ForLevel(user.Level)
+
+	// Reads from the peer count into Uplink of the connection, which is the downlink of the user, and vice versa.
+	conn := &internet.StatCouterConnection{Connection: peer}
+	if p.Stats.UserUplink {
+		if c, _ := stats.GetOrRegisterCounter(s.stats, stats.UserUplinkCounterName(user.Email)); c != nil {
+			conn.Downlink = c
+		}
+	}
+	if p.Stats.UserDownlink {
+		if c, _ := stats.GetOrRegisterCounter(s.stats, stats.UserDownlinkCounterName(user.Email)); c != nil {
+			conn.Uplink = c
+		}
+	}
+	return conn
+}
+
+// acceptBindPeer accepts the first connection from the expected peer. Connections from other IPs are
+// rejected, if the expected peer is given as an IP.
+func acceptBindPeer(ctx context.Context, listener net.Listener, expected net.Address, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		listener.Close() // nolint: errcheck
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+		if expected.Family().IsIP() && expected != net.AnyIP && expected != net.AnyIPv6 {
+			if source := net.DestinationFromAddr(conn.RemoteAddr()).Address; !source.IP().Equal(expected.IP()) {
+				newError("rejecting BIND connection from ", source, ", expecting ", expected).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+				conn.Close() // nolint: errcheck
+				continue
+			}
+		}
+		return conn, nil
+	}
+}
+
 func (s *Server) transport(ctx context.Context, reader io.Reader, writer io.Writer, dest net.Destination, dispatcher routing.Dispatcher) error {
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, s.policy().Timeouts.ConnectionIdle)
@@ -205,9 +415,16 @@ func (s *Server) handleUDPPayload(ctx context.Context, conn internet.Connection,
 		conn.Write(udpMessage.Bytes()) // nolint: errcheck
 	})
 
-	if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.Source.IsValid() {
-		newError("client UDP connection from ", inbound.Source).WriteToLog(session.ExportIDToError(ctx))
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil || !inbound.Source.IsValid() {
+		return newError("UDP source not specified")
+	}
+	association := s.associations.attach(inbound.Source, conn)
+	if association == nil {
+		return newError("no UDP association for ", inbound.Source)
 	}
+	defer s.associations.detach(association, conn)
+	newError("client UDP connection from ", inbound.Source).WriteToLog(session.ExportIDToError(ctx))
 
 	reader := buf.NewPacketReader(conn)
 	for {
@@ -16,6 +16,8 @@ type MemoryAccount struct {
 	AlterIDs []*protocol.ID
 	// Security type of the account. Used for client connections.
 	Security protocol.SecurityType
+	// AEADHeader indicates whether to use AEAD request header. Used for client connections.
+	AEADHeader bool
 }
 
 // AnyValidID returns an ID that is either the main ID or one of the alternative IDs if any.
@@ -44,8 +46,9 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 	}
 	protoID := protocol.NewID(id)
 	return &MemoryAccount{
-		ID:       protoID,
-		AlterIDs: protocol.NewAlterIDs(protoID, uint16(a.AlterId)),
-		Security: a.SecuritySettings.GetSecurityType(),
+		ID:         protoID,
+		AlterIDs:   protocol.NewAlterIDs(protoID, uint16(a.AlterId)),
+		Security:   a.SecuritySettings.GetSecurityType(),
+		AEADHeader: a.AeadHeader,
 	}, nil
 }
@@ -0,0 +1,82 @@
+package aead_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	. "v2ray.com/core/proxy/vmess/aead"
+)
+
+func newKey() []byte {
+	key := make([]byte, 16)
+	common.Must2(rand.Read(key))
+	return key
+}
+
+func TestAuthID(t *testing.T) {
+	key := newKey()
+	now := time.Now().Unix()
+	authID := CreateAuthID(key, now)
+
+	ts, ok := NewAuthIDDecoder(key).Decode(authID)
+	if !ok {
+		t.Fatal("failed to decode auth ID")
+	}
+	if ts != now {
+		t.Error("unexpected timestamp: ", ts, " want ", now)
+	}
+
+	if _, ok := NewAuthIDDecoder(newKey()).Decode(authID); ok {
+		t.Error("auth ID is decoded with wrong key")
+	}
+
+	if authID == CreateAuthID(key, now) {
+		t.Error("auth IDs of the same time are identical")
+	}
+}
+
+func TestHeader(t *testing.T) {
+	key := newKey()
+	header := []byte("test header")
+
+	sealed := SealHeader(key, header)
+	var authID [AuthIDLen]byte
+	copy(authID[:], sealed)
+
+	opened, err := OpenHeader(key, authID, bytes.NewReader(sealed[AuthIDLen:]))
+	common.Must(err)
+	if r := cmp.Diff(opened, header); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := OpenHeader(newKey(), authID, bytes.NewReader(sealed[AuthIDLen:])); err == nil {
+		t.Error("header is opened with wrong key")
+	}
+
+	sealed[len(sealed)-1] ^= 1
+	if _, err := OpenHeader(key, authID, bytes.NewReader(sealed[AuthIDLen:])); err == nil {
+		t.Error("tampered header is opened")
+	}
+}
+
+func TestResponseHeader(t *testing.T) {
+	key := newKey()
+	iv := newKey()
+	header := []byte{1, 2, 3, 4}
+
+	sealed := SealResponseHeader(key, iv, header)
+	opened, err := OpenResponseHeader(key, iv, bytes.NewReader(sealed))
+	common.Must(err)
+	if r := cmp.Diff(opened, header); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := OpenResponseHeader(key, newKey(), bytes.NewReader(sealed)); err == nil {
+		t.Error("response header is opened with wrong IV")
+	}
+}
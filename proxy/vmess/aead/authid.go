@@ -0,0 +1,56 @@
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"hash/crc32"
+
+	"v2ray.com/core/common"
+)
+
+// AuthIDLen is the length of an auth ID in bytes.
+const AuthIDLen = 16
+
+// CreateAuthID returns an auth ID for the given command key at the given unix time. The auth ID is
+// a single AES block of timestamp, random bytes and CRC32 checksum, encrypted by a key derived
+// from the command key.
+func CreateAuthID(cmdKey []byte, t int64) [AuthIDLen]byte {
+	var authID [AuthIDLen]byte
+	binary.BigEndian.PutUint64(authID[:8], uint64(t))
+	common.Must2(rand.Read(authID[8:12]))
+	binary.BigEndian.PutUint32(authID[12:], crc32.ChecksumIEEE(authID[:12]))
+
+	newAuthIDBlock(cmdKey).Encrypt(authID[:], authID[:])
+	return authID
+}
+
+func newAuthIDBlock(cmdKey []byte) cipher.Block {
+	block, err := aes.NewCipher(KDF16(cmdKey, kdfSaltConstAuthIDEncryptionKey))
+	common.Must(err)
+	return block
+}
+
+// AuthIDDecoder decrypts auth IDs of a single user.
+type AuthIDDecoder struct {
+	block cipher.Block
+}
+
+// NewAuthIDDecoder creates a new AuthIDDecoder for the given command key.
+func NewAuthIDDecoder(cmdKey []byte) *AuthIDDecoder {
+	return &AuthIDDecoder{
+		block: newAuthIDBlock(cmdKey),
+	}
+}
+
+// Decode decrypts the given auth ID and returns its timestamp. It returns false if the auth ID
+// was not created by the command key of this decoder.
+func (d *AuthIDDecoder) Decode(authID [AuthIDLen]byte) (int64, bool) {
+	var plaintext [AuthIDLen]byte
+	d.block.Decrypt(plaintext[:], authID[:])
+	if crc32.ChecksumIEEE(plaintext[:12]) != binary.BigEndian.Uint32(plaintext[12:]) {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(plaintext[:8])), true
+}
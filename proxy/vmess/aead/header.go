@@ -0,0 +1,132 @@
+package aead
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/crypto"
+)
+
+const (
+	connectionNonceLen = 8
+	gcmNonceLen        = 12
+	maxHeaderLen       = 1<<16 - 1
+)
+
+// headerSealer seals a header into a sealed 2-byte length, followed by the sealed header itself.
+type headerSealer struct {
+	lengthAEAD   cipher.AEAD
+	lengthNonce  []byte
+	payloadAEAD  cipher.AEAD
+	payloadNonce []byte
+	ad           []byte
+}
+
+func (s *headerSealer) seal(dst []byte, header []byte) []byte {
+	var length [2]byte
+	binary.BigEndian.PutUint16(length[:], uint16(len(header)))
+	dst = s.lengthAEAD.Seal(dst, s.lengthNonce, length[:], s.ad)
+	return s.payloadAEAD.Seal(dst, s.payloadNonce, header, s.ad)
+}
+
+func (s *headerSealer) readLength(reader io.Reader) (int, error) {
+	sealedLength := make([]byte, 2+s.lengthAEAD.Overhead())
+	if _, err := io.ReadFull(reader, sealedLength); err != nil {
+		return 0, newError("failed to read header length").Base(err)
+	}
+	length, err := s.lengthAEAD.Open(sealedLength[:0], s.lengthNonce, sealedLength, s.ad)
+	if err != nil {
+		return 0, newError("failed to open header length").Base(err)
+	}
+	return int(binary.BigEndian.Uint16(length)), nil
+}
+
+func (s *headerSealer) readPayload(reader io.Reader, length int) ([]byte, error) {
+	payload := make([]byte, length+s.payloadAEAD.Overhead())
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, newError("failed to read header").Base(err)
+	}
+	header, err := s.payloadAEAD.Open(payload[:0], s.payloadNonce, payload, s.ad)
+	if err != nil {
+		return nil, newError("failed to open header").Base(err)
+	}
+	return header, nil
+}
+
+func newRequestHeaderSealer(cmdKey []byte, authID []byte, connectionNonce []byte) *headerSealer {
+	aid, nonce := string(authID), string(connectionNonce)
+	return &headerSealer{
+		lengthAEAD:   crypto.NewAesGcm(KDF16(cmdKey, kdfSaltConstVMessHeaderLengthAEADKey, aid, nonce)),
+		lengthNonce:  KDF(cmdKey, kdfSaltConstVMessHeaderLengthAEADIV, aid, nonce)[:gcmNonceLen],
+		payloadAEAD:  crypto.NewAesGcm(KDF16(cmdKey, kdfSaltConstVMessHeaderPayloadAEADKey, aid, nonce)),
+		payloadNonce: KDF(cmdKey, kdfSaltConstVMessHeaderPayloadAEADIV, aid, nonce)[:gcmNonceLen],
+		ad:           authID,
+	}
+}
+
+func newResponseHeaderSealer(key []byte, iv []byte) *headerSealer {
+	return &headerSealer{
+		lengthAEAD:   crypto.NewAesGcm(KDF16(key, kdfSaltConstResponseHeaderLengthKey)),
+		lengthNonce:  KDF(iv, kdfSaltConstResponseHeaderLengthIV)[:gcmNonceLen],
+		payloadAEAD:  crypto.NewAesGcm(KDF16(key, kdfSaltConstResponseHeaderPayloadKey)),
+		payloadNonce: KDF(iv, kdfSaltConstResponseHeaderPayloadIV)[:gcmNonceLen],
+	}
+}
+
+// SealHeader seals the given request header with the command key of the user. The result
+// consists of the auth ID, a random connection nonce, the sealed header length, and the sealed
+// header.
+func SealHeader(cmdKey []byte, header []byte) []byte {
+	if len(header) > maxHeaderLen {
+		panic("VMess header too long")
+	}
+
+	authID := CreateAuthID(cmdKey, time.Now().Unix())
+	var connectionNonce [connectionNonceLen]byte
+	common.Must2(rand.Read(connectionNonce[:]))
+
+	sealer := newRequestHeaderSealer(cmdKey, authID[:], connectionNonce[:])
+	output := make([]byte, 0, AuthIDLen+connectionNonceLen+2+len(header)+2*sealer.payloadAEAD.Overhead())
+	output = append(output, authID[:]...)
+	output = append(output, connectionNonce[:]...)
+	return sealer.seal(output, header)
+}
+
+// OpenHeader reads a sealed request header following the given auth ID from the reader, and
+// returns the header in plaintext.
+func OpenHeader(cmdKey []byte, authID [AuthIDLen]byte, reader io.Reader) ([]byte, error) {
+	var connectionNonce [connectionNonceLen]byte
+	if _, err := io.ReadFull(reader, connectionNonce[:]); err != nil {
+		return nil, newError("failed to read connection nonce").Base(err)
+	}
+
+	sealer := newRequestHeaderSealer(cmdKey, authID[:], connectionNonce[:])
+	length, err := sealer.readLength(reader)
+	if err != nil {
+		return nil, err
+	}
+	return sealer.readPayload(reader, length)
+}
+
+// SealResponseHeader seals the given response header with the response body key and IV.
+func SealResponseHeader(key []byte, iv []byte, header []byte) []byte {
+	if len(header) > maxHeaderLen {
+		panic("VMess header too long")
+	}
+	return newResponseHeaderSealer(key, iv).seal(nil, header)
+}
+
+// OpenResponseHeader reads a sealed response header from the reader, and returns the header in
+// plaintext.
+func OpenResponseHeader(key []byte, iv []byte, reader io.Reader) ([]byte, error) {
+	sealer := newResponseHeaderSealer(key, iv)
+	length, err := sealer.readLength(reader)
+	if err != nil {
+		return nil, err
+	}
+	return sealer.readPayload(reader, length)
+}
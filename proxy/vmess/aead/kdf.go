@@ -0,0 +1,42 @@
+// Package aead implements the AEAD header format of VMess. The request header is sealed with
+// AES-GCM under keys derived from the command key of the user, and is identified by an encrypted
+// auth ID instead of a hash of user ID and timestamp.
+package aead
+
+//go:generate errorgen
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+const (
+	kdfSaltConstVMessAEADKDF              = "VMess AEAD KDF"
+	kdfSaltConstAuthIDEncryptionKey       = "AES Auth ID Encryption"
+	kdfSaltConstVMessHeaderLengthAEADKey  = "VMess Header AEAD Key_Length"
+	kdfSaltConstVMessHeaderLengthAEADIV   = "VMess Header AEAD Nonce_Length"
+	kdfSaltConstVMessHeaderPayloadAEADKey = "VMess Header AEAD Key"
+	kdfSaltConstVMessHeaderPayloadAEADIV  = "VMess Header AEAD Nonce"
+	kdfSaltConstResponseHeaderLengthKey   = "AEAD Resp Header Len Key"
+	kdfSaltConstResponseHeaderLengthIV    = "AEAD Resp Header Len IV"
+	kdfSaltConstResponseHeaderPayloadKey  = "AEAD Resp Header Key"
+	kdfSaltConstResponseHeaderPayloadIV   = "AEAD Resp Header IV"
+)
+
+// KDF derives a 32-byte key from the given key and path, by chaining HMAC-SHA256 over each element.
+func KDF(key []byte, path ...string) []byte {
+	h := hmac.New(sha256.New, []byte(kdfSaltConstVMessAEADKDF))
+	h.Write(key) // nolint: errcheck
+	derived := h.Sum(nil)
+	for _, p := range path {
+		h = hmac.New(sha256.New, derived)
+		h.Write([]byte(p)) // nolint: errcheck
+		derived = h.Sum(derived[:0])
+	}
+	return derived
+}
+
+// KDF16 returns the first 16 bytes of KDF.
+func KDF16(key []byte, path ...string) []byte {
+	return KDF(key, path...)[:16]
+}
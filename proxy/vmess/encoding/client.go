@@ -1,8 +1,10 @@
 package encoding
 
 import (
+	"bytes"
 	"crypto/md5"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/binary"
 	"hash"
 	"hash/fnv"
@@ -18,6 +20,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
 	"v2ray.com/core/proxy/vmess"
+	"v2ray.com/core/proxy/vmess/aead"
 )
 
 func hashTimestamp(h hash.Hash, t protocol.Timestamp) []byte {
@@ -30,6 +33,7 @@ func hashTimestamp(h hash.Hash, t protocol.Timestamp) []byte {
 
 // ClientSession stores connection session info for VMess client.
 type ClientSession struct {
+	isAEAD          bool
 	idHash          protocol.IDHash
 	requestBodyKey  [16]byte
 	requestBodyIV   [16]byte
@@ -39,17 +43,27 @@ type ClientSession struct {
 	responseHeader  byte
 }
 
-// NewClientSession creates a new ClientSession.
-func NewClientSession(idHash protocol.IDHash) *ClientSession {
+// NewClientSession creates a new ClientSession. If isAEAD is true, the request header is sent in
+// AEAD format.
+func NewClientSession(isAEAD bool, idHash protocol.IDHash) *ClientSession {
 	randomBytes := make([]byte, 33) // 16 + 16 + 1
 	common.Must2(rand.Read(randomBytes))
 
-	session := &ClientSession{}
+	session := &ClientSession{
+		isAEAD: isAEAD,
+	}
 	copy(session.requestBodyKey[:], randomBytes[:16])
 	copy(session.requestBodyIV[:], randomBytes[16:32])
 	session.responseHeader = randomBytes[32]
-	session.responseBodyKey = md5.Sum(session.requestBodyKey[:])
-	session.responseBodyIV = md5.Sum(session.requestBodyIV[:])
+	if isAEAD {
+		responseBodyKey := sha256.Sum256(session.requestBodyKey[:])
+		copy(session.responseBodyKey[:], responseBodyKey[:16])
+		responseBodyIV := sha256.Sum256(session.requestBodyIV[:])
+		copy(session.responseBodyIV[:], responseBodyIV[:16])
+	} else {
+		session.responseBodyKey = md5.Sum(session.requestBodyKey[:])
+		session.responseBodyIV = md5.Sum(session.requestBodyIV[:])
+	}
 	session.idHash = idHash
 
 	return session
@@ -58,9 +72,11 @@ func NewClientSession(idHash protocol.IDHash) *ClientSession {
 func (c *ClientSession) EncodeRequestHeader(header *protocol.RequestHeader, writer io.Writer) error {
 	timestamp := protocol.NewTimestampGenerator(protocol.NowTime(), 30)()
 	account := header.User.Account.(*vmess.MemoryAccount)
-	idHash := c.idHash(account.AnyValidID().Bytes())
-	common.Must2(serial.WriteUint64(idHash, uint64(timestamp)))
-	common.Must2(writer.Write(idHash.Sum(nil)))
+	if !c.isAEAD {
+		idHash := c.idHash(account.AnyValidID().Bytes())
+		common.Must2(serial.WriteUint64(idHash, uint64(timestamp)))
+		common.Must2(writer.Write(idHash.Sum(nil)))
+	}
 
 	buffer := buf.New()
 	defer buffer.Release()
@@ -92,6 +108,11 @@ func (c *ClientSession) EncodeRequestHeader(header *protocol.RequestHeader, writ
 		fnv1a.Sum(hashBytes[:0])
 	}
 
+	if c.isAEAD {
+		common.Must2(writer.Write(aead.SealHeader(account.ID.CmdKey(), buffer.Bytes())))
+		return nil
+	}
+
 	iv := hashTimestamp(md5.New(), timestamp)
 	aesStream := crypto.NewAesEncryptionStream(account.ID.CmdKey(), iv[:])
 	aesStream.XORKeyStream(buffer.Bytes(), buffer.Bytes())
@@ -164,10 +185,21 @@ func (c *ClientSession) DecodeResponseHeader(reader io.Reader) (*protocol.Respon
 	aesStream := crypto.NewAesDecryptionStream(c.responseBodyKey[:], c.responseBodyIV[:])
 	c.responseReader = crypto.NewCryptionReader(aesStream, reader)
 
+	if c.isAEAD {
+		header, err := aead.OpenResponseHeader(c.responseBodyKey[:], c.responseBodyIV[:], reader)
+		if err != nil {
+			return nil, newError("failed to read response header").Base(err).AtWarning()
+		}
+		return c.parseResponseHeader(bytes.NewReader(header))
+	}
+	return c.parseResponseHeader(c.responseReader)
+}
+
+func (c *ClientSession) parseResponseHeader(reader io.Reader) (*protocol.ResponseHeader, error) {
 	buffer := buf.StackNew()
 	defer buffer.Release()
 
-	if _, err := buffer.ReadFullFrom(c.responseReader, 4); err != nil {
+	if _, err := buffer.ReadFullFrom(reader, 4); err != nil {
 		return nil, newError("failed to read response header").Base(err).AtWarning()
 	}
 
@@ -184,7 +216,7 @@ func (c *ClientSession) DecodeResponseHeader(reader io.Reader) (*protocol.Respon
 		dataLen := int32(buffer.Byte(3))
 
 		buffer.Clear()
-		if _, err := buffer.ReadFullFrom(c.responseReader, dataLen); err != nil {
+		if _, err := buffer.ReadFullFrom(reader, dataLen); err != nil {
 			return nil, newError("failed to read response command").Base(err)
 		}
 		command, err := UnmarshalCommand(cmdID, buffer.Bytes())
@@ -42,7 +42,7 @@ func TestRequestSerialization(t *testing.T) {
 	}
 
 	buffer := buf.New()
-	client := NewClientSession(protocol.DefaultIDHash)
+	client := NewClientSession(false, protocol.DefaultIDHash)
 	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
 
 	buffer2 := buf.New()
@@ -92,7 +92,7 @@ func TestInvalidRequest(t *testing.T) {
 	}
 
 	buffer := buf.New()
-	client := NewClientSession(protocol.DefaultIDHash)
+	client := NewClientSession(false, protocol.DefaultIDHash)
 	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
 
 	buffer2 := buf.New()
@@ -133,7 +133,7 @@ func TestMuxRequest(t *testing.T) {
 	}
 
 	buffer := buf.New()
-	client := NewClientSession(protocol.DefaultIDHash)
+	client := NewClientSession(false, protocol.DefaultIDHash)
 	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
 
 	buffer2 := buf.New()
@@ -154,3 +154,118 @@ func TestMuxRequest(t *testing.T) {
 		t.Error(r)
 	}
 }
+
+func TestAEADRequestSerialization(t *testing.T) {
+	user := &protocol.MemoryUser{
+		Level: 0,
+		Email: "test@v2ray.com",
+	}
+	id := uuid.New()
+	account := &vmess.Account{
+		Id:         id.String(),
+		AlterId:    0,
+		AeadHeader: true,
+	}
+	user.Account = toAccount(account)
+
+	expectedRequest := &protocol.RequestHeader{
+		Version:  1,
+		User:     user,
+		Command:  protocol.RequestCommandTCP,
+		Address:  net.DomainAddress("www.v2ray.com"),
+		Port:     net.Port(443),
+		Security: protocol.SecurityType_AES128_GCM,
+	}
+
+	buffer := buf.New()
+	client := NewClientSession(true, protocol.DefaultIDHash)
+	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
+
+	buffer2 := buf.New()
+	buffer2.Write(buffer.Bytes())
+
+	sessionHistory := NewSessionHistory()
+	defer common.Close(sessionHistory)
+
+	userValidator := vmess.NewTimedUserValidator(protocol.DefaultIDHash)
+	userValidator.Add(user)
+	defer common.Close(userValidator)
+
+	server := NewServerSession(userValidator, sessionHistory)
+	actualRequest, err := server.DecodeRequestHeader(buffer)
+	common.Must(err)
+
+	if r := cmp.Diff(actualRequest, expectedRequest, cmp.AllowUnexported(protocol.ID{})); r != "" {
+		t.Error(r)
+	}
+
+	_, err = NewServerSession(userValidator, sessionHistory).DecodeRequestHeader(buffer2)
+	// anti replay attack
+	if err == nil {
+		t.Error("nil error")
+	}
+
+	responseBuffer := buf.New()
+	expectedResponse := &protocol.ResponseHeader{
+		Option: protocol.ResponseOptionConnectionReuse,
+		Command: &protocol.CommandSwitchAccount{
+			ID:       uuid.New(),
+			AlterIds: 16,
+			Level:    1,
+			ValidMin: 30,
+			Host:     net.DomainAddress("www.v2ray.com"),
+			Port:     net.Port(443),
+		},
+	}
+	server.EncodeResponseHeader(expectedResponse, responseBuffer)
+	actualResponse, err := client.DecodeResponseHeader(responseBuffer)
+	common.Must(err)
+
+	if r := cmp.Diff(actualResponse, expectedResponse); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestAEADForced(t *testing.T) {
+	user := &protocol.MemoryUser{
+		Level: 0,
+		Email: "test@v2ray.com",
+	}
+	id := uuid.New()
+	account := &vmess.Account{
+		Id:      id.String(),
+		AlterId: 0,
+	}
+	user.Account = toAccount(account)
+
+	request := &protocol.RequestHeader{
+		Version:  1,
+		User:     user,
+		Command:  protocol.RequestCommandTCP,
+		Address:  net.DomainAddress("www.v2ray.com"),
+		Port:     net.Port(443),
+		Security: protocol.SecurityType_AES128_GCM,
+	}
+
+	sessionHistory := NewSessionHistory()
+	defer common.Close(sessionHistory)
+
+	userValidator := vmess.NewTimedUserValidator(protocol.DefaultIDHash)
+	userValidator.Add(user)
+	defer common.Close(userValidator)
+
+	for _, isAEAD := range []bool{false, true} {
+		buffer := buf.New()
+		common.Must(NewClientSession(isAEAD, protocol.DefaultIDHash).EncodeRequestHeader(request, buffer))
+
+		server := NewServerSession(userValidator, sessionHistory)
+		server.SetAEADForced(true)
+		_, err := server.DecodeRequestHeader(buffer)
+		if isAEAD && err != nil {
+			t.Error("failed to decode AEAD header: ", err)
+		}
+		if !isAEAD && err == nil {
+			t.Error("legacy header is not rejected")
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package encoding
 
 import (
+	"bytes"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/binary"
 	"hash/fnv"
 	"io"
@@ -18,6 +20,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/task"
 	"v2ray.com/core/proxy/vmess"
+	"v2ray.com/core/proxy/vmess/aead"
 )
 
 type sessionId struct {
@@ -97,6 +100,8 @@ type ServerSession struct {
 	responseBodyIV  [16]byte
 	responseWriter  io.Writer
 	responseHeader  byte
+	isAEAD          bool
+	isAEADForced    bool
 }
 
 // NewServerSession creates a new ServerSession, using the given UserValidator.
@@ -108,6 +113,11 @@ func NewServerSession(validator *vmess.TimedUserValidator, sessionHistory *Sessi
 	}
 }
 
+// SetAEADForced sets whether the session rejects requests in legacy header format.
+func (s *ServerSession) SetAEADForced(isAEADForced bool) {
+	s.isAEADForced = isAEADForced
+}
+
 func parseSecurityType(b byte) protocol.SecurityType {
 	if _, f := protocol.SecurityType_name[int32(b)]; f {
 		st := protocol.SecurityType(b)
@@ -121,25 +131,40 @@ func parseSecurityType(b byte) protocol.SecurityType {
 }
 
 // DecodeRequestHeader decodes and returns (if successful) a RequestHeader from an input stream.
+// Both legacy and AEAD header formats are accepted, unless AEAD header is forced.
 func (s *ServerSession) DecodeRequestHeader(reader io.Reader) (*protocol.RequestHeader, error) {
 	buffer := buf.New()
 	defer buffer.Release()
 
-	if _, err := buffer.ReadFullFrom(reader, protocol.IDBytesLen); err != nil {
+	var authID [aead.AuthIDLen]byte
+	if _, err := io.ReadFull(reader, authID[:]); err != nil {
 		return nil, newError("failed to read request header").Base(err)
 	}
 
-	user, timestamp, valid := s.userValidator.Get(buffer.Bytes())
-	if !valid {
-		return nil, newError("invalid user")
+	var decryptor io.Reader
+	user, timestamp, valid := s.userValidator.Get(authID[:])
+	if valid {
+		if s.isAEADForced {
+			return nil, newError("legacy header is rejected for user ", user.Email)
+		}
+		iv := hashTimestamp(md5.New(), timestamp)
+		aesStream := crypto.NewAesDecryptionStream(user.Account.(*vmess.MemoryAccount).ID.CmdKey(), iv[:])
+		decryptor = crypto.NewCryptionReader(aesStream, reader)
+	} else {
+		aeadUser, err := s.userValidator.GetAEAD(authID)
+		if err != nil {
+			return nil, err
+		}
+		header, err := aead.OpenHeader(aeadUser.Account.(*vmess.MemoryAccount).ID.CmdKey(), authID, reader)
+		if err != nil {
+			return nil, newError("failed to read request header").Base(err)
+		}
+		user = aeadUser
+		s.isAEAD = true
+		decryptor = bytes.NewReader(header)
 	}
-
-	iv := hashTimestamp(md5.New(), timestamp)
 	vmessAccount := user.Account.(*vmess.MemoryAccount)
 
-	aesStream := crypto.NewAesDecryptionStream(vmessAccount.ID.CmdKey(), iv[:])
-	decryptor := crypto.NewCryptionReader(aesStream, reader)
-
 	buffer.Clear()
 	if _, err := buffer.ReadFullFrom(decryptor, 38); err != nil {
 		return nil, newError("failed to read request header").Base(err)
@@ -273,13 +298,32 @@ func (s *ServerSession) DecodeRequestBody(request *protocol.RequestHeader, reade
 
 // EncodeResponseHeader writes encoded response header into the given writer.
 func (s *ServerSession) EncodeResponseHeader(header *protocol.ResponseHeader, writer io.Writer) {
-	s.responseBodyKey = md5.Sum(s.requestBodyKey[:])
-	s.responseBodyIV = md5.Sum(s.requestBodyIV[:])
+	if s.isAEAD {
+		responseBodyKey := sha256.Sum256(s.requestBodyKey[:])
+		copy(s.responseBodyKey[:], responseBodyKey[:16])
+		responseBodyIV := sha256.Sum256(s.requestBodyIV[:])
+		copy(s.responseBodyIV[:], responseBodyIV[:16])
+	} else {
+		s.responseBodyKey = md5.Sum(s.requestBodyKey[:])
+		s.responseBodyIV = md5.Sum(s.requestBodyIV[:])
+	}
 
 	aesStream := crypto.NewAesEncryptionStream(s.responseBodyKey[:], s.responseBodyIV[:])
 	encryptionWriter := crypto.NewCryptionWriter(aesStream, writer)
 	s.responseWriter = encryptionWriter
 
+	if s.isAEAD {
+		buffer := buf.New()
+		defer buffer.Release()
+
+		common.Must2(buffer.Write([]byte{s.responseHeader, byte(header.Option)}))
+		if err := MarshalCommand(header.Command, buffer); err != nil {
+			common.Must2(buffer.Write([]byte{0x00, 0x00}))
+		}
+		common.Must2(writer.Write(aead.SealResponseHeader(s.responseBodyKey[:], s.responseBodyIV[:], buffer.Bytes())))
+		return
+	}
+
 	common.Must2(encryptionWriter.Write([]byte{s.responseHeader, byte(header.Option)}))
 	err := MarshalCommand(header.Command, encryptionWriter)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	proto "github.com/golang/protobuf/proto"
 	math "math"
 	protocol "v2ray.com/core/common/protocol"
+	fallback "v2ray.com/core/proxy/fallback"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -109,9 +110,13 @@ type Config struct {
 	Default              *DefaultConfig   `protobuf:"bytes,2,opt,name=default,proto3" json:"default,omitempty"`
 	Detour               *DetourConfig    `protobuf:"bytes,3,opt,name=detour,proto3" json:"detour,omitempty"`
 	SecureEncryptionOnly bool             `protobuf:"varint,4,opt,name=secure_encryption_only,json=secureEncryptionOnly,proto3" json:"secure_encryption_only,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	// Fallbacks for connections that fail VMess handshake.
+	Fallback []*fallback.Fallback `protobuf:"bytes,5,rep,name=fallback,proto3" json:"fallback,omitempty"`
+	// Rejects requests in legacy header format, accepting AEAD header only.
+	DisableLegacyHeader  bool     `protobuf:"varint,6,opt,name=disable_legacy_header,json=disableLegacyHeader,proto3" json:"disable_legacy_header,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
@@ -167,6 +172,20 @@ func (m *Config) GetSecureEncryptionOnly() bool {
 	return false
 }
 
+func (m *Config) GetFallback() []*fallback.Fallback {
+	if m != nil {
+		return m.Fallback
+	}
+	return nil
+}
+
+func (m *Config) GetDisableLegacyHeader() bool {
+	if m != nil {
+		return m.DisableLegacyHeader
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*DetourConfig)(nil), "v2ray.core.proxy.vmess.inbound.DetourConfig")
 	proto.RegisterType((*DefaultConfig)(nil), "v2ray.core.proxy.vmess.inbound.DefaultConfig")
@@ -178,26 +197,30 @@ func init() {
 }
 
 var fileDescriptor_a47d4a41f33382d2 = []byte{
-	// 333 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x90, 0x4f, 0x4f, 0xf2, 0x40,
-	0x10, 0xc6, 0xd3, 0xc2, 0x0b, 0xbc, 0x8b, 0x78, 0x68, 0x88, 0xa9, 0x1e, 0x48, 0xd3, 0x13, 0x26,
-	0xba, 0x9b, 0x54, 0x3e, 0x80, 0x11, 0x8c, 0xe1, 0x24, 0x69, 0x22, 0x07, 0x2f, 0xa4, 0x6c, 0x07,
-	0xd3, 0x64, 0xbb, 0x43, 0xb6, 0x5b, 0x62, 0xcf, 0x7e, 0x1b, 0x3f, 0xa5, 0x61, 0x5a, 0xfc, 0x77,
-	0x90, 0xdb, 0xce, 0xce, 0xef, 0x79, 0xe6, 0x99, 0x61, 0x62, 0x17, 0x99, 0xa4, 0xe2, 0x12, 0x73,
-	0x21, 0xd1, 0x80, 0xd8, 0x1a, 0x7c, 0xad, 0xc4, 0x2e, 0x87, 0xa2, 0x10, 0x99, 0x5e, 0x63, 0xa9,
-	0x53, 0x21, 0x51, 0x6f, 0xb2, 0x17, 0xbe, 0x35, 0x68, 0xd1, 0x1b, 0x1d, 0x04, 0x06, 0x38, 0xc1,
-	0x9c, 0x60, 0xde, 0xc0, 0x17, 0x97, 0xbf, 0x0c, 0x25, 0xe6, 0x39, 0x6a, 0x41, 0x62, 0x89, 0x4a,
-	0x94, 0x05, 0x98, 0xda, 0x2a, 0x1c, 0xb1, 0x93, 0x19, 0x58, 0x2c, 0xcd, 0x94, 0x06, 0x78, 0xa7,
-	0xcc, 0xb5, 0xe8, 0x3b, 0x81, 0x33, 0xfe, 0x1f, 0xbb, 0x16, 0xc3, 0x5b, 0x36, 0x98, 0xc1, 0x26,
-	0x29, 0x95, 0x6d, 0x80, 0x73, 0xd6, 0x4b, 0x94, 0x05, 0xb3, 0xca, 0x52, 0xc2, 0x06, 0x71, 0x97,
-	0xea, 0x79, 0xea, 0x0d, 0xd9, 0x3f, 0x05, 0x3b, 0x50, 0xbe, 0x4b, 0xff, 0x75, 0x11, 0xbe, 0xb9,
-	0xac, 0xd3, 0x68, 0x27, 0xac, 0xbd, 0x1f, 0xed, 0x3b, 0x41, 0x6b, 0xdc, 0x8f, 0x02, 0xfe, 0x6d,
-	0x8d, 0x3a, 0x22, 0x3f, 0x44, 0xe4, 0x4f, 0x05, 0x98, 0x98, 0x68, 0xef, 0x81, 0x75, 0xd3, 0x3a,
-	0x02, 0x19, 0xf7, 0xa3, 0x6b, 0xfe, 0xf7, 0xfe, 0xfc, 0x47, 0xe2, 0xf8, 0xa0, 0xf6, 0x66, 0xac,
-	0x93, 0xd2, 0xae, 0x7e, 0x8b, 0x7c, 0xae, 0x8e, 0xfb, 0x7c, 0x5d, 0x26, 0x6e, 0xb4, 0xde, 0x84,
-	0x9d, 0x15, 0x20, 0x4b, 0x03, 0x2b, 0xd0, 0xd2, 0x54, 0x5b, 0x9b, 0xa1, 0x5e, 0xa1, 0x56, 0x95,
-	0xdf, 0x0e, 0x9c, 0x71, 0x2f, 0x1e, 0xd6, 0xdd, 0xfb, 0xcf, 0xe6, 0xa3, 0x56, 0xd5, 0xdd, 0x82,
-	0x85, 0x12, 0xf3, 0x23, 0x03, 0x17, 0xce, 0x73, 0xb7, 0x79, 0xbe, 0xbb, 0xa3, 0x65, 0x14, 0x27,
-	0x15, 0x9f, 0xee, 0xd9, 0x05, 0xb1, 0x4b, 0x62, 0xe7, 0x35, 0xb0, 0xee, 0xd0, 0xad, 0x6e, 0x3e,
-	0x02, 0x00, 0x00, 0xff, 0xff, 0xbf, 0x49, 0x84, 0xe1, 0x3e, 0x02, 0x00, 0x00,
+	// 396 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0x4f, 0x6f, 0xd4, 0x30,
+	0x10, 0xc5, 0x95, 0xb4, 0xcd, 0x2e, 0x2e, 0xe5, 0x60, 0x0a, 0x0a, 0x3d, 0xac, 0xa2, 0x70, 0x59,
+	0x10, 0xd8, 0x52, 0xe8, 0x1d, 0x44, 0x97, 0x3f, 0x95, 0x90, 0x58, 0x59, 0xa2, 0x07, 0x2e, 0x91,
+	0xe3, 0xcc, 0x96, 0x08, 0xc7, 0xb3, 0x72, 0x92, 0x15, 0xf9, 0x4a, 0x7c, 0x42, 0x8e, 0xa8, 0x93,
+	0xa4, 0x50, 0x40, 0xf4, 0x66, 0xfb, 0xfd, 0xde, 0x1b, 0x3f, 0x9b, 0xc9, 0x5d, 0xe6, 0x75, 0x2f,
+	0x0c, 0xd6, 0xd2, 0xa0, 0x07, 0xb9, 0xf5, 0xf8, 0xad, 0x97, 0xbb, 0x1a, 0x9a, 0x46, 0x56, 0xae,
+	0xc0, 0xce, 0x95, 0xd2, 0xa0, 0xdb, 0x54, 0x97, 0x62, 0xeb, 0xb1, 0x45, 0xbe, 0x98, 0x0c, 0x1e,
+	0x04, 0xc1, 0x82, 0x60, 0x31, 0xc2, 0x27, 0x4f, 0xfe, 0x08, 0x34, 0x58, 0xd7, 0xe8, 0x24, 0x99,
+	0x0d, 0x5a, 0xd9, 0x35, 0xe0, 0x87, 0xa8, 0x93, 0xa7, 0xff, 0x9c, 0xbd, 0xd1, 0xd6, 0x16, 0xda,
+	0x7c, 0xbd, 0x31, 0x36, 0x5d, 0xb0, 0xbb, 0x2b, 0x68, 0xb1, 0xf3, 0x67, 0x74, 0xca, 0xef, 0xb1,
+	0xb0, 0xc5, 0x38, 0x48, 0x82, 0xe5, 0x1d, 0x15, 0xb6, 0x98, 0xbe, 0x62, 0x47, 0x2b, 0xd8, 0xe8,
+	0xce, 0xb6, 0x23, 0xf0, 0x88, 0xcd, 0xb5, 0x6d, 0xc1, 0xe7, 0x55, 0x49, 0xd8, 0x91, 0x9a, 0xd1,
+	0xfe, 0xbc, 0xe4, 0xc7, 0xec, 0xc0, 0xc2, 0x0e, 0x6c, 0x1c, 0xd2, 0xf9, 0xb0, 0x49, 0x7f, 0x84,
+	0x2c, 0x1a, 0xbd, 0xa7, 0x6c, 0xff, 0xea, 0x9a, 0x71, 0x90, 0xec, 0x2d, 0x0f, 0xb3, 0x44, 0xfc,
+	0x56, 0x79, 0xa8, 0x23, 0xa6, 0x3a, 0xe2, 0x53, 0x03, 0x5e, 0x11, 0xcd, 0xdf, 0xb1, 0x59, 0x39,
+	0x5c, 0x81, 0x82, 0x0f, 0xb3, 0xe7, 0xe2, 0xff, 0x6f, 0x25, 0x6e, 0xdc, 0x58, 0x4d, 0x6e, 0xbe,
+	0x62, 0x51, 0x49, 0x5d, 0xe3, 0x3d, 0xca, 0x79, 0x76, 0x7b, 0xce, 0xaf, 0x97, 0x51, 0xa3, 0x97,
+	0x9f, 0xb2, 0x87, 0x0d, 0x98, 0xce, 0x43, 0x0e, 0xce, 0xf8, 0x7e, 0xdb, 0x56, 0xe8, 0x72, 0x74,
+	0xb6, 0x8f, 0xf7, 0x93, 0x60, 0x39, 0x57, 0xc7, 0x83, 0xfa, 0xe6, 0x5a, 0xfc, 0xe8, 0x6c, 0xcf,
+	0x5f, 0xb2, 0xf9, 0xf4, 0x01, 0xf1, 0x01, 0xd5, 0x7f, 0xfc, 0xf7, 0xf4, 0x89, 0x10, 0x6f, 0xc7,
+	0x85, 0xba, 0x36, 0xf1, 0x8c, 0x3d, 0x28, 0xab, 0x46, 0x17, 0x16, 0x72, 0x0b, 0x97, 0xda, 0xf4,
+	0xf9, 0x17, 0xd0, 0x25, 0xf8, 0x38, 0xa2, 0xa9, 0xf7, 0x47, 0xf1, 0x03, 0x69, 0xef, 0x49, 0x7a,
+	0xbd, 0x66, 0xa9, 0xc1, 0xfa, 0x96, 0x96, 0xeb, 0xe0, 0xf3, 0x6c, 0x5c, 0x7e, 0x0f, 0x17, 0x17,
+	0x99, 0xd2, 0xbd, 0x38, 0xbb, 0x62, 0xd7, 0xc4, 0x5e, 0x10, 0x7b, 0x3e, 0x00, 0x45, 0x44, 0x1f,
+	0xf4, 0xe2, 0xe7, 0x00, 0x5b, 0x06, 0x21, 0x4a, 0xdf, 0x02, 0x00, 0x00,
 }
@@ -25,6 +25,8 @@ import (
 	feature_inbound "v2ray.com/core/features/inbound"
 	"v2ray.com/core/features/policy"
 	"v2ray.com/core/features/routing"
+	"v2ray.com/core/features/stats"
+	"v2ray.com/core/proxy/fallback"
 	"v2ray.com/core/proxy/vmess"
 	"v2ray.com/core/proxy/vmess/encoding"
 	"v2ray.com/core/transport/internet"
@@ -104,11 +106,14 @@ func (v *userByEmail) Remove(email string) bool {
 type Handler struct {
 	policyManager         policy.Manager
 	inboundHandlerManager feature_inbound.Manager
+	statsManager          stats.Manager
 	clients               *vmess.TimedUserValidator
 	usersByEmail          *userByEmail
 	detours               *DetourConfig
 	sessionHistory        *encoding.SessionHistory
 	secure                bool
+	aeadOnly              bool
+	fallbacks             []*fallback.Fallback
 }
 
 // New creates a new VMess inbound handler.
@@ -117,11 +122,14 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 	handler := &Handler{
 		policyManager:         v.GetFeature(policy.ManagerType()).(policy.Manager),
 		inboundHandlerManager: v.GetFeature(feature_inbound.ManagerType()).(feature_inbound.Manager),
+		statsManager:          v.GetFeature(stats.ManagerType()).(stats.Manager),
 		clients:               vmess.NewTimedUserValidator(protocol.DefaultIDHash),
 		detours:               config.Detour,
 		usersByEmail:          newUserByEmail(config.GetDefaultValue()),
 		sessionHistory:        encoding.NewSessionHistory(),
 		secure:                config.SecureEncryptionOnly,
+		aeadOnly:              config.DisableLegacyHeader,
+		fallbacks:             config.Fallback,
 	}
 
 	for _, user := range config.User {
@@ -163,6 +171,7 @@ func (h *Handler) AddUser(ctx context.Context, user *protocol.MemoryUser) error
 	if len(user.Email) > 0 && !h.usersByEmail.Add(user) {
 		return newError("User ", user.Email, " already exists.")
 	}
+	stats.RegisterUserQuota(h.statsManager, user)
 	return h.clients.Add(user)
 }
 
@@ -222,8 +231,16 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 		return newError("unable to set read deadline").Base(err).AtWarning()
 	}
 
-	reader := &buf.BufferedReader{Reader: buf.NewReader(connection)}
+	var rawReader io.Reader = connection
+	var recorder *fallback.Recorder
+	if len(h.fallbacks) > 0 {
+		recorder = fallback.NewRecorder(connection)
+		rawReader = recorder
+	}
+
+	reader := &buf.BufferedReader{Reader: buf.NewReader(rawReader)}
 	svrSession := encoding.NewServerSession(h.clients, h.sessionHistory)
+	svrSession.SetAEADForced(h.aeadOnly)
 	request, err := svrSession.DecodeRequestHeader(reader)
 	if err != nil {
 		if errors.Cause(err) != io.EOF {
@@ -234,9 +251,15 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 				Reason: err,
 			})
 			err = newError("invalid request from ", connection.RemoteAddr()).Base(err).AtInfo()
+			if recorder != nil {
+				return h.fallback(ctx, connection, recorder, err)
+			}
 		}
 		return err
 	}
+	if recorder != nil {
+		recorder.Stop()
+	}
 
 	if h.secure && isInsecureEncryption(request.Security) {
 		log.Record(&log.AccessMessage{
@@ -249,6 +272,17 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 		return newError("client is using insecure encryption: ", request.Security)
 	}
 
+	if err := stats.CheckUserQuota(h.statsManager, request.User); err != nil {
+		log.Record(&log.AccessMessage{
+			From:   connection.RemoteAddr(),
+			To:     "",
+			Status: log.AccessRejected,
+			Reason: err,
+			Email:  request.User.Email,
+		})
+		return newError("rejecting request from ", connection.RemoteAddr()).Base(err)
+	}
+
 	if request.Command != protocol.RequestCommandMux {
 		ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
 			From:   connection.RemoteAddr(),
@@ -314,6 +348,14 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 	return nil
 }
 
+func (h *Handler) fallback(ctx context.Context, connection internet.Connection, recorder *fallback.Recorder, err error) error {
+	newError("falling back on failed handshake").Base(err).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+	if err := connection.SetReadDeadline(time.Time{}); err != nil {
+		newError("unable to set back read deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
+	}
+	return fallback.Serve(ctx, h.fallbacks, connection, recorder, h.policyManager.ForLevel(0))
+}
+
 func (h *Handler) generateCommand(ctx context.Context, request *protocol.RequestHeader) protocol.ResponseCommand {
 	if h.detours != nil {
 		tag := h.detours.To
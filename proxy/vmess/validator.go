@@ -11,6 +11,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
 	"v2ray.com/core/common/task"
+	"v2ray.com/core/proxy/vmess/aead"
 )
 
 const (
@@ -19,8 +20,9 @@ const (
 )
 
 type user struct {
-	user    protocol.MemoryUser
-	lastSec protocol.Timestamp
+	user          protocol.MemoryUser
+	lastSec       protocol.Timestamp
+	authIDDecoder *aead.AuthIDDecoder
 }
 
 // TimedUserValidator is a user Validator based on time.
@@ -31,6 +33,9 @@ type TimedUserValidator struct {
 	hasher   protocol.IDHash
 	baseTime protocol.Timestamp
 	task     *task.Periodic
+
+	authIDAccess sync.Mutex
+	authIDs      map[[aead.AuthIDLen]byte]int64
 }
 
 type indexTimePair struct {
@@ -45,6 +50,7 @@ func NewTimedUserValidator(hasher protocol.IDHash) *TimedUserValidator {
 		userHash: make(map[[16]byte]indexTimePair, 1024),
 		hasher:   hasher,
 		baseTime: protocol.Timestamp(time.Now().Unix() - cacheDurationSec*2),
+		authIDs:  make(map[[aead.AuthIDLen]byte]int64, 128),
 	}
 	tuv.task = &task.Periodic{
 		Interval: updateInterval,
@@ -109,6 +115,14 @@ func (v *TimedUserValidator) updateUserHash() {
 	if expire > v.baseTime {
 		v.removeExpiredHashes(uint32(expire - v.baseTime))
 	}
+
+	v.authIDAccess.Lock()
+	for authID, expireSec := range v.authIDs {
+		if expireSec < now.Unix() {
+			delete(v.authIDs, authID)
+		}
+	}
+	v.authIDAccess.Unlock()
 }
 
 func (v *TimedUserValidator) Add(u *protocol.MemoryUser) error {
@@ -118,8 +132,9 @@ func (v *TimedUserValidator) Add(u *protocol.MemoryUser) error {
 	nowSec := time.Now().Unix()
 
 	uu := &user{
-		user:    *u,
-		lastSec: protocol.Timestamp(nowSec - cacheDurationSec),
+		user:          *u,
+		lastSec:       protocol.Timestamp(nowSec - cacheDurationSec),
+		authIDDecoder: aead.NewAuthIDDecoder(u.Account.(*MemoryAccount).ID.CmdKey()),
 	}
 	v.users = append(v.users, uu)
 	v.generateNewHashes(protocol.Timestamp(nowSec), uu)
@@ -142,6 +157,40 @@ func (v *TimedUserValidator) Get(userHash []byte) (*protocol.MemoryUser, protoco
 	return nil, 0, false
 }
 
+// GetAEAD returns the user who created the given auth ID of AEAD header. An auth ID is accepted
+// only once, and only if its timestamp is within the cache duration from now.
+func (v *TimedUserValidator) GetAEAD(authID [aead.AuthIDLen]byte) (*protocol.MemoryUser, error) {
+	v.RLock()
+	var found *protocol.MemoryUser
+	var timestamp int64
+	for _, u := range v.users {
+		if t, ok := u.authIDDecoder.Decode(authID); ok {
+			user := u.user
+			found = &user
+			timestamp = t
+			break
+		}
+	}
+	v.RUnlock()
+
+	if found == nil {
+		return nil, newError("invalid user")
+	}
+
+	nowSec := time.Now().Unix()
+	if timestamp < nowSec-cacheDurationSec || timestamp > nowSec+cacheDurationSec {
+		return nil, newError("invalid timestamp of auth ID: ", timestamp)
+	}
+
+	v.authIDAccess.Lock()
+	defer v.authIDAccess.Unlock()
+	if _, replayed := v.authIDs[authID]; replayed {
+		return nil, newError("duplicated auth ID, possibly under replay attack")
+	}
+	v.authIDs[authID] = nowSec + cacheDurationSec*2
+	return found, nil
+}
+
 func (v *TimedUserValidator) Remove(email string) bool {
 	v.Lock()
 	defer v.Unlock()
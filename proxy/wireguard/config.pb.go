@@ -0,0 +1,277 @@
+package wireguard
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+	net "v2ray.com/core/common/net"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// CIDR is an IP network in the form of IP and prefix length.
+type CIDR struct {
+	// IP address, 4 or 16 bytes.
+	Ip                   []byte   `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Prefix               uint32   `protobuf:"varint,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CIDR) Reset()         { *m = CIDR{} }
+func (m *CIDR) String() string { return proto.CompactTextString(m) }
+func (*CIDR) ProtoMessage()    {}
+func (*CIDR) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7ebce2d83e06edb2, []int{0}
+}
+
+func (m *CIDR) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CIDR.Unmarshal(m, b)
+}
+func (m *CIDR) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CIDR.Marshal(b, m, deterministic)
+}
+func (m *CIDR) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CIDR.Merge(m, src)
+}
+func (m *CIDR) XXX_Size() int {
+	return xxx_messageInfo_CIDR.Size(m)
+}
+func (m *CIDR) XXX_DiscardUnknown() {
+	xxx_messageInfo_CIDR.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CIDR proto.InternalMessageInfo
+
+func (m *CIDR) GetIp() []byte {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *CIDR) GetPrefix() uint32 {
+	if m != nil {
+		return m.Prefix
+	}
+	return 0
+}
+
+type PeerConfig struct {
+	// Curve25519 public key of the peer.
+	PublicKey []byte `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// Optional pre-shared symmetric key.
+	PreSharedKey []byte `protobuf:"bytes,2,opt,name=pre_shared_key,json=preSharedKey,proto3" json:"pre_shared_key,omitempty"`
+	// Endpoint of the peer.
+	Address *net.IPOrDomain `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Port    uint32          `protobuf:"varint,4,opt,name=port,proto3" json:"port,omitempty"`
+	// Interval of persistent keepalive in seconds. 0 to disable.
+	KeepAlive uint32 `protobuf:"varint,5,opt,name=keep_alive,json=keepAlive,proto3" json:"keep_alive,omitempty"`
+	// Networks that are routed to this peer.
+	AllowedIps           []*CIDR  `protobuf:"bytes,6,rep,name=allowed_ips,json=allowedIps,proto3" json:"allowed_ips,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerConfig) Reset()         { *m = PeerConfig{} }
+func (m *PeerConfig) String() string { return proto.CompactTextString(m) }
+func (*PeerConfig) ProtoMessage()    {}
+func (*PeerConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7ebce2d83e06edb2, []int{1}
+}
+
+func (m *PeerConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerConfig.Unmarshal(m, b)
+}
+func (m *PeerConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerConfig.Marshal(b, m, deterministic)
+}
+func (m *PeerConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerConfig.Merge(m, src)
+}
+func (m *PeerConfig) XXX_Size() int {
+	return xxx_messageInfo_PeerConfig.Size(m)
+}
+func (m *PeerConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerConfig proto.InternalMessageInfo
+
+func (m *PeerConfig) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *PeerConfig) GetPreSharedKey() []byte {
+	if m != nil {
+		return m.PreSharedKey
+	}
+	return nil
+}
+
+func (m *PeerConfig) GetAddress() *net.IPOrDomain {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *PeerConfig) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *PeerConfig) GetKeepAlive() uint32 {
+	if m != nil {
+		return m.KeepAlive
+	}
+	return 0
+}
+
+func (m *PeerConfig) GetAllowedIps() []*CIDR {
+	if m != nil {
+		return m.AllowedIps
+	}
+	return nil
+}
+
+type Config struct {
+	// Curve25519 private key of the local interface.
+	SecretKey []byte `protobuf:"bytes,1,opt,name=secret_key,json=secretKey,proto3" json:"secret_key,omitempty"`
+	// IP addresses of the local interface, 4 or 16 bytes each.
+	Address [][]byte      `protobuf:"bytes,2,rep,name=address,proto3" json:"address,omitempty"`
+	Peer    []*PeerConfig `protobuf:"bytes,3,rep,name=peer,proto3" json:"peer,omitempty"`
+	// MTU of the local interface. 0 for default.
+	Mtu uint32 `protobuf:"varint,4,opt,name=mtu,proto3" json:"mtu,omitempty"`
+	// Reserved bytes in the header of every outgoing message. Some
+	// WireGuard servers use them to identify clients.
+	Reserved             []byte   `protobuf:"bytes,5,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	UserLevel            uint32   `protobuf:"varint,6,opt,name=user_level,json=userLevel,proto3" json:"user_level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Config) Reset()         { *m = Config{} }
+func (m *Config) String() string { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()    {}
+func (*Config) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7ebce2d83e06edb2, []int{2}
+}
+
+func (m *Config) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Config.Unmarshal(m, b)
+}
+func (m *Config) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Config.Marshal(b, m, deterministic)
+}
+func (m *Config) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Config.Merge(m, src)
+}
+func (m *Config) XXX_Size() int {
+	return xxx_messageInfo_Config.Size(m)
+}
+func (m *Config) XXX_DiscardUnknown() {
+	xxx_messageInfo_Config.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Config proto.InternalMessageInfo
+
+func (m *Config) GetSecretKey() []byte {
+	if m != nil {
+		return m.SecretKey
+	}
+	return nil
+}
+
+func (m *Config) GetAddress() [][]byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *Config) GetPeer() []*PeerConfig {
+	if m != nil {
+		return m.Peer
+	}
+	return nil
+}
+
+func (m *Config) GetMtu() uint32 {
+	if m != nil {
+		return m.Mtu
+	}
+	return 0
+}
+
+func (m *Config) GetReserved() []byte {
+	if m != nil {
+		return m.Reserved
+	}
+	return nil
+}
+
+func (m *Config) GetUserLevel() uint32 {
+	if m != nil {
+		return m.UserLevel
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*CIDR)(nil), "v2ray.core.proxy.wireguard.CIDR")
+	proto.RegisterType((*PeerConfig)(nil), "v2ray.core.proxy.wireguard.PeerConfig")
+	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.wireguard.Config")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/proxy/wireguard/config.proto", fileDescriptor_7ebce2d83e06edb2)
+}
+
+var fileDescriptor_7ebce2d83e06edb2 = []byte{
+	// 421 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0xd1, 0x8a, 0xd3, 0x40,
+	0x14, 0x25, 0x49, 0x8d, 0xf6, 0xb6, 0x2e, 0x32, 0x0f, 0x32, 0x14, 0x94, 0x58, 0x44, 0x03, 0xc2,
+	0x04, 0xea, 0x9b, 0x3e, 0xad, 0x5d, 0x90, 0xaa, 0x60, 0x89, 0xa0, 0xe0, 0x4b, 0xc8, 0x26, 0x77,
+	0xd7, 0x61, 0x93, 0xcc, 0x70, 0x27, 0xed, 0x6e, 0xde, 0xfd, 0x1a, 0x3f, 0xc5, 0xaf, 0x92, 0x99,
+	0x64, 0x9b, 0x45, 0xd8, 0xbe, 0xcd, 0x3d, 0x73, 0xce, 0xdc, 0x73, 0x0e, 0x03, 0x6f, 0xf6, 0x2b,
+	0xca, 0x3b, 0x51, 0xa8, 0x3a, 0x29, 0x14, 0x61, 0xa2, 0x49, 0xdd, 0x74, 0xc9, 0xb5, 0x24, 0xbc,
+	0xdc, 0xe5, 0x54, 0x26, 0x85, 0x6a, 0x2e, 0xe4, 0xa5, 0xd0, 0xa4, 0x5a, 0xc5, 0x16, 0xb7, 0x64,
+	0x42, 0xe1, 0x88, 0xe2, 0x40, 0x5c, 0xbc, 0xfe, 0xef, 0xa1, 0x42, 0xd5, 0xb5, 0x6a, 0x92, 0x06,
+	0xdb, 0x24, 0x2f, 0x4b, 0x42, 0x63, 0xfa, 0x47, 0x96, 0x02, 0x26, 0xeb, 0xcd, 0x59, 0xca, 0x4e,
+	0xc0, 0x97, 0x9a, 0x7b, 0x91, 0x17, 0xcf, 0x53, 0x5f, 0x6a, 0xf6, 0x14, 0x42, 0x4d, 0x78, 0x21,
+	0x6f, 0xb8, 0x1f, 0x79, 0xf1, 0xe3, 0x74, 0x98, 0x96, 0xbf, 0x7d, 0x80, 0x2d, 0x22, 0xad, 0x9d,
+	0x13, 0xf6, 0x0c, 0x40, 0xef, 0xce, 0x2b, 0x59, 0x64, 0x57, 0xd8, 0x0d, 0xf2, 0x69, 0x8f, 0x7c,
+	0xc6, 0x8e, 0xbd, 0x84, 0x13, 0x4d, 0x98, 0x99, 0x5f, 0x39, 0x61, 0xe9, 0x28, 0xbe, 0xa3, 0xcc,
+	0x35, 0xe1, 0x37, 0x07, 0x5a, 0xd6, 0x7b, 0x78, 0x38, 0x98, 0xe2, 0x41, 0xe4, 0xc5, 0xb3, 0xd5,
+	0x0b, 0x71, 0x27, 0x5a, 0x6f, 0x5d, 0x34, 0xd8, 0x8a, 0xcd, 0xf6, 0x2b, 0x9d, 0xa9, 0x3a, 0x97,
+	0x4d, 0x7a, 0xab, 0x60, 0x0c, 0x26, 0x5a, 0x51, 0xcb, 0x27, 0xce, 0xa6, 0x3b, 0x5b, 0x57, 0x57,
+	0x88, 0x3a, 0xcb, 0x2b, 0xb9, 0x47, 0xfe, 0xc0, 0xdd, 0x4c, 0x2d, 0x72, 0x6a, 0x01, 0x76, 0x0a,
+	0xb3, 0xbc, 0xaa, 0xd4, 0x35, 0x96, 0x99, 0xd4, 0x86, 0x87, 0x51, 0x10, 0xcf, 0x56, 0x91, 0xb8,
+	0xbf, 0x4e, 0x61, 0x2b, 0x4a, 0x61, 0x10, 0x6d, 0xb4, 0x59, 0xfe, 0xf5, 0x20, 0x1c, 0x2b, 0x30,
+	0x58, 0x10, 0xb6, 0x77, 0x2b, 0xe8, 0x11, 0x1b, 0x8e, 0x8f, 0xe1, 0xfc, 0x28, 0x88, 0xe7, 0xa3,
+	0xf3, 0x77, 0x30, 0xd1, 0x88, 0xc4, 0x03, 0xb7, 0xff, 0xd5, 0xb1, 0xfd, 0x63, 0xe3, 0xa9, 0xd3,
+	0xb0, 0x27, 0x10, 0xd4, 0xed, 0x6e, 0x08, 0x6d, 0x8f, 0x6c, 0x01, 0x8f, 0x08, 0x0d, 0xd2, 0x1e,
+	0x4b, 0x97, 0x78, 0x9e, 0x1e, 0x66, 0x6b, 0x71, 0x67, 0x90, 0xb2, 0x0a, 0xf7, 0x58, 0xf1, 0xb0,
+	0xef, 0xc3, 0x22, 0x5f, 0x2c, 0xf0, 0xe1, 0x13, 0x3c, 0x2f, 0x54, 0x7d, 0x64, 0xff, 0xd6, 0xfb,
+	0x39, 0x3d, 0x0c, 0x7f, 0xfc, 0xc5, 0xf7, 0x55, 0x9a, 0x77, 0x62, 0x6d, 0x99, 0x5b, 0xc7, 0xfc,
+	0x21, 0x09, 0x3f, 0xda, 0xcb, 0xf3, 0xd0, 0x7d, 0xab, 0xb7, 0xff, 0x06, 0x00, 0xc4, 0x2b, 0x8a,
+	0xea, 0xca, 0x02, 0x00, 0x00,
+}
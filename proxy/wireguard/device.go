@@ -0,0 +1,661 @@
+// +build !confonly
+
+package wireguard
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/blake2s"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/signal/done"
+)
+
+const (
+	defaultMTU = 1420
+	// maxQueuedPackets is the number of packets kept for a peer while waiting for a handshake.
+	maxQueuedPackets = 1024
+	maxMessageSize   = 65535
+)
+
+// peerConn sends messages to a peer.
+type peerConn interface {
+	io.Writer
+	io.Closer
+}
+
+// dialFunc opens a datagram connection to a peer endpoint.
+type dialFunc func(dest net.Destination) (net.Conn, error)
+
+type indexEntry struct {
+	peer    *peer
+	keypair *keypair
+}
+
+// device is a WireGuard interface. It encrypts IP packets from the output of
+// its stack and delivers decrypted packets back to the stack.
+type device struct {
+	privateKey privateKey
+	publicKey  publicKey
+	mac1Key    [blake2s.Size]byte
+	reserved   [3]byte
+	mtu        int
+	dial       dialFunc
+	stack      *stack
+
+	peers      []*peer
+	peersByKey map[publicKey]*peer
+
+	indexMu sync.RWMutex
+	indices map[uint32]indexEntry
+
+	done *done.Instance
+}
+
+func newDevice(config *Config, dial dialFunc) (*device, error) {
+	if len(config.SecretKey) != 32 {
+		return nil, newError("invalid secret key")
+	}
+	d := &device{
+		mtu:        int(config.Mtu),
+		dial:       dial,
+		peersByKey: make(map[publicKey]*peer),
+		indices:    make(map[uint32]indexEntry),
+		done:       done.New(),
+	}
+	if d.mtu == 0 {
+		d.mtu = defaultMTU
+	}
+	if d.mtu < 1280 || d.mtu > maxMessageSize-messageTransportOverhead {
+		return nil, newError("invalid MTU ", d.mtu)
+	}
+	if len(config.Reserved) > 3 {
+		return nil, newError("too many reserved bytes")
+	}
+	copy(d.reserved[:], config.Reserved)
+
+	copy(d.privateKey[:], config.SecretKey)
+	d.privateKey.clamp()
+	d.publicKey = d.privateKey.publicKey()
+	d.mac1Key = blake2s.Sum256(append([]byte(labelMAC1), d.publicKey[:]...))
+
+	for _, pc := range config.Peer {
+		p, err := newPeer(d, pc)
+		if err != nil {
+			return nil, err
+		}
+		if _, found := d.peersByKey[p.publicKey]; found {
+			return nil, newError("duplicated peer")
+		}
+		d.peers = append(d.peers, p)
+		d.peersByKey[p.publicKey] = p
+	}
+	if len(d.peers) == 0 {
+		return nil, newError("no peer specified")
+	}
+
+	var addresses []net.IP
+	for _, a := range config.Address {
+		ip := net.IP(a)
+		if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+			return nil, newError("invalid local address ", ip)
+		}
+		addresses = append(addresses, ip)
+	}
+	if len(addresses) == 0 {
+		return nil, newError("no local address specified")
+	}
+	d.stack = newStack(addresses, d.mtu, d.output)
+
+	go d.timerLoop()
+	return d, nil
+}
+
+func (d *device) newIndex(entry indexEntry) uint32 {
+	d.indexMu.Lock()
+	defer d.indexMu.Unlock()
+
+	var b [4]byte
+	for {
+		if _, err := io.ReadFull(randReader, b[:]); err != nil {
+			continue
+		}
+		index := binary.LittleEndian.Uint32(b[:])
+		if _, found := d.indices[index]; index != 0 && !found {
+			d.indices[index] = entry
+			return index
+		}
+	}
+}
+
+func (d *device) replaceIndex(index uint32, entry indexEntry) {
+	d.indexMu.Lock()
+	d.indices[index] = entry
+	d.indexMu.Unlock()
+}
+
+func (d *device) removeIndex(index uint32) {
+	if index == 0 {
+		return
+	}
+	d.indexMu.Lock()
+	delete(d.indices, index)
+	d.indexMu.Unlock()
+}
+
+func (d *device) lookupIndex(index uint32) (indexEntry, bool) {
+	d.indexMu.RLock()
+	defer d.indexMu.RUnlock()
+	entry, found := d.indices[index]
+	return entry, found
+}
+
+// route returns the peer with the longest allowed IP prefix matching ip.
+func (d *device) route(ip net.IP) *peer {
+	var best *peer
+	bestLen := -1
+	for _, p := range d.peers {
+		for _, n := range p.allowedIPs {
+			if !n.Contains(ip) {
+				continue
+			}
+			if ones, _ := n.Mask.Size(); ones > bestLen {
+				best = p
+				bestLen = ones
+			}
+		}
+	}
+	return best
+}
+
+// output sends an IP packet from the stack to its peer. It takes ownership of the packet.
+func (d *device) output(packet []byte) {
+	dst := destinationIP(packet)
+	if dst == nil {
+		return
+	}
+	p := d.route(dst)
+	if p == nil {
+		newError("no route to ", dst).AtDebug().WriteToLog()
+		return
+	}
+	p.sendPacket(packet)
+}
+
+// receive handles a message received from src. The message is only valid
+// during the call.
+func (d *device) receive(msg []byte, src peerConn) {
+	if len(msg) < 4 {
+		return
+	}
+	msg[1], msg[2], msg[3] = 0, 0, 0
+
+	switch msg[0] {
+	case messageInitiationType:
+		if len(msg) != messageInitiationSize || !d.checkMAC1(msg) {
+			return
+		}
+		p, err := d.consumeInitiation(msg)
+		if err != nil {
+			newError("failed to consume handshake initiation").Base(err).AtDebug().WriteToLog()
+			return
+		}
+		p.setConn(src)
+		resp, err := d.createResponse(p)
+		if err != nil {
+			newError("failed to create handshake response").Base(err).AtWarning().WriteToLog()
+			return
+		}
+		if _, err := d.beginSymmetricSession(p); err != nil {
+			newError("failed to derive keys").Base(err).AtWarning().WriteToLog()
+			return
+		}
+		p.markReceived(false)
+		p.send(resp)
+
+	case messageResponseType:
+		if len(msg) != messageResponseSize || !d.checkMAC1(msg) {
+			return
+		}
+		p, err := d.consumeResponse(msg)
+		if err != nil {
+			newError("failed to consume handshake response").Base(err).AtDebug().WriteToLog()
+			return
+		}
+		p.setConn(src)
+		if _, err := d.beginSymmetricSession(p); err != nil {
+			newError("failed to derive keys").Base(err).AtWarning().WriteToLog()
+			return
+		}
+		p.markReceived(false)
+		p.handshakeCompleted()
+
+	case messageCookieReplyType:
+		if len(msg) != messageCookieReplySize {
+			return
+		}
+		if err := d.consumeCookieReply(msg); err != nil {
+			newError("failed to consume cookie reply").Base(err).AtDebug().WriteToLog()
+		}
+
+	case messageTransportType:
+		if len(msg) < messageTransportOverhead {
+			return
+		}
+		entry, found := d.lookupIndex(binary.LittleEndian.Uint32(msg[4:8]))
+		if !found || entry.keypair == nil {
+			return
+		}
+		p := entry.peer
+		packet, err := entry.keypair.open(msg)
+		if err != nil {
+			return
+		}
+		p.setConn(src)
+		if p.receivedWithKeypair(entry.keypair) {
+			p.flushQueue()
+		}
+		p.markReceived(len(packet) > 0)
+		if len(packet) == 0 {
+			return
+		}
+		packet = trimPacket(packet)
+		if packet == nil {
+			return
+		}
+		if src := sourceIP(packet); src == nil || d.route(src) != p {
+			newError("dropping packet from unallowed source ", src).AtDebug().WriteToLog()
+			return
+		}
+		d.stack.deliver(packet)
+	}
+}
+
+func (d *device) timerLoop() {
+	ticker := time.NewTicker(time.Second / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.done.Wait():
+			return
+		case now := <-ticker.C:
+			for _, p := range d.peers {
+				p.tick(now)
+			}
+		}
+	}
+}
+
+func (d *device) Close() error {
+	if err := d.done.Close(); err != nil {
+		return err
+	}
+	d.stack.Close()
+	for _, p := range d.peers {
+		p.close()
+	}
+	return nil
+}
+
+// peer is a remote WireGuard interface.
+type peer struct {
+	device       *device
+	publicKey    publicKey
+	presharedKey [32]byte
+	mac1Key      [blake2s.Size]byte
+	cookieKey    [blake2s.Size]byte
+	endpoint     net.Destination
+	keepAlive    time.Duration
+	allowedIPs   []*net.IPNet
+
+	handshakeMu sync.Mutex
+	handshake   handshake
+
+	cookieMu   sync.Mutex
+	cookie     [blake2s.Size128]byte
+	cookieTime time.Time
+	lastMAC1   [blake2s.Size128]byte
+
+	dialMu sync.Mutex
+
+	sync.Mutex
+	conn              peerConn
+	current           *keypair
+	previous          *keypair
+	next              *keypair
+	queue             [][]byte
+	handshakeStarted  time.Time
+	lastHandshakeSent time.Time
+	lastSent          time.Time
+	lastDataReceived  time.Time
+}
+
+func newPeer(d *device, config *PeerConfig) (*peer, error) {
+	if len(config.PublicKey) != 32 {
+		return nil, newError("invalid public key of peer")
+	}
+	p := &peer{
+		device:    d,
+		keepAlive: time.Duration(config.KeepAlive) * time.Second,
+	}
+	copy(p.publicKey[:], config.PublicKey)
+	if len(config.PreSharedKey) > 0 {
+		if len(config.PreSharedKey) != 32 {
+			return nil, newError("invalid pre-shared key")
+		}
+		copy(p.presharedKey[:], config.PreSharedKey)
+	}
+	p.mac1Key = blake2s.Sum256(append([]byte(labelMAC1), p.publicKey[:]...))
+	p.cookieKey = blake2s.Sum256(append([]byte(labelCookie), p.publicKey[:]...))
+	p.handshake.precomputedStaticStatic, _ = d.privateKey.sharedSecret(p.publicKey)
+
+	if config.Address != nil && config.Port != 0 {
+		p.endpoint = net.UDPDestination(config.Address.AsAddress(), net.Port(config.Port))
+	}
+
+	for _, cidr := range config.AllowedIps {
+		ip := net.IP(cidr.Ip)
+		bits := len(ip) * 8
+		if (len(ip) != net.IPv4len && len(ip) != net.IPv6len) || int(cidr.Prefix) > bits {
+			return nil, newError("invalid allowed IPs ", ip, "/", cidr.Prefix)
+		}
+		mask := net.CIDRMask(int(cidr.Prefix), bits)
+		p.allowedIPs = append(p.allowedIPs, &net.IPNet{IP: ip.Mask(mask), Mask: mask})
+	}
+	if len(p.allowedIPs) == 0 {
+		p.allowedIPs = []*net.IPNet{
+			{IP: make(net.IP, net.IPv4len), Mask: net.CIDRMask(0, 32)},
+			{IP: make(net.IP, net.IPv6len), Mask: net.CIDRMask(0, 128)},
+		}
+	}
+
+	return p, nil
+}
+
+// installKeypair rotates the keypairs of the peer after a handshake.
+func (p *peer) installKeypair(kp *keypair) {
+	p.Lock()
+	defer p.Unlock()
+
+	d := p.device
+	if kp.isInitiator {
+		if p.next != nil {
+			d.removeKeypair(p.previous)
+			p.previous = p.next
+			p.next = nil
+			d.removeKeypair(p.current)
+		} else {
+			d.removeKeypair(p.previous)
+			p.previous = p.current
+		}
+		p.current = kp
+	} else {
+		d.removeKeypair(p.next)
+		p.next = kp
+		d.removeKeypair(p.previous)
+		p.previous = nil
+	}
+}
+
+func (d *device) removeKeypair(kp *keypair) {
+	if kp != nil {
+		d.removeIndex(kp.localIndex)
+	}
+}
+
+// receivedWithKeypair confirms the next keypair once the initiator uses it.
+// It returns true if the keypair becomes current.
+func (p *peer) receivedWithKeypair(kp *keypair) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.next != kp {
+		return false
+	}
+	p.device.removeKeypair(p.previous)
+	p.previous = p.current
+	p.current = p.next
+	p.next = nil
+	return true
+}
+
+func (p *peer) markReceived(data bool) {
+	if !data {
+		return
+	}
+	p.Lock()
+	p.lastDataReceived = time.Now()
+	p.Unlock()
+}
+
+func (p *peer) handshakeCompleted() {
+	p.Lock()
+	p.handshakeStarted = time.Time{}
+	empty := len(p.queue) == 0
+	p.Unlock()
+
+	if empty {
+		// Confirms the session to the responder.
+		p.sendPacket(nil)
+		return
+	}
+	p.flushQueue()
+}
+
+// flushQueue sends packets queued during the handshake.
+func (p *peer) flushQueue() {
+	p.Lock()
+	queue := p.queue
+	p.queue = nil
+	p.Unlock()
+
+	for _, packet := range queue {
+		p.sendPacket(packet)
+	}
+}
+
+// sendPacket encrypts and sends an IP packet, or queues it until a session is
+// established. An empty packet is a keepalive.
+func (p *peer) sendPacket(packet []byte) {
+	p.Lock()
+	kp := p.current
+	if kp == nil || kp.expired() {
+		if len(p.queue) >= maxQueuedPackets {
+			p.queue = p.queue[1:]
+		}
+		p.queue = append(p.queue, packet)
+		p.Unlock()
+		p.startHandshake()
+		return
+	}
+	p.Unlock()
+
+	if msg, ok := kp.seal(pad(packet, p.device.mtu)); ok {
+		p.send(msg)
+	}
+	if kp.needsRekey() {
+		p.startHandshake()
+	}
+}
+
+func pad(packet []byte, mtu int) []byte {
+	if len(packet) == 0 {
+		return packet
+	}
+	size := (len(packet) + 15) &^ 15
+	if size > mtu {
+		size = mtu
+	}
+	if size <= len(packet) {
+		return packet
+	}
+	padded := make([]byte, size)
+	copy(padded, packet)
+	return padded
+}
+
+func (p *peer) startHandshake() {
+	p.Lock()
+	if !p.handshakeStarted.IsZero() {
+		p.Unlock()
+		return
+	}
+	p.handshakeStarted = time.Now()
+	p.Unlock()
+
+	p.sendHandshakeInitiation()
+}
+
+func (p *peer) sendHandshakeInitiation() {
+	p.Lock()
+	if time.Since(p.lastHandshakeSent) < rekeyTimeout {
+		p.Unlock()
+		return
+	}
+	p.lastHandshakeSent = time.Now()
+	p.Unlock()
+
+	msg, err := p.device.createInitiation(p)
+	if err != nil {
+		newError("failed to create handshake initiation").Base(err).AtWarning().WriteToLog()
+		return
+	}
+	p.send(msg)
+}
+
+func (p *peer) tick(now time.Time) {
+	p.Lock()
+
+	var initiate, keepalive bool
+	if !p.handshakeStarted.IsZero() {
+		if now.Sub(p.handshakeStarted) >= rekeyAttemptTime {
+			newError("handshake with peer did not complete after ", rekeyAttemptTime).AtWarning().WriteToLog()
+			p.handshakeStarted = time.Time{}
+			p.queue = nil
+		} else if now.Sub(p.lastHandshakeSent) >= rekeyTimeout {
+			initiate = true
+		}
+	}
+	if p.current != nil {
+		if now.Sub(p.current.created) >= rejectAfterTime*3 {
+			p.device.removeKeypair(p.previous)
+			p.device.removeKeypair(p.current)
+			p.device.removeKeypair(p.next)
+			p.previous, p.current, p.next = nil, nil, nil
+		} else if p.lastDataReceived.After(p.lastSent) && now.Sub(p.lastDataReceived) >= keepaliveTimeout {
+			keepalive = true
+		}
+	}
+	if p.keepAlive > 0 && p.endpoint.IsValid() && now.Sub(p.lastSent) >= p.keepAlive {
+		keepalive = true
+	}
+
+	p.Unlock()
+
+	if initiate {
+		p.sendHandshakeInitiation()
+	}
+	if keepalive {
+		p.sendPacket(nil)
+	}
+}
+
+func (p *peer) setConn(src peerConn) {
+	if src == nil || p.endpoint.IsValid() {
+		return
+	}
+	p.Lock()
+	p.conn = src
+	p.Unlock()
+}
+
+func (p *peer) connection() (peerConn, error) {
+	p.Lock()
+	conn := p.conn
+	p.Unlock()
+	if conn != nil {
+		return conn, nil
+	}
+	if !p.endpoint.IsValid() {
+		return nil, newError("endpoint of peer unknown")
+	}
+
+	p.dialMu.Lock()
+	defer p.dialMu.Unlock()
+
+	p.Lock()
+	conn = p.conn
+	p.Unlock()
+	if conn != nil {
+		return conn, nil
+	}
+
+	c, err := p.device.dial(p.endpoint)
+	if err != nil {
+		return nil, newError("failed to dial peer ", p.endpoint).Base(err)
+	}
+	p.Lock()
+	p.conn = c
+	p.Unlock()
+	go p.readLoop(c)
+	return c, nil
+}
+
+func (p *peer) readLoop(conn net.Conn) {
+	b := make([]byte, maxMessageSize)
+	for {
+		n, err := conn.Read(b)
+		if err != nil {
+			p.resetConn(conn)
+			return
+		}
+		p.device.receive(b[:n], conn)
+	}
+}
+
+func (p *peer) resetConn(conn peerConn) {
+	p.Lock()
+	if p.conn == conn {
+		p.conn = nil
+	}
+	p.Unlock()
+	conn.Close() // nolint: errcheck
+}
+
+// send writes a message to the peer, setting the reserved bytes.
+func (p *peer) send(msg []byte) {
+	if p.device.done.Done() {
+		return
+	}
+	conn, err := p.connection()
+	if err != nil {
+		newError("failed to send message").Base(err).AtDebug().WriteToLog()
+		return
+	}
+	copy(msg[1:4], p.device.reserved[:])
+	if _, err := conn.Write(msg); err != nil {
+		newError("failed to send message to peer").Base(err).AtDebug().WriteToLog()
+		if p.endpoint.IsValid() {
+			p.resetConn(conn)
+		}
+		return
+	}
+	p.Lock()
+	p.lastSent = time.Now()
+	p.Unlock()
+}
+
+func (p *peer) close() {
+	p.Lock()
+	conn := p.conn
+	p.conn = nil
+	p.queue = nil
+	p.Unlock()
+	if conn != nil && p.endpoint.IsValid() {
+		conn.Close() // nolint: errcheck
+	}
+}
@@ -0,0 +1,132 @@
+// +build !confonly
+
+package wireguard
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Protocol timing constants, see section 6 of the WireGuard paper.
+const (
+	rekeyAfterMessages      = 1 << 60
+	rejectAfterMessages     = 1<<64 - 1<<13 - 1
+	rekeyAfterTime          = 120 * time.Second
+	rejectAfterTime         = 180 * time.Second
+	rekeyAttemptTime        = 90 * time.Second
+	rekeyTimeout            = 5 * time.Second
+	keepaliveTimeout        = 10 * time.Second
+	cookieRefreshTime       = 120 * time.Second
+	handshakeInitiationRate = time.Second / 50
+)
+
+// keypair holds transport keys derived from one handshake.
+type keypair struct {
+	sendNonce   uint64 // accessed atomically
+	send        cipher.AEAD
+	receive     cipher.AEAD
+	created     time.Time
+	isInitiator bool
+	localIndex  uint32
+	remoteIndex uint32
+
+	replayMu sync.Mutex
+	replay   replayFilter
+}
+
+func (kp *keypair) expired() bool {
+	return time.Since(kp.created) >= rejectAfterTime || atomic.LoadUint64(&kp.sendNonce) >= rejectAfterMessages
+}
+
+func (kp *keypair) needsRekey() bool {
+	age := time.Since(kp.created)
+	if atomic.LoadUint64(&kp.sendNonce) >= rekeyAfterMessages {
+		return true
+	}
+	if kp.isInitiator {
+		return age >= rekeyAfterTime
+	}
+	return age >= rejectAfterTime-keepaliveTimeout-rekeyTimeout
+}
+
+// seal encrypts the packet into a transport message. The packet must be
+// padded already.
+func (kp *keypair) seal(packet []byte) ([]byte, bool) {
+	counter := atomic.AddUint64(&kp.sendNonce, 1) - 1
+	if counter >= rejectAfterMessages {
+		return nil, false
+	}
+
+	msg := make([]byte, messageTransportHeaderSize, messageTransportOverhead+len(packet))
+	msg[0] = messageTransportType
+	binary.LittleEndian.PutUint32(msg[4:8], kp.remoteIndex)
+	binary.LittleEndian.PutUint64(msg[8:16], counter)
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	binary.LittleEndian.PutUint64(nonce[4:], counter)
+	return kp.send.Seal(msg, nonce[:], packet, nil), true
+}
+
+// open decrypts a transport message in place and returns the plaintext.
+func (kp *keypair) open(msg []byte) ([]byte, error) {
+	counter := binary.LittleEndian.Uint64(msg[8:16])
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	binary.LittleEndian.PutUint64(nonce[4:], counter)
+	content := msg[messageTransportHeaderSize:]
+	plaintext, err := kp.receive.Open(content[:0], nonce[:], content, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	kp.replayMu.Lock()
+	valid := kp.replay.validate(counter, rejectAfterMessages)
+	kp.replayMu.Unlock()
+	if !valid {
+		return nil, newError("replayed counter ", counter)
+	}
+	return plaintext, nil
+}
+
+const (
+	replayBlockBits  = 64
+	replayRingBlocks = 1 << 7
+	replayWindowSize = (replayRingBlocks - 1) * replayBlockBits
+)
+
+// replayFilter is a sliding window of received counters as described in RFC 6479.
+type replayFilter struct {
+	last uint64
+	ring [replayRingBlocks]uint64
+}
+
+// validate returns true if the counter is within the window and not seen before.
+func (f *replayFilter) validate(counter uint64, limit uint64) bool {
+	if counter >= limit {
+		return false
+	}
+	indexBlock := counter / replayBlockBits
+	if counter > f.last {
+		current := f.last / replayBlockBits
+		diff := indexBlock - current
+		if diff > replayRingBlocks {
+			diff = replayRingBlocks
+		}
+		for i := current + 1; i <= current+diff; i++ {
+			f.ring[i%replayRingBlocks] = 0
+		}
+		f.last = counter
+	} else if f.last-counter > replayWindowSize {
+		return false
+	}
+	indexBlock %= replayRingBlocks
+	bit := uint64(1) << (counter % replayBlockBits)
+	old := f.ring[indexBlock]
+	f.ring[indexBlock] = old | bit
+	return old&bit == 0
+}
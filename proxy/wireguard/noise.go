@@ -0,0 +1,481 @@
+// +build !confonly
+
+package wireguard
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"hash"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+)
+
+const (
+	messageInitiationType  = 1
+	messageResponseType    = 2
+	messageCookieReplyType = 3
+	messageTransportType   = 4
+)
+
+const (
+	messageInitiationSize      = 148
+	messageResponseSize        = 92
+	messageCookieReplySize     = 64
+	messageTransportHeaderSize = 16
+	poly1305TagSize            = 16
+	messageTransportOverhead   = messageTransportHeaderSize + poly1305TagSize
+)
+
+const (
+	noiseConstruction = "Noise_IKpsk2_25519_ChaChaPoly_BLAKE2s"
+	noiseIdentifier   = "WireGuard v1 zx2c4 Jason@zx2c4.com"
+	labelMAC1         = "mac1----"
+	labelCookie       = "cookie--"
+)
+
+const (
+	handshakeZeroed = iota
+	handshakeInitiationCreated
+	handshakeInitiationConsumed
+	handshakeResponseCreated
+	handshakeResponseConsumed
+)
+
+var (
+	initialChainKey [blake2s.Size]byte
+	initialHash     [blake2s.Size]byte
+	zeroNonce       [chacha20poly1305.NonceSize]byte
+)
+
+func init() {
+	initialChainKey = blake2s.Sum256([]byte(noiseConstruction))
+	mixHash(&initialHash, &initialChainKey, []byte(noiseIdentifier))
+}
+
+// Sources of randomness and time of handshakes. Tests replace them to reproduce handshakes of the
+// reference implementation.
+var (
+	randReader   io.Reader = rand.Reader
+	handshakeNow           = time.Now
+)
+
+type privateKey [32]byte
+type publicKey [32]byte
+
+func newPrivateKey() (sk privateKey, err error) {
+	_, err = io.ReadFull(randReader, sk[:])
+	sk.clamp()
+	return
+}
+
+func (sk *privateKey) clamp() {
+	sk[0] &= 248
+	sk[31] = (sk[31] & 127) | 64
+}
+
+func (sk *privateKey) publicKey() (pk publicKey) {
+	curve25519.ScalarBaseMult((*[32]byte)(&pk), (*[32]byte)(sk))
+	return
+}
+
+// sharedSecret computes the Diffie-Hellman shared secret. It returns false if
+// the result is all zero, i.e., the public key has low order.
+func (sk *privateKey) sharedSecret(pk publicKey) (ss [32]byte, ok bool) {
+	curve25519.ScalarMult(&ss, (*[32]byte)(sk), (*[32]byte)(&pk))
+	return ss, !isZero(ss[:])
+}
+
+func isZero(b []byte) bool {
+	var acc byte
+	for _, v := range b {
+		acc |= v
+	}
+	return subtle.ConstantTimeByteEq(acc, 0) == 1
+}
+
+func newBlake2s() hash.Hash {
+	h, _ := blake2s.New256(nil)
+	return h
+}
+
+func mixHash(dst, h *[blake2s.Size]byte, data []byte) {
+	hash := newBlake2s()
+	hash.Write(h[:])
+	hash.Write(data)
+	hash.Sum(dst[:0])
+}
+
+func hmac1(sum *[blake2s.Size]byte, key, in0 []byte) {
+	mac := hmac.New(newBlake2s, key)
+	mac.Write(in0)
+	mac.Sum(sum[:0])
+}
+
+func hmac2(sum *[blake2s.Size]byte, key, in0, in1 []byte) {
+	mac := hmac.New(newBlake2s, key)
+	mac.Write(in0)
+	mac.Write(in1)
+	mac.Sum(sum[:0])
+}
+
+func kdf1(t0 *[blake2s.Size]byte, key, input []byte) {
+	var prk [blake2s.Size]byte
+	hmac1(&prk, key, input)
+	hmac1(t0, prk[:], []byte{0x1})
+}
+
+func kdf2(t0, t1 *[blake2s.Size]byte, key, input []byte) {
+	var prk [blake2s.Size]byte
+	hmac1(&prk, key, input)
+	hmac1(t0, prk[:], []byte{0x1})
+	hmac2(t1, prk[:], t0[:], []byte{0x2})
+}
+
+func kdf3(t0, t1, t2 *[blake2s.Size]byte, key, input []byte) {
+	var prk [blake2s.Size]byte
+	hmac1(&prk, key, input)
+	hmac1(t0, prk[:], []byte{0x1})
+	hmac2(t1, prk[:], t0[:], []byte{0x2})
+	hmac2(t2, prk[:], t1[:], []byte{0x3})
+}
+
+func mixKey(chainKey *[blake2s.Size]byte, data []byte) {
+	kdf1(chainKey, chainKey[:], data)
+}
+
+func mac(dst []byte, key []byte, data []byte) {
+	h, _ := blake2s.New128(key)
+	h.Write(data)
+	var sum [blake2s.Size128]byte
+	copy(dst, h.Sum(sum[:0]))
+}
+
+// tai64n returns the TAI64N timestamp of the given time. Like in other implementations, the nanoseconds are truncated to about 16ms,
+// so that the timestamp does not reveal the precise time of the host.
+func tai64n(t time.Time) (ts [12]byte) {
+	binary.BigEndian.PutUint64(ts[:], uint64(0x400000000000000a+t.Unix()))
+	binary.BigEndian.PutUint32(ts[8:], uint32(t.Nanosecond())&^(0x1000000-1))
+	return
+}
+
+// handshake is the state of the Noise IK handshake with a peer.
+type handshake struct {
+	state                     int
+	hash                      [blake2s.Size]byte
+	chainKey                  [blake2s.Size]byte
+	localEphemeral            privateKey
+	remoteEphemeral           publicKey
+	localIndex                uint32
+	remoteIndex               uint32
+	precomputedStaticStatic   [32]byte
+	lastTimestamp             [12]byte
+	lastInitiationConsumption time.Time
+}
+
+func (hs *handshake) clear() {
+	hs.state = handshakeZeroed
+	hs.hash = [blake2s.Size]byte{}
+	hs.chainKey = [blake2s.Size]byte{}
+	hs.localEphemeral = privateKey{}
+	hs.remoteEphemeral = publicKey{}
+}
+
+func (d *device) createInitiation(p *peer) ([]byte, error) {
+	hs := &p.handshake
+	p.handshakeMu.Lock()
+	defer p.handshakeMu.Unlock()
+
+	if isZero(hs.precomputedStaticStatic[:]) {
+		return nil, newError("invalid public key of peer")
+	}
+
+	var err error
+	hs.hash = initialHash
+	hs.chainKey = initialChainKey
+	hs.localEphemeral, err = newPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	mixHash(&hs.hash, &hs.hash, p.publicKey[:])
+
+	msg := make([]byte, messageInitiationSize)
+	msg[0] = messageInitiationType
+
+	ephemeral := hs.localEphemeral.publicKey()
+	copy(msg[8:40], ephemeral[:])
+	mixKey(&hs.chainKey, ephemeral[:])
+	mixHash(&hs.hash, &hs.hash, ephemeral[:])
+
+	ss, ok := hs.localEphemeral.sharedSecret(p.publicKey)
+	if !ok {
+		return nil, newError("invalid public key of peer")
+	}
+	var key [chacha20poly1305.KeySize]byte
+	kdf2(&hs.chainKey, &key, hs.chainKey[:], ss[:])
+	aead, _ := chacha20poly1305.New(key[:])
+	aead.Seal(msg[40:40], zeroNonce[:], d.publicKey[:], hs.hash[:])
+	mixHash(&hs.hash, &hs.hash, msg[40:88])
+
+	kdf2(&hs.chainKey, &key, hs.chainKey[:], hs.precomputedStaticStatic[:])
+	timestamp := tai64n(handshakeNow())
+	aead, _ = chacha20poly1305.New(key[:])
+	aead.Seal(msg[88:88], zeroNonce[:], timestamp[:], hs.hash[:])
+	mixHash(&hs.hash, &hs.hash, msg[88:116])
+
+	d.removeIndex(hs.localIndex)
+	hs.localIndex = d.newIndex(indexEntry{peer: p})
+	binary.LittleEndian.PutUint32(msg[4:8], hs.localIndex)
+	hs.state = handshakeInitiationCreated
+
+	p.addMACs(msg)
+	return msg, nil
+}
+
+func (d *device) consumeInitiation(msg []byte) (*peer, error) {
+	hash := initialHash
+	chainKey := initialChainKey
+	mixHash(&hash, &hash, d.publicKey[:])
+	mixHash(&hash, &hash, msg[8:40])
+	mixKey(&chainKey, msg[8:40])
+
+	var remoteEphemeral publicKey
+	copy(remoteEphemeral[:], msg[8:40])
+	ss, ok := d.privateKey.sharedSecret(remoteEphemeral)
+	if !ok {
+		return nil, newError("invalid ephemeral key")
+	}
+	var key [chacha20poly1305.KeySize]byte
+	kdf2(&chainKey, &key, chainKey[:], ss[:])
+	aead, _ := chacha20poly1305.New(key[:])
+	var peerKey publicKey
+	if _, err := aead.Open(peerKey[:0], zeroNonce[:], msg[40:88], hash[:]); err != nil {
+		return nil, newError("failed to decrypt static key").Base(err)
+	}
+	mixHash(&hash, &hash, msg[40:88])
+
+	p := d.peersByKey[peerKey]
+	if p == nil {
+		return nil, newError("unknown peer")
+	}
+
+	hs := &p.handshake
+	p.handshakeMu.Lock()
+	defer p.handshakeMu.Unlock()
+
+	if isZero(hs.precomputedStaticStatic[:]) {
+		return nil, newError("invalid public key of peer")
+	}
+	kdf2(&chainKey, &key, chainKey[:], hs.precomputedStaticStatic[:])
+	aead, _ = chacha20poly1305.New(key[:])
+	var timestamp [12]byte
+	if _, err := aead.Open(timestamp[:0], zeroNonce[:], msg[88:116], hash[:]); err != nil {
+		return nil, newError("failed to decrypt timestamp").Base(err)
+	}
+	mixHash(&hash, &hash, msg[88:116])
+
+	if bytes.Compare(timestamp[:], hs.lastTimestamp[:]) <= 0 {
+		return nil, newError("replayed handshake initiation")
+	}
+	if time.Since(hs.lastInitiationConsumption) <= handshakeInitiationRate {
+		return nil, newError("handshake initiation flood")
+	}
+
+	hs.hash = hash
+	hs.chainKey = chainKey
+	hs.remoteIndex = binary.LittleEndian.Uint32(msg[4:8])
+	hs.remoteEphemeral = remoteEphemeral
+	hs.lastTimestamp = timestamp
+	hs.lastInitiationConsumption = time.Now()
+	hs.state = handshakeInitiationConsumed
+
+	return p, nil
+}
+
+func (d *device) createResponse(p *peer) ([]byte, error) {
+	hs := &p.handshake
+	p.handshakeMu.Lock()
+	defer p.handshakeMu.Unlock()
+
+	if hs.state != handshakeInitiationConsumed {
+		return nil, newError("handshake initiation not consumed")
+	}
+
+	var err error
+	hs.localEphemeral, err = newPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	d.removeIndex(hs.localIndex)
+	hs.localIndex = d.newIndex(indexEntry{peer: p})
+
+	msg := make([]byte, messageResponseSize)
+	msg[0] = messageResponseType
+	binary.LittleEndian.PutUint32(msg[4:8], hs.localIndex)
+	binary.LittleEndian.PutUint32(msg[8:12], hs.remoteIndex)
+
+	ephemeral := hs.localEphemeral.publicKey()
+	copy(msg[12:44], ephemeral[:])
+	mixHash(&hs.hash, &hs.hash, ephemeral[:])
+	mixKey(&hs.chainKey, ephemeral[:])
+
+	ss, ok := hs.localEphemeral.sharedSecret(hs.remoteEphemeral)
+	if !ok {
+		return nil, newError("invalid ephemeral key")
+	}
+	mixKey(&hs.chainKey, ss[:])
+	ss, ok = hs.localEphemeral.sharedSecret(p.publicKey)
+	if !ok {
+		return nil, newError("invalid public key of peer")
+	}
+	mixKey(&hs.chainKey, ss[:])
+
+	var tau, key [blake2s.Size]byte
+	kdf3(&hs.chainKey, &tau, &key, hs.chainKey[:], p.presharedKey[:])
+	mixHash(&hs.hash, &hs.hash, tau[:])
+
+	aead, _ := chacha20poly1305.New(key[:])
+	aead.Seal(msg[44:44], zeroNonce[:], nil, hs.hash[:])
+	mixHash(&hs.hash, &hs.hash, msg[44:60])
+
+	hs.state = handshakeResponseCreated
+
+	p.addMACs(msg)
+	return msg, nil
+}
+
+func (d *device) consumeResponse(msg []byte) (*peer, error) {
+	entry, found := d.lookupIndex(binary.LittleEndian.Uint32(msg[8:12]))
+	if !found || entry.keypair != nil {
+		return nil, newError("unknown receiver index")
+	}
+	p := entry.peer
+
+	hs := &p.handshake
+	p.handshakeMu.Lock()
+	defer p.handshakeMu.Unlock()
+
+	if hs.state != handshakeInitiationCreated {
+		return nil, newError("unexpected handshake response")
+	}
+
+	hash := hs.hash
+	chainKey := hs.chainKey
+
+	var remoteEphemeral publicKey
+	copy(remoteEphemeral[:], msg[12:44])
+	mixHash(&hash, &hash, remoteEphemeral[:])
+	mixKey(&chainKey, remoteEphemeral[:])
+
+	ss, ok := hs.localEphemeral.sharedSecret(remoteEphemeral)
+	if !ok {
+		return nil, newError("invalid ephemeral key")
+	}
+	mixKey(&chainKey, ss[:])
+	ss, ok = d.privateKey.sharedSecret(remoteEphemeral)
+	if !ok {
+		return nil, newError("invalid ephemeral key")
+	}
+	mixKey(&chainKey, ss[:])
+
+	var tau, key [blake2s.Size]byte
+	kdf3(&chainKey, &tau, &key, chainKey[:], p.presharedKey[:])
+	mixHash(&hash, &hash, tau[:])
+
+	aead, _ := chacha20poly1305.New(key[:])
+	if _, err := aead.Open(nil, zeroNonce[:], msg[44:60], hash[:]); err != nil {
+		return nil, newError("failed to authenticate handshake response").Base(err)
+	}
+	mixHash(&hash, &hash, msg[44:60])
+
+	hs.hash = hash
+	hs.chainKey = chainKey
+	hs.remoteIndex = binary.LittleEndian.Uint32(msg[4:8])
+	hs.state = handshakeResponseConsumed
+
+	return p, nil
+}
+
+// beginSymmetricSession derives transport keys from a completed handshake.
+func (d *device) beginSymmetricSession(p *peer) (*keypair, error) {
+	hs := &p.handshake
+	p.handshakeMu.Lock()
+	defer p.handshakeMu.Unlock()
+
+	var sendKey, receiveKey [chacha20poly1305.KeySize]byte
+	var isInitiator bool
+	switch hs.state {
+	case handshakeResponseConsumed:
+		kdf2(&sendKey, &receiveKey, hs.chainKey[:], nil)
+		isInitiator = true
+	case handshakeResponseCreated:
+		kdf2(&receiveKey, &sendKey, hs.chainKey[:], nil)
+	default:
+		return nil, newError("invalid handshake state")
+	}
+
+	kp := &keypair{
+		created:     time.Now(),
+		isInitiator: isInitiator,
+		localIndex:  hs.localIndex,
+		remoteIndex: hs.remoteIndex,
+	}
+	kp.send, _ = chacha20poly1305.New(sendKey[:])
+	kp.receive, _ = chacha20poly1305.New(receiveKey[:])
+
+	hs.clear()
+	hs.localIndex = 0
+	d.replaceIndex(kp.localIndex, indexEntry{peer: p, keypair: kp})
+
+	p.installKeypair(kp)
+	return kp, nil
+}
+
+func (d *device) consumeCookieReply(msg []byte) error {
+	entry, found := d.lookupIndex(binary.LittleEndian.Uint32(msg[4:8]))
+	if !found {
+		return newError("unknown receiver index")
+	}
+	p := entry.peer
+
+	p.cookieMu.Lock()
+	defer p.cookieMu.Unlock()
+
+	aead, _ := chacha20poly1305.NewX(p.cookieKey[:])
+	cookie, err := aead.Open(nil, msg[8:32], msg[32:64], p.lastMAC1[:])
+	if err != nil {
+		return newError("failed to decrypt cookie").Base(err)
+	}
+	copy(p.cookie[:], cookie)
+	p.cookieTime = time.Now()
+	return nil
+}
+
+// addMACs fills mac1 and mac2 fields at the end of a handshake message.
+func (p *peer) addMACs(msg []byte) {
+	n := len(msg)
+	mac(msg[n-32:n-16], p.mac1Key[:], msg[:n-32])
+
+	p.cookieMu.Lock()
+	defer p.cookieMu.Unlock()
+
+	copy(p.lastMAC1[:], msg[n-32:n-16])
+	if !p.cookieTime.IsZero() && time.Since(p.cookieTime) <= cookieRefreshTime {
+		mac(msg[n-16:], p.cookie[:], msg[:n-16])
+	}
+}
+
+// checkMAC1 verifies the mac1 field of a handshake message sent to this device.
+func (d *device) checkMAC1(msg []byte) bool {
+	n := len(msg)
+	var expected [blake2s.Size128]byte
+	mac(expected[:], d.mac1Key[:], msg[:n-32])
+	return hmac.Equal(expected[:], msg[n-32:n-16])
+}
@@ -0,0 +1,445 @@
+// +build !confonly
+
+package wireguard
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"v2ray.com/core/common/net"
+)
+
+const (
+	protocolTCP = 6
+	protocolUDP = 17
+
+	ipv4HeaderSize = 20
+	ipv6HeaderSize = 40
+	udpHeaderSize  = 8
+
+	ephemeralPortFirst = 32768
+	ephemeralPortLast  = 60999
+)
+
+// endpointID identifies a transport endpoint pair in the stack.
+type endpointID struct {
+	localAddr  [16]byte
+	localPort  uint16
+	remoteAddr [16]byte
+	remotePort uint16
+}
+
+func newEndpointID(localAddr net.IP, localPort uint16, remoteAddr net.IP, remotePort uint16) endpointID {
+	id := endpointID{localPort: localPort, remotePort: remotePort}
+	copy(id.localAddr[:], localAddr.To16())
+	copy(id.remoteAddr[:], remoteAddr.To16())
+	return id
+}
+
+// stack is a minimal IPv4 and IPv6 stack that terminates TCP and UDP flows
+// on the addresses of a WireGuard interface.
+type stack struct {
+	addresses []net.IP
+	mtu       int
+	output    func([]byte)
+	ipID      uint32
+
+	sync.Mutex
+	tcpConns     map[endpointID]*tcpConn
+	tcpListeners map[uint16]*tcpListener
+	udpConns     map[uint16]*udpConn
+	closed       bool
+}
+
+func newStack(addresses []net.IP, mtu int, output func([]byte)) *stack {
+	return &stack{
+		addresses:    addresses,
+		mtu:          mtu,
+		output:       output,
+		tcpConns:     make(map[endpointID]*tcpConn),
+		tcpListeners: make(map[uint16]*tcpListener),
+		udpConns:     make(map[uint16]*udpConn),
+	}
+}
+
+// localAddress returns the address of the interface used to reach the given address.
+func (s *stack) localAddress(remote net.IP) (net.IP, error) {
+	isIPv4 := remote.To4() != nil
+	for _, addr := range s.addresses {
+		if (addr.To4() != nil) == isIPv4 {
+			return addr, nil
+		}
+	}
+	return nil, newError("no local address to reach ", remote)
+}
+
+func (s *stack) hasIPv4() bool {
+	for _, addr := range s.addresses {
+		if addr.To4() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *stack) hasIPv6() bool {
+	for _, addr := range s.addresses {
+		if addr.To4() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *stack) isLocal(ip net.IP) bool {
+	for _, addr := range s.addresses {
+		if addr.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// allocatePort picks an unused ephemeral port. It must be called with s locked.
+func (s *stack) allocatePort(used func(uint16) bool) (uint16, error) {
+	n := ephemeralPortLast - ephemeralPortFirst + 1
+	start := rand.Intn(n)
+	for i := 0; i < n; i++ {
+		port := uint16(ephemeralPortFirst + (start+i)%n)
+		if !used(port) {
+			return port, nil
+		}
+	}
+	return 0, newError("no ephemeral port available")
+}
+
+func (s *stack) tcpPortUsed(port uint16) bool {
+	if _, found := s.tcpListeners[port]; found {
+		return true
+	}
+	for id := range s.tcpConns {
+		if id.localPort == port {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *stack) udpPortUsed(port uint16) bool {
+	_, found := s.udpConns[port]
+	return found
+}
+
+// maxPayload returns the maximum size of the transport segment in an IP packet to the given address.
+func (s *stack) maxPayload(remote net.IP) int {
+	if remote.To4() != nil {
+		return s.mtu - ipv4HeaderSize
+	}
+	return s.mtu - ipv6HeaderSize
+}
+
+// newPacket allocates an IP packet and returns it together with its transport payload.
+func (s *stack) newPacket(src, dst net.IP, protocol byte, size int) ([]byte, []byte) {
+	if src4, dst4 := src.To4(), dst.To4(); src4 != nil && dst4 != nil {
+		packet := make([]byte, ipv4HeaderSize+size)
+		packet[0] = 0x45
+		binary.BigEndian.PutUint16(packet[2:], uint16(len(packet)))
+		binary.BigEndian.PutUint16(packet[4:], uint16(atomic.AddUint32(&s.ipID, 1)))
+		binary.BigEndian.PutUint16(packet[6:], 0x4000)
+		packet[8] = 64
+		packet[9] = protocol
+		copy(packet[12:16], src4)
+		copy(packet[16:20], dst4)
+		binary.BigEndian.PutUint16(packet[10:], finishChecksum(checksum(packet[:ipv4HeaderSize], 0)))
+		return packet, packet[ipv4HeaderSize:]
+	}
+
+	packet := make([]byte, ipv6HeaderSize+size)
+	packet[0] = 0x60
+	binary.BigEndian.PutUint16(packet[4:], uint16(size))
+	packet[6] = protocol
+	packet[7] = 64
+	copy(packet[8:24], src.To16())
+	copy(packet[24:40], dst.To16())
+	return packet, packet[ipv6HeaderSize:]
+}
+
+// deliver handles an IP packet from the interface. The packet is only valid during the call.
+func (s *stack) deliver(packet []byte) {
+	var src, dst net.IP
+	var protocol byte
+	var payload []byte
+
+	switch packet[0] >> 4 {
+	case 4:
+		headerSize := int(packet[0]&0x0f) * 4
+		if headerSize < ipv4HeaderSize || len(packet) < headerSize {
+			return
+		}
+		if binary.BigEndian.Uint16(packet[6:8])&0x3fff != 0 {
+			// Fragments are not supported.
+			return
+		}
+		protocol = packet[9]
+		src = net.IP(packet[12:16])
+		dst = net.IP(packet[16:20])
+		payload = packet[headerSize:]
+	case 6:
+		protocol = packet[6]
+		src = net.IP(packet[8:24])
+		dst = net.IP(packet[24:40])
+		payload = packet[ipv6HeaderSize:]
+	default:
+		return
+	}
+
+	if !s.isLocal(dst) {
+		return
+	}
+	if checksum(payload, pseudoHeaderChecksum(src, dst, protocol, len(payload))) != 0xffff {
+		if protocol != protocolUDP || len(payload) < udpHeaderSize || binary.BigEndian.Uint16(payload[6:8]) != 0 || src.To4() == nil {
+			return
+		}
+	}
+
+	switch protocol {
+	case protocolTCP:
+		s.deliverTCP(src, dst, payload)
+	case protocolUDP:
+		s.deliverUDP(src, dst, payload)
+	}
+}
+
+func (s *stack) deliverUDP(src, dst net.IP, payload []byte) {
+	if len(payload) < udpHeaderSize {
+		return
+	}
+	length := int(binary.BigEndian.Uint16(payload[4:6]))
+	if length < udpHeaderSize || length > len(payload) {
+		return
+	}
+	srcPort := binary.BigEndian.Uint16(payload[0:2])
+	dstPort := binary.BigEndian.Uint16(payload[2:4])
+
+	s.Lock()
+	conn := s.udpConns[dstPort]
+	s.Unlock()
+	if conn == nil {
+		return
+	}
+	conn.deliver(src, srcPort, payload[udpHeaderSize:length])
+}
+
+func (s *stack) sendUDP(src net.IP, srcPort uint16, dst net.IP, dstPort uint16, data []byte) error {
+	if len(data)+udpHeaderSize > s.maxPayload(dst) {
+		return newError("UDP packet too large: ", len(data))
+	}
+	packet, payload := s.newPacket(src, dst, protocolUDP, udpHeaderSize+len(data))
+	binary.BigEndian.PutUint16(payload[0:], srcPort)
+	binary.BigEndian.PutUint16(payload[2:], dstPort)
+	binary.BigEndian.PutUint16(payload[4:], uint16(len(payload)))
+	copy(payload[udpHeaderSize:], data)
+	sum := finishChecksum(checksum(payload, pseudoHeaderChecksum(src, dst, protocolUDP, len(payload))))
+	if sum == 0 {
+		sum = 0xffff
+	}
+	binary.BigEndian.PutUint16(payload[6:], sum)
+	s.output(packet)
+	return nil
+}
+
+// dialUDP opens a UDP flow to the destination.
+func (s *stack) dialUDP(dest net.Destination) (*udpConn, error) {
+	remote := dest.Address.IP()
+	local, err := s.localAddress(remote)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := s.listenUDP(local, 0)
+	if err != nil {
+		return nil, err
+	}
+	conn.remoteAddr = remote
+	conn.remotePort = uint16(dest.Port)
+	return conn, nil
+}
+
+// listenUDP binds a UDP endpoint on the port, or an ephemeral port if port is 0.
+func (s *stack) listenUDP(local net.IP, port uint16) (*udpConn, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return nil, newError("stack closed")
+	}
+	if port == 0 {
+		p, err := s.allocatePort(s.udpPortUsed)
+		if err != nil {
+			return nil, err
+		}
+		port = p
+	} else if s.udpPortUsed(port) {
+		return nil, newError("UDP port ", port, " in use")
+	}
+
+	conn := newUDPConn(s, local, port)
+	s.udpConns[port] = conn
+	return conn, nil
+}
+
+func (s *stack) removeUDP(port uint16, conn *udpConn) {
+	s.Lock()
+	if s.udpConns[port] == conn {
+		delete(s.udpConns, port)
+	}
+	s.Unlock()
+}
+
+func (s *stack) Close() {
+	s.Lock()
+	s.closed = true
+	var tcpConns []*tcpConn
+	for _, c := range s.tcpConns {
+		tcpConns = append(tcpConns, c)
+	}
+	var listeners []*tcpListener
+	for _, l := range s.tcpListeners {
+		listeners = append(listeners, l)
+	}
+	var udpConns []*udpConn
+	for _, c := range s.udpConns {
+		udpConns = append(udpConns, c)
+	}
+	s.Unlock()
+
+	for _, c := range tcpConns {
+		c.abort(newError("stack closed"))
+	}
+	for _, l := range listeners {
+		l.Close() // nolint: errcheck
+	}
+	for _, c := range udpConns {
+		c.Close() // nolint: errcheck
+	}
+}
+
+func checksum(b []byte, initial uint32) uint32 {
+	sum := initial
+	for len(b) >= 2 {
+		sum += uint32(b[0])<<8 | uint32(b[1])
+		b = b[2:]
+	}
+	if len(b) == 1 {
+		sum += uint32(b[0]) << 8
+	}
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+	return sum
+}
+
+func finishChecksum(sum uint32) uint16 {
+	return ^uint16(sum)
+}
+
+func pseudoHeaderChecksum(src, dst net.IP, protocol byte, length int) uint32 {
+	var sum uint32
+	if src4, dst4 := src.To4(), dst.To4(); src4 != nil && dst4 != nil {
+		sum = checksum(src4, 0)
+		sum = checksum(dst4, sum)
+	} else {
+		sum = checksum(src.To16(), 0)
+		sum = checksum(dst.To16(), sum)
+	}
+	sum += uint32(protocol) + uint32(length)
+	return checksum(nil, sum)
+}
+
+func sourceIP(packet []byte) net.IP {
+	switch packet[0] >> 4 {
+	case 4:
+		if len(packet) >= ipv4HeaderSize {
+			return net.IP(packet[12:16])
+		}
+	case 6:
+		if len(packet) >= ipv6HeaderSize {
+			return net.IP(packet[8:24])
+		}
+	}
+	return nil
+}
+
+func destinationIP(packet []byte) net.IP {
+	switch packet[0] >> 4 {
+	case 4:
+		if len(packet) >= ipv4HeaderSize {
+			return net.IP(packet[16:20])
+		}
+	case 6:
+		if len(packet) >= ipv6HeaderSize {
+			return net.IP(packet[24:40])
+		}
+	}
+	return nil
+}
+
+// trimPacket removes padding after the IP packet. It returns nil if the packet is malformed.
+func trimPacket(packet []byte) []byte {
+	var size int
+	switch packet[0] >> 4 {
+	case 4:
+		if len(packet) < ipv4HeaderSize {
+			return nil
+		}
+		size = int(binary.BigEndian.Uint16(packet[2:4]))
+		if size < ipv4HeaderSize {
+			return nil
+		}
+	case 6:
+		if len(packet) < ipv6HeaderSize {
+			return nil
+		}
+		size = ipv6HeaderSize + int(binary.BigEndian.Uint16(packet[4:6]))
+	default:
+		return nil
+	}
+	if size > len(packet) {
+		return nil
+	}
+	return packet[:size]
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// deadline wakes up waiters when a deadline passes.
+type deadline struct {
+	t     time.Time
+	timer *time.Timer
+}
+
+func (d *deadline) set(t time.Time, wake func()) {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.t = t
+	if t.IsZero() {
+		return
+	}
+	if dur := time.Until(t); dur > 0 {
+		d.timer = time.AfterFunc(dur, wake)
+	} else {
+		go wake()
+	}
+}
+
+func (d *deadline) exceeded() bool {
+	return !d.t.IsZero() && !time.Now().Before(d.t)
+}
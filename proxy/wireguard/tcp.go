@@ -0,0 +1,1061 @@
+// +build !confonly
+
+package wireguard
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/signal/done"
+)
+
+const (
+	tcpFlagFIN = 0x01
+	tcpFlagSYN = 0x02
+	tcpFlagRST = 0x04
+	tcpFlagPSH = 0x08
+	tcpFlagACK = 0x10
+
+	tcpHeaderSize = 20
+
+	tcpSendBufferSize     = 256 * 1024
+	tcpReceiveBufferSize  = 1024 * 1024
+	tcpReceiveWindowShift = 5
+	tcpInitialWindow      = 10
+	tcpMaxOutOfOrder      = 256
+	tcpListenBacklog      = 128
+
+	tcpInitialRTO      = time.Second
+	tcpMinRTO          = 200 * time.Millisecond
+	tcpMaxRTO          = 60 * time.Second
+	tcpMaxSYNRetries   = 5
+	tcpMaxRetries      = 12
+	tcpTimeWait        = 2 * time.Second
+	tcpFinWait2Timeout = 60 * time.Second
+)
+
+const (
+	tcpStateClosed = iota
+	tcpStateSynSent
+	tcpStateSynReceived
+	tcpStateEstablished
+	tcpStateFinWait1
+	tcpStateFinWait2
+	tcpStateCloseWait
+	tcpStateClosing
+	tcpStateLastAck
+	tcpStateTimeWait
+)
+
+func seqLT(a, b uint32) bool { return int32(a-b) < 0 }
+func seqGT(a, b uint32) bool { return int32(a-b) > 0 }
+func seqGE(a, b uint32) bool { return int32(a-b) >= 0 }
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+type tcpSegment struct {
+	srcPort uint16
+	dstPort uint16
+	seq     uint32
+	ack     uint32
+	flags   byte
+	window  uint16
+	mss     int
+	wscale  int
+	payload []byte
+}
+
+func parseTCPSegment(b []byte) (*tcpSegment, bool) {
+	if len(b) < tcpHeaderSize {
+		return nil, false
+	}
+	dataOffset := int(b[12]>>4) * 4
+	if dataOffset < tcpHeaderSize || dataOffset > len(b) {
+		return nil, false
+	}
+	seg := &tcpSegment{
+		srcPort: binary.BigEndian.Uint16(b[0:2]),
+		dstPort: binary.BigEndian.Uint16(b[2:4]),
+		seq:     binary.BigEndian.Uint32(b[4:8]),
+		ack:     binary.BigEndian.Uint32(b[8:12]),
+		flags:   b[13],
+		window:  binary.BigEndian.Uint16(b[14:16]),
+		wscale:  -1,
+		payload: b[dataOffset:],
+	}
+
+	options := b[tcpHeaderSize:dataOffset]
+	for len(options) > 0 {
+		kind := options[0]
+		if kind == 0 {
+			break
+		}
+		if kind == 1 {
+			options = options[1:]
+			continue
+		}
+		if len(options) < 2 || options[1] < 2 || int(options[1]) > len(options) {
+			break
+		}
+		length := int(options[1])
+		switch {
+		case kind == 2 && length == 4:
+			seg.mss = int(binary.BigEndian.Uint16(options[2:4]))
+		case kind == 3 && length == 3:
+			seg.wscale = minInt(int(options[2]), 14)
+		}
+		options = options[length:]
+	}
+
+	return seg, true
+}
+
+func (seg *tcpSegment) length() uint32 {
+	n := uint32(len(seg.payload))
+	if seg.flags&tcpFlagSYN != 0 {
+		n++
+	}
+	if seg.flags&tcpFlagFIN != 0 {
+		n++
+	}
+	return n
+}
+
+func (s *stack) deliverTCP(src, dst net.IP, b []byte) {
+	seg, ok := parseTCPSegment(b)
+	if !ok {
+		return
+	}
+	id := newEndpointID(dst, seg.dstPort, src, seg.srcPort)
+
+	s.Lock()
+	conn := s.tcpConns[id]
+	if conn == nil {
+		listener := s.tcpListeners[seg.dstPort]
+		if listener != nil && seg.flags&(tcpFlagSYN|tcpFlagACK|tcpFlagRST) == tcpFlagSYN {
+			conn = newTCPConn(s, dst, seg.dstPort, src, seg.srcPort)
+			conn.listener = listener
+			s.tcpConns[id] = conn
+			s.Unlock()
+			conn.acceptSYN(seg)
+			return
+		}
+	}
+	s.Unlock()
+
+	if conn == nil {
+		if seg.flags&tcpFlagRST == 0 {
+			s.sendReset(dst, src, seg)
+		}
+		return
+	}
+	conn.handleSegment(seg)
+}
+
+// sendReset responds to a segment that does not belong to any connection.
+func (s *stack) sendReset(local, remote net.IP, seg *tcpSegment) {
+	packet, b := s.newPacket(local, remote, protocolTCP, tcpHeaderSize)
+	binary.BigEndian.PutUint16(b[0:], seg.dstPort)
+	binary.BigEndian.PutUint16(b[2:], seg.srcPort)
+	if seg.flags&tcpFlagACK != 0 {
+		binary.BigEndian.PutUint32(b[4:], seg.ack)
+		b[13] = tcpFlagRST
+	} else {
+		binary.BigEndian.PutUint32(b[8:], seg.seq+seg.length())
+		b[13] = tcpFlagRST | tcpFlagACK
+	}
+	b[12] = tcpHeaderSize / 4 << 4
+	binary.BigEndian.PutUint16(b[16:], finishChecksum(checksum(b, pseudoHeaderChecksum(local, remote, protocolTCP, len(b)))))
+	s.output(packet)
+}
+
+func (s *stack) removeTCP(c *tcpConn) {
+	s.Lock()
+	if s.tcpConns[c.id] == c {
+		delete(s.tcpConns, c.id)
+	}
+	s.Unlock()
+}
+
+// dialTCP opens a TCP connection to the destination.
+func (s *stack) dialTCP(ctx context.Context, dest net.Destination) (*tcpConn, error) {
+	remote := dest.Address.IP()
+	local, err := s.localAddress(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return nil, newError("stack closed")
+	}
+	port, err := s.allocatePort(s.tcpPortUsed)
+	if err != nil {
+		s.Unlock()
+		return nil, err
+	}
+	c := newTCPConn(s, local, port, remote, uint16(dest.Port))
+	s.tcpConns[c.id] = c
+	s.Unlock()
+
+	c.mu.Lock()
+	c.state = tcpStateSynSent
+	c.rttStart = time.Now()
+	c.sendSYN()
+	c.armTimer()
+	c.mu.Unlock()
+
+	select {
+	case <-c.established:
+	case <-ctx.Done():
+		c.abort(ctx.Err())
+		return nil, ctx.Err()
+	}
+
+	c.mu.Lock()
+	err = c.err
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// listenTCP accepts TCP connections on the port of all local addresses.
+func (s *stack) listenTCP(port uint16) (*tcpListener, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return nil, newError("stack closed")
+	}
+	if s.tcpPortUsed(port) {
+		return nil, newError("TCP port ", port, " in use")
+	}
+	l := &tcpListener{
+		stack:  s,
+		port:   port,
+		accept: make(chan *tcpConn, tcpListenBacklog),
+		done:   done.New(),
+	}
+	s.tcpListeners[port] = l
+	return l, nil
+}
+
+type tcpListener struct {
+	stack  *stack
+	port   uint16
+	accept chan *tcpConn
+	done   *done.Instance
+}
+
+func (l *tcpListener) enqueue(c *tcpConn) bool {
+	if l.done.Done() {
+		return false
+	}
+	select {
+	case l.accept <- c:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *tcpListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.accept:
+		return c, nil
+	case <-l.done.Wait():
+		return nil, io.EOF
+	}
+}
+
+func (l *tcpListener) Close() error {
+	if err := l.done.Close(); err != nil {
+		return err
+	}
+	l.stack.Lock()
+	if l.stack.tcpListeners[l.port] == l {
+		delete(l.stack.tcpListeners, l.port)
+	}
+	l.stack.Unlock()
+	return nil
+}
+
+func (l *tcpListener) Addr() net.Addr {
+	return &net.TCPAddr{IP: l.stack.addresses[0], Port: int(l.port)}
+}
+
+type tcpOutOfOrder struct {
+	seq  uint32
+	data []byte
+	fin  bool
+}
+
+// tcpConn is a TCP connection in the stack. It implements net.Conn.
+type tcpConn struct {
+	stack      *stack
+	id         endpointID
+	localAddr  net.IP
+	localPort  uint16
+	remoteAddr net.IP
+	remotePort uint16
+	listener   *tcpListener
+
+	mu          sync.Mutex
+	cond        *sync.Cond
+	state       int
+	err         error
+	userClosed  bool
+	established chan struct{}
+	signaled    bool
+
+	mss         int
+	sndWndShift uint
+	rcvWndShift uint
+
+	iss       uint32
+	sndUna    uint32
+	sndNxt    uint32
+	sndMax    uint32
+	sndWnd    uint32
+	sndBuf    []byte
+	finQueued bool
+	finSent   bool
+	// finSeq is the sequence number of FIN, valid if hasFinSeq is set.
+	finSeq    uint32
+	hasFinSeq bool
+	cwnd      int
+	ssthresh  int
+	dupAcks   int
+
+	srtt          time.Duration
+	rttvar        time.Duration
+	rto           time.Duration
+	rttMeasuring  bool
+	rttSeq        uint32
+	rttStart      time.Time
+	retries       int
+	timer         *time.Timer
+	timerDeadline time.Time
+
+	irs         uint32
+	rcvNxt      uint32
+	rcvBuf      []byte
+	outOfOrder  []tcpOutOfOrder
+	finReceived bool
+	lastWindow  int
+
+	readDeadline  deadline
+	writeDeadline deadline
+}
+
+func newTCPConn(s *stack, local net.IP, localPort uint16, remote net.IP, remotePort uint16) *tcpConn {
+	// Addresses may refer to the buffer of an incoming packet.
+	local = append(net.IP(nil), local...)
+	remote = append(net.IP(nil), remote...)
+	c := &tcpConn{
+		stack:       s,
+		id:          newEndpointID(local, localPort, remote, remotePort),
+		localAddr:   local,
+		localPort:   localPort,
+		remoteAddr:  remote,
+		remotePort:  remotePort,
+		established: make(chan struct{}),
+		mss:         s.maxPayload(remote) - tcpHeaderSize,
+		iss:         rand.Uint32(),
+		ssthresh:    1 << 30,
+		rto:         tcpInitialRTO,
+	}
+	c.cond = sync.NewCond(&c.mu)
+	c.sndUna = c.iss
+	c.sndNxt = c.iss + 1
+	c.sndMax = c.sndNxt
+	c.timer = time.AfterFunc(time.Hour, c.onTimer)
+	c.timer.Stop()
+	return c
+}
+
+func (c *tcpConn) receiveWindow() int {
+	return maxInt(tcpReceiveBufferSize-len(c.rcvBuf), 0)
+}
+
+func (c *tcpConn) sendSegment(seq uint32, flags byte, data []byte) {
+	optionSize := 0
+	if flags&tcpFlagSYN != 0 {
+		// MSS option, and window scale option if it is offered or accepted.
+		optionSize = 4
+		if c.state == tcpStateSynSent || c.rcvWndShift > 0 {
+			optionSize += 4
+		}
+	}
+	packet, b := c.stack.newPacket(c.localAddr, c.remoteAddr, protocolTCP, tcpHeaderSize+optionSize+len(data))
+	binary.BigEndian.PutUint16(b[0:], c.localPort)
+	binary.BigEndian.PutUint16(b[2:], c.remotePort)
+	binary.BigEndian.PutUint32(b[4:], seq)
+	if flags&tcpFlagACK != 0 {
+		binary.BigEndian.PutUint32(b[8:], c.rcvNxt)
+	}
+	b[12] = byte((tcpHeaderSize+optionSize)/4) << 4
+	b[13] = flags
+
+	// Window in SYN segments is never scaled.
+	shift := c.rcvWndShift
+	if flags&tcpFlagSYN != 0 {
+		shift = 0
+	}
+	window := minInt(c.receiveWindow()>>shift, 0xffff)
+	binary.BigEndian.PutUint16(b[14:], uint16(window))
+	c.lastWindow = window << shift
+
+	if optionSize > 0 {
+		b[20], b[21] = 2, 4
+		binary.BigEndian.PutUint16(b[22:], uint16(c.mss))
+	}
+	if optionSize > 4 {
+		b[24], b[25], b[26], b[27] = 1, 3, 3, tcpReceiveWindowShift
+	}
+
+	copy(b[tcpHeaderSize+optionSize:], data)
+	binary.BigEndian.PutUint16(b[16:], finishChecksum(checksum(b, pseudoHeaderChecksum(c.localAddr, c.remoteAddr, protocolTCP, len(b)))))
+	c.stack.output(packet)
+}
+
+func (c *tcpConn) sendSYN() {
+	flags := byte(tcpFlagSYN)
+	if c.state == tcpStateSynReceived {
+		flags |= tcpFlagACK
+	}
+	c.sendSegment(c.iss, flags, nil)
+}
+
+func (c *tcpConn) sendACK() {
+	c.sendSegment(c.sndNxt, tcpFlagACK, nil)
+}
+
+func (c *tcpConn) applySYNOptions(seg *tcpSegment) {
+	if seg.mss > 0 && seg.mss < c.mss {
+		c.mss = seg.mss
+	}
+	if seg.wscale >= 0 {
+		c.sndWndShift = uint(seg.wscale)
+		c.rcvWndShift = tcpReceiveWindowShift
+	}
+	c.cwnd = tcpInitialWindow * c.mss
+	c.sndWnd = uint32(seg.window)
+}
+
+func (c *tcpConn) acceptSYN(seg *tcpSegment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.state = tcpStateSynReceived
+	c.irs = seg.seq
+	c.rcvNxt = seg.seq + 1
+	c.applySYNOptions(seg)
+	c.sendSYN()
+	c.armTimer()
+}
+
+func (c *tcpConn) handleSegment(seg *tcpSegment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case tcpStateClosed:
+		return
+
+	case tcpStateSynSent:
+		if seg.flags&tcpFlagACK != 0 && seg.ack != c.iss+1 {
+			if seg.flags&tcpFlagRST == 0 {
+				c.stack.sendReset(c.localAddr, c.remoteAddr, seg)
+			}
+			return
+		}
+		if seg.flags&tcpFlagRST != 0 {
+			if seg.flags&tcpFlagACK != 0 {
+				c.fail(newError("connection refused by ", c.remoteAddr, ":", c.remotePort))
+			}
+			return
+		}
+		if seg.flags&(tcpFlagSYN|tcpFlagACK) != tcpFlagSYN|tcpFlagACK {
+			return
+		}
+		c.irs = seg.seq
+		c.rcvNxt = seg.seq + 1
+		c.applySYNOptions(seg)
+		c.sndUna = seg.ack
+		c.state = tcpStateEstablished
+		if c.retries == 0 {
+			c.updateRTT(time.Since(c.rttStart))
+		}
+		c.retries = 0
+		c.stopTimer()
+		c.sendACK()
+		c.signalEstablished()
+		return
+
+	case tcpStateSynReceived:
+		if seg.flags&tcpFlagRST != 0 {
+			c.close()
+			return
+		}
+		if seg.flags&tcpFlagSYN != 0 {
+			if seg.seq == c.irs {
+				c.sendSYN()
+			}
+			return
+		}
+		if seg.flags&tcpFlagACK == 0 {
+			return
+		}
+		if seg.ack != c.iss+1 {
+			c.stack.sendReset(c.localAddr, c.remoteAddr, seg)
+			return
+		}
+		c.sndUna = seg.ack
+		c.sndWnd = uint32(seg.window) << c.sndWndShift
+		c.state = tcpStateEstablished
+		c.retries = 0
+		c.stopTimer()
+		if !c.listener.enqueue(c) {
+			c.sendSegment(c.sndNxt, tcpFlagRST|tcpFlagACK, nil)
+			c.close()
+			return
+		}
+	}
+
+	if seg.flags&tcpFlagRST != 0 {
+		if seg.seq == c.rcvNxt || (seqGT(seg.seq, c.rcvNxt) && seqLT(seg.seq, c.rcvNxt+uint32(c.lastWindow))) {
+			c.fail(newError("connection reset by ", c.remoteAddr, ":", c.remotePort))
+		}
+		return
+	}
+	if seg.flags&tcpFlagSYN != 0 {
+		c.sendACK()
+		return
+	}
+	if seg.flags&tcpFlagACK == 0 {
+		return
+	}
+
+	c.processACK(seg)
+	if c.state == tcpStateClosed {
+		return
+	}
+	c.processData(seg)
+}
+
+func (c *tcpConn) processACK(seg *tcpSegment) {
+	ack := seg.ack
+	if seqGT(ack, c.sndMax) {
+		c.sendACK()
+		return
+	}
+
+	window := uint32(seg.window) << c.sndWndShift
+	if seqGT(ack, c.sndUna) {
+		acked := int(ack - c.sndUna)
+		c.sndBuf = c.sndBuf[minInt(acked, len(c.sndBuf)):]
+		if len(c.sndBuf) == 0 {
+			c.sndBuf = nil
+		}
+		c.sndUna = ack
+		if seqLT(c.sndNxt, ack) {
+			c.sndNxt = ack
+		}
+		if c.rttMeasuring && seqGE(ack, c.rttSeq) {
+			c.updateRTT(time.Since(c.rttStart))
+			c.rttMeasuring = false
+		}
+		if c.cwnd < c.ssthresh {
+			c.cwnd += minInt(acked, c.mss)
+		} else {
+			c.cwnd += maxInt(c.mss*c.mss/c.cwnd, 1)
+		}
+		c.dupAcks = 0
+		c.retries = 0
+		c.sndWnd = window
+
+		if c.sndUna == c.sndMax {
+			c.stopTimer()
+		} else {
+			c.restartTimer()
+		}
+
+		if c.hasFinSeq && seqGT(ack, c.finSeq) {
+			switch c.state {
+			case tcpStateFinWait1:
+				c.state = tcpStateFinWait2
+				time.AfterFunc(tcpFinWait2Timeout, c.expire)
+			case tcpStateClosing:
+				c.enterTimeWait()
+			case tcpStateLastAck:
+				c.close()
+				return
+			}
+		}
+		c.cond.Broadcast()
+	} else if ack == c.sndUna {
+		if len(seg.payload) == 0 && seg.flags&tcpFlagFIN == 0 && window == c.sndWnd && window > 0 && c.sndUna != c.sndMax {
+			c.dupAcks++
+			if c.dupAcks == 3 {
+				c.ssthresh = maxInt(int(c.sndMax-c.sndUna)/2, 2*c.mss)
+				c.cwnd = c.ssthresh
+				c.retransmit()
+			}
+		}
+		if c.sndWnd == 0 && window > 0 {
+			// The window is reopened, so the probe that is not accepted is sent again with the following data.
+			c.sndNxt = c.sndUna
+		}
+		c.sndWnd = window
+	}
+
+	c.trySend()
+}
+
+func (c *tcpConn) processData(seg *tcpSegment) {
+	data := seg.payload
+	fin := seg.flags&tcpFlagFIN != 0
+	if len(data) == 0 && !fin {
+		return
+	}
+	if c.finReceived {
+		c.sendACK()
+		return
+	}
+
+	seq := seg.seq
+	end := seq + uint32(len(data))
+	if seqLT(end, c.rcvNxt) || (end == c.rcvNxt && !fin) {
+		// Duplicate.
+		c.sendACK()
+		return
+	}
+	if seqLT(seq, c.rcvNxt) {
+		data = data[c.rcvNxt-seq:]
+		seq = c.rcvNxt
+	}
+
+	window := c.receiveWindow()
+	if seq == c.rcvNxt {
+		if len(data) > window {
+			data = data[:window]
+			fin = false
+		}
+		c.receive(data, fin)
+		c.drainOutOfOrder()
+		c.cond.Broadcast()
+	} else if int(seq-c.rcvNxt)+len(data) <= window && len(c.outOfOrder) < tcpMaxOutOfOrder {
+		c.outOfOrder = append(c.outOfOrder, tcpOutOfOrder{
+			seq:  seq,
+			data: append([]byte(nil), data...),
+			fin:  fin,
+		})
+	}
+	c.sendACK()
+}
+
+func (c *tcpConn) receive(data []byte, fin bool) {
+	if !c.userClosed {
+		c.rcvBuf = append(c.rcvBuf, data...)
+	}
+	c.rcvNxt += uint32(len(data))
+	if !fin {
+		return
+	}
+
+	c.rcvNxt++
+	c.finReceived = true
+	switch c.state {
+	case tcpStateEstablished:
+		c.state = tcpStateCloseWait
+	case tcpStateFinWait1:
+		c.state = tcpStateClosing
+	case tcpStateFinWait2:
+		c.enterTimeWait()
+	}
+}
+
+func (c *tcpConn) drainOutOfOrder() {
+	for progress := true; progress && !c.finReceived; {
+		progress = false
+		for i, o := range c.outOfOrder {
+			if seqGT(o.seq, c.rcvNxt) {
+				continue
+			}
+			c.outOfOrder = append(c.outOfOrder[:i], c.outOfOrder[i+1:]...)
+			if end := o.seq + uint32(len(o.data)); seqGT(end, c.rcvNxt) || (end == c.rcvNxt && o.fin) {
+				c.receive(o.data[c.rcvNxt-o.seq:], o.fin)
+			}
+			progress = true
+			break
+		}
+	}
+	if c.finReceived {
+		c.outOfOrder = nil
+	}
+}
+
+// trySend sends pending data and FIN within the send window.
+func (c *tcpConn) trySend() {
+	switch c.state {
+	case tcpStateEstablished, tcpStateCloseWait, tcpStateFinWait1, tcpStateClosing, tcpStateLastAck:
+	default:
+		return
+	}
+
+	window := minInt(int(c.sndWnd), c.cwnd)
+	for {
+		offset := int(c.sndNxt - c.sndUna)
+		if offset >= len(c.sndBuf) {
+			break
+		}
+		n := minInt(minInt(len(c.sndBuf)-offset, c.mss), window-offset)
+		if n <= 0 {
+			break
+		}
+		if !c.rttMeasuring && !seqLT(c.sndNxt, c.sndMax) {
+			c.rttMeasuring = true
+			c.rttSeq = c.sndNxt + uint32(n)
+			c.rttStart = time.Now()
+		}
+		c.sendSegment(c.sndNxt, tcpFlagACK|tcpFlagPSH, c.sndBuf[offset:offset+n])
+		c.sndNxt += uint32(n)
+		if seqGT(c.sndNxt, c.sndMax) {
+			c.sndMax = c.sndNxt
+		}
+	}
+
+	if c.finQueued && !c.finSent && int(c.sndNxt-c.sndUna) == len(c.sndBuf) {
+		c.finSeq = c.sndNxt
+		c.hasFinSeq = true
+		c.sendSegment(c.sndNxt, tcpFlagFIN|tcpFlagACK, nil)
+		c.finSent = true
+		c.sndNxt++
+		if seqGT(c.sndNxt, c.sndMax) {
+			c.sndMax = c.sndNxt
+		}
+		switch c.state {
+		case tcpStateEstablished:
+			c.state = tcpStateFinWait1
+		case tcpStateCloseWait:
+			c.state = tcpStateLastAck
+		}
+	}
+
+	if c.sndUna != c.sndMax || len(c.sndBuf) > 0 {
+		c.armTimer()
+	}
+}
+
+// retransmit resends the first unacknowledged segment.
+func (c *tcpConn) retransmit() {
+	c.rttMeasuring = false
+	if n := minInt(minInt(len(c.sndBuf), c.mss), int(c.sndMax-c.sndUna)); n > 0 {
+		c.sendSegment(c.sndUna, tcpFlagACK|tcpFlagPSH, c.sndBuf[:n])
+	} else if c.hasFinSeq {
+		c.sendSegment(c.finSeq, tcpFlagFIN|tcpFlagACK, nil)
+	}
+}
+
+func (c *tcpConn) onTimer() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.timerDeadline.IsZero() || time.Now().Before(c.timerDeadline) {
+		return
+	}
+	c.timerDeadline = time.Time{}
+
+	switch c.state {
+	case tcpStateClosed, tcpStateTimeWait, tcpStateFinWait2:
+		return
+	case tcpStateSynSent, tcpStateSynReceived:
+		c.retries++
+		if c.retries > tcpMaxSYNRetries {
+			c.fail(newError("connection to ", c.remoteAddr, ":", c.remotePort, " timed out"))
+			return
+		}
+		c.backoff()
+		c.sendSYN()
+		c.armTimer()
+		return
+	}
+
+	if c.sndWnd == 0 && len(c.sndBuf) > 0 {
+		// Probes the zero window. Probes are not counted as retries, so the connection is kept while the
+		// receiver doesn't read.
+		c.sendSegment(c.sndUna, tcpFlagACK, c.sndBuf[:1])
+		c.sndNxt = c.sndUna + 1
+		if seqGT(c.sndNxt, c.sndMax) {
+			c.sndMax = c.sndNxt
+		}
+		c.backoff()
+		c.armTimer()
+		return
+	}
+	if c.sndUna == c.sndMax {
+		return
+	}
+
+	c.retries++
+	if c.retries > tcpMaxRetries {
+		c.sendSegment(c.sndNxt, tcpFlagRST|tcpFlagACK, nil)
+		c.fail(newError("connection to ", c.remoteAddr, ":", c.remotePort, " timed out"))
+		return
+	}
+	c.ssthresh = maxInt(int(c.sndMax-c.sndUna)/2, 2*c.mss)
+	c.cwnd = c.mss
+	c.dupAcks = 0
+	c.backoff()
+	c.rttMeasuring = false
+	// Go back to the first unacknowledged segment.
+	c.sndNxt = c.sndUna
+	c.finSent = false
+	c.trySend()
+	c.armTimer()
+}
+
+func (c *tcpConn) backoff() {
+	c.rto *= 2
+	if c.rto > tcpMaxRTO {
+		c.rto = tcpMaxRTO
+	}
+}
+
+func (c *tcpConn) updateRTT(r time.Duration) {
+	if r <= 0 {
+		return
+	}
+	if c.srtt == 0 {
+		c.srtt = r
+		c.rttvar = r / 2
+	} else {
+		diff := c.srtt - r
+		if diff < 0 {
+			diff = -diff
+		}
+		c.rttvar = (3*c.rttvar + diff) / 4
+		c.srtt = (7*c.srtt + r) / 8
+	}
+	c.rto = c.srtt + 4*c.rttvar
+	if c.rto < tcpMinRTO {
+		c.rto = tcpMinRTO
+	}
+	if c.rto > tcpMaxRTO {
+		c.rto = tcpMaxRTO
+	}
+}
+
+func (c *tcpConn) armTimer() {
+	if !c.timerDeadline.IsZero() {
+		return
+	}
+	c.restartTimer()
+}
+
+func (c *tcpConn) restartTimer() {
+	c.timerDeadline = time.Now().Add(c.rto)
+	c.timer.Reset(c.rto)
+}
+
+func (c *tcpConn) stopTimer() {
+	c.timerDeadline = time.Time{}
+	c.timer.Stop()
+}
+
+func (c *tcpConn) signalEstablished() {
+	if !c.signaled {
+		c.signaled = true
+		close(c.established)
+	}
+}
+
+func (c *tcpConn) enterTimeWait() {
+	c.state = tcpStateTimeWait
+	c.stopTimer()
+	time.AfterFunc(tcpTimeWait, c.expire)
+}
+
+func (c *tcpConn) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == tcpStateTimeWait || c.state == tcpStateFinWait2 {
+		c.close()
+	}
+}
+
+// close releases the connection from the stack.
+func (c *tcpConn) close() {
+	c.state = tcpStateClosed
+	c.stopTimer()
+	if c.err == nil {
+		c.err = io.EOF
+	}
+	c.signalEstablished()
+	c.cond.Broadcast()
+	c.stack.removeTCP(c)
+}
+
+func (c *tcpConn) fail(err error) {
+	c.err = err
+	c.close()
+}
+
+// abort resets the connection.
+func (c *tcpConn) abort(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == tcpStateClosed {
+		return
+	}
+	if c.state != tcpStateSynSent {
+		c.sendSegment(c.sndNxt, tcpFlagRST|tcpFlagACK, nil)
+	}
+	c.fail(err)
+}
+
+func (c *tcpConn) wake() {
+	c.mu.Lock()
+	c.cond.Broadcast()
+	c.mu.Unlock()
+}
+
+// Read implements net.Conn.
+func (c *tcpConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		if c.userClosed {
+			return 0, io.ErrClosedPipe
+		}
+		if len(c.rcvBuf) > 0 {
+			n := copy(b, c.rcvBuf)
+			c.rcvBuf = c.rcvBuf[n:]
+			if len(c.rcvBuf) == 0 {
+				c.rcvBuf = nil
+			}
+			if c.lastWindow < tcpReceiveBufferSize/2 && c.receiveWindow() >= tcpReceiveBufferSize/2 && !c.finReceived && c.state != tcpStateClosed {
+				// Window update.
+				c.sendACK()
+			}
+			return n, nil
+		}
+		if c.finReceived {
+			return 0, io.EOF
+		}
+		if c.err != nil {
+			return 0, c.err
+		}
+		if c.readDeadline.exceeded() {
+			return 0, timeoutError{}
+		}
+		c.cond.Wait()
+	}
+}
+
+// Write implements net.Conn.
+func (c *tcpConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	total := 0
+	for len(b) > 0 {
+		if c.userClosed || c.finQueued {
+			return total, io.ErrClosedPipe
+		}
+		if c.state != tcpStateEstablished && c.state != tcpStateCloseWait {
+			if c.err != nil {
+				return total, c.err
+			}
+			return total, io.ErrClosedPipe
+		}
+		if c.writeDeadline.exceeded() {
+			return total, timeoutError{}
+		}
+		space := tcpSendBufferSize - len(c.sndBuf)
+		if space <= 0 {
+			c.cond.Wait()
+			continue
+		}
+		n := minInt(space, len(b))
+		c.sndBuf = append(c.sndBuf, b[:n]...)
+		b = b[n:]
+		total += n
+		c.trySend()
+	}
+	return total, nil
+}
+
+// Close implements net.Conn. It sends FIN after all pending data.
+func (c *tcpConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.userClosed {
+		return nil
+	}
+	c.userClosed = true
+	c.rcvBuf = nil
+	c.cond.Broadcast()
+
+	switch c.state {
+	case tcpStateSynSent, tcpStateSynReceived:
+		c.close()
+	case tcpStateEstablished, tcpStateCloseWait:
+		c.finQueued = true
+		c.trySend()
+	}
+	return nil
+}
+
+func (c *tcpConn) LocalAddr() net.Addr {
+	return &net.TCPAddr{IP: c.localAddr, Port: int(c.localPort)}
+}
+
+func (c *tcpConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: c.remoteAddr, Port: int(c.remotePort)}
+}
+
+func (c *tcpConn) SetDeadline(t time.Time) error {
+	c.SetReadDeadline(t)  // nolint: errcheck
+	c.SetWriteDeadline(t) // nolint: errcheck
+	return nil
+}
+
+func (c *tcpConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline.set(t, c.wake)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *tcpConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.writeDeadline.set(t, c.wake)
+	c.mu.Unlock()
+	return nil
+}
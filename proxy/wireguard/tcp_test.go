@@ -0,0 +1,347 @@
+package wireguard
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+)
+
+// tcpTestLink carries packets of one direction between two stacks, and drops the TCP segments that its filter
+// rejects.
+type tcpTestLink struct {
+	sync.Mutex
+	filter func(*tcpSegment) bool
+	queue  chan []byte
+}
+
+func newTCPTestLink(to *stack) *tcpTestLink {
+	l := &tcpTestLink{queue: make(chan []byte, 4096)}
+	go func() {
+		for packet := range l.queue {
+			to.deliver(packet)
+		}
+	}()
+	return l
+}
+
+func (l *tcpTestLink) setFilter(filter func(*tcpSegment) bool) {
+	l.Lock()
+	l.filter = filter
+	l.Unlock()
+}
+
+func (l *tcpTestLink) output(packet []byte) {
+	l.Lock()
+	filter := l.filter
+	l.Unlock()
+
+	if filter != nil {
+		seg, ok := parseTCPSegment(packet[ipv4HeaderSize:])
+		if ok && !filter(seg) {
+			return
+		}
+	}
+	select {
+	case l.queue <- packet:
+	default:
+	}
+}
+
+// tcpTestPair is a client stack and a server stack connected by links without a WireGuard device.
+type tcpTestPair struct {
+	client, server         *stack
+	uplink, downlink       *tcpTestLink
+	clientConn, serverConn *tcpConn
+}
+
+func newTCPTestPair(t *testing.T) (*tcpTestPair, func()) {
+	var uplink, downlink *tcpTestLink
+	client := newStack([]net.IP{net.ParseIP("10.0.0.2")}, 1420, func(b []byte) { uplink.output(b) })
+	server := newStack([]net.IP{net.ParseIP("10.0.0.1")}, 1420, func(b []byte) { downlink.output(b) })
+	uplink = newTCPTestLink(server)
+	downlink = newTCPTestLink(client)
+
+	listener, err := server.listenTCP(80)
+	common.Must(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	clientConn, err := client.dialTCP(ctx, net.TCPDestination(net.ParseAddress("10.0.0.1"), 80))
+	common.Must(err)
+	serverConn, err := listener.Accept()
+	common.Must(err)
+
+	p := &tcpTestPair{
+		client:     client,
+		server:     server,
+		uplink:     uplink,
+		downlink:   downlink,
+		clientConn: clientConn,
+		serverConn: serverConn.(*tcpConn),
+	}
+	return p, func() {
+		listener.Close()
+		client.Close()
+		server.Close()
+	}
+}
+
+// inspect calls f with the connection locked.
+func (c *tcpConn) inspect(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f()
+}
+
+// waitFor polls the condition on the locked connection until it holds.
+func waitFor(t *testing.T, c *tcpConn, timeout time.Duration, condition func() bool) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var ok bool
+		c.inspect(func() { ok = condition() })
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in ", timeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readAll reads size bytes from the connection, and compares them with the payload.
+func readAll(t *testing.T, conn *tcpConn, payload []byte) {
+	common.Must(conn.SetReadDeadline(time.Now().Add(30 * time.Second)))
+	response := make([]byte, len(payload))
+	if _, err := io.ReadFull(conn, response); err != nil {
+		t.Fatal("failed to read: ", err)
+	}
+	if !bytes.Equal(response, payload) {
+		t.Error("unexpected data")
+	}
+}
+
+func TestTCPRetransmissionTimeout(t *testing.T) {
+	p, closePair := newTCPTestPair(t)
+	defer closePair()
+
+	var rto time.Duration
+	var start uint32
+	var mss int
+	p.clientConn.inspect(func() {
+		rto = p.clientConn.rto
+		start = p.clientConn.sndNxt
+		mss = p.clientConn.mss
+	})
+
+	var access sync.Mutex
+	var dropped []uint32
+	p.uplink.setFilter(func(seg *tcpSegment) bool {
+		if len(seg.payload) == 0 {
+			return true
+		}
+		access.Lock()
+		dropped = append(dropped, seg.seq)
+		access.Unlock()
+		return false
+	})
+
+	payload := make([]byte, 32*1024)
+	common.Must2(rand.Read(payload))
+	common.Must2(p.clientConn.Write(payload))
+
+	waitFor(t, p.clientConn, 10*time.Second, func() bool { return p.clientConn.retries >= 2 })
+	p.clientConn.inspect(func() {
+		if p.clientConn.rto != 4*rto {
+			t.Error("expect RTO ", 4*rto, " after 2 timeouts, but got ", p.clientConn.rto)
+		}
+		if p.clientConn.cwnd != mss {
+			t.Error("expect congestion window of 1 segment after timeout, but got ", p.clientConn.cwnd)
+		}
+		if p.clientConn.ssthresh != tcpInitialWindow*mss/2 {
+			t.Error("expect slow start threshold of half the initial window, but got ", p.clientConn.ssthresh)
+		}
+	})
+
+	access.Lock()
+	if len(dropped) < tcpInitialWindow+2 {
+		t.Error("expect initial window and 2 retransmissions, but got ", len(dropped), " segments")
+	} else {
+		for _, seq := range dropped[len(dropped)-2:] {
+			if seq != start {
+				t.Error("expect retransmission of first unacknowledged segment ", start, ", but got ", seq)
+			}
+		}
+	}
+	access.Unlock()
+
+	p.uplink.setFilter(nil)
+	readAll(t, p.serverConn, payload)
+	waitFor(t, p.clientConn, 10*time.Second, func() bool {
+		return p.clientConn.retries == 0 && p.clientConn.sndUna == p.clientConn.sndMax
+	})
+}
+
+func TestTCPTimeoutFailsConnection(t *testing.T) {
+	p, closePair := newTCPTestPair(t)
+	defer closePair()
+
+	p.uplink.setFilter(func(*tcpSegment) bool { return false })
+	p.clientConn.inspect(func() {
+		// Starts the backoff from 1ms, so that all retries are done in seconds.
+		p.clientConn.rto = time.Millisecond
+	})
+	common.Must2(p.clientConn.Write([]byte("lost")))
+
+	common.Must(p.clientConn.SetReadDeadline(time.Now().Add(30 * time.Second)))
+	if _, err := p.clientConn.Read(make([]byte, 1)); err == nil || err == io.EOF {
+		t.Error("expect time out error, but got ", err)
+	}
+	p.clientConn.inspect(func() {
+		if p.clientConn.retries != tcpMaxRetries+1 {
+			t.Error("expect ", tcpMaxRetries, " retries, but got ", p.clientConn.retries-1)
+		}
+		if p.clientConn.state != tcpStateClosed {
+			t.Error("expect closed connection, but got state ", p.clientConn.state)
+		}
+	})
+}
+
+func TestTCPFastRetransmit(t *testing.T) {
+	p, closePair := newTCPTestPair(t)
+	defer closePair()
+
+	var start uint32
+	p.clientConn.inspect(func() {
+		start = p.clientConn.sndNxt
+		// Recovers from the loss only by duplicate ACKs.
+		p.clientConn.rto = time.Minute
+	})
+
+	var access sync.Mutex
+	lost := false
+	p.uplink.setFilter(func(seg *tcpSegment) bool {
+		access.Lock()
+		defer access.Unlock()
+		if seg.seq == start && len(seg.payload) > 0 && !lost {
+			lost = true
+			return false
+		}
+		return true
+	})
+
+	payload := make([]byte, 64*1024)
+	common.Must2(rand.Read(payload))
+	common.Must2(p.clientConn.Write(payload))
+	readAll(t, p.serverConn, payload)
+
+	access.Lock()
+	if !lost {
+		t.Error("first segment is not dropped")
+	}
+	access.Unlock()
+	p.clientConn.inspect(func() {
+		if p.clientConn.ssthresh == 1<<30 {
+			t.Error("slow start threshold is not reduced by fast retransmit")
+		}
+		if p.clientConn.retries != 0 {
+			t.Error("unexpected retransmission timeout")
+		}
+	})
+}
+
+func TestTCPReceiveWindow(t *testing.T) {
+	p, closePair := newTCPTestPair(t)
+	defer closePair()
+
+	var access sync.Mutex
+	var probes int
+	p.uplink.setFilter(func(seg *tcpSegment) bool {
+		if len(seg.payload) == 1 {
+			access.Lock()
+			probes++
+			access.Unlock()
+		}
+		return true
+	})
+
+	payload := make([]byte, tcpReceiveBufferSize+tcpSendBufferSize/2)
+	common.Must2(rand.Read(payload))
+	writeDone := make(chan error, 1)
+	go func() {
+		_, err := p.clientConn.Write(payload)
+		writeDone <- err
+	}()
+
+	// The server doesn't read, so its receive buffer fills up and the sender is blocked by the zero window.
+	waitFor(t, p.serverConn, 10*time.Second, func() bool { return len(p.serverConn.rcvBuf) == tcpReceiveBufferSize })
+	waitFor(t, p.clientConn, 10*time.Second, func() bool { return p.clientConn.sndWnd == 0 })
+	p.clientConn.inspect(func() {
+		if inFlight := int(p.clientConn.sndMax - p.clientConn.sndUna); inFlight > 1 {
+			t.Error("expect at most 1 byte in flight to the zero window, but got ", inFlight)
+		}
+	})
+	p.serverConn.inspect(func() {
+		if n := len(p.serverConn.rcvBuf); n != tcpReceiveBufferSize {
+			t.Error("expect ", tcpReceiveBufferSize, " bytes in receive buffer, but got ", n)
+		}
+	})
+
+	waitProbes := func(n int) {
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			access.Lock()
+			sent := probes
+			access.Unlock()
+			if sent >= n {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("expect ", n, " zero window probes, but got ", sent)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// The zero window is probed without counting as retransmission timeouts, so the connection is not failed by
+	// a receiver that doesn't read for long.
+	p.clientConn.inspect(func() { p.clientConn.rto = time.Millisecond })
+	waitProbes(3)
+	p.clientConn.inspect(func() {
+		if p.clientConn.retries != 0 {
+			t.Error("zero window probes are counted as ", p.clientConn.retries, " retries")
+		}
+		if p.clientConn.state != tcpStateEstablished {
+			t.Error("unexpected state ", p.clientConn.state)
+		}
+	})
+
+	// The window update resumes the transfer without waiting for the next probe, which is minutes away.
+	var sent int
+	p.clientConn.inspect(func() {
+		p.clientConn.rto = time.Minute
+		access.Lock()
+		sent = probes
+		access.Unlock()
+	})
+	waitProbes(sent + 1)
+	readAll(t, p.serverConn, payload)
+	select {
+	case err := <-writeDone:
+		common.Must(err)
+	case <-time.After(10 * time.Second):
+		t.Error("write is not finished")
+	}
+	p.clientConn.inspect(func() {
+		if p.clientConn.ssthresh != 1<<30 {
+			t.Error("reopened window is taken for loss")
+		}
+	})
+}
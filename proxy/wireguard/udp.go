@@ -0,0 +1,148 @@
+// +build !confonly
+
+package wireguard
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/signal/done"
+)
+
+const udpQueueSize = 256
+
+type udpPacket struct {
+	from    *net.UDPAddr
+	payload []byte
+}
+
+// udpConn is a UDP endpoint in the stack. It is connected if the remote address is set.
+type udpConn struct {
+	stack      *stack
+	localAddr  net.IP
+	localPort  uint16
+	remoteAddr net.IP
+	remotePort uint16
+
+	queue chan udpPacket
+	done  *done.Instance
+
+	access       sync.Mutex
+	readDeadline deadline
+	readTimeout  chan struct{}
+}
+
+func newUDPConn(s *stack, local net.IP, port uint16) *udpConn {
+	return &udpConn{
+		stack:       s,
+		localAddr:   local,
+		localPort:   port,
+		queue:       make(chan udpPacket, udpQueueSize),
+		done:        done.New(),
+		readTimeout: make(chan struct{}),
+	}
+}
+
+func (c *udpConn) deliver(src net.IP, srcPort uint16, payload []byte) {
+	if c.remoteAddr != nil && (!c.remoteAddr.Equal(src) || c.remotePort != srcPort) {
+		return
+	}
+	p := udpPacket{
+		from:    &net.UDPAddr{IP: append(net.IP(nil), src...), Port: int(srcPort)},
+		payload: append([]byte(nil), payload...),
+	}
+	select {
+	case c.queue <- p:
+	default:
+		// Drop the packet if the reader falls behind.
+	}
+}
+
+func (c *udpConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	c.access.Lock()
+	timeout := c.readTimeout
+	exceeded := c.readDeadline.exceeded()
+	c.access.Unlock()
+	if exceeded {
+		return 0, nil, timeoutError{}
+	}
+
+	select {
+	case p := <-c.queue:
+		return copy(b, p.payload), p.from, nil
+	case <-c.done.Wait():
+		return 0, nil, io.EOF
+	case <-timeout:
+		return 0, nil, timeoutError{}
+	}
+}
+
+func (c *udpConn) Read(b []byte) (int, error) {
+	n, _, err := c.ReadFrom(b)
+	return n, err
+}
+
+func (c *udpConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	if c.done.Done() {
+		return 0, io.ErrClosedPipe
+	}
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return 0, newError("invalid address ", addr)
+	}
+	if err := c.stack.sendUDP(c.localAddr, c.localPort, udpAddr.IP, uint16(udpAddr.Port), b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *udpConn) Write(b []byte) (int, error) {
+	if c.remoteAddr == nil {
+		return 0, newError("UDP endpoint not connected")
+	}
+	return c.WriteTo(b, c.RemoteAddr())
+}
+
+func (c *udpConn) Close() error {
+	if err := c.done.Close(); err != nil {
+		return err
+	}
+	c.stack.removeUDP(c.localPort, c)
+	c.access.Lock()
+	c.readDeadline.set(time.Time{}, nil)
+	c.access.Unlock()
+	return nil
+}
+
+func (c *udpConn) LocalAddr() net.Addr {
+	return &net.UDPAddr{IP: c.localAddr, Port: int(c.localPort)}
+}
+
+func (c *udpConn) RemoteAddr() net.Addr {
+	return &net.UDPAddr{IP: c.remoteAddr, Port: int(c.remotePort)}
+}
+
+func (c *udpConn) SetDeadline(t time.Time) error {
+	c.SetReadDeadline(t) // nolint: errcheck
+	return c.SetWriteDeadline(t)
+}
+
+func (c *udpConn) SetReadDeadline(t time.Time) error {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	timeout := make(chan struct{})
+	c.readTimeout = timeout
+	var once sync.Once
+	c.readDeadline.set(t, func() {
+		once.Do(func() { close(timeout) })
+	})
+	return nil
+}
+
+// SetWriteDeadline implements net.Conn. Writes never block.
+func (c *udpConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
@@ -0,0 +1,199 @@
+// +build !confonly
+
+// Package wireguard is an outbound handler that tunnels connections through a WireGuard peer.
+// TCP and UDP flows are terminated on a userspace network stack, and WireGuard messages are
+// carried over the UDP dialer of the outbound.
+package wireguard
+
+//go:generate errorgen
+
+import (
+	"context"
+	"sync"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/dice"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/dns"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/transport"
+	"v2ray.com/core/transport/internet"
+)
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		h := &Handler{ctx: ctx}
+		if err := core.RequireFeatures(ctx, func(pm policy.Manager, d dns.Client) error {
+			return h.Init(config.(*Config), pm, d)
+		}); err != nil {
+			return nil, err
+		}
+		return h, nil
+	}))
+}
+
+// Handler is an outbound connection handler that sends traffic over WireGuard.
+type Handler struct {
+	ctx           context.Context
+	policyManager policy.Manager
+	dns           dns.Client
+	userLevel     uint32
+	device        *device
+
+	access sync.Mutex
+	dialer internet.Dialer
+}
+
+// Init initializes the Handler with necessary parameters.
+func (h *Handler) Init(config *Config, pm policy.Manager, d dns.Client) error {
+	if h.ctx == nil {
+		h.ctx = context.Background()
+	}
+	h.policyManager = pm
+	h.dns = d
+	h.userLevel = config.UserLevel
+
+	device, err := newDevice(config, h.dial)
+	if err != nil {
+		return newError("failed to create WireGuard device").Base(err)
+	}
+	h.device = device
+	return nil
+}
+
+// dial opens a connection to a peer with the dialer bound by the first request.
+func (h *Handler) dial(dest net.Destination) (net.Conn, error) {
+	h.access.Lock()
+	dialer := h.dialer
+	h.access.Unlock()
+
+	if dialer == nil {
+		return nil, newError("no dialer available yet")
+	}
+	return dialer.Dial(h.ctx, dest)
+}
+
+func (h *Handler) resolveIP(ctx context.Context, domain string) net.Address {
+	var lookupFunc func(string) ([]net.IP, error) = h.dns.LookupIP
+
+	stack := h.device.stack
+	if !stack.hasIPv6() {
+		if lookupIPv4, ok := h.dns.(dns.IPv4Lookup); ok {
+			lookupFunc = lookupIPv4.LookupIPv4
+		}
+	} else if !stack.hasIPv4() {
+		if lookupIPv6, ok := h.dns.(dns.IPv6Lookup); ok {
+			lookupFunc = lookupIPv6.LookupIPv6
+		}
+	}
+
+	ips, err := lookupFunc(domain)
+	if err != nil {
+		newError("failed to get IP address for domain ", domain).Base(err).WriteToLog(session.ExportIDToError(ctx))
+	}
+	var candidates []net.IP
+	for _, ip := range ips {
+		if _, err := stack.localAddress(ip); err == nil {
+			candidates = append(candidates, ip)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return net.IPAddress(candidates[dice.Roll(len(candidates))])
+}
+
+// Process implements proxy.Outbound.
+func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer internet.Dialer) error {
+	outbound := session.OutboundFromContext(ctx)
+	if outbound == nil || !outbound.Target.IsValid() {
+		return newError("target not specified.")
+	}
+
+	// All requests come from the same outbound, so the dialer is bound once, and the connection to the peer
+	// doesn't change with requests.
+	h.access.Lock()
+	if h.dialer == nil {
+		h.dialer = dialer
+	}
+	h.access.Unlock()
+
+	destination := outbound.Target
+	if destination.Address.Family().IsDomain() {
+		ip := h.resolveIP(ctx, destination.Address.Domain())
+		if ip == nil {
+			return newError("failed to resolve domain ", destination.Address.Domain())
+		}
+		destination.Address = ip
+	}
+	newError("tunneling request to ", destination, " via WireGuard").WriteToLog(session.ExportIDToError(ctx))
+
+	var conn net.Conn
+	if destination.Network == net.Network_TCP {
+		c, err := h.device.stack.dialTCP(ctx, destination)
+		if err != nil {
+			return newError("failed to open connection to ", destination).Base(err)
+		}
+		conn = c
+	} else {
+		c, err := h.device.stack.dialUDP(destination)
+		if err != nil {
+			return newError("failed to open connection to ", destination).Base(err)
+		}
+		conn = c
+	}
+	defer conn.Close() // nolint: errcheck
+
+	plcy := h.policyManager.ForLevel(h.userLevel)
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, plcy.Timeouts.ConnectionIdle)
+
+	requestDone := func() error {
+		defer timer.SetTimeout(plcy.Timeouts.DownlinkOnly)
+
+		var writer buf.Writer
+		if destination.Network == net.Network_TCP {
+			writer = buf.NewWriter(conn)
+		} else {
+			writer = &buf.SequentialWriter{Writer: conn}
+		}
+
+		if err := buf.Copy(link.Reader, writer, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to process request").Base(err)
+		}
+
+		return nil
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(plcy.Timeouts.UplinkOnly)
+
+		var reader buf.Reader
+		if destination.Network == net.Network_TCP {
+			reader = buf.NewReader(conn)
+		} else {
+			reader = buf.NewPacketReader(conn)
+		}
+		if err := buf.Copy(reader, link.Writer, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to process response").Base(err)
+		}
+
+		return nil
+	}
+
+	if err := task.Run(ctx, requestDone, task.OnSuccess(responseDone, task.Close(link.Writer))); err != nil {
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
+
+// Close implements common.Closable.
+func (h *Handler) Close() error {
+	return h.device.Close()
+}
@@ -0,0 +1,596 @@
+package wireguard
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	mrand "math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/dns/localdns"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/transport"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/pipe"
+)
+
+func TestReplayFilter(t *testing.T) {
+	var f replayFilter
+	const limit = 1 << 20
+
+	for _, c := range []uint64{0, 1, 5, 3, 2, 4} {
+		if !f.validate(c, limit) {
+			t.Error("counter ", c, " should be accepted")
+		}
+	}
+	for _, c := range []uint64{0, 3, 5} {
+		if f.validate(c, limit) {
+			t.Error("counter ", c, " should be rejected as replay")
+		}
+	}
+	if !f.validate(replayWindowSize+100, limit) {
+		t.Error("counter ahead of window should be accepted")
+	}
+	if f.validate(6, limit) {
+		t.Error("counter behind window should be rejected")
+	}
+	if f.validate(limit, limit) {
+		t.Error("counter over limit should be rejected")
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	keyA, pubA := newTestKey()
+	keyB, pubB := newTestKey()
+	psk := make([]byte, 32)
+	common.Must2(rand.Read(psk))
+
+	a, err := newDevice(&Config{
+		SecretKey: keyA,
+		Address:   [][]byte{{10, 0, 0, 1}},
+		Peer:      []*PeerConfig{{PublicKey: pubB, PreSharedKey: psk}},
+	}, nil)
+	common.Must(err)
+	defer a.Close()
+	b, err := newDevice(&Config{
+		SecretKey: keyB,
+		Address:   [][]byte{{10, 0, 0, 2}},
+		Peer:      []*PeerConfig{{PublicKey: pubA, PreSharedKey: psk}},
+	}, nil)
+	common.Must(err)
+	defer b.Close()
+
+	initiation, err := a.createInitiation(a.peers[0])
+	common.Must(err)
+	if !b.checkMAC1(initiation) {
+		t.Fatal("invalid mac1 in initiation")
+	}
+	peerOfB, err := b.consumeInitiation(initiation)
+	common.Must(err)
+	if peerOfB != b.peers[0] {
+		t.Fatal("unexpected peer")
+	}
+	if _, err := b.consumeInitiation(initiation); err == nil {
+		t.Error("replayed initiation should be rejected")
+	}
+
+	response, err := b.createResponse(peerOfB)
+	common.Must(err)
+	kpB, err := b.beginSymmetricSession(peerOfB)
+	common.Must(err)
+	if !a.checkMAC1(response) {
+		t.Fatal("invalid mac1 in response")
+	}
+	peerOfA, err := a.consumeResponse(response)
+	common.Must(err)
+	kpA, err := a.beginSymmetricSession(peerOfA)
+	common.Must(err)
+
+	payload := []byte("test payload")
+	msg, ok := kpA.seal(payload)
+	if !ok {
+		t.Fatal("failed to seal")
+	}
+	plaintext, err := kpB.open(append([]byte(nil), msg...))
+	common.Must(err)
+	if r := cmp.Diff(plaintext, payload); r != "" {
+		t.Error(r)
+	}
+	if _, err := kpB.open(msg); err == nil {
+		t.Error("replayed message should be rejected")
+	}
+
+	msg, _ = kpB.seal(payload)
+	plaintext, err = kpA.open(msg)
+	common.Must(err)
+	if r := cmp.Diff(plaintext, payload); r != "" {
+		t.Error(r)
+	}
+}
+
+// handshakeVectors are produced by wireguard-go with the same keys, randomness and time.
+var handshakeVectors = struct {
+	initiatorStatic, responderStatic, presharedKey string
+	initiatorEphemeral, responderEphemeral         string
+	initiatorIndex, responderIndex                 string
+	initiation, response                           string
+	initiatorPayload, initiatorTransport           string
+	responderPayload, responderTransport           string
+}{
+	initiatorStatic:    "483ac9dccb949b68b5403dc4e275aa970eb9d806dcbef2ffe4e48871d4ceec6e",
+	responderStatic:    "3889c555c37e6ed93a5a7a6226b02a3a276fa974d6a963b0ccd654b04365d85a",
+	presharedKey:       "a39896c38493c8c642fa09e267c37e63a751248c537c0f8e2c9fcf13f7ac7c6d",
+	initiatorEphemeral: "620be318ccfe9b1685ba2954a24c1b57f2cec0e57e5e2e0ae7c831e91d14309f",
+	responderEphemeral: "4f59b20a2e70ef843c89814a5b15c851882fccafd2ffbde0f825b60d0f90f5ae",
+	initiatorIndex:     "6d5e44ae",
+	responderIndex:     "fa1608b4",
+	initiation: "010000006d5e44aee91cb54a921625d460fb12619c852bb40a21b4e55077827fd19a9bca6607cd5cd273d6512a10a66408" +
+		"48105b9e99296cbad5de0f331737b94b51be805afaadc6bfebc48992d21af6b195a5e8efac2d3eb878a0b7d48edc02d85c8f6ace" +
+		"25224e997f90f3895d5d134158e2f886ac9174d893d2385169d6886f75668a00000000000000000000000000000000",
+	response: "02000000fa1608b46d5e44ae83b5a6ba9c40fbdba070e69b84ff3d88191fba6b8c604849de918e5a5743ef3c8bb53d374cdc" +
+		"dd7a60f193d6fd2d8ac3b3e73a4bbc1de7e074415901ef510fda00000000000000000000000000000000",
+	initiatorPayload: "c949ec54bfe198899169aaab89ddbd251de334c78b7a77b653aa899acdb45f27",
+	initiatorTransport: "04000000fa1608b40000000000000000a9ead023c5320c3329e450153244f87b2ba263b0bfabf3c89d33b2bdb92ff327" +
+		"64f3b4de936bad80b420f4ea4ca1b44f",
+	responderPayload: "284fc94132cda19ec630b53a49d8332b5d00830e8ea43333b0e649d74176038e",
+	responderTransport: "040000006d5e44ae070000000000000048d9b995963f0b45c4cd757ad5819f4f4127cc9fbe3f217d2e8283279ae29dd0" +
+		"41a01a05fe595c969927eb4b62c99674",
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	common.Must(err)
+	return b
+}
+
+func TestHandshakeVectors(t *testing.T) {
+	v := handshakeVectors
+	defer func(r io.Reader, now func() time.Time) {
+		randReader, handshakeNow = r, now
+	}(randReader, handshakeNow)
+	handshakeNow = func() time.Time {
+		return time.Unix(1700000000, 123456789)
+	}
+
+	initiatorKey := privateKey{}
+	copy(initiatorKey[:], mustDecodeHex(v.initiatorStatic))
+	initiatorPub := initiatorKey.publicKey()
+	responderKey := privateKey{}
+	copy(responderKey[:], mustDecodeHex(v.responderStatic))
+	responderPub := responderKey.publicKey()
+	psk := mustDecodeHex(v.presharedKey)
+
+	a, err := newDevice(&Config{
+		SecretKey: initiatorKey[:],
+		Address:   [][]byte{{10, 0, 0, 1}},
+		Peer:      []*PeerConfig{{PublicKey: responderPub[:], PreSharedKey: psk}},
+	}, nil)
+	common.Must(err)
+	defer a.Close()
+	b, err := newDevice(&Config{
+		SecretKey: responderKey[:],
+		Address:   [][]byte{{10, 0, 0, 2}},
+		Peer:      []*PeerConfig{{PublicKey: initiatorPub[:], PreSharedKey: psk}},
+	}, nil)
+	common.Must(err)
+	defer b.Close()
+
+	randReader = bytes.NewReader(append(mustDecodeHex(v.initiatorEphemeral), mustDecodeHex(v.initiatorIndex)...))
+	initiation, err := a.createInitiation(a.peers[0])
+	common.Must(err)
+	if r := cmp.Diff(hex.EncodeToString(initiation), v.initiation); r != "" {
+		t.Error("initiation: ", r)
+	}
+
+	initiation = mustDecodeHex(v.initiation)
+	if !b.checkMAC1(initiation) {
+		t.Fatal("invalid mac1 in initiation")
+	}
+	peerOfB, err := b.consumeInitiation(initiation)
+	common.Must(err)
+	randReader = bytes.NewReader(append(mustDecodeHex(v.responderEphemeral), mustDecodeHex(v.responderIndex)...))
+	response, err := b.createResponse(peerOfB)
+	common.Must(err)
+	if r := cmp.Diff(hex.EncodeToString(response), v.response); r != "" {
+		t.Error("response: ", r)
+	}
+	kpB, err := b.beginSymmetricSession(peerOfB)
+	common.Must(err)
+
+	response = mustDecodeHex(v.response)
+	if !a.checkMAC1(response) {
+		t.Fatal("invalid mac1 in response")
+	}
+	peerOfA, err := a.consumeResponse(response)
+	common.Must(err)
+	kpA, err := a.beginSymmetricSession(peerOfA)
+	common.Must(err)
+
+	msg, _ := kpA.seal(mustDecodeHex(v.initiatorPayload))
+	if r := cmp.Diff(hex.EncodeToString(msg), v.initiatorTransport); r != "" {
+		t.Error("initiator transport: ", r)
+	}
+	plaintext, err := kpB.open(mustDecodeHex(v.initiatorTransport))
+	common.Must(err)
+	if r := cmp.Diff(hex.EncodeToString(plaintext), v.initiatorPayload); r != "" {
+		t.Error(r)
+	}
+
+	kpB.sendNonce = 7
+	msg, _ = kpB.seal(mustDecodeHex(v.responderPayload))
+	if r := cmp.Diff(hex.EncodeToString(msg), v.responderTransport); r != "" {
+		t.Error("responder transport: ", r)
+	}
+	plaintext, err = kpA.open(mustDecodeHex(v.responderTransport))
+	common.Must(err)
+	if r := cmp.Diff(hex.EncodeToString(plaintext), v.responderPayload); r != "" {
+		t.Error(r)
+	}
+}
+
+func newTestKey() ([]byte, []byte) {
+	sk, err := newPrivateKey()
+	common.Must(err)
+	pk := sk.publicKey()
+	return sk[:], pk[:]
+}
+
+// linkConditions are the probabilities of transport messages being lost, duplicated or reordered.
+type linkConditions struct {
+	loss      float64
+	duplicate float64
+	reorder   float64
+}
+
+// impairedLink applies linkConditions to the messages in one direction. A reordered message is held back and
+// delivered after the next one.
+type impairedLink struct {
+	linkConditions
+
+	access sync.Mutex
+	held   []byte
+}
+
+func (l *impairedLink) send(b []byte, deliver func([]byte)) {
+	if b[0] != messageTransportType {
+		deliver(b)
+		return
+	}
+
+	l.access.Lock()
+	defer l.access.Unlock()
+
+	switch {
+	case mrand.Float64() < l.loss:
+		return
+	case l.held == nil && mrand.Float64() < l.reorder:
+		l.held = append([]byte(nil), b...)
+		return
+	}
+	if mrand.Float64() < l.duplicate {
+		// Receiving decrypts in place, so the duplicate gets its own copy.
+		deliver(append([]byte(nil), b...))
+	}
+	deliver(b)
+	if l.held != nil {
+		deliver(l.held)
+		l.held = nil
+	}
+}
+
+type impairedWriter struct {
+	conn peerConn
+	link *impairedLink
+}
+
+func (w *impairedWriter) Write(b []byte) (int, error) {
+	var err error
+	w.link.send(b, func(b []byte) {
+		if _, e := w.conn.Write(b); e != nil {
+			err = e
+		}
+	})
+	return len(b), err
+}
+
+func (w *impairedWriter) Close() error {
+	return nil
+}
+
+type packetConnWriter struct {
+	conn net.PacketConn
+	addr net.Addr
+}
+
+func (w *packetConnWriter) Write(b []byte) (int, error) {
+	return w.conn.WriteTo(b, w.addr)
+}
+
+func (w *packetConnWriter) Close() error {
+	return nil
+}
+
+// testServer is a WireGuard device listening on a local UDP port.
+type testServer struct {
+	*device
+	conn   net.PacketConn
+	config *PeerConfig
+}
+
+// newTestServer creates a device that accepts the returned client config as its only peer. The conditions apply to
+// messages in both directions.
+func newTestServer(t *testing.T, conditions linkConditions) (*testServer, *Config) {
+	serverKey, serverPub := newTestKey()
+	clientKey, clientPub := newTestKey()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.LocalHostIP.IP()})
+	common.Must(err)
+
+	d, err := newDevice(&Config{
+		SecretKey: serverKey,
+		Address:   [][]byte{{10, 0, 0, 1}, net.ParseIP("fd00::1")},
+		Peer: []*PeerConfig{{
+			PublicKey: clientPub,
+			AllowedIps: []*CIDR{
+				{Ip: []byte{10, 0, 0, 2}, Prefix: 32},
+				{Ip: net.ParseIP("fd00::2"), Prefix: 128},
+			},
+		}},
+	}, nil)
+	common.Must(err)
+
+	uplink := &impairedLink{linkConditions: conditions}
+	downlink := &impairedLink{linkConditions: conditions}
+	go func() {
+		b := make([]byte, maxMessageSize)
+		for {
+			n, addr, err := conn.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			writer := &impairedWriter{conn: &packetConnWriter{conn: conn, addr: addr}, link: downlink}
+			uplink.send(b[:n], func(b []byte) {
+				d.receive(b, writer)
+			})
+		}
+	}()
+
+	clientConfig := &Config{
+		SecretKey: clientKey,
+		Address:   [][]byte{{10, 0, 0, 2}, net.ParseIP("fd00::2")},
+		Reserved:  []byte{1, 2, 3},
+		Peer: []*PeerConfig{{
+			PublicKey: serverPub,
+			Address:   net.NewIPOrDomain(net.LocalHostIP),
+			Port:      uint32(conn.LocalAddr().(*net.UDPAddr).Port),
+		}},
+	}
+	return &testServer{device: d, conn: conn}, clientConfig
+}
+
+func (s *testServer) Close() error {
+	s.conn.Close()
+	return s.device.Close()
+}
+
+func dialUDP(dest net.Destination) (net.Conn, error) {
+	return net.DialUDP("udp", nil, &net.UDPAddr{IP: dest.Address.IP(), Port: int(dest.Port)})
+}
+
+func serveTCPEcho(l *tcpListener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			io.Copy(conn, conn) // nolint: errcheck
+		}()
+	}
+}
+
+func testTCPEcho(t *testing.T, client *device, dest net.Destination, size int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := client.stack.dialTCP(ctx, dest)
+	common.Must(err)
+	defer conn.Close()
+
+	payload := make([]byte, size)
+	common.Must2(rand.Read(payload))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		common.Must2(conn.Write(payload))
+	}()
+
+	response := make([]byte, size)
+	common.Must(conn.SetReadDeadline(time.Now().Add(30 * time.Second)))
+	if _, err := io.ReadFull(conn, response); err != nil {
+		t.Fatal("failed to read echo from ", dest, ": ", err)
+	}
+	wg.Wait()
+	if r := cmp.Diff(response, payload); r != "" {
+		t.Error("unexpected response from ", dest)
+	}
+}
+
+func TestDevicePair(t *testing.T) {
+	server, clientConfig := newTestServer(t, linkConditions{})
+	defer server.Close()
+
+	client, err := newDevice(clientConfig, dialUDP)
+	common.Must(err)
+	defer client.Close()
+
+	listener, err := server.stack.listenTCP(80)
+	common.Must(err)
+	defer listener.Close()
+	go serveTCPEcho(listener)
+
+	testTCPEcho(t, client, net.TCPDestination(net.ParseAddress("10.0.0.1"), 80), 1024*1024)
+	testTCPEcho(t, client, net.TCPDestination(net.ParseAddress("fd00::1"), 80), 64*1024)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.stack.dialTCP(ctx, net.TCPDestination(net.ParseAddress("10.0.0.1"), 81)); err == nil {
+		t.Error("expect connection to closed port to fail")
+	}
+
+	udpServer, err := server.stack.listenUDP(net.ParseIP("10.0.0.1"), 53)
+	common.Must(err)
+	defer udpServer.Close()
+	go func() {
+		b := make([]byte, 2048)
+		for {
+			n, addr, err := udpServer.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			udpServer.WriteTo(b[:n], addr) // nolint: errcheck
+		}
+	}()
+
+	udpClient, err := client.stack.dialUDP(net.UDPDestination(net.ParseAddress("10.0.0.1"), 53))
+	common.Must(err)
+	defer udpClient.Close()
+
+	payload := make([]byte, 1024)
+	common.Must2(rand.Read(payload))
+	common.Must2(udpClient.Write(payload))
+	common.Must(udpClient.SetReadDeadline(time.Now().Add(5 * time.Second)))
+	response := make([]byte, 2048)
+	n, err := udpClient.Read(response)
+	common.Must(err)
+	if r := cmp.Diff(response[:n], payload); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := udpClient.Write(make([]byte, 1500)); err == nil {
+		t.Error("expect error on oversized UDP packet")
+	}
+}
+
+func TestDevicePairWithLoss(t *testing.T) {
+	server, clientConfig := newTestServer(t, linkConditions{loss: 0.05})
+	defer server.Close()
+
+	client, err := newDevice(clientConfig, dialUDP)
+	common.Must(err)
+	defer client.Close()
+
+	listener, err := server.stack.listenTCP(80)
+	common.Must(err)
+	defer listener.Close()
+	go serveTCPEcho(listener)
+
+	testTCPEcho(t, client, net.TCPDestination(net.ParseAddress("10.0.0.1"), 80), 256*1024)
+}
+
+func TestDevicePairWithReordering(t *testing.T) {
+	for _, conditions := range []linkConditions{
+		{reorder: 0.1},
+		{duplicate: 0.1},
+		{loss: 0.03, duplicate: 0.03, reorder: 0.05},
+	} {
+		server, clientConfig := newTestServer(t, conditions)
+
+		client, err := newDevice(clientConfig, dialUDP)
+		common.Must(err)
+
+		listener, err := server.stack.listenTCP(80)
+		common.Must(err)
+		go serveTCPEcho(listener)
+
+		testTCPEcho(t, client, net.TCPDestination(net.ParseAddress("10.0.0.1"), 80), 256*1024)
+
+		listener.Close()
+		client.Close()
+		server.Close()
+	}
+}
+
+type testDialer struct {
+	id int
+}
+
+func (testDialer) Dial(ctx context.Context, dest net.Destination) (internet.Connection, error) {
+	return dialUDP(dest)
+}
+
+func (testDialer) Address() net.Address {
+	return nil
+}
+
+func TestHandler(t *testing.T) {
+	server, clientConfig := newTestServer(t, linkConditions{})
+	defer server.Close()
+
+	listener, err := server.stack.listenTCP(80)
+	common.Must(err)
+	defer listener.Close()
+	go serveTCPEcho(listener)
+
+	h := new(Handler)
+	common.Must(h.Init(clientConfig, policy.DefaultManager{}, localdns.New()))
+	defer h.Close()
+
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+		Target: net.TCPDestination(net.ParseAddress("10.0.0.1"), 80),
+	})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.Process(ctx, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter}, testDialer{id: 1})
+	}()
+
+	payload := make([]byte, 32*1024)
+	common.Must2(rand.Read(payload))
+	common.Must(uplinkWriter.WriteMultiBuffer(buf.MergeBytes(nil, payload)))
+
+	var response []byte
+	for len(response) < len(payload) {
+		mb, err := downlinkReader.ReadMultiBufferTimeout(10 * time.Second)
+		if err != nil {
+			t.Fatal("failed to read response: ", err)
+		}
+		for _, b := range mb {
+			response = append(response, b.Bytes()...)
+		}
+		buf.ReleaseMulti(mb)
+	}
+	if r := cmp.Diff(response, payload); r != "" {
+		t.Error("unexpected response")
+	}
+
+	common.Must(uplinkWriter.Close())
+	select {
+	case <-errCh:
+	case <-time.After(10 * time.Second):
+		t.Error("handler does not finish after connection closes")
+	}
+	// The dialer of later requests doesn't replace the one of the first request.
+	uplinkReader, uplinkWriter = pipe.New()
+	_, downlinkWriter = pipe.New()
+	common.Must(uplinkWriter.Close())
+	h.Process(ctx, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter}, testDialer{id: 2}) // nolint: errcheck
+	if h.dialer != (testDialer{id: 1}) {
+		t.Error("dialer is replaced by ", h.dialer)
+	}
+}
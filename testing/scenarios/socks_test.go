@@ -1,6 +1,7 @@
 package scenarios
 
 import (
+	"io"
 	"testing"
 	"time"
 
@@ -11,6 +12,8 @@ import (
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/app/router"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
@@ -369,3 +372,295 @@ func TestSocksConformance(t *testing.T) {
 		}
 	}
 }
+
+func socks5Request(conn net.Conn, cmd byte, dest net.Destination) (net.Destination, error) {
+	if _, err := conn.Write([]byte{5, 1, 0}); err != nil {
+		return net.Destination{}, err
+	}
+	method := make([]byte, 2)
+	if _, err := io.ReadFull(conn, method); err != nil {
+		return net.Destination{}, err
+	}
+	request := []byte{5, cmd, 0, 1}
+	request = append(request, dest.Address.IP().To4()...)
+	request = append(request, byte(dest.Port>>8), byte(dest.Port))
+	if _, err := conn.Write(request); err != nil {
+		return net.Destination{}, err
+	}
+	return readSocks5Reply(conn)
+}
+
+func readSocks5Reply(conn net.Conn) (net.Destination, error) {
+	reply := make([]byte, 10)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return net.Destination{}, err
+	}
+	if reply[1] != 0 {
+		return net.Destination{}, errors.New("socks error: ", reply[1])
+	}
+	return net.TCPDestination(net.IPAddress(reply[4:8]), net.PortFromBytes(reply[8:10])), nil
+}
+
+func TestSocksBind(t *testing.T) {
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+					AuthType:    socks.AuthType_NO_AUTH,
+					BindEnabled: true,
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	conn, err := net.Dial("tcp", net.TCPDestination(net.LocalHostIP, serverPort).NetAddr())
+	common.Must(err)
+	defer conn.Close()
+
+	bindAddr, err := socks5Request(conn, 2, net.TCPDestination(net.LocalHostIP, 0))
+	common.Must(err)
+
+	peer, err := net.Dial("tcp", bindAddr.NetAddr())
+	common.Must(err)
+	defer peer.Close()
+
+	peerAddr, err := readSocks5Reply(conn)
+	common.Must(err)
+	if peerAddr.NetAddr() != peer.LocalAddr().String() {
+		t.Error("unexpected peer address: ", peerAddr, ", want ", peer.LocalAddr())
+	}
+
+	exchange := func(from net.Conn, to net.Conn, payload string) {
+		common.Must2(from.Write([]byte(payload)))
+		response := make([]byte, len(payload))
+		common.Must(to.SetReadDeadline(time.Now().Add(time.Second * 5)))
+		common.Must2(io.ReadFull(to, response))
+		if string(response) != payload {
+			t.Error("unexpected payload: ", string(response), ", want ", payload)
+		}
+	}
+	exchange(peer, conn, "from peer")
+	exchange(conn, peer, "from client")
+}
+
+func TestSocks4Bind(t *testing.T) {
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+					AuthType:    socks.AuthType_NO_AUTH,
+					BindEnabled: true,
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	conn, err := net.Dial("tcp", net.TCPDestination(net.LocalHostIP, serverPort).NetAddr())
+	common.Must(err)
+	defer conn.Close()
+
+	readReply := func() net.Destination {
+		reply := make([]byte, 8)
+		common.Must(conn.SetReadDeadline(time.Now().Add(time.Second * 5)))
+		common.Must2(io.ReadFull(conn, reply))
+		if reply[1] != 90 {
+			t.Fatal("socks4 error: ", reply[1])
+		}
+		return net.TCPDestination(net.IPAddress(reply[4:8]), net.PortFromBytes(reply[2:4]))
+	}
+
+	// BIND, with the expected peer 127.0.0.1 and an empty user ID.
+	common.Must2(conn.Write([]byte{4, 2, 0, 0, 127, 0, 0, 1, 0}))
+	bindAddr := readReply()
+
+	peer, err := net.Dial("tcp", bindAddr.NetAddr())
+	common.Must(err)
+	defer peer.Close()
+
+	if peerAddr := readReply(); peerAddr.NetAddr() != peer.LocalAddr().String() {
+		t.Error("unexpected peer address: ", peerAddr, ", want ", peer.LocalAddr())
+	}
+
+	common.Must2(peer.Write([]byte("from peer")))
+	response := make([]byte, 9)
+	common.Must2(io.ReadFull(conn, response))
+	if string(response) != "from peer" {
+		t.Error("unexpected payload: ", string(response))
+	}
+}
+
+func TestSocksBindRejectedByRouting(t *testing.T) {
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&router.Config{
+				Rule: []*router.RoutingRule{
+					{
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "blocked",
+						},
+						InboundTag: []string{"socks"},
+					},
+				},
+			}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				Tag: "socks",
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+					AuthType:    socks.AuthType_NO_AUTH,
+					BindEnabled: true,
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+			{
+				Tag:           "blocked",
+				ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	conn, err := net.Dial("tcp", net.TCPDestination(net.LocalHostIP, serverPort).NetAddr())
+	common.Must(err)
+	defer conn.Close()
+
+	common.Must(conn.SetReadDeadline(time.Now().Add(time.Second * 5)))
+	if bindAddr, err := socks5Request(conn, 2, net.TCPDestination(net.LocalHostIP, 0)); err == nil {
+		t.Error("BIND is not rejected, listening on ", bindAddr)
+	}
+}
+
+func TestSocksUDPAssociation(t *testing.T) {
+	udpServer := udp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := udpServer.Start()
+	common.Must(err)
+	defer udpServer.Close()
+
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+					AuthType:      socks.AuthType_NO_AUTH,
+					Address:       net.NewIPOrDomain(net.LocalHostIP),
+					UdpEnabled:    true,
+					UdpStrictPort: true,
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	newUDPConn := func() *net.UDPConn {
+		conn, err := net.DialUDP("udp", nil, &net.UDPAddr{
+			IP:   []byte{127, 0, 0, 1},
+			Port: int(serverPort),
+		})
+		common.Must(err)
+		return conn
+	}
+	// roundTrip returns whether the UDP request gets a response through the Socks server.
+	roundTrip := func(conn *net.UDPConn) bool {
+		payload := []byte("socks udp")
+		packet, err := socks.EncodeUDPPacket(&protocol.RequestHeader{
+			Address: dest.Address,
+			Port:    dest.Port,
+		}, payload)
+		common.Must(err)
+		common.Must2(conn.Write(packet.Bytes()))
+		packet.Release()
+
+		response := buf.New()
+		defer response.Release()
+		common.Must(conn.SetReadDeadline(time.Now().Add(time.Second)))
+		if _, err := response.ReadFrom(conn); err != nil {
+			return false
+		}
+		if _, err := socks.DecodeUDPPacket(response); err != nil {
+			t.Error(err)
+			return false
+		}
+		return string(response.Bytes()) == string(xor(payload))
+	}
+
+	udpConn := newUDPConn()
+	defer udpConn.Close()
+	if roundTrip(udpConn) {
+		t.Error("UDP packet is relayed without association")
+	}
+
+	tcpConn, err := net.Dial("tcp", net.TCPDestination(net.LocalHostIP, serverPort).NetAddr())
+	common.Must(err)
+	_, err = socks5Request(tcpConn, 3, net.DestinationFromAddr(udpConn.LocalAddr()))
+	common.Must(err)
+
+	if !roundTrip(udpConn) {
+		t.Error("UDP packet is not relayed with association")
+	}
+
+	otherConn := newUDPConn()
+	defer otherConn.Close()
+	if roundTrip(otherConn) {
+		t.Error("UDP packet from undeclared port is relayed")
+	}
+
+	common.Must(tcpConn.Close())
+	time.Sleep(time.Millisecond * 500)
+	if roundTrip(udpConn) {
+		t.Error("UDP packet is relayed after association closes")
+	}
+}
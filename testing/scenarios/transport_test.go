@@ -29,6 +29,7 @@ import (
 	"v2ray.com/core/transport/internet/headers/wechat"
 	"v2ray.com/core/transport/internet/quic"
 	tcptransport "v2ray.com/core/transport/internet/tcp"
+	"v2ray.com/core/transport/internet/websocket"
 )
 
 func TestHttpConnectionHeader(t *testing.T) {
@@ -383,3 +384,118 @@ func TestVMessQuic(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestWebSocketSharedPort(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	serverPort := tcp.PickPort()
+	paths := []string{"/a", "/b"}
+	userIDs := []*protocol.ID{protocol.NewID(uuid.New()), protocol.NewID(uuid.New())}
+
+	serverConfig := &core.Config{
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+	for i, path := range paths {
+		serverConfig.Inbound = append(serverConfig.Inbound, &core.InboundHandlerConfig{
+			ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+				PortRange: net.SinglePortRange(serverPort),
+				Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				StreamSettings: &internet.StreamConfig{
+					ProtocolName: "websocket",
+					TransportSettings: []*internet.TransportConfig{
+						{
+							ProtocolName: "websocket",
+							Settings:     serial.ToTypedMessage(&websocket.Config{Path: path}),
+						},
+					},
+				},
+			}),
+			ProxySettings: serial.ToTypedMessage(&inbound.Config{
+				User: []*protocol.User{
+					{
+						Account: serial.ToTypedMessage(&vmess.Account{
+							Id: userIDs[i].String(),
+						}),
+					},
+				},
+			}),
+		})
+	}
+
+	configs := []*core.Config{serverConfig}
+	var clientPorts []net.Port
+	for i, path := range paths {
+		clientPort := tcp.PickPort()
+		clientPorts = append(clientPorts, clientPort)
+		configs = append(configs, &core.Config{
+			Inbound: []*core.InboundHandlerConfig{
+				{
+					ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+						PortRange: net.SinglePortRange(clientPort),
+						Listen:    net.NewIPOrDomain(net.LocalHostIP),
+					}),
+					ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+						Address: net.NewIPOrDomain(dest.Address),
+						Port:    uint32(dest.Port),
+						NetworkList: &net.NetworkList{
+							Network: []net.Network{net.Network_TCP},
+						},
+					}),
+				},
+			},
+			Outbound: []*core.OutboundHandlerConfig{
+				{
+					ProxySettings: serial.ToTypedMessage(&outbound.Config{
+						Receiver: []*protocol.ServerEndpoint{
+							{
+								Address: net.NewIPOrDomain(net.LocalHostIP),
+								Port:    uint32(serverPort),
+								User: []*protocol.User{
+									{
+										Account: serial.ToTypedMessage(&vmess.Account{
+											Id: userIDs[i].String(),
+										}),
+									},
+								},
+							},
+						},
+					}),
+					SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+						StreamSettings: &internet.StreamConfig{
+							ProtocolName: "websocket",
+							TransportSettings: []*internet.TransportConfig{
+								{
+									ProtocolName: "websocket",
+									Settings:     serial.ToTypedMessage(&websocket.Config{Path: path}),
+								},
+							},
+						},
+					}),
+				},
+			},
+		})
+	}
+
+	servers, err := InitializeServerConfigs(configs...)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	var errg errgroup.Group
+	for _, port := range clientPorts {
+		for i := 0; i < 4; i++ {
+			errg.Go(testTCPConn(port, 1024*1024, time.Second*20))
+		}
+	}
+	if err := errg.Wait(); err != nil {
+		t.Error(err)
+	}
+}
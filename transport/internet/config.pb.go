@@ -309,12 +309,18 @@ type SocketConfig struct {
 	Tproxy SocketConfig_TProxyMode `protobuf:"varint,3,opt,name=tproxy,proto3,enum=v2ray.core.transport.internet.SocketConfig_TProxyMode" json:"tproxy,omitempty"`
 	// ReceiveOriginalDestAddress is for enabling IP_RECVORIGDSTADDR socket option.
 	// This option is for UDP only.
-	ReceiveOriginalDestAddress bool     `protobuf:"varint,4,opt,name=receive_original_dest_address,json=receiveOriginalDestAddress,proto3" json:"receive_original_dest_address,omitempty"`
-	BindAddress                []byte   `protobuf:"bytes,5,opt,name=bind_address,json=bindAddress,proto3" json:"bind_address,omitempty"`
-	BindPort                   uint32   `protobuf:"varint,6,opt,name=bind_port,json=bindPort,proto3" json:"bind_port,omitempty"`
-	XXX_NoUnkeyedLiteral       struct{} `json:"-"`
-	XXX_unrecognized           []byte   `json:"-"`
-	XXX_sizecache              int32    `json:"-"`
+	ReceiveOriginalDestAddress bool   `protobuf:"varint,4,opt,name=receive_original_dest_address,json=receiveOriginalDestAddress,proto3" json:"receive_original_dest_address,omitempty"`
+	BindAddress                []byte `protobuf:"bytes,5,opt,name=bind_address,json=bindAddress,proto3" json:"bind_address,omitempty"`
+	BindPort                   uint32 `protobuf:"varint,6,opt,name=bind_port,json=bindPort,proto3" json:"bind_port,omitempty"`
+	// AcceptProxyProtocol is for reading PROXY protocol v1 or v2 header from
+	// incoming TCP connections.
+	AcceptProxyProtocol bool `protobuf:"varint,7,opt,name=accept_proxy_protocol,json=acceptProxyProtocol,proto3" json:"accept_proxy_protocol,omitempty"`
+	// HappyEyeballs enables racing TCP connections across all IP addresses of
+	// the destination, as described in RFC 8305.
+	HappyEyeballs        *HappyEyeballsConfig `protobuf:"bytes,8,opt,name=happy_eyeballs,json=happyEyeballs,proto3" json:"happy_eyeballs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *SocketConfig) Reset()         { *m = SocketConfig{} }
@@ -384,6 +390,81 @@ func (m *SocketConfig) GetBindPort() uint32 {
 	return 0
 }
 
+func (m *SocketConfig) GetAcceptProxyProtocol() bool {
+	if m != nil {
+		return m.AcceptProxyProtocol
+	}
+	return false
+}
+
+func (m *SocketConfig) GetHappyEyeballs() *HappyEyeballsConfig {
+	if m != nil {
+		return m.HappyEyeballs
+	}
+	return nil
+}
+
+type HappyEyeballsConfig struct {
+	// Whether to try IPv4 addresses first. IPv6 addresses are tried first by
+	// default.
+	PreferIpv4 bool `protobuf:"varint,1,opt,name=prefer_ipv4,json=preferIpv4,proto3" json:"prefer_ipv4,omitempty"`
+	// Delay in milliseconds before starting the next connection attempt.
+	// 250 if not set.
+	TryDelayMs uint32 `protobuf:"varint,2,opt,name=try_delay_ms,json=tryDelayMs,proto3" json:"try_delay_ms,omitempty"`
+	// Number of addresses of the preferred family to try before trying an
+	// address of the other family. 1 if not set.
+	Interleave           uint32   `protobuf:"varint,3,opt,name=interleave,proto3" json:"interleave,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HappyEyeballsConfig) Reset()         { *m = HappyEyeballsConfig{} }
+func (m *HappyEyeballsConfig) String() string { return proto.CompactTextString(m) }
+func (*HappyEyeballsConfig) ProtoMessage()    {}
+func (*HappyEyeballsConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_91dbc815c3d97a05, []int{4}
+}
+
+func (m *HappyEyeballsConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HappyEyeballsConfig.Unmarshal(m, b)
+}
+func (m *HappyEyeballsConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HappyEyeballsConfig.Marshal(b, m, deterministic)
+}
+func (m *HappyEyeballsConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HappyEyeballsConfig.Merge(m, src)
+}
+func (m *HappyEyeballsConfig) XXX_Size() int {
+	return xxx_messageInfo_HappyEyeballsConfig.Size(m)
+}
+func (m *HappyEyeballsConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_HappyEyeballsConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HappyEyeballsConfig proto.InternalMessageInfo
+
+func (m *HappyEyeballsConfig) GetPreferIpv4() bool {
+	if m != nil {
+		return m.PreferIpv4
+	}
+	return false
+}
+
+func (m *HappyEyeballsConfig) GetTryDelayMs() uint32 {
+	if m != nil {
+		return m.TryDelayMs
+	}
+	return 0
+}
+
+func (m *HappyEyeballsConfig) GetInterleave() uint32 {
+	if m != nil {
+		return m.Interleave
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("v2ray.core.transport.internet.TransportProtocol", TransportProtocol_name, TransportProtocol_value)
 	proto.RegisterEnum("v2ray.core.transport.internet.SocketConfig_TCPFastOpenState", SocketConfig_TCPFastOpenState_name, SocketConfig_TCPFastOpenState_value)
@@ -392,6 +473,7 @@ func init() {
 	proto.RegisterType((*StreamConfig)(nil), "v2ray.core.transport.internet.StreamConfig")
 	proto.RegisterType((*ProxyConfig)(nil), "v2ray.core.transport.internet.ProxyConfig")
 	proto.RegisterType((*SocketConfig)(nil), "v2ray.core.transport.internet.SocketConfig")
+	proto.RegisterType((*HappyEyeballsConfig)(nil), "v2ray.core.transport.internet.HappyEyeballsConfig")
 }
 
 func init() {
@@ -399,45 +481,53 @@ func init() {
 }
 
 var fileDescriptor_91dbc815c3d97a05 = []byte{
-	// 636 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0xdd, 0x6a, 0xdb, 0x4c,
-	0x10, 0x8d, 0x2c, 0xc7, 0x91, 0xc7, 0x8e, 0xa3, 0xec, 0x95, 0xc9, 0x47, 0xf8, 0x12, 0x17, 0x8a,
-	0x69, 0x41, 0x0a, 0x2a, 0xed, 0x55, 0x6f, 0x12, 0xbb, 0xa5, 0xa1, 0x4d, 0x2c, 0x64, 0xb5, 0x85,
-	0x40, 0x11, 0x6b, 0x69, 0x6c, 0x44, 0x2c, 0xad, 0xd9, 0xdd, 0x86, 0xfa, 0x95, 0x0a, 0xbd, 0xeb,
-	0x43, 0xf4, 0xb1, 0xca, 0xae, 0x7e, 0x6a, 0xd2, 0x92, 0x26, 0xf4, 0x6e, 0x34, 0x73, 0xe6, 0xcc,
-	0x9c, 0x39, 0x5a, 0x70, 0x6e, 0x3c, 0x4e, 0xd7, 0x4e, 0xcc, 0x32, 0x37, 0x66, 0x1c, 0x5d, 0xc9,
-	0x69, 0x2e, 0x56, 0x8c, 0x4b, 0x37, 0xcd, 0x25, 0xf2, 0x1c, 0xa5, 0x1b, 0xb3, 0x7c, 0x9e, 0x2e,
-	0x9c, 0x15, 0x67, 0x92, 0x91, 0xc3, 0x0a, 0xcf, 0xd1, 0xa9, 0xb1, 0x4e, 0x85, 0x3d, 0x38, 0xb9,
-	0x45, 0x17, 0xb3, 0x2c, 0x63, 0xb9, 0x2b, 0x90, 0xa7, 0x74, 0xe9, 0xca, 0xf5, 0x0a, 0x93, 0x28,
-	0x43, 0x21, 0xe8, 0x02, 0x0b, 0xc2, 0xc1, 0x0f, 0x03, 0xf6, 0xc2, 0x8a, 0x68, 0xa4, 0x47, 0x91,
-	0x77, 0x60, 0xe9, 0x62, 0xcc, 0x96, 0x7d, 0xe3, 0xc8, 0x18, 0xf6, 0xbc, 0x13, 0xe7, 0xce, 0xb9,
-	0x4e, 0xcd, 0xe0, 0x97, 0x7d, 0x41, 0xcd, 0x40, 0x1e, 0xc1, 0x6e, 0x15, 0x47, 0x39, 0xcd, 0xb0,
-	0x6f, 0x1e, 0x19, 0xc3, 0x76, 0xd0, 0xad, 0x92, 0x97, 0x34, 0x43, 0x72, 0x06, 0x96, 0x40, 0x29,
-	0xd3, 0x7c, 0x21, 0xfa, 0x8d, 0x23, 0x63, 0xd8, 0xf1, 0x1e, 0x6f, 0x8e, 0x2c, 0x74, 0x38, 0x85,
-	0x0e, 0x27, 0x54, 0x3a, 0x2e, 0x0a, 0x19, 0x41, 0xdd, 0x37, 0xf8, 0x6e, 0x42, 0x77, 0x2a, 0x39,
-	0xd2, 0xac, 0xd4, 0xe1, 0xff, 0xbb, 0x8e, 0xb3, 0x46, 0xdf, 0xb8, 0x4b, 0xcb, 0xf6, 0x1f, 0xb4,
-	0x7c, 0x02, 0x52, 0x53, 0x47, 0x1b, 0xaa, 0xcc, 0x61, 0xc7, 0x73, 0xee, 0xbb, 0x40, 0x21, 0x21,
-	0xd8, 0xaf, 0x31, 0xd3, 0x92, 0x48, 0xed, 0x20, 0x30, 0xfe, 0xcc, 0x53, 0xb9, 0x8e, 0x94, 0xa3,
-	0xd5, 0x3d, 0xab, 0xa4, 0xba, 0x0e, 0x99, 0xc2, 0x7e, 0x0d, 0xaa, 0x57, 0x68, 0xea, 0x15, 0xee,
-	0x7b, 0x58, 0xbb, 0x22, 0xa8, 0x27, 0x87, 0xb0, 0x27, 0x58, 0x7c, 0x8d, 0x1b, 0xaa, 0x5a, 0xda,
-	0xab, 0xa7, 0x7f, 0x51, 0x35, 0xd5, 0x5d, 0xa5, 0xa4, 0x5e, 0xc1, 0x51, 0xb1, 0x0e, 0xfe, 0x87,
-	0x8e, 0xcf, 0xd9, 0x97, 0x75, 0x69, 0x9a, 0x0d, 0xa6, 0xa4, 0x0b, 0xed, 0x57, 0x3b, 0x50, 0xe1,
-	0xe0, 0x9b, 0xf2, 0x75, 0x83, 0x81, 0x10, 0x68, 0x66, 0x94, 0x5f, 0x6b, 0xcc, 0x76, 0xa0, 0x63,
-	0x72, 0x09, 0xa6, 0x9c, 0x33, 0xfd, 0xef, 0xf4, 0xbc, 0x97, 0x0f, 0xd8, 0xc7, 0x09, 0x47, 0xfe,
-	0x6b, 0x2a, 0xe4, 0x64, 0x85, 0xf9, 0x54, 0x52, 0x89, 0x81, 0x22, 0x22, 0x97, 0xd0, 0x92, 0x2b,
-	0xb5, 0x96, 0x3e, 0x6f, 0xcf, 0x7b, 0xf1, 0x20, 0x4a, 0x2d, 0xe8, 0x82, 0x25, 0x18, 0x94, 0x2c,
-	0xe4, 0x14, 0x0e, 0x39, 0xc6, 0x98, 0xde, 0x60, 0xc4, 0x78, 0xba, 0x48, 0x73, 0xba, 0x8c, 0x12,
-	0x14, 0x32, 0xa2, 0x49, 0xc2, 0x51, 0x28, 0x73, 0x8c, 0xa1, 0x15, 0x1c, 0x94, 0xa0, 0x49, 0x89,
-	0x19, 0xa3, 0x90, 0xa7, 0x05, 0x82, 0x1c, 0x43, 0x77, 0x96, 0xe6, 0x49, 0xdd, 0xa1, 0xfe, 0xbd,
-	0x6e, 0xd0, 0x51, 0xb9, 0x0a, 0xf2, 0x1f, 0xb4, 0x35, 0x44, 0xed, 0xa6, 0xbd, 0xd9, 0x0d, 0x2c,
-	0x95, 0xf0, 0x19, 0x97, 0x83, 0xe7, 0x60, 0xdf, 0xd6, 0x4a, 0x2c, 0x68, 0x9e, 0x8a, 0x73, 0x61,
-	0x6f, 0x11, 0x80, 0xd6, 0xab, 0x9c, 0xce, 0x96, 0x68, 0x1b, 0xa4, 0x03, 0x3b, 0xe3, 0x54, 0xe8,
-	0x8f, 0xc6, 0xc0, 0x05, 0xf8, 0xa5, 0x87, 0xec, 0x80, 0x39, 0x99, 0xcf, 0x0b, 0x7c, 0x91, 0xb6,
-	0x0d, 0xd2, 0x05, 0x2b, 0xc0, 0x24, 0xe5, 0x18, 0x4b, 0xbb, 0xf1, 0xe4, 0x0a, 0xf6, 0x7f, 0x7b,
-	0x47, 0xaa, 0x2f, 0x1c, 0xf9, 0xf6, 0x96, 0x0a, 0xde, 0x8f, 0x7d, 0xdb, 0x50, 0xa3, 0x2f, 0xde,
-	0x8e, 0x7c, 0xbb, 0x41, 0x76, 0xa1, 0xfd, 0x11, 0x67, 0xc5, 0x05, 0x6d, 0x53, 0x15, 0xde, 0x84,
-	0xa1, 0x6f, 0x37, 0x89, 0x0d, 0xdd, 0x31, 0xcb, 0x68, 0x9a, 0x97, 0xb5, 0xed, 0xb3, 0x09, 0x1c,
-	0xc7, 0x2c, 0xbb, 0xdb, 0x0b, 0xdf, 0xb8, 0xb2, 0xaa, 0xf8, 0x6b, 0xe3, 0xf0, 0x83, 0x17, 0xd0,
-	0xb5, 0x33, 0x52, 0xd8, 0x7a, 0x2d, 0xe7, 0xbc, 0xac, 0xcf, 0x5a, 0xfa, 0xe9, 0x3e, 0xfb, 0x19,
-	0x00, 0x00, 0xff, 0xff, 0xce, 0xe9, 0xc8, 0x20, 0x89, 0x05, 0x00, 0x00,
+	// 757 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xdf, 0x8f, 0xdb, 0x44,
+	0x10, 0xae, 0xe3, 0xdc, 0x9d, 0x6f, 0xe2, 0xa4, 0xbe, 0xad, 0x90, 0xa2, 0xa2, 0xa3, 0x69, 0x90,
+	0x50, 0x04, 0x92, 0x53, 0x99, 0xc2, 0x13, 0x2f, 0x77, 0x49, 0x51, 0x4f, 0x70, 0x77, 0xd6, 0x26,
+	0x80, 0xa8, 0x84, 0xac, 0x8d, 0x3d, 0x49, 0xad, 0xda, 0x5e, 0x6b, 0x77, 0x89, 0xea, 0x7f, 0x89,
+	0x67, 0x1e, 0xf9, 0x03, 0xf8, 0xb3, 0xd0, 0xae, 0x7f, 0x10, 0x95, 0xea, 0xca, 0xa9, 0x6f, 0x9b,
+	0x99, 0x6f, 0xbe, 0xf9, 0xbe, 0x99, 0x89, 0xc1, 0xdf, 0x07, 0x82, 0x55, 0x7e, 0xcc, 0xf3, 0x79,
+	0xcc, 0x05, 0xce, 0x95, 0x60, 0x85, 0x2c, 0xb9, 0x50, 0xf3, 0xb4, 0x50, 0x28, 0x0a, 0x54, 0xf3,
+	0x98, 0x17, 0xdb, 0x74, 0xe7, 0x97, 0x82, 0x2b, 0x4e, 0xce, 0x5b, 0xbc, 0x40, 0xbf, 0xc3, 0xfa,
+	0x2d, 0xf6, 0xf1, 0xb3, 0x77, 0xe8, 0x62, 0x9e, 0xe7, 0xbc, 0x98, 0x4b, 0x14, 0x29, 0xcb, 0xe6,
+	0xaa, 0x2a, 0x31, 0x89, 0x72, 0x94, 0x92, 0xed, 0xb0, 0x26, 0x9c, 0xfe, 0x6d, 0xc1, 0xc3, 0x75,
+	0x4b, 0xb4, 0x30, 0xad, 0xc8, 0x8f, 0xe0, 0x98, 0x64, 0xcc, 0xb3, 0xb1, 0x35, 0xb1, 0x66, 0xa3,
+	0xe0, 0x99, 0x7f, 0x67, 0x5f, 0xbf, 0x63, 0x08, 0x9b, 0x3a, 0xda, 0x31, 0x90, 0xcf, 0x61, 0xd8,
+	0xbe, 0xa3, 0x82, 0xe5, 0x38, 0xb6, 0x27, 0xd6, 0xec, 0x94, 0xba, 0x6d, 0xf0, 0x86, 0xe5, 0x48,
+	0x2e, 0xc1, 0x91, 0xa8, 0x54, 0x5a, 0xec, 0xe4, 0xb8, 0x37, 0xb1, 0x66, 0x83, 0xe0, 0x8b, 0xc3,
+	0x96, 0xb5, 0x0f, 0xbf, 0xf6, 0xe1, 0xaf, 0xb5, 0x8f, 0xeb, 0xda, 0x06, 0xed, 0xea, 0xa6, 0x7f,
+	0xda, 0xe0, 0xae, 0x94, 0x40, 0x96, 0x37, 0x3e, 0xc2, 0x8f, 0xf7, 0x71, 0xd9, 0x1b, 0x5b, 0x77,
+	0x79, 0x39, 0x7a, 0x8f, 0x97, 0xdf, 0x80, 0x74, 0xd4, 0xd1, 0x81, 0x2b, 0x7b, 0x36, 0x08, 0xfc,
+	0xff, 0x2b, 0xa0, 0xb6, 0x40, 0xcf, 0x3a, 0xcc, 0xaa, 0x21, 0xd2, 0x1a, 0x24, 0xc6, 0xbf, 0x8b,
+	0x54, 0x55, 0x91, 0xde, 0x68, 0x3b, 0xcf, 0x36, 0xa8, 0xa7, 0x43, 0x56, 0x70, 0xd6, 0x81, 0x3a,
+	0x09, 0xfd, 0x89, 0x7d, 0x8f, 0xc1, 0x7a, 0x2d, 0x41, 0xd7, 0x79, 0x0d, 0x0f, 0x25, 0x8f, 0xdf,
+	0xe0, 0x81, 0xab, 0x63, 0xb3, 0xab, 0xaf, 0x3e, 0xe0, 0x6a, 0x65, 0xaa, 0x1a, 0x4b, 0xa3, 0x9a,
+	0xa3, 0x65, 0x9d, 0x3e, 0x81, 0x41, 0x28, 0xf8, 0xdb, 0xaa, 0x59, 0x9a, 0x07, 0xb6, 0x62, 0x3b,
+	0xb3, 0xaf, 0x53, 0xaa, 0x9f, 0xd3, 0xbf, 0xfa, 0xe0, 0x1e, 0x32, 0x10, 0x02, 0xfd, 0x9c, 0x89,
+	0x37, 0x06, 0x73, 0x44, 0xcd, 0x9b, 0xdc, 0x80, 0xad, 0xb6, 0xdc, 0xdc, 0xce, 0x28, 0xf8, 0xee,
+	0x1e, 0x7a, 0xfc, 0xf5, 0x22, 0xfc, 0x9e, 0x49, 0x75, 0x5b, 0x62, 0xb1, 0x52, 0x4c, 0x21, 0xd5,
+	0x44, 0xe4, 0x06, 0x8e, 0x55, 0xa9, 0x65, 0x99, 0xf1, 0x8e, 0x82, 0x6f, 0xef, 0x45, 0x69, 0x0c,
+	0x5d, 0xf3, 0x04, 0x69, 0xc3, 0x42, 0x2e, 0xe0, 0x5c, 0x60, 0x8c, 0xe9, 0x1e, 0x23, 0x2e, 0xd2,
+	0x5d, 0x5a, 0xb0, 0x2c, 0x4a, 0x50, 0xaa, 0x88, 0x25, 0x89, 0x40, 0xa9, 0x97, 0x63, 0xcd, 0x1c,
+	0xfa, 0xb8, 0x01, 0xdd, 0x36, 0x98, 0x25, 0x4a, 0x75, 0x51, 0x23, 0xc8, 0x53, 0x70, 0x37, 0x69,
+	0x91, 0x74, 0x15, 0xfa, 0xf6, 0x5c, 0x3a, 0xd0, 0xb1, 0x16, 0xf2, 0x29, 0x9c, 0x1a, 0x88, 0xd6,
+	0x66, 0x76, 0x33, 0xa4, 0x8e, 0x0e, 0x84, 0x5c, 0x28, 0x12, 0xc0, 0x27, 0x2c, 0x8e, 0xb1, 0x54,
+	0x91, 0x91, 0x14, 0x75, 0xff, 0x8d, 0x13, 0xd3, 0xfa, 0x51, 0x9d, 0x34, 0xd2, 0xdb, 0xf3, 0x27,
+	0xbf, 0xc2, 0xe8, 0x35, 0x2b, 0xcb, 0x2a, 0xc2, 0x0a, 0x37, 0x2c, 0xcb, 0xe4, 0xd8, 0x31, 0x1b,
+	0x0f, 0x3e, 0x30, 0x8e, 0x97, 0xba, 0xe8, 0x45, 0x53, 0xd3, 0x2c, 0x7e, 0xf8, 0xfa, 0x30, 0x38,
+	0xfd, 0x06, 0xbc, 0x77, 0x47, 0x4f, 0x1c, 0xe8, 0x5f, 0xc8, 0x2b, 0xe9, 0x3d, 0x20, 0x00, 0xc7,
+	0x2f, 0x0a, 0xb6, 0xc9, 0xd0, 0xb3, 0xc8, 0x00, 0x4e, 0x96, 0xa9, 0x34, 0x3f, 0x7a, 0xd3, 0x39,
+	0xc0, 0xbf, 0xe3, 0x25, 0x27, 0x60, 0xdf, 0x6e, 0xb7, 0x35, 0xbe, 0x0e, 0x7b, 0x16, 0x71, 0xc1,
+	0xa1, 0x98, 0xa4, 0x02, 0x63, 0xe5, 0xf5, 0xa6, 0x6f, 0xe1, 0xd1, 0x7b, 0xd4, 0x90, 0x27, 0x30,
+	0x28, 0x05, 0x6e, 0x51, 0x44, 0x69, 0xb9, 0x7f, 0x6e, 0x6e, 0xc9, 0xa1, 0x50, 0x87, 0xae, 0xca,
+	0xfd, 0x73, 0x32, 0x01, 0x57, 0x89, 0x2a, 0x4a, 0x30, 0x63, 0x55, 0x94, 0xd7, 0x9f, 0xa5, 0x21,
+	0x05, 0x25, 0xaa, 0xa5, 0x0e, 0x5d, 0x4b, 0xf2, 0x19, 0x80, 0x31, 0x9c, 0x21, 0xdb, 0xd7, 0x7f,
+	0xc3, 0x21, 0x3d, 0x88, 0x7c, 0xf9, 0x0a, 0xce, 0xfe, 0xf3, 0x41, 0xd1, 0x8a, 0xd7, 0x8b, 0xd0,
+	0x7b, 0xa0, 0x1f, 0x3f, 0x2d, 0x43, 0xcf, 0xd2, 0xa6, 0xaf, 0x7f, 0x58, 0x84, 0x5e, 0x8f, 0x0c,
+	0xe1, 0xf4, 0x17, 0xdc, 0xd4, 0xa7, 0xe4, 0xd9, 0x3a, 0xf1, 0x72, 0xbd, 0x0e, 0xbd, 0x3e, 0xf1,
+	0xc0, 0x5d, 0xf2, 0x9c, 0xa5, 0x45, 0x93, 0x3b, 0xba, 0xbc, 0x85, 0xa7, 0x31, 0xcf, 0xef, 0xde,
+	0x42, 0x68, 0xbd, 0x72, 0xda, 0xf7, 0x1f, 0xbd, 0xf3, 0x9f, 0x03, 0xca, 0x2a, 0x7f, 0xa1, 0xb1,
+	0x9d, 0x2c, 0xff, 0xaa, 0xc9, 0x6f, 0x8e, 0xcd, 0x39, 0x7c, 0xfd, 0xcf, 0x00, 0xcf, 0x02, 0x9b,
+	0x90, 0x92, 0x06, 0x00, 0x00,
 }
@@ -10,6 +10,18 @@ type Connection interface {
 	net.Conn
 }
 
+// UnwrapConnection returns the innermost connection of conn, following the wrappers that expose the connection
+// they wrap by NetConn(), such as TLS and PROXY protocol connections.
+func UnwrapConnection(conn net.Conn) net.Conn {
+	for {
+		wrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			return conn
+		}
+		conn = wrapper.NetConn()
+	}
+}
+
 type StatCouterConnection struct {
 	Connection
 	Uplink   stats.Counter
@@ -3,11 +3,14 @@ package internet_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/dns"
 	"v2ray.com/core/testing/servers/tcp"
 	. "v2ray.com/core/transport/internet"
 )
@@ -25,3 +28,116 @@ func TestDialWithLocalAddr(t *testing.T) {
 	}
 	conn.Close()
 }
+
+func TestHappyEyeballsSortIPs(t *testing.T) {
+	ips := []net.IP{
+		net.ParseIP("2001:db8::1"),
+		net.ParseIP("2001:db8::2"),
+		net.ParseIP("2001:db8::3"),
+		net.ParseIP("192.0.2.1"),
+		net.ParseIP("192.0.2.2"),
+	}
+
+	testCases := []struct {
+		config   *HappyEyeballsConfig
+		expected []string
+	}{
+		{
+			config:   &HappyEyeballsConfig{},
+			expected: []string{"2001:db8::1", "192.0.2.1", "2001:db8::2", "192.0.2.2", "2001:db8::3"},
+		},
+		{
+			config:   &HappyEyeballsConfig{PreferIpv4: true},
+			expected: []string{"192.0.2.1", "2001:db8::1", "192.0.2.2", "2001:db8::2", "2001:db8::3"},
+		},
+		{
+			config:   &HappyEyeballsConfig{Interleave: 2},
+			expected: []string{"2001:db8::1", "2001:db8::2", "192.0.2.1", "2001:db8::3", "192.0.2.2"},
+		},
+	}
+	for _, tc := range testCases {
+		var sorted []string
+		for _, ip := range tc.config.SortIPs(ips) {
+			sorted = append(sorted, ip.String())
+		}
+		if r := cmp.Diff(sorted, tc.expected); r != "" {
+			t.Error(r)
+		}
+	}
+}
+
+func TestDialHappyEyeballs(t *testing.T) {
+	server := &tcp.Server{}
+	dest, err := server.Start()
+	common.Must(err)
+	defer server.Close()
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+		// 192.0.2.1 is reserved for documentation, and connections to it never succeed.
+		ResolvedIPs: []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("127.0.0.1")},
+	})
+	sockopt := &SocketConfig{
+		HappyEyeballs: &HappyEyeballsConfig{
+			PreferIpv4: true,
+			TryDelayMs: 100,
+		},
+	}
+
+	start := time.Now()
+	conn, err := DialSystem(ctx, net.TCPDestination(net.LocalHostIP, dest.Port), sockopt)
+	common.Must(err)
+	defer conn.Close()
+
+	if r := cmp.Diff(conn.RemoteAddr().String(), "127.0.0.1:"+dest.Port.String()); r != "" {
+		t.Error(r)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Error("happy eyeballs dialing takes too long: ", d)
+	}
+}
+
+type staticDNSClient struct {
+	ips map[string][]net.IP
+}
+
+func (*staticDNSClient) Type() interface{} {
+	return dns.ClientType()
+}
+
+func (*staticDNSClient) Start() error { return nil }
+
+func (*staticDNSClient) Close() error { return nil }
+
+func (c *staticDNSClient) LookupIP(domain string) ([]net.IP, error) {
+	if ips, found := c.ips[domain]; found {
+		return ips, nil
+	}
+	return nil, dns.ErrEmptyResponse
+}
+
+func TestDialHappyEyeballsWithDNSClient(t *testing.T) {
+	server := &tcp.Server{}
+	dest, err := server.Start()
+	common.Must(err)
+	defer server.Close()
+
+	ctx := ContextWithDNSClient(context.Background(), &staticDNSClient{
+		ips: map[string][]net.IP{
+			"v2ray.test": {net.ParseIP("192.0.2.1"), net.ParseIP("127.0.0.1")},
+		},
+	})
+	sockopt := &SocketConfig{
+		HappyEyeballs: &HappyEyeballsConfig{
+			PreferIpv4: true,
+			TryDelayMs: 100,
+		},
+	}
+
+	conn, err := DialSystem(ctx, net.TCPDestination(net.DomainAddress("v2ray.test"), dest.Port), sockopt)
+	common.Must(err)
+	defer conn.Close()
+
+	if r := cmp.Diff(conn.RemoteAddr().String(), "127.0.0.1:"+dest.Port.String()); r != "" {
+		t.Error(r)
+	}
+}
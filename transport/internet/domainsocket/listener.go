@@ -56,7 +56,7 @@ func Listen(ctx context.Context, address net.Address, port net.Port, streamSetti
 	}
 
 	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
-		ln.tlsConfig = config.GetTLSConfig()
+		ln.tlsConfig = config.GetTLSConfig(tls.WithContext(ctx))
 	}
 
 	go ln.run()
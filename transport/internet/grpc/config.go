@@ -0,0 +1,23 @@
+// +build !confonly
+
+package grpc
+
+import (
+	"v2ray.com/core/common"
+	"v2ray.com/core/transport/internet"
+)
+
+const protocolName = "grpc"
+
+func (c *Config) getServiceName() string {
+	if len(c.ServiceName) == 0 {
+		return "GunService"
+	}
+	return c.ServiceName
+}
+
+func init() {
+	common.Must(internet.RegisterProtocolConfigCreator(protocolName, func() interface{} {
+		return new(Config)
+	}))
+}
@@ -0,0 +1,172 @@
+// +build !confonly
+
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	utls "github.com/refraction-networking/utls"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal/done"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/grpc/encoding"
+	"v2ray.com/core/transport/internet/tls"
+)
+
+var (
+	globalDialerMap    map[internet.DialerKey]*grpc.ClientConn
+	globalDialerAccess sync.Mutex
+)
+
+// closeWait is the time to wait for the server to finish the stream after the connection is closed.
+const closeWait = time.Second * 8
+
+// getGrpcClient returns a client connection to the destination. Tunnels of the same destination
+// and stream settings are multiplexed over one HTTP/2 connection.
+func getGrpcClient(dest net.Destination, streamSettings *internet.MemoryStreamConfig) (*grpc.ClientConn, error) {
+	globalDialerAccess.Lock()
+	defer globalDialerAccess.Unlock()
+
+	if globalDialerMap == nil {
+		globalDialerMap = make(map[internet.DialerKey]*grpc.ClientConn)
+	}
+
+	key := internet.NewDialerKey(dest, streamSettings)
+	if client, found := globalDialerMap[key]; found && client.GetState() != connectivity.Shutdown {
+		return client, nil
+	}
+
+	tlsSettings := tls.ConfigFromStreamSettings(streamSettings)
+	var fingerprint *utls.ClientHelloID
+	if tlsSettings != nil {
+		fingerprint = tls.GetFingerprint(tlsSettings.Fingerprint)
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			conn, err := internet.DialSystem(ctx, dest, streamSettings.SocketSettings)
+			if err != nil || fingerprint == nil {
+				return conn, err
+			}
+			// The TLS handshake with a fingerprint is done here, so gRPC runs over it as plain text.
+			uConn, err := tls.FingerprintClient(conn, tlsSettings.GetTLSConfig(tls.WithDestination(dest), tls.WithNextProto("h2")), fingerprint)
+			if err != nil {
+				conn.Close()
+				return nil, err
+			}
+			return uConn, nil
+		}),
+		grpc.WithBackoffMaxDelay(time.Second * 19),
+	}
+	if tlsSettings != nil && fingerprint == nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsSettings.GetTLSConfig(tls.WithDestination(dest)))))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	client, err := grpc.Dial(dest.NetAddr(), opts...)
+	if err != nil {
+		return nil, newError("failed to create gRPC client to ", dest).Base(err)
+	}
+	globalDialerMap[key] = client
+	return client, nil
+}
+
+// Dial dials a new tunnel to the given destination.
+func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (internet.Connection, error) {
+	newError("creating connection to ", dest).WriteToLog(session.ExportIDToError(ctx))
+
+	grpcSettings := streamSettings.ProtocolSettings.(*Config)
+	client, err := getGrpcClient(dest, streamSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stream outlives the dialing context, and is cancelled after the connection is closed.
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream, err := encoding.TunCustomName(streamCtx, client, grpcSettings.getServiceName())
+	if err != nil {
+		cancel()
+		return nil, newError("failed to open gRPC stream to ", dest).Base(err).AtWarning()
+	}
+
+	finished := done.New()
+	reader := &finishReader{
+		reader: encoding.NewHunkReader(stream),
+		done:   finished,
+	}
+	writer := encoding.NewHunkWriter(stream)
+	closer := &streamCloser{
+		writer: writer,
+		done:   finished,
+		cancel: cancel,
+	}
+
+	return net.NewConnection(
+		net.ConnectionOutputMulti(reader),
+		net.ConnectionInputMulti(writer),
+		net.ConnectionOnClose(closer),
+		net.ConnectionRemoteAddr(remoteAddr(dest, stream.Context())),
+	), nil
+}
+
+// remoteAddr returns the address of the destination, or the address that the domain of the destination resolves to.
+func remoteAddr(dest net.Destination, streamCtx context.Context) net.Addr {
+	if dest.Address.Family().IsIP() {
+		return &net.TCPAddr{
+			IP:   dest.Address.IP(),
+			Port: int(dest.Port),
+		}
+	}
+	if p, ok := peer.FromContext(streamCtx); ok && p.Addr != nil {
+		return p.Addr
+	}
+	return &net.TCPAddr{}
+}
+
+// finishReader marks the stream finished when the server ends it.
+type finishReader struct {
+	reader *encoding.HunkReader
+	done   *done.Instance
+}
+
+func (r *finishReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.reader.ReadMultiBuffer()
+	if err != nil {
+		r.done.Close()
+	}
+	return mb, err
+}
+
+type streamCloser struct {
+	writer *encoding.HunkWriter
+	done   *done.Instance
+	cancel context.CancelFunc
+}
+
+// Close half-closes the stream, and releases it once the server finishes, or after closeWait.
+func (c *streamCloser) Close() error {
+	err := c.writer.Close()
+	go func() {
+		select {
+		case <-c.done.Wait():
+		case <-time.After(closeWait):
+		}
+		c.cancel()
+	}()
+	return err
+}
+
+func init() {
+	common.Must(internet.RegisterTransportDialer(protocolName, Dial))
+}
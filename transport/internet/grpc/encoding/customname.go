@@ -0,0 +1,39 @@
+package encoding
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// ServiceDesc returns the description of GunService with the given service name.
+func ServiceDesc(name string) *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: name,
+		HandlerType: (*GunServiceServer)(nil),
+		Methods:     []grpc.MethodDesc{},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    "Tun",
+				Handler:       _GunService_Tun_Handler,
+				ServerStreams: true,
+				ClientStreams: true,
+			},
+		},
+		Metadata: "v2ray.com/core/transport/internet/grpc/encoding/stream.proto",
+	}
+}
+
+// RegisterGunServiceServerX registers GunService with the given service name.
+func RegisterGunServiceServerX(s *grpc.Server, srv GunServiceServer, name string) {
+	s.RegisterService(ServiceDesc(name), srv)
+}
+
+// TunCustomName opens a Tun stream on the GunService of the given name.
+func TunCustomName(ctx context.Context, cc *grpc.ClientConn, name string, opts ...grpc.CallOption) (GunService_TunClient, error) {
+	stream, err := cc.NewStream(ctx, &ServiceDesc(name).Streams[0], "/"+name+"/Tun", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &gunServiceTunClient{stream}, nil
+}
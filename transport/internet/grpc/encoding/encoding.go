@@ -0,0 +1,4 @@
+// Package encoding tunnels connections over a bidirectional streaming gRPC method.
+package encoding
+
+//go:generate errorgen
@@ -0,0 +1,90 @@
+package encoding
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"v2ray.com/core/common/buf"
+)
+
+// HunkStream is a stream of Hunks. Both client and server side of a Tun stream implement it.
+type HunkStream interface {
+	Context() context.Context
+	Send(*Hunk) error
+	Recv() (*Hunk, error)
+}
+
+// HunkReader is a buf.Reader that reads Hunks from a stream.
+type HunkReader struct {
+	stream HunkStream
+}
+
+// NewHunkReader creates a new HunkReader.
+func NewHunkReader(stream HunkStream) *HunkReader {
+	return &HunkReader{stream: stream}
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *HunkReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	hunk, err := r.stream.Recv()
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, newError("failed to receive hunk").Base(err)
+	}
+	return buf.MergeBytes(nil, hunk.Data), nil
+}
+
+// HunkWriter is a buf.Writer that writes each buffer as a Hunk to a stream.
+type HunkWriter struct {
+	access sync.Mutex
+	stream HunkStream
+	closed bool
+}
+
+// NewHunkWriter creates a new HunkWriter.
+func NewHunkWriter(stream HunkStream) *HunkWriter {
+	return &HunkWriter{stream: stream}
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *HunkWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	defer buf.ReleaseMulti(mb)
+
+	w.access.Lock()
+	defer w.access.Unlock()
+
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+
+	for _, b := range mb {
+		if b.IsEmpty() {
+			continue
+		}
+		// Send serializes the message before returning, so the buffer can be released afterwards.
+		if err := w.stream.Send(&Hunk{Data: b.Bytes()}); err != nil {
+			return newError("failed to send hunk").Base(err)
+		}
+	}
+	return nil
+}
+
+// Close implements io.Closer. It half-closes client streams, as the server ends a stream by returning
+// from its handler.
+func (w *HunkWriter) Close() error {
+	w.access.Lock()
+	defer w.access.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if s, ok := w.stream.(interface{ CloseSend() error }); ok {
+		return s.CloseSend()
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package encoding
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Hunk is a piece of data in a tunneled connection.
+type Hunk struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Hunk) Reset()         { *m = Hunk{} }
+func (m *Hunk) String() string { return proto.CompactTextString(m) }
+func (*Hunk) ProtoMessage()    {}
+func (*Hunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4800cf7c443aa090, []int{0}
+}
+
+func (m *Hunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Hunk.Unmarshal(m, b)
+}
+func (m *Hunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Hunk.Marshal(b, m, deterministic)
+}
+func (m *Hunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Hunk.Merge(m, src)
+}
+func (m *Hunk) XXX_Size() int {
+	return xxx_messageInfo_Hunk.Size(m)
+}
+func (m *Hunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_Hunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Hunk proto.InternalMessageInfo
+
+func (m *Hunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Hunk)(nil), "v2ray.core.transport.internet.grpc.encoding.Hunk")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/transport/internet/grpc/encoding/stream.proto", fileDescriptor_4800cf7c443aa090)
+}
+
+var fileDescriptor_4800cf7c443aa090 = []byte{
+	// 203 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0xd0, 0xb1, 0x4b, 0xc5, 0x30,
+	0x10, 0xc7, 0x71, 0xa2, 0x0f, 0x91, 0xe0, 0x94, 0x49, 0xde, 0x24, 0x4e, 0x0f, 0x0a, 0x17, 0xad,
+	0xab, 0x93, 0x22, 0xd5, 0x4d, 0x6a, 0x71, 0x70, 0x8b, 0xe9, 0x51, 0x4a, 0xe9, 0x5d, 0xb9, 0xa6,
+	0x85, 0x0e, 0xfe, 0x43, 0xfe, 0x95, 0x92, 0x62, 0xba, 0x77, 0xfb, 0x0d, 0xf7, 0xf9, 0x0e, 0xa7,
+	0x1f, 0xe7, 0x5c, 0xdc, 0x02, 0x9e, 0x7b, 0xeb, 0x59, 0xd0, 0x06, 0x71, 0x34, 0x0e, 0x2c, 0xc1,
+	0xb6, 0x14, 0x50, 0x08, 0x83, 0x6d, 0x64, 0xf0, 0x16, 0xc9, 0x73, 0xdd, 0x52, 0x63, 0xc7, 0x20,
+	0xe8, 0x7a, 0x18, 0x84, 0x03, 0x9b, 0x2c, 0x69, 0x41, 0xd8, 0x24, 0x24, 0x09, 0x51, 0x42, 0x92,
+	0xb7, 0x47, 0x7d, 0x78, 0x9d, 0xa8, 0x33, 0x46, 0x1f, 0x6a, 0x17, 0xdc, 0xb5, 0xba, 0x51, 0xa7,
+	0xab, 0x72, 0xdd, 0xf9, 0x8f, 0xd6, 0xc5, 0x44, 0x1f, 0x28, 0x73, 0xeb, 0xd1, 0xb0, 0x3e, 0xaf,
+	0x26, 0x32, 0xf7, 0xb0, 0x23, 0x0f, 0xb1, 0x7d, 0xdc, 0x4f, 0x4e, 0xea, 0x4e, 0x3d, 0x75, 0xda,
+	0x7a, 0xee, 0xf7, 0xd8, 0x77, 0xf5, 0x75, 0x99, 0xf6, 0xef, 0x59, 0xf6, 0x99, 0x97, 0x6e, 0x81,
+	0xe7, 0x28, 0xab, 0x4d, 0xbe, 0x25, 0x59, 0x44, 0xf9, 0xf2, 0x7f, 0xfd, 0x7d, 0xb1, 0xfe, 0xee,
+	0xe1, 0x6f, 0x00, 0xd8, 0xc4, 0x5c, 0xc2, 0x7b, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GunServiceClient is the client API for GunService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GunServiceClient interface {
+	Tun(ctx context.Context, opts ...grpc.CallOption) (GunService_TunClient, error)
+}
+
+type gunServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGunServiceClient(cc *grpc.ClientConn) GunServiceClient {
+	return &gunServiceClient{cc}
+}
+
+func (c *gunServiceClient) Tun(ctx context.Context, opts ...grpc.CallOption) (GunService_TunClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GunService_serviceDesc.Streams[0], "/v2ray.core.transport.internet.grpc.encoding.GunService/Tun", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gunServiceTunClient{stream}
+	return x, nil
+}
+
+type GunService_TunClient interface {
+	Send(*Hunk) error
+	Recv() (*Hunk, error)
+	grpc.ClientStream
+}
+
+type gunServiceTunClient struct {
+	grpc.ClientStream
+}
+
+func (x *gunServiceTunClient) Send(m *Hunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gunServiceTunClient) Recv() (*Hunk, error) {
+	m := new(Hunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GunServiceServer is the server API for GunService service.
+type GunServiceServer interface {
+	Tun(GunService_TunServer) error
+}
+
+// UnimplementedGunServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedGunServiceServer struct {
+}
+
+func (*UnimplementedGunServiceServer) Tun(srv GunService_TunServer) error {
+	return status.Errorf(codes.Unimplemented, "method Tun not implemented")
+}
+
+func RegisterGunServiceServer(s *grpc.Server, srv GunServiceServer) {
+	s.RegisterService(&_GunService_serviceDesc, srv)
+}
+
+func _GunService_Tun_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GunServiceServer).Tun(&gunServiceTunServer{stream})
+}
+
+type GunService_TunServer interface {
+	Send(*Hunk) error
+	Recv() (*Hunk, error)
+	grpc.ServerStream
+}
+
+type gunServiceTunServer struct {
+	grpc.ServerStream
+}
+
+func (x *gunServiceTunServer) Send(m *Hunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gunServiceTunServer) Recv() (*Hunk, error) {
+	m := new(Hunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _GunService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v2ray.core.transport.internet.grpc.encoding.GunService",
+	HandlerType: (*GunServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Tun",
+			Handler:       _GunService_Tun_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "v2ray.com/core/transport/internet/grpc/encoding/stream.proto",
+}
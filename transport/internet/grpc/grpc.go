@@ -0,0 +1,4 @@
+// Package grpc implements a transport that tunnels connections over a bidirectional streaming gRPC method.
+package grpc
+
+//go:generate errorgen
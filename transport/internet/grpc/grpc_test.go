@@ -0,0 +1,251 @@
+package grpc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/tls/cert"
+	"v2ray.com/core/testing/servers/tcp"
+	"v2ray.com/core/transport/internet"
+	. "v2ray.com/core/transport/internet/grpc"
+	"v2ray.com/core/transport/internet/tls"
+)
+
+func echo(conn internet.Connection) {
+	go func() {
+		defer conn.Close()
+
+		b := buf.New()
+		defer b.Release()
+		for {
+			b.Clear()
+			if _, err := b.ReadFrom(conn); err != nil {
+				return
+			}
+			if _, err := conn.Write(b.Bytes()); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func testEcho(t *testing.T, conn internet.Connection, size int) {
+	payload := make([]byte, size)
+	common.Must2(rand.Read(payload))
+	go func() {
+		common.Must2(conn.Write(payload))
+	}()
+
+	response := buf.New()
+	defer response.Release()
+	var received []byte
+	for len(received) < size {
+		response.Clear()
+		common.Must2(response.ReadFrom(conn))
+		received = append(received, response.Bytes()...)
+	}
+	if r := cmp.Diff(received, payload); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestGRPCConnection(t *testing.T) {
+	port := tcp.PickPort()
+	listener, err := Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "grpc",
+		ProtocolSettings: &Config{ServiceName: "v2ray.test.Tunnel"},
+	}, echo)
+	common.Must(err)
+	defer listener.Close()
+
+	conn, err := Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+		ProtocolName:     "grpc",
+		ProtocolSettings: &Config{ServiceName: "v2ray.test.Tunnel"},
+	})
+	common.Must(err)
+	defer conn.Close()
+
+	testEcho(t, conn, 1024)
+	testEcho(t, conn, 64*1024)
+}
+
+func TestGRPCConnectionToDomain(t *testing.T) {
+	port := tcp.PickPort()
+	listener, err := Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "grpc",
+		ProtocolSettings: &Config{},
+	}, echo)
+	common.Must(err)
+	defer listener.Close()
+
+	conn, err := Dial(context.Background(), net.TCPDestination(net.DomainAddress("localhost"), port), &internet.MemoryStreamConfig{
+		ProtocolName:     "grpc",
+		ProtocolSettings: &Config{},
+	})
+	common.Must(err)
+	defer conn.Close()
+
+	if r := cmp.Diff(conn.RemoteAddr().String(), net.TCPDestination(net.LocalHostIP, port).NetAddr()); r != "" {
+		t.Error(r)
+	}
+	testEcho(t, conn, 1024)
+}
+
+func TestGRPCConnectionTLS(t *testing.T) {
+	sources := make(chan net.Addr, 4)
+	port := tcp.PickPort()
+	listener, err := Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "grpc",
+		ProtocolSettings: &Config{},
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			Certificate: []*tls.Certificate{tls.ParseCertificate(cert.MustGenerate(nil, cert.CommonName("www.v2ray.com")))},
+		},
+	}, func(conn internet.Connection) {
+		sources <- conn.RemoteAddr()
+		echo(conn)
+	})
+	common.Must(err)
+	defer listener.Close()
+
+	streamSettings := &internet.MemoryStreamConfig{
+		ProtocolName:     "grpc",
+		ProtocolSettings: &Config{},
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			ServerName:    "www.v2ray.com",
+			AllowInsecure: true,
+		},
+	}
+
+	for i := 0; i < 4; i++ {
+		conn, err := Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), streamSettings)
+		common.Must(err)
+		testEcho(t, conn, 10240)
+		common.Must(conn.Close())
+	}
+
+	// Tunnels of the same settings share one HTTP/2 connection.
+	first := <-sources
+	for i := 1; i < 4; i++ {
+		if source := <-sources; source.String() != first.String() {
+			t.Error("tunnel from ", source, ", want ", first)
+		}
+	}
+}
+
+// forwardClientHello forwards connections on a new port to the given port, and sends the first TLS record of each
+// connection to the returned channel.
+func forwardClientHello(t *testing.T, port net.Port) (net.Port, <-chan []byte) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	hellos := make(chan []byte, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		target, err := net.Dial("tcp", net.TCPDestination(net.LocalHostIP, port).NetAddr())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer target.Close()
+
+		header := make([]byte, 5)
+		common.Must2(io.ReadFull(conn, header))
+		record := make([]byte, 5+int(binary.BigEndian.Uint16(header[3:])))
+		copy(record, header)
+		common.Must2(io.ReadFull(conn, record[5:]))
+		hellos <- record
+		common.Must2(target.Write(record))
+
+		go io.Copy(conn, target)
+		io.Copy(target, conn)
+	}()
+	return net.Port(listener.Addr().(*net.TCPAddr).Port), hellos
+}
+
+// hasGREASECipherSuite returns whether the ClientHello in the TLS record offers a GREASE cipher suite, as
+// browsers do and crypto/tls doesn't.
+func hasGREASECipherSuite(record []byte) bool {
+	// Record header, handshake header, version and random.
+	p := 5 + 4 + 2 + 32
+	p += 1 + int(record[p])
+	n := int(binary.BigEndian.Uint16(record[p:]))
+	for i := p + 2; i < p+2+n; i += 2 {
+		if record[i] == record[i+1] && record[i]&0x0f == 0x0a {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGRPCConnectionFingerprint(t *testing.T) {
+	port := tcp.PickPort()
+	listener, err := Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "grpc",
+		ProtocolSettings: &Config{},
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			Certificate: []*tls.Certificate{tls.ParseCertificate(cert.MustGenerate(nil, cert.CommonName("www.v2ray.com")))},
+		},
+	}, echo)
+	common.Must(err)
+	defer listener.Close()
+
+	forwardPort, hellos := forwardClientHello(t, port)
+	conn, err := Dial(context.Background(), net.TCPDestination(net.LocalHostIP, forwardPort), &internet.MemoryStreamConfig{
+		ProtocolName:     "grpc",
+		ProtocolSettings: &Config{},
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			ServerName:    "www.v2ray.com",
+			AllowInsecure: true,
+			Fingerprint:   "chrome",
+		},
+	})
+	common.Must(err)
+	defer conn.Close()
+
+	testEcho(t, conn, 10240)
+	if !hasGREASECipherSuite(<-hellos) {
+		t.Error("ClientHello is not of the chrome fingerprint")
+	}
+}
+
+func TestGRPCServiceNameMismatch(t *testing.T) {
+	port := tcp.PickPort()
+	listener, err := Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "grpc",
+		ProtocolSettings: &Config{ServiceName: "a"},
+	}, echo)
+	common.Must(err)
+	defer listener.Close()
+
+	conn, err := Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+		ProtocolName:     "grpc",
+		ProtocolSettings: &Config{ServiceName: "b"},
+	})
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	common.Must2(conn.Write([]byte("test")))
+	b := buf.New()
+	defer b.Release()
+	if _, err := b.ReadFrom(conn); err == nil {
+		t.Error("expect error for unknown service")
+	}
+}
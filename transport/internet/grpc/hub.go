@@ -0,0 +1,109 @@
+// +build !confonly
+
+package grpc
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	http_proto "v2ray.com/core/common/protocol/http"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal/done"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/grpc/encoding"
+	"v2ray.com/core/transport/internet/tls"
+)
+
+type Listener struct {
+	server   *grpc.Server
+	listener net.Listener
+	handler  internet.ConnHandler
+}
+
+// Tun implements encoding.GunServiceServer.
+func (l *Listener) Tun(stream encoding.GunService_TunServer) error {
+	remoteAddr := l.listener.Addr()
+	if p, ok := peer.FromContext(stream.Context()); ok {
+		remoteAddr = p.Addr
+	}
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		forwardedAddrs := http_proto.ParseXForwardedFor(http.Header{"X-Forwarded-For": md.Get("x-forwarded-for")})
+		if addr, ok := remoteAddr.(*net.TCPAddr); ok && len(forwardedAddrs) > 0 && forwardedAddrs[0].Family().IsIP() {
+			remoteAddr = &net.TCPAddr{
+				IP:   forwardedAddrs[0].IP(),
+				Port: addr.Port,
+			}
+		}
+	}
+
+	finished := done.New()
+	l.handler(net.NewConnection(
+		net.ConnectionOutputMulti(encoding.NewHunkReader(stream)),
+		net.ConnectionInputMulti(encoding.NewHunkWriter(stream)),
+		net.ConnectionOnClose(finished),
+		net.ConnectionLocalAddr(l.listener.Addr()),
+		net.ConnectionRemoteAddr(remoteAddr),
+	))
+
+	// The stream ends when the handler returns.
+	select {
+	case <-finished.Wait():
+	case <-stream.Context().Done():
+	}
+	return nil
+}
+
+// Addr implements net.Listener.Addr().
+func (l *Listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Close implements net.Listener.Close().
+func (l *Listener) Close() error {
+	l.server.Stop()
+	return nil
+}
+
+// Listen creates a gRPC server that accepts tunnels on the given address.
+func Listen(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, handler internet.ConnHandler) (internet.Listener, error) {
+	grpcSettings := streamSettings.ProtocolSettings.(*Config)
+
+	var options []grpc.ServerOption
+	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(config.GetTLSConfig(tls.WithContext(ctx)))))
+	}
+
+	listener, err := internet.ListenSystem(ctx, &net.TCPAddr{
+		IP:   address.IP(),
+		Port: int(port),
+	}, streamSettings.SocketSettings)
+	if err != nil {
+		return nil, newError("failed to listen TCP on ", address, ":", port).Base(err)
+	}
+
+	l := &Listener{
+		server:   grpc.NewServer(options...),
+		listener: listener,
+		handler:  handler,
+	}
+	encoding.RegisterGunServiceServerX(l.server, l, grpcSettings.getServiceName())
+
+	go func() {
+		if err := l.server.Serve(listener); err != nil {
+			newError("failed to serve gRPC").Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		}
+	}()
+
+	return l, nil
+}
+
+func init() {
+	common.Must(internet.RegisterTransportListener(protocolName, Listen))
+}
@@ -0,0 +1,185 @@
+package internet
+
+import (
+	"context"
+	"time"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/dns"
+)
+
+type dnsClientKey struct{}
+
+// ContextWithDNSClient returns a new context with the DNS client, which resolves domains to race connections to.
+func ContextWithDNSClient(ctx context.Context, client dns.Client) context.Context {
+	return context.WithValue(ctx, dnsClientKey{}, client)
+}
+
+// DNSClientFromContext returns the DNS client in the context, or nil if not contained.
+func DNSClientFromContext(ctx context.Context) dns.Client {
+	if client, ok := ctx.Value(dnsClientKey{}).(dns.Client); ok {
+		return client
+	}
+	return nil
+}
+
+// lookupIPs resolves the domain with the DNS client in the context, or with the system resolver if there is none.
+func lookupIPs(ctx context.Context, domain string) ([]net.IP, error) {
+	if client := DNSClientFromContext(ctx); client != nil {
+		return client.LookupIP(domain)
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+	return ips, nil
+}
+
+// GetTryDelay returns the delay between connection attempts.
+func (c *HappyEyeballsConfig) GetTryDelay() time.Duration {
+	if c == nil || c.TryDelayMs == 0 {
+		return 250 * time.Millisecond
+	}
+	return time.Duration(c.TryDelayMs) * time.Millisecond
+}
+
+// SortIPs orders the IPs for connection attempts, alternating between the address families
+// as RFC 8305 section 4 suggests.
+func (c *HappyEyeballsConfig) SortIPs(ips []net.IP) []net.IP {
+	var preferred, other []net.IP
+	for _, ip := range ips {
+		if (ip.To4() != nil) == c.PreferIpv4 {
+			preferred = append(preferred, ip)
+		} else {
+			other = append(other, ip)
+		}
+	}
+
+	interleave := int(c.Interleave)
+	if interleave == 0 {
+		interleave = 1
+	}
+	sorted := make([]net.IP, 0, len(ips))
+	for len(preferred) > 0 || len(other) > 0 {
+		n := interleave
+		if n > len(preferred) {
+			n = len(preferred)
+		}
+		sorted = append(sorted, preferred[:n]...)
+		preferred = preferred[n:]
+		if len(other) > 0 {
+			sorted = append(sorted, other[0])
+			other = other[1:]
+		}
+	}
+	return sorted
+}
+
+// happyEyeballsIPs returns candidate IPs of the destination, or nil if there is nothing to race.
+func happyEyeballsIPs(ctx context.Context, src net.Address, dest net.Destination) []net.IP {
+	var ips []net.IP
+	switch {
+	case dest.Address.Family().IsDomain():
+		var err error
+		ips, err = lookupIPs(ctx, dest.Address.Domain())
+		if err != nil {
+			newError("failed to lookup ", dest.Address).Base(err).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+			return nil
+		}
+	default:
+		// The outbound proxy may have resolved the target domain and picked one of its IPs.
+		outbound := session.OutboundFromContext(ctx)
+		if outbound == nil {
+			return nil
+		}
+		for _, ip := range outbound.ResolvedIPs {
+			if ip.Equal(dest.Address.IP()) {
+				ips = outbound.ResolvedIPs
+				break
+			}
+		}
+	}
+
+	if src != nil && src.Family().IsIP() && src != net.AnyIP {
+		var sameFamily []net.IP
+		for _, ip := range ips {
+			if (ip.To4() != nil) == src.Family().IsIPv4() {
+				sameFamily = append(sameFamily, ip)
+			}
+		}
+		ips = sameFamily
+	}
+
+	if len(ips) < 2 {
+		return nil
+	}
+	return ips
+}
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// raceDial dials the IPs in order, starting the next attempt after the given delay or as soon
+// as the previous attempt fails. The first established connection is returned, and the others
+// are closed.
+func raceDial(ctx context.Context, ips []net.IP, delay time.Duration, dial func(context.Context, net.IP) (net.Conn, error)) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult, len(ips))
+	next := 0
+	pending := 0
+	startNext := func() <-chan time.Time {
+		ip := ips[next]
+		next++
+		pending++
+		go func() {
+			conn, err := dial(ctx, ip)
+			results <- dialResult{conn: conn, err: err}
+		}()
+		if next == len(ips) {
+			return nil
+		}
+		return time.After(delay)
+	}
+	closePending := func() {
+		go func(n int) {
+			for i := 0; i < n; i++ {
+				if r := <-results; r.conn != nil {
+					r.conn.Close() // nolint: errcheck
+				}
+			}
+		}(pending)
+	}
+
+	tryNext := startNext()
+	var lastErr error
+	for {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				closePending()
+				return r.conn, nil
+			}
+			lastErr = r.err
+			if next < len(ips) {
+				tryNext = startNext()
+			} else if pending == 0 {
+				return nil, lastErr
+			}
+		case <-tryNext:
+			tryNext = startNext()
+		case <-ctx.Done():
+			closePending()
+			return nil, ctx.Err()
+		}
+	}
+}
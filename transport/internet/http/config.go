@@ -3,6 +3,11 @@
 package http
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/dice"
 	"v2ray.com/core/transport/internet"
@@ -42,6 +47,39 @@ func (c *Config) getNormalizedPath() string {
 	return c.Path
 }
 
+// getRequestPath returns the path of a new request.
+func (c *Config) getRequestPath() string {
+	path := c.getNormalizedPath()
+	if !c.RandomPath {
+		return path
+	}
+	if path[len(path)-1] != '/' {
+		path += "/"
+	}
+	var b [8]byte
+	common.Must2(rand.Read(b[:]))
+	return path + hex.EncodeToString(b[:])
+}
+
+func (c *Config) getMethod() string {
+	if len(c.Method) == 0 {
+		return "PUT"
+	}
+	return c.Method
+}
+
+func (c *Config) isValidMethod(method string) bool {
+	return len(c.Method) == 0 || strings.EqualFold(c.Method, method)
+}
+
+func (c *Config) getRequestHeader() http.Header {
+	header := make(http.Header)
+	for _, h := range c.Header {
+		header.Add(h.Key, h.Value)
+	}
+	return header
+}
+
 func init() {
 	common.Must(internet.RegisterProtocolConfigCreator(protocolName, func() interface{} {
 		return new(Config)
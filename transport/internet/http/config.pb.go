@@ -17,9 +17,63 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type Header struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Header) Reset()         { *m = Header{} }
+func (m *Header) String() string { return proto.CompactTextString(m) }
+func (*Header) ProtoMessage()    {}
+func (*Header) Descriptor() ([]byte, []int) {
+	return fileDescriptor_18c29e00ea34cfae, []int{0}
+}
+
+func (m *Header) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Header.Unmarshal(m, b)
+}
+func (m *Header) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Header.Marshal(b, m, deterministic)
+}
+func (m *Header) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Header.Merge(m, src)
+}
+func (m *Header) XXX_Size() int {
+	return xxx_messageInfo_Header.Size(m)
+}
+func (m *Header) XXX_DiscardUnknown() {
+	xxx_messageInfo_Header.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Header proto.InternalMessageInfo
+
+func (m *Header) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Header) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
 type Config struct {
-	Host                 []string `protobuf:"bytes,1,rep,name=host,proto3" json:"host,omitempty"`
-	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Host []string `protobuf:"bytes,1,rep,name=host,proto3" json:"host,omitempty"`
+	Path string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// HTTP method of requests. Defaults to PUT. The server accepts any method if empty.
+	Method string `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	// Extra headers of requests.
+	Header []*Header `protobuf:"bytes,4,rep,name=header,proto3" json:"header,omitempty"`
+	// Whether to append a random suffix to the path of each request. The server accepts any path
+	// that starts with the configured path.
+	RandomPath           bool     `protobuf:"varint,5,opt,name=random_path,json=randomPath,proto3" json:"random_path,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -29,7 +83,7 @@ func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_18c29e00ea34cfae, []int{0}
+	return fileDescriptor_18c29e00ea34cfae, []int{1}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -64,7 +118,29 @@ func (m *Config) GetPath() string {
 	return ""
 }
 
+func (m *Config) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *Config) GetHeader() []*Header {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *Config) GetRandomPath() bool {
+	if m != nil {
+		return m.RandomPath
+	}
+	return false
+}
+
 func init() {
+	proto.RegisterType((*Header)(nil), "v2ray.core.transport.internet.http.Header")
 	proto.RegisterType((*Config)(nil), "v2ray.core.transport.internet.http.Config")
 }
 
@@ -73,16 +149,22 @@ func init() {
 }
 
 var fileDescriptor_18c29e00ea34cfae = []byte{
-	// 173 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x32, 0x2e, 0x33, 0x2a, 0x4a,
-	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x2f, 0x29, 0x4a, 0xcc, 0x2b,
-	0x2e, 0xc8, 0x2f, 0x2a, 0xd1, 0xcf, 0xcc, 0x2b, 0x49, 0x2d, 0xca, 0x4b, 0x2d, 0xd1, 0xcf, 0x28,
-	0x29, 0x29, 0xd0, 0x4f, 0xce, 0xcf, 0x4b, 0xcb, 0x4c, 0xd7, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17,
-	0x52, 0x82, 0x69, 0x2a, 0x4a, 0xd5, 0x83, 0x6b, 0xd0, 0x83, 0x69, 0xd0, 0x03, 0x69, 0x50, 0x32,
-	0xe0, 0x62, 0x73, 0x06, 0xeb, 0x11, 0x12, 0xe2, 0x62, 0xc9, 0xc8, 0x2f, 0x2e, 0x91, 0x60, 0x54,
-	0x60, 0xd6, 0xe0, 0x0c, 0x02, 0xb3, 0x41, 0x62, 0x05, 0x89, 0x25, 0x19, 0x12, 0x4c, 0x0a, 0x8c,
-	0x20, 0x31, 0x10, 0xdb, 0x29, 0x94, 0x4b, 0x2d, 0x39, 0x3f, 0x57, 0x8f, 0xb0, 0xd9, 0x01, 0x8c,
-	0x51, 0x2c, 0x20, 0x7a, 0x15, 0x93, 0x52, 0x98, 0x51, 0x50, 0x62, 0xa5, 0x9e, 0x33, 0x48, 0x71,
-	0x08, 0x5c, 0xb1, 0x27, 0x4c, 0xb1, 0x47, 0x49, 0x49, 0x41, 0x12, 0x1b, 0xd8, 0xcd, 0xc6, 0x80,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xdc, 0xf2, 0x95, 0x63, 0xea, 0x00, 0x00, 0x00,
+	// 262 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x90, 0x31, 0x4f, 0xc3, 0x30,
+	0x10, 0x85, 0xe5, 0x26, 0x8d, 0xe8, 0x75, 0x41, 0x16, 0x42, 0xd9, 0x88, 0x32, 0xa0, 0x88, 0xc1,
+	0x41, 0xe9, 0x3f, 0x68, 0x97, 0xb2, 0x55, 0x11, 0x30, 0xb0, 0x20, 0x93, 0x18, 0x5c, 0x41, 0x7c,
+	0x96, 0x7b, 0x54, 0xca, 0x5f, 0x42, 0xe2, 0x3f, 0x22, 0x3b, 0x49, 0x57, 0x3a, 0xf9, 0xdd, 0xd3,
+	0xfb, 0xfc, 0x4e, 0x07, 0xab, 0x63, 0xe5, 0x64, 0x2f, 0x1a, 0xec, 0xca, 0x06, 0x9d, 0x2a, 0xc9,
+	0x49, 0x73, 0xb0, 0xe8, 0xa8, 0xdc, 0x1b, 0x52, 0xce, 0x28, 0x2a, 0x35, 0x91, 0x2d, 0x1b, 0x34,
+	0xef, 0xfb, 0x0f, 0x61, 0x1d, 0x12, 0xf2, 0x7c, 0x82, 0x9c, 0x12, 0x27, 0x40, 0x4c, 0x80, 0xf0,
+	0x40, 0x7e, 0x0f, 0xc9, 0x56, 0xc9, 0x56, 0x39, 0x7e, 0x09, 0xd1, 0xa7, 0xea, 0x53, 0x96, 0xb1,
+	0x62, 0x51, 0x7b, 0xc9, 0xaf, 0x60, 0x7e, 0x94, 0x5f, 0xdf, 0x2a, 0x9d, 0x05, 0x6f, 0x18, 0xf2,
+	0x5f, 0x06, 0xc9, 0x26, 0xd4, 0x70, 0x0e, 0xb1, 0xc6, 0x03, 0xa5, 0x2c, 0x8b, 0x8a, 0x45, 0x1d,
+	0xb4, 0xf7, 0xac, 0x24, 0x3d, 0x32, 0x41, 0xf3, 0x6b, 0x48, 0x3a, 0x45, 0x1a, 0xdb, 0x34, 0x0a,
+	0xee, 0x38, 0xf1, 0x35, 0x24, 0x3a, 0x94, 0xa7, 0x71, 0x16, 0x15, 0xcb, 0xea, 0x4e, 0xfc, 0xbf,
+	0xb1, 0x18, 0xd6, 0xad, 0x47, 0x92, 0xdf, 0xc0, 0xd2, 0x49, 0xd3, 0x62, 0xf7, 0x1a, 0x6a, 0xe7,
+	0x19, 0x2b, 0x2e, 0x6a, 0x18, 0xac, 0x9d, 0x24, 0xbd, 0x7e, 0x82, 0xdb, 0x06, 0xbb, 0x33, 0x7e,
+	0xde, 0xb1, 0x97, 0xd8, 0xbf, 0x3f, 0xb3, 0xfc, 0xb9, 0xaa, 0x65, 0x2f, 0x36, 0x3e, 0xfc, 0x78,
+	0x0a, 0x3f, 0x4c, 0xe1, 0x2d, 0x91, 0x7d, 0x4b, 0xc2, 0x8d, 0x57, 0x7f, 0x03, 0x00, 0xcc, 0xee,
+	0xed, 0xef, 0x9a, 0x01, 0x00, 0x00,
 }
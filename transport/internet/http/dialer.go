@@ -19,82 +19,84 @@ import (
 )
 
 var (
-	globalDialerMap    map[net.Destination]*http.Client
+	globalDialerMap    map[internet.DialerKey]*http.Client
 	globalDailerAccess sync.Mutex
 )
 
-func getHTTPClient(ctx context.Context, dest net.Destination, tlsSettings *tls.Config) (*http.Client, error) {
+func getHTTPClient(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) *http.Client {
 	globalDailerAccess.Lock()
 	defer globalDailerAccess.Unlock()
 
 	if globalDialerMap == nil {
-		globalDialerMap = make(map[net.Destination]*http.Client)
+		globalDialerMap = make(map[internet.DialerKey]*http.Client)
 	}
 
-	if client, found := globalDialerMap[dest]; found {
-		return client, nil
+	key := internet.NewDialerKey(dest, streamSettings)
+	if client, found := globalDialerMap[key]; found {
+		return client
 	}
 
+	tlsSettings := tls.ConfigFromStreamSettings(streamSettings)
 	transport := &http2.Transport{
 		DialTLS: func(network string, addr string, tlsConfig *gotls.Config) (net.Conn, error) {
-			rawHost, rawPort, err := net.SplitHostPort(addr)
+			pconn, err := internet.DialSystem(context.Background(), dest, streamSettings.SocketSettings)
 			if err != nil {
 				return nil, err
 			}
-			if len(rawPort) == 0 {
-				rawPort = "443"
+			if tlsSettings == nil {
+				// h2c with prior knowledge.
+				return pconn, nil
 			}
-			port, err := net.PortFromString(rawPort)
-			if err != nil {
-				return nil, err
-			}
-			address := net.ParseAddress(rawHost)
-
-			pconn, err := internet.DialSystem(context.Background(), net.TCPDestination(address, port), nil)
-			if err != nil {
-				return nil, err
+			if fingerprint := tls.GetFingerprint(tlsSettings.Fingerprint); fingerprint != nil {
+				conn, err := tls.FingerprintClient(pconn, tlsConfig, fingerprint)
+				if err != nil {
+					pconn.Close()
+					return nil, err
+				}
+				return conn, nil
 			}
 			return gotls.Client(pconn, tlsConfig), nil
 		},
-		TLSClientConfig: tlsSettings.GetTLSConfig(tls.WithDestination(dest), tls.WithNextProto("h2")),
+	}
+	if tlsSettings != nil {
+		transport.TLSClientConfig = tlsSettings.GetTLSConfig(tls.WithDestination(dest), tls.WithNextProto("h2"))
+	} else {
+		transport.AllowHTTP = true
 	}
 
 	client := &http.Client{
 		Transport: transport,
 	}
 
-	globalDialerMap[dest] = client
-	return client, nil
+	globalDialerMap[key] = client
+	return client
 }
 
 // Dial dials a new TCP connection to the given destination.
 func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (internet.Connection, error) {
 	httpSettings := streamSettings.ProtocolSettings.(*Config)
-	tlsConfig := tls.ConfigFromStreamSettings(streamSettings)
-	if tlsConfig == nil {
-		return nil, newError("TLS must be enabled for http transport.").AtWarning()
-	}
-	client, err := getHTTPClient(ctx, dest, tlsConfig)
-	if err != nil {
-		return nil, err
+	client := getHTTPClient(ctx, dest, streamSettings)
+	scheme := "https"
+	if tls.ConfigFromStreamSettings(streamSettings) == nil {
+		scheme = "http"
 	}
 
 	opts := pipe.OptionsFromContext(ctx)
 	preader, pwriter := pipe.New(opts...)
 	breader := &buf.BufferedReader{Reader: preader}
 	request := &http.Request{
-		Method: "PUT",
+		Method: httpSettings.getMethod(),
 		Host:   httpSettings.getRandomHost(),
 		Body:   breader,
 		URL: &url.URL{
-			Scheme: "https",
+			Scheme: scheme,
 			Host:   dest.NetAddr(),
-			Path:   httpSettings.getNormalizedPath(),
+			Path:   httpSettings.getRequestPath(),
 		},
 		Proto:      "HTTP/2",
 		ProtoMajor: 2,
 		ProtoMinor: 0,
-		Header:     make(http.Header),
+		Header:     httpSettings.getRequestHeader(),
 	}
 	// Disable any compression method from server.
 	request.Header.Set("Accept-Encoding", "identity")
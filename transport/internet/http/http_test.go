@@ -3,10 +3,15 @@ package http_test
 import (
 	"context"
 	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
@@ -92,3 +97,115 @@ func TestHTTPConnection(t *testing.T) {
 		t.Error(r)
 	}
 }
+
+func TestH2CConnection(t *testing.T) {
+	port := tcp.PickPort()
+	listener, err := Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName: "http",
+		ProtocolSettings: &Config{
+			Path:   "/tunnel",
+			Method: "POST",
+		},
+	}, func(conn internet.Connection) {
+		go func() {
+			defer conn.Close()
+
+			b := buf.New()
+			defer b.Release()
+
+			for {
+				b.Clear()
+				if _, err := b.ReadFrom(conn); err != nil {
+					return
+				}
+				if _, err := conn.Write(b.Bytes()); err != nil {
+					return
+				}
+			}
+		}()
+	})
+	common.Must(err)
+	defer listener.Close()
+
+	time.Sleep(time.Second)
+
+	streamSettings := &internet.MemoryStreamConfig{
+		ProtocolName: "http",
+		ProtocolSettings: &Config{
+			Path:       "/tunnel",
+			Method:     "POST",
+			RandomPath: true,
+			Header: []*Header{
+				{Key: "User-Agent", Value: "v2ray-test"},
+			},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		conn, err := Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), streamSettings)
+		common.Must(err)
+
+		const N = 1024
+		b1 := make([]byte, N)
+		common.Must2(rand.Read(b1))
+		common.Must2(conn.Write(b1))
+
+		b2 := buf.New()
+		common.Must2(b2.ReadFullFrom(conn, N))
+		if r := cmp.Diff(b2.Bytes(), b1); r != "" {
+			t.Error(r)
+		}
+		b2.Release()
+		common.Must(conn.Close())
+	}
+
+	// Requests with a different method are rejected.
+	if _, err := Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+		ProtocolName:     "http",
+		ProtocolSettings: &Config{Path: "/tunnel"},
+	}); err == nil {
+		t.Error("expect error for PUT request")
+	}
+}
+
+func TestRequestShaping(t *testing.T) {
+	requests := make(chan *http.Request, 2)
+	server := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+		w.WriteHeader(200)
+	}), &http2.Server{}))
+	defer server.Close()
+
+	dest, err := net.ParseDestination("tcp:" + server.Listener.Addr().String())
+	common.Must(err)
+	streamSettings := &internet.MemoryStreamConfig{
+		ProtocolName: "http",
+		ProtocolSettings: &Config{
+			Host:       []string{"www.v2ray.com"},
+			Path:       "/tunnel",
+			Method:     "POST",
+			RandomPath: true,
+			Header: []*Header{
+				{Key: "User-Agent", Value: "v2ray-test"},
+			},
+		},
+	}
+
+	var paths []string
+	for i := 0; i < 2; i++ {
+		conn, err := Dial(context.Background(), dest, streamSettings)
+		common.Must(err)
+		common.Must(conn.Close())
+
+		r := <-requests
+		if r.Method != "POST" || r.Host != "www.v2ray.com" || r.Header.Get("User-Agent") != "v2ray-test" {
+			t.Error("unexpected request: ", r.Method, " ", r.Host, " ", r.Header)
+		}
+		if !strings.HasPrefix(r.URL.Path, "/tunnel/") {
+			t.Error("unexpected path: ", r.URL.Path)
+		}
+		paths = append(paths, r.URL.Path)
+	}
+	if paths[0] == paths[1] {
+		t.Error("random path repeats: ", paths[0])
+	}
+}
@@ -65,6 +65,11 @@ func (l *Listener) ServeHTTP(writer http.ResponseWriter, request *http.Request)
 		return
 	}
 
+	if !l.config.isValidMethod(request.Method) {
+		writer.WriteHeader(405)
+		return
+	}
+
 	writer.Header().Set("Cache-Control", "no-store")
 	writer.WriteHeader(200)
 	if f, ok := writer.(http.Flusher); ok {
@@ -118,7 +123,7 @@ func Listen(ctx context.Context, address net.Address, port net.Port, streamSetti
 	} else {
 		server = &http.Server{
 			Addr:              serial.Concat(address, ":", port),
-			TLSConfig:         config.GetTLSConfig(tls.WithNextProto("h2")),
+			TLSConfig:         config.GetTLSConfig(tls.WithNextProto("h2"), tls.WithContext(ctx)),
 			Handler:           listener,
 			ReadHeaderTimeout: time.Second * 4,
 		}
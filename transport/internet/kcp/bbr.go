@@ -0,0 +1,168 @@
+// +build !confonly
+
+package kcp
+
+type bbrMode int
+
+const (
+	bbrStartup bbrMode = iota
+	bbrDrain
+	bbrProbeBandwidth
+	bbrProbeRTT
+)
+
+const (
+	// Window gains, in percent.
+	bbrStartupGain = 289
+	bbrDrainGain   = 100
+	bbrWindowGain  = 200
+
+	// Number of rounds that the max delivery rate is taken from.
+	bbrBandwidthRounds = 10
+	// Milliseconds after which the min RTT has to be measured again.
+	bbrMinRTTExpiry = 10000
+	// Milliseconds to stay with the min window when probing RTT.
+	bbrProbeRTTTime = 200
+	bbrMinWindow    = 16
+)
+
+// Pacing gains of rounds when probing bandwidth, in percent.
+var bbrCycleGains = [...]uint32{125, 75, 100, 100, 100, 100, 100, 100}
+
+// bbrController sizes the window after the bandwidth-delay product of the path, measured from the
+// delivery rate of acks and the min RTT, in the manner of BBR. As mKCP sends in bursts every TTI
+// instead of pacing, pacing gains are applied to the window.
+type bbrController struct {
+	initialWindow uint32
+	maxWindow     uint32
+	tti           uint32
+
+	mode         bbrMode
+	minRTT       uint32
+	minRTTStamp  uint32
+	probeRTTDone uint32
+
+	started        bool
+	roundStart     uint32
+	roundDelivered uint32
+	rounds         int
+	bandwidth      [bbrBandwidthRounds]uint32
+
+	fullBandwidth       uint32
+	fullBandwidthRounds int
+	cycleIndex          int
+}
+
+func newBBRController(config *Config) *bbrController {
+	return &bbrController{
+		initialWindow: config.GetSendingInFlightSize(),
+		maxWindow:     config.GetSendingBufferSize(),
+		tti:           config.GetTTIValue(),
+	}
+}
+
+// maxBandwidth returns the max delivery rate of recent rounds, in segments per second.
+func (c *bbrController) maxBandwidth() uint32 {
+	var bw uint32
+	for _, b := range c.bandwidth {
+		if b > bw {
+			bw = b
+		}
+	}
+	return bw
+}
+
+// roundTime returns the min RTT, but no less than TTI as acks are sent once per TTI.
+func (c *bbrController) roundTime() uint32 {
+	if c.minRTT < c.tti {
+		return c.tti
+	}
+	return c.minRTT
+}
+
+func (c *bbrController) OnAck(current uint32, acked uint32, rtt uint32) {
+	if !c.started {
+		c.started = true
+		c.roundStart = current
+	}
+
+	expired := c.minRTT > 0 && current-c.minRTTStamp > bbrMinRTTExpiry
+	if expired && c.mode == bbrProbeBandwidth {
+		c.mode = bbrProbeRTT
+		c.probeRTTDone = current + bbrProbeRTTTime
+	}
+	if rtt > 0 && (c.minRTT == 0 || rtt <= c.minRTT || expired) {
+		c.minRTT = rtt
+		c.minRTTStamp = current
+	}
+
+	c.roundDelivered += acked
+	elapsed := current - c.roundStart
+	if elapsed < c.roundTime() {
+		return
+	}
+
+	c.bandwidth[c.rounds%bbrBandwidthRounds] = uint32(uint64(c.roundDelivered) * 1000 / uint64(elapsed))
+	c.rounds++
+	c.roundStart = current
+	c.roundDelivered = 0
+	c.onRoundEnd(current)
+}
+
+func (c *bbrController) onRoundEnd(current uint32) {
+	switch c.mode {
+	case bbrStartup:
+		// Leave startup when bandwidth stops growing by 25% for 3 rounds.
+		if bw := c.maxBandwidth(); bw >= c.fullBandwidth*5/4 {
+			c.fullBandwidth = bw
+			c.fullBandwidthRounds = 0
+		} else {
+			c.fullBandwidthRounds++
+			if c.fullBandwidthRounds >= 3 {
+				c.mode = bbrDrain
+			}
+		}
+	case bbrDrain:
+		c.mode = bbrProbeBandwidth
+		c.cycleIndex = 0
+	case bbrProbeBandwidth:
+		c.cycleIndex = (c.cycleIndex + 1) % len(bbrCycleGains)
+	case bbrProbeRTT:
+		// current >= c.probeRTTDone
+		if current-c.probeRTTDone < 0x7FFFFFFF {
+			c.minRTTStamp = current
+			c.mode = bbrProbeBandwidth
+			c.cycleIndex = 0
+		}
+	}
+}
+
+func (*bbrController) OnPacketLoss(lossRate uint32) {}
+
+func (c *bbrController) Window(current uint32) uint32 {
+	bw := c.maxBandwidth()
+	if bw == 0 {
+		return c.initialWindow
+	}
+
+	var gain uint32
+	switch c.mode {
+	case bbrStartup:
+		gain = bbrStartupGain
+	case bbrDrain:
+		gain = bbrDrainGain
+	case bbrProbeBandwidth:
+		gain = bbrWindowGain * bbrCycleGains[c.cycleIndex] / 100
+	case bbrProbeRTT:
+		return bbrMinWindow
+	}
+
+	window := uint64(bw) * uint64(c.roundTime()) / 1000 * uint64(gain) / 100
+	if window < bbrMinWindow {
+		return bbrMinWindow
+	}
+	if window > uint64(c.maxWindow) {
+		return c.maxWindow
+	}
+	return uint32(window)
+}
@@ -76,6 +76,22 @@ func (c *Config) GetPackerHeader() (internet.PacketHeader, error) {
 	return nil, nil
 }
 
+// GetFECEncoder returns a FEC encoder for outgoing packets, or nil if FEC is disabled.
+func (c *Config) GetFECEncoder() (*FECEncoder, error) {
+	if c.Fec == nil {
+		return nil, nil
+	}
+	return NewFECEncoder(int(c.Fec.DataShards), int(c.Fec.ParityShards))
+}
+
+// GetFECDecoder returns a FEC decoder for incoming packets, or nil if FEC is disabled.
+func (c *Config) GetFECDecoder() (*FECDecoder, error) {
+	if c.Fec == nil {
+		return nil, nil
+	}
+	return NewFECDecoder(int(c.Fec.DataShards), int(c.Fec.ParityShards))
+}
+
 func (c *Config) GetSendingInFlightSize() uint32 {
 	size := c.GetUplinkCapacityValue() * 1024 * 1024 / c.GetMTUValue() / (1000 / c.GetTTIValue())
 	if size < 8 {
@@ -18,6 +18,34 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// Algorithm that adjusts the sending window when congestion control is enabled.
+type CongestionControl int32
+
+const (
+	// Shrinks or grows the window by packet loss rate.
+	CongestionControl_LossBased CongestionControl = 0
+	// Probes bottleneck bandwidth and round trip time, like BBR.
+	CongestionControl_BBR CongestionControl = 1
+)
+
+var CongestionControl_name = map[int32]string{
+	0: "LossBased",
+	1: "BBR",
+}
+
+var CongestionControl_value = map[string]int32{
+	"LossBased": 0,
+	"BBR":       1,
+}
+
+func (x CongestionControl) String() string {
+	return proto.EnumName(CongestionControl_name, int32(x))
+}
+
+func (CongestionControl) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_3746d5d763e81577, []int{0}
+}
+
 // Maximum Transmission Unit, in bytes.
 type MTU struct {
 	Value                uint32   `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
@@ -297,25 +325,79 @@ func (m *ConnectionReuse) GetEnable() bool {
 	return false
 }
 
+// Reed-Solomon forward error correction. Each group of data_shards packets is
+// followed by parity_shards parity packets, so that up to parity_shards lost
+// packets in a group can be recovered without retransmission.
+type FEC struct {
+	DataShards           uint32   `protobuf:"varint,1,opt,name=data_shards,json=dataShards,proto3" json:"data_shards,omitempty"`
+	ParityShards         uint32   `protobuf:"varint,2,opt,name=parity_shards,json=parityShards,proto3" json:"parity_shards,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FEC) Reset()         { *m = FEC{} }
+func (m *FEC) String() string { return proto.CompactTextString(m) }
+func (*FEC) ProtoMessage()    {}
+func (*FEC) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3746d5d763e81577, []int{7}
+}
+
+func (m *FEC) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FEC.Unmarshal(m, b)
+}
+func (m *FEC) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FEC.Marshal(b, m, deterministic)
+}
+func (m *FEC) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FEC.Merge(m, src)
+}
+func (m *FEC) XXX_Size() int {
+	return xxx_messageInfo_FEC.Size(m)
+}
+func (m *FEC) XXX_DiscardUnknown() {
+	xxx_messageInfo_FEC.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FEC proto.InternalMessageInfo
+
+func (m *FEC) GetDataShards() uint32 {
+	if m != nil {
+		return m.DataShards
+	}
+	return 0
+}
+
+func (m *FEC) GetParityShards() uint32 {
+	if m != nil {
+		return m.ParityShards
+	}
+	return 0
+}
+
 type Config struct {
-	Mtu                  *MTU                 `protobuf:"bytes,1,opt,name=mtu,proto3" json:"mtu,omitempty"`
-	Tti                  *TTI                 `protobuf:"bytes,2,opt,name=tti,proto3" json:"tti,omitempty"`
-	UplinkCapacity       *UplinkCapacity      `protobuf:"bytes,3,opt,name=uplink_capacity,json=uplinkCapacity,proto3" json:"uplink_capacity,omitempty"`
-	DownlinkCapacity     *DownlinkCapacity    `protobuf:"bytes,4,opt,name=downlink_capacity,json=downlinkCapacity,proto3" json:"downlink_capacity,omitempty"`
-	Congestion           bool                 `protobuf:"varint,5,opt,name=congestion,proto3" json:"congestion,omitempty"`
-	WriteBuffer          *WriteBuffer         `protobuf:"bytes,6,opt,name=write_buffer,json=writeBuffer,proto3" json:"write_buffer,omitempty"`
-	ReadBuffer           *ReadBuffer          `protobuf:"bytes,7,opt,name=read_buffer,json=readBuffer,proto3" json:"read_buffer,omitempty"`
-	HeaderConfig         *serial.TypedMessage `protobuf:"bytes,8,opt,name=header_config,json=headerConfig,proto3" json:"header_config,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Mtu               *MTU                 `protobuf:"bytes,1,opt,name=mtu,proto3" json:"mtu,omitempty"`
+	Tti               *TTI                 `protobuf:"bytes,2,opt,name=tti,proto3" json:"tti,omitempty"`
+	UplinkCapacity    *UplinkCapacity      `protobuf:"bytes,3,opt,name=uplink_capacity,json=uplinkCapacity,proto3" json:"uplink_capacity,omitempty"`
+	DownlinkCapacity  *DownlinkCapacity    `protobuf:"bytes,4,opt,name=downlink_capacity,json=downlinkCapacity,proto3" json:"downlink_capacity,omitempty"`
+	Congestion        bool                 `protobuf:"varint,5,opt,name=congestion,proto3" json:"congestion,omitempty"`
+	WriteBuffer       *WriteBuffer         `protobuf:"bytes,6,opt,name=write_buffer,json=writeBuffer,proto3" json:"write_buffer,omitempty"`
+	ReadBuffer        *ReadBuffer          `protobuf:"bytes,7,opt,name=read_buffer,json=readBuffer,proto3" json:"read_buffer,omitempty"`
+	HeaderConfig      *serial.TypedMessage `protobuf:"bytes,8,opt,name=header_config,json=headerConfig,proto3" json:"header_config,omitempty"`
+	Fec               *FEC                 `protobuf:"bytes,10,opt,name=fec,proto3" json:"fec,omitempty"`
+	CongestionControl CongestionControl    `protobuf:"varint,11,opt,name=congestion_control,json=congestionControl,proto3,enum=v2ray.core.transport.internet.kcp.CongestionControl" json:"congestion_control,omitempty"`
+	// Whether to report RTT, window and retransmits of each connection to stats.
+	Stats                bool     `protobuf:"varint,12,opt,name=stats,proto3" json:"stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3746d5d763e81577, []int{7}
+	return fileDescriptor_3746d5d763e81577, []int{8}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -392,7 +474,29 @@ func (m *Config) GetHeaderConfig() *serial.TypedMessage {
 	return nil
 }
 
+func (m *Config) GetFec() *FEC {
+	if m != nil {
+		return m.Fec
+	}
+	return nil
+}
+
+func (m *Config) GetCongestionControl() CongestionControl {
+	if m != nil {
+		return m.CongestionControl
+	}
+	return CongestionControl_LossBased
+}
+
+func (m *Config) GetStats() bool {
+	if m != nil {
+		return m.Stats
+	}
+	return false
+}
+
 func init() {
+	proto.RegisterEnum("v2ray.core.transport.internet.kcp.CongestionControl", CongestionControl_name, CongestionControl_value)
 	proto.RegisterType((*MTU)(nil), "v2ray.core.transport.internet.kcp.MTU")
 	proto.RegisterType((*TTI)(nil), "v2ray.core.transport.internet.kcp.TTI")
 	proto.RegisterType((*UplinkCapacity)(nil), "v2ray.core.transport.internet.kcp.UplinkCapacity")
@@ -400,6 +504,7 @@ func init() {
 	proto.RegisterType((*WriteBuffer)(nil), "v2ray.core.transport.internet.kcp.WriteBuffer")
 	proto.RegisterType((*ReadBuffer)(nil), "v2ray.core.transport.internet.kcp.ReadBuffer")
 	proto.RegisterType((*ConnectionReuse)(nil), "v2ray.core.transport.internet.kcp.ConnectionReuse")
+	proto.RegisterType((*FEC)(nil), "v2ray.core.transport.internet.kcp.FEC")
 	proto.RegisterType((*Config)(nil), "v2ray.core.transport.internet.kcp.Config")
 }
 
@@ -408,35 +513,42 @@ func init() {
 }
 
 var fileDescriptor_3746d5d763e81577 = []byte{
-	// 471 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x5f, 0x6f, 0xd3, 0x3e,
-	0x14, 0x55, 0xd7, 0xae, 0xbf, 0xfe, 0x6e, 0xf7, 0xa7, 0x44, 0x08, 0x45, 0x20, 0xa1, 0xb5, 0x12,
-	0xd3, 0x78, 0xc0, 0x81, 0xee, 0x85, 0xe7, 0x95, 0x97, 0x32, 0x15, 0x81, 0x95, 0x82, 0xb4, 0x97,
-	0xe2, 0x3a, 0xb7, 0xc5, 0x6a, 0x63, 0x5b, 0x8e, 0xb3, 0xaa, 0x7c, 0x24, 0x3e, 0x0d, 0x1f, 0x09,
-	0xc5, 0x6e, 0xd6, 0xae, 0x68, 0x2c, 0x6f, 0x71, 0xee, 0x39, 0xc7, 0xd6, 0x39, 0xf7, 0x40, 0xff,
-	0xb6, 0x6f, 0xd8, 0x9a, 0x70, 0x95, 0x46, 0x5c, 0x19, 0x8c, 0xac, 0x61, 0x32, 0xd3, 0xca, 0xd8,
-	0x48, 0x48, 0x8b, 0x46, 0xa2, 0x8d, 0x16, 0x5c, 0x47, 0x5c, 0xc9, 0x99, 0x98, 0x13, 0x6d, 0x94,
-	0x55, 0x41, 0xb7, 0xe4, 0x18, 0x24, 0x77, 0x78, 0x52, 0xe2, 0xc9, 0x82, 0xeb, 0xe7, 0x6f, 0xf7,
-	0x64, 0xb9, 0x4a, 0x53, 0x25, 0xa3, 0x0c, 0x8d, 0x60, 0xcb, 0xc8, 0xae, 0x35, 0x26, 0x93, 0x14,
-	0xb3, 0x8c, 0xcd, 0xd1, 0x8b, 0xf6, 0x5e, 0x40, 0x7d, 0x14, 0x8f, 0x83, 0xa7, 0x70, 0x78, 0xcb,
-	0x96, 0x39, 0x86, 0xb5, 0xb3, 0xda, 0xc5, 0x31, 0xf5, 0x87, 0x62, 0x18, 0xc7, 0xc3, 0x07, 0x86,
-	0xe7, 0x70, 0x32, 0xd6, 0x4b, 0x21, 0x17, 0x03, 0xa6, 0x19, 0x17, 0x76, 0xfd, 0x00, 0xee, 0x02,
-	0x3a, 0x1f, 0xd4, 0x4a, 0x56, 0x40, 0x76, 0xa1, 0xfd, 0xcd, 0x08, 0x8b, 0x57, 0xf9, 0x6c, 0x86,
-	0x26, 0x08, 0xa0, 0x91, 0x89, 0x9f, 0x25, 0xc6, 0x7d, 0xf7, 0xce, 0x00, 0x28, 0xb2, 0xe4, 0x1f,
-	0x88, 0xd7, 0x70, 0x3a, 0x50, 0x52, 0x22, 0xb7, 0x42, 0x49, 0x8a, 0x79, 0x86, 0xc1, 0x33, 0x68,
-	0xa2, 0x64, 0xd3, 0xa5, 0x07, 0xb6, 0xe8, 0xe6, 0xd4, 0xfb, 0xdd, 0x80, 0xe6, 0xc0, 0x39, 0x1c,
-	0xbc, 0x87, 0x7a, 0x6a, 0x73, 0x37, 0x6f, 0xf7, 0xcf, 0xc9, 0xa3, 0x4e, 0x93, 0x51, 0x3c, 0xa6,
-	0x05, 0xa5, 0x60, 0x5a, 0x2b, 0xc2, 0x83, 0xca, 0xcc, 0x38, 0x1e, 0xd2, 0x82, 0x12, 0xdc, 0xc0,
-	0x69, 0xee, 0x0c, 0x9c, 0xf0, 0x8d, 0x2f, 0x61, 0xdd, 0xa9, 0xbc, 0xab, 0xa0, 0x72, 0xdf, 0x7a,
-	0x7a, 0x92, 0xdf, 0x8f, 0xe2, 0x3b, 0x3c, 0x49, 0x36, 0xa6, 0x6f, 0xd5, 0x1b, 0x4e, 0xfd, 0xb2,
-	0x82, 0xfa, 0x7e, 0x60, 0xb4, 0x93, 0xec, 0x47, 0xf8, 0x12, 0x80, 0x2b, 0x39, 0xc7, 0xac, 0xf0,
-	0x39, 0x3c, 0x74, 0xc6, 0xee, 0xfc, 0x09, 0xbe, 0xc0, 0xd1, 0xaa, 0x08, 0x73, 0x32, 0x75, 0x59,
-	0x85, 0x4d, 0x77, 0x39, 0xa9, 0x70, 0xf9, 0xce, 0x0e, 0xd0, 0xf6, 0x6a, 0x67, 0x21, 0x3e, 0x41,
-	0xdb, 0x20, 0x4b, 0x4a, 0xc5, 0xff, 0x9c, 0xe2, 0x9b, 0x0a, 0x8a, 0xdb, 0x95, 0xa1, 0x60, 0xb6,
-	0xeb, 0x73, 0x0d, 0xc7, 0x3f, 0x90, 0x25, 0x68, 0x26, 0xbe, 0x67, 0x61, 0xeb, 0xef, 0x10, 0x7d,
-	0x83, 0x88, 0x6f, 0x10, 0x89, 0x8b, 0x06, 0x8d, 0x7c, 0x81, 0xe8, 0x91, 0x27, 0xfb, 0x0d, 0xfa,
-	0xd8, 0x68, 0xfd, 0xdf, 0x81, 0x2b, 0x0a, 0xaf, 0xb8, 0x4a, 0x1f, 0x7f, 0xd2, 0xe7, 0xda, 0x4d,
-	0x7d, 0xc1, 0xf5, 0xaf, 0x83, 0xee, 0xd7, 0x3e, 0x65, 0x6b, 0x32, 0x28, 0xa0, 0xf1, 0x1d, 0x74,
-	0x58, 0x42, 0xaf, 0xb9, 0x9e, 0x36, 0x5d, 0x53, 0x2f, 0xff, 0x04, 0x00, 0x00, 0xff, 0xff, 0x54,
-	0xdd, 0xba, 0xf9, 0x34, 0x04, 0x00, 0x00,
+	// 584 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0x51, 0x6f, 0xd3, 0x30,
+	0x14, 0x85, 0xe9, 0xba, 0x75, 0xdb, 0x4d, 0xbb, 0x75, 0x16, 0x42, 0x11, 0x48, 0xb0, 0x15, 0x31,
+	0x0d, 0x10, 0x09, 0x74, 0x3c, 0xf0, 0xdc, 0xb0, 0x49, 0x63, 0x0c, 0x81, 0xc9, 0x40, 0xda, 0x4b,
+	0xf1, 0x1c, 0x77, 0x8b, 0xda, 0xd8, 0x91, 0xed, 0xac, 0x2a, 0xef, 0xfc, 0x19, 0x7e, 0x25, 0xb2,
+	0xdd, 0xb4, 0x5b, 0xa7, 0xb1, 0xbc, 0xe5, 0xde, 0x9c, 0xf3, 0xc5, 0xf2, 0xb9, 0x37, 0xd0, 0xbd,
+	0xea, 0x4a, 0x32, 0x09, 0xa8, 0xc8, 0x42, 0x2a, 0x24, 0x0b, 0xb5, 0x24, 0x5c, 0xe5, 0x42, 0xea,
+	0x30, 0xe5, 0x9a, 0x49, 0xce, 0x74, 0x38, 0xa4, 0x79, 0x48, 0x05, 0x1f, 0xa4, 0x17, 0x41, 0x2e,
+	0x85, 0x16, 0x68, 0xa7, 0xf4, 0x48, 0x16, 0xcc, 0xf4, 0x41, 0xa9, 0x0f, 0x86, 0x34, 0x7f, 0xfc,
+	0x76, 0x01, 0x4b, 0x45, 0x96, 0x09, 0x1e, 0x2a, 0x26, 0x53, 0x32, 0x0a, 0xf5, 0x24, 0x67, 0x49,
+	0x3f, 0x63, 0x4a, 0x91, 0x0b, 0xe6, 0xa0, 0x9d, 0x27, 0x50, 0x3f, 0x89, 0x4f, 0xd1, 0x43, 0x58,
+	0xb9, 0x22, 0xa3, 0x82, 0xf9, 0xb5, 0xed, 0xda, 0x5e, 0x0b, 0xbb, 0xc2, 0xbc, 0x8c, 0xe3, 0xa3,
+	0x3b, 0x5e, 0xee, 0xc2, 0xc6, 0x69, 0x3e, 0x4a, 0xf9, 0x30, 0x22, 0x39, 0xa1, 0xa9, 0x9e, 0xdc,
+	0xa1, 0xdb, 0x83, 0xf6, 0x47, 0x31, 0xe6, 0x15, 0x94, 0x3b, 0xe0, 0xfd, 0x94, 0xa9, 0x66, 0xbd,
+	0x62, 0x30, 0x60, 0x12, 0x21, 0x58, 0x56, 0xe9, 0xef, 0x52, 0x63, 0x9f, 0x3b, 0xdb, 0x00, 0x98,
+	0x91, 0xe4, 0x3f, 0x8a, 0x97, 0xb0, 0x19, 0x09, 0xce, 0x19, 0xd5, 0xa9, 0xe0, 0x98, 0x15, 0x8a,
+	0xa1, 0x47, 0xd0, 0x60, 0x9c, 0x9c, 0x8f, 0x9c, 0x70, 0x0d, 0x4f, 0xab, 0xce, 0x31, 0xd4, 0x0f,
+	0x0f, 0x22, 0xf4, 0x0c, 0xbc, 0x84, 0x68, 0xd2, 0x57, 0x97, 0x44, 0x26, 0x6a, 0x0a, 0x03, 0xd3,
+	0xfa, 0x6e, 0x3b, 0xe8, 0x39, 0xb4, 0x72, 0x22, 0x53, 0x3d, 0x29, 0x25, 0x4b, 0x56, 0xd2, 0x74,
+	0x4d, 0x27, 0xea, 0xfc, 0x69, 0x40, 0x23, 0xb2, 0x71, 0xa1, 0x0f, 0x50, 0xcf, 0x74, 0x61, 0x41,
+	0x5e, 0x77, 0x37, 0xb8, 0x37, 0xb6, 0xe0, 0x24, 0x3e, 0xc5, 0xc6, 0x62, 0x9c, 0x5a, 0xa7, 0xfe,
+	0x52, 0x65, 0x67, 0x1c, 0x1f, 0x61, 0x63, 0x41, 0x67, 0xb0, 0x59, 0xd8, 0x34, 0xfa, 0x74, 0x7a,
+	0xc9, 0x7e, 0xdd, 0x52, 0xde, 0x55, 0xa0, 0xdc, 0xcc, 0x11, 0x6f, 0x14, 0x37, 0x73, 0xfd, 0x05,
+	0x5b, 0xc9, 0x34, 0xc1, 0x39, 0x7d, 0xd9, 0xd2, 0xf7, 0x2b, 0xd0, 0x17, 0xd3, 0xc7, 0xed, 0x64,
+	0x71, 0x1e, 0x9e, 0x02, 0x50, 0xc1, 0x2f, 0x98, 0x32, 0xa1, 0xf9, 0x2b, 0x36, 0xa5, 0x6b, 0x1d,
+	0xf4, 0x0d, 0x9a, 0x63, 0x33, 0x19, 0xfd, 0x73, 0x1b, 0xbc, 0xdf, 0xb0, 0x1f, 0x0f, 0x2a, 0x7c,
+	0xfc, 0xda, 0x40, 0x61, 0x6f, 0x3c, 0x2f, 0xd0, 0x17, 0xf0, 0x24, 0x23, 0x49, 0x49, 0x5c, 0xb5,
+	0xc4, 0x37, 0x15, 0x88, 0xf3, 0xf9, 0xc3, 0x20, 0x67, 0xcf, 0xe8, 0x18, 0x5a, 0x97, 0x8c, 0x24,
+	0x4c, 0xf6, 0xdd, 0xd2, 0xfa, 0x6b, 0xb7, 0x43, 0x74, 0xeb, 0x18, 0xb8, 0x75, 0x0c, 0x62, 0xb3,
+	0x8e, 0x27, 0x6e, 0x1b, 0x71, 0xd3, 0x99, 0xe7, 0x13, 0x34, 0x60, 0xd4, 0x87, 0xca, 0x73, 0x70,
+	0x78, 0x10, 0x61, 0x63, 0x41, 0x14, 0xd0, 0xfc, 0xde, 0xcc, 0x51, 0xb4, 0x14, 0x23, 0xdf, 0xdb,
+	0xae, 0xed, 0x6d, 0x74, 0xdf, 0x57, 0x00, 0x45, 0x33, 0x73, 0xe4, 0xbc, 0x78, 0x8b, 0x2e, 0xb6,
+	0xcc, 0xfa, 0x2a, 0x4d, 0xb4, 0xf2, 0x9b, 0x36, 0x29, 0x57, 0x7c, 0x5a, 0x5e, 0x5b, 0x6f, 0xc3,
+	0xab, 0xd7, 0xb0, 0x75, 0x8b, 0x81, 0x5a, 0xb0, 0xfe, 0x59, 0x28, 0xd5, 0x23, 0x8a, 0x25, 0xed,
+	0x07, 0x68, 0x15, 0xea, 0xbd, 0x1e, 0x6e, 0xd7, 0x7a, 0x18, 0x5e, 0x50, 0x91, 0xdd, 0x7f, 0xac,
+	0xaf, 0xb5, 0xb3, 0xfa, 0x90, 0xe6, 0x7f, 0x97, 0x76, 0x7e, 0x74, 0x31, 0x99, 0x04, 0x91, 0x91,
+	0xc6, 0x33, 0xe9, 0x51, 0x29, 0x3d, 0xa6, 0xf9, 0x79, 0xc3, 0xfe, 0xd8, 0xf6, 0xff, 0x0d, 0x00,
+	0x84, 0xa5, 0x52, 0xca, 0x63, 0x05, 0x00, 0x00,
 }
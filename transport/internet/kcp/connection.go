@@ -14,6 +14,7 @@ import (
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/common/signal/semaphore"
+	"v2ray.com/core/features/stats"
 )
 
 var (
@@ -173,6 +174,8 @@ type ConnMetadata struct {
 	LocalAddr    net.Addr
 	RemoteAddr   net.Addr
 	Conversation uint16
+	// StatsManager, if not nil, receives metrics of the connection.
+	StatsManager stats.Manager
 }
 
 // Connection is a KCP connection over UDP.
@@ -198,6 +201,7 @@ type Connection struct {
 	sendingWorker   *SendingWorker
 
 	output SegmentWriter
+	stats  *connectionStats
 
 	dataUpdater *Updater
 	pingUpdater *Updater
@@ -224,6 +228,9 @@ func NewConnection(meta ConnMetadata, writer PacketWriter, closer io.Closer, con
 
 	conn.receivingWorker = NewReceivingWorker(conn)
 	conn.sendingWorker = NewSendingWorker(conn)
+	if meta.StatsManager != nil {
+		conn.stats = newConnectionStats(meta.StatsManager, meta)
+	}
 
 	isTerminating := func() bool {
 		return conn.State().Is(StateTerminating, StateTerminated)
@@ -539,6 +546,9 @@ func (c *Connection) Terminate() {
 	c.closer.Close()
 	c.sendingWorker.Release()
 	c.receivingWorker.Release()
+	if c.stats != nil {
+		c.stats.Close()
+	}
 }
 
 func (c *Connection) HandleOption(opt SegmentOption) {
@@ -638,6 +648,10 @@ func (c *Connection) flush() {
 	c.receivingWorker.Flush(current)
 	c.sendingWorker.Flush(current)
 
+	if c.stats != nil {
+		c.stats.Update(c.Metrics())
+	}
+
 	if current-atomic.LoadUint32(&c.lastPingTime) >= 3000 {
 		c.Ping(current, CommandPing)
 	}
@@ -67,13 +67,23 @@ func DialKCP(ctx context.Context, dest net.Destination, streamSettings *internet
 	if err != nil {
 		return nil, newError("failed to create security").Base(err)
 	}
+	fecEncoder, err := kcpSettings.GetFECEncoder()
+	if err != nil {
+		return nil, newError("failed to create FEC encoder").Base(err)
+	}
+	fecDecoder, err := kcpSettings.GetFECDecoder()
+	if err != nil {
+		return nil, newError("failed to create FEC decoder").Base(err)
+	}
 	reader := &KCPPacketReader{
 		Header:   header,
 		Security: security,
+		FEC:      fecDecoder,
 	}
 	writer := &KCPPacketWriter{
 		Header:   header,
 		Security: security,
+		FEC:      fecEncoder,
 		Writer:   rawConn,
 	}
 
@@ -82,6 +92,7 @@ func DialKCP(ctx context.Context, dest net.Destination, streamSettings *internet
 		LocalAddr:    rawConn.LocalAddr(),
 		RemoteAddr:   rawConn.RemoteAddr(),
 		Conversation: conv,
+		StatsManager: getStatsManager(ctx, kcpSettings),
 	}, writer, rawConn, kcpSettings)
 
 	go fetchInput(ctx, rawConn, reader, session)
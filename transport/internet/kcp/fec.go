@@ -0,0 +1,272 @@
+// +build !confonly
+
+package kcp
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+const (
+	fecHeaderSize = 6
+	fecSizeSize   = 2
+	// FECOverhead is the number of bytes that FEC adds to each data packet.
+	FECOverhead = fecHeaderSize + fecSizeSize
+
+	fecTypeData   = 0xf1
+	fecTypeParity = 0xf2
+
+	// Number of FEC groups that a decoder keeps for recovery.
+	fecMaxGroups = 64
+)
+
+// FECEncoder adds Reed-Solomon parity packets to an outgoing packet stream.
+//
+// Each packet is prefixed with a 4-byte sequence number and a 2-byte type. Data packets carry
+// their payload prefixed by its 2-byte length, and are sent out as is. After every DataShards
+// data packets, ParityShards parity packets are sent, computed over the length-prefixed payloads
+// zero-padded to the longest one in the group.
+type FECEncoder struct {
+	sync.Mutex
+	rs      *reedSolomon
+	limit   uint32
+	next    uint32
+	count   int
+	maxSize int
+	shards  [][]byte
+	frame   []byte
+}
+
+// NewFECEncoder creates a new FECEncoder with the given number of data and parity shards.
+func NewFECEncoder(dataShards, parityShards int) (*FECEncoder, error) {
+	rs, err := newReedSolomon(dataShards, parityShards)
+	if err != nil {
+		return nil, err
+	}
+	total := uint32(dataShards + parityShards)
+	return &FECEncoder{
+		rs:     rs,
+		limit:  0xffffffff / total * total,
+		shards: make([][]byte, total),
+	}, nil
+}
+
+// Encode passes the FEC packet of b, and parity packets if b completes a group, to output.
+func (e *FECEncoder) Encode(b []byte, output func([]byte) error) error {
+	e.Lock()
+	defer e.Unlock()
+
+	shard := append(e.shards[e.count][:0], 0, 0)
+	binary.BigEndian.PutUint16(shard, uint16(len(b)+fecSizeSize))
+	shard = append(shard, b...)
+	e.shards[e.count] = shard
+	if len(shard) > e.maxSize {
+		e.maxSize = len(shard)
+	}
+
+	if err := output(e.seal(fecTypeData, shard)); err != nil {
+		return err
+	}
+
+	e.count++
+	if e.count < e.rs.dataShards {
+		return nil
+	}
+
+	for i, s := range e.shards {
+		if i < e.rs.dataShards {
+			for len(s) < e.maxSize {
+				s = append(s, 0)
+			}
+		} else if cap(s) >= e.maxSize {
+			s = s[:e.maxSize]
+		} else {
+			s = make([]byte, e.maxSize)
+		}
+		e.shards[i] = s
+	}
+	e.rs.Encode(e.shards)
+
+	var err error
+	for _, s := range e.shards[e.rs.dataShards:] {
+		if err = output(e.seal(fecTypeParity, s)); err != nil {
+			break
+		}
+	}
+	if e.next == e.limit {
+		e.next = 0
+	}
+	e.count = 0
+	e.maxSize = 0
+	return err
+}
+
+func (e *FECEncoder) seal(typ uint16, shard []byte) []byte {
+	frame := append(e.frame[:0], 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(frame, e.next)
+	binary.BigEndian.PutUint16(frame[4:], typ)
+	frame = append(frame, shard...)
+	e.frame = frame
+	e.next++
+	return frame
+}
+
+type fecGroup struct {
+	shards [][]byte
+	count  int
+	done   bool
+}
+
+// FECDecoder recovers lost data packets from packets produced by a FECEncoder.
+// It is not safe for concurrent use.
+type FECDecoder struct {
+	rs     *reedSolomon
+	groups map[uint32]*fecGroup
+	order  []uint32
+}
+
+// NewFECDecoder creates a new FECDecoder with the given number of data and parity shards.
+func NewFECDecoder(dataShards, parityShards int) (*FECDecoder, error) {
+	rs, err := newReedSolomon(dataShards, parityShards)
+	if err != nil {
+		return nil, err
+	}
+	return &FECDecoder{
+		rs:     rs,
+		groups: make(map[uint32]*fecGroup),
+	}, nil
+}
+
+// Decode parses a FEC packet, and returns the data payloads that it carries or recovers.
+func (d *FECDecoder) Decode(b []byte) [][]byte {
+	if len(b) < fecHeaderSize+fecSizeSize {
+		return nil
+	}
+	seq := binary.BigEndian.Uint32(b)
+	typ := binary.BigEndian.Uint16(b[4:])
+	shard := b[fecHeaderSize:]
+
+	total := uint32(d.rs.dataShards + d.rs.parityShards)
+	idx := int(seq % total)
+	switch {
+	case typ == fecTypeData && idx < d.rs.dataShards:
+	case typ == fecTypeParity && idx >= d.rs.dataShards:
+	default:
+		return nil
+	}
+
+	var result [][]byte
+	if typ == fecTypeData {
+		payload := unpackShard(shard)
+		if payload == nil {
+			return nil
+		}
+		result = append(result, payload)
+	}
+
+	group := d.getGroup(seq / total)
+	if group.done || group.shards[idx] != nil {
+		return result
+	}
+	group.shards[idx] = append([]byte(nil), shard...)
+	group.count++
+
+	if group.count < d.rs.dataShards {
+		return result
+	}
+	if hasAllData(group.shards[:d.rs.dataShards]) {
+		d.finish(group)
+		return result
+	}
+
+	recovered, ok := d.reconstruct(group)
+	d.finish(group)
+	if ok {
+		result = append(result, recovered...)
+	}
+	return result
+}
+
+func (d *FECDecoder) getGroup(id uint32) *fecGroup {
+	if group, found := d.groups[id]; found {
+		return group
+	}
+	if len(d.order) >= fecMaxGroups {
+		delete(d.groups, d.order[0])
+		d.order = append(d.order[:0], d.order[1:]...)
+	}
+	group := &fecGroup{
+		shards: make([][]byte, d.rs.dataShards+d.rs.parityShards),
+	}
+	d.groups[id] = group
+	d.order = append(d.order, id)
+	return group
+}
+
+func (d *FECDecoder) finish(group *fecGroup) {
+	group.done = true
+	group.shards = nil
+}
+
+func (d *FECDecoder) reconstruct(group *fecGroup) ([][]byte, bool) {
+	size := -1
+	for _, s := range group.shards[d.rs.dataShards:] {
+		if s != nil {
+			size = len(s)
+			break
+		}
+	}
+	if size < 0 {
+		return nil, false
+	}
+
+	var missing []int
+	for i, s := range group.shards {
+		if s == nil {
+			if i < d.rs.dataShards {
+				missing = append(missing, i)
+			}
+			continue
+		}
+		if len(s) > size {
+			return nil, false
+		}
+		if i < d.rs.dataShards {
+			group.shards[i] = append(s, make([]byte, size-len(s))...)
+		} else if len(s) != size {
+			return nil, false
+		}
+	}
+
+	if err := d.rs.Reconstruct(group.shards); err != nil {
+		return nil, false
+	}
+
+	result := make([][]byte, 0, len(missing))
+	for _, i := range missing {
+		if payload := unpackShard(group.shards[i]); payload != nil {
+			result = append(result, payload)
+		}
+	}
+	return result, true
+}
+
+func hasAllData(shards [][]byte) bool {
+	for _, s := range shards {
+		if s == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func unpackShard(shard []byte) []byte {
+	if len(shard) < fecSizeSize {
+		return nil
+	}
+	size := int(binary.BigEndian.Uint16(shard))
+	if size < fecSizeSize || size > len(shard) {
+		return nil
+	}
+	return shard[fecSizeSize:size]
+}
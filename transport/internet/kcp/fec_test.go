@@ -0,0 +1,301 @@
+package kcp_test
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	mrand "math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/headers/srtp"
+	"v2ray.com/core/transport/internet/headers/wechat"
+	. "v2ray.com/core/transport/internet/kcp"
+)
+
+func TestFECRecovery(t *testing.T) {
+	encoder, err := NewFECEncoder(4, 2)
+	common.Must(err)
+	decoder, err := NewFECDecoder(4, 2)
+	common.Must(err)
+
+	var sent [][]byte
+	var packets [][]byte
+	for i := 0; i < 8; i++ {
+		payload := make([]byte, 100+i*10)
+		common.Must2(rand.Read(payload))
+		sent = append(sent, payload)
+		common.Must(encoder.Encode(payload, func(b []byte) error {
+			packets = append(packets, append([]byte(nil), b...))
+			return nil
+		}))
+	}
+	if len(packets) != 12 {
+		t.Fatal("unexpected number of packets: ", len(packets))
+	}
+
+	// Drop two data packets of the first group, and one data and one parity packet of the second.
+	lost := map[int]bool{0: true, 2: true, 7: true, 11: true}
+	received := make(map[string]bool)
+	for i, packet := range packets {
+		if lost[i] {
+			continue
+		}
+		for _, payload := range decoder.Decode(packet) {
+			received[string(payload)] = true
+		}
+	}
+
+	for i, payload := range sent {
+		if !received[string(payload)] {
+			t.Error("payload ", i, " is not recovered")
+		}
+	}
+}
+
+func TestFECDeliveryUnderLoss(t *testing.T) {
+	const (
+		loss    = 0.1
+		packets = 10000
+	)
+
+	deliveryRatio := func(dataShards, parityShards int) float64 {
+		encoder, err := NewFECEncoder(dataShards, parityShards)
+		common.Must(err)
+		decoder, err := NewFECDecoder(dataShards, parityShards)
+		common.Must(err)
+
+		rnd := mrand.New(mrand.NewSource(1))
+		delivered := 0
+		payload := make([]byte, 1024)
+		for i := 0; i < packets; i++ {
+			common.Must(encoder.Encode(payload, func(b []byte) error {
+				if rnd.Float64() >= loss {
+					delivered += len(decoder.Decode(b))
+				}
+				return nil
+			}))
+		}
+		return float64(delivered) / packets
+	}
+
+	withFEC := deliveryRatio(10, 3)
+	if withFEC < 0.97 {
+		t.Error("delivery ratio with FEC: ", withFEC)
+	}
+
+	rnd := mrand.New(mrand.NewSource(1))
+	withoutFEC := 0
+	for i := 0; i < packets; i++ {
+		if rnd.Float64() >= loss {
+			withoutFEC++
+		}
+	}
+	if r := float64(withoutFEC) / packets; withFEC <= r {
+		t.Error("FEC does not improve delivery: ", withFEC, " vs ", r)
+	}
+}
+
+type delayedPacket struct {
+	payload []byte
+	arrival time.Time
+}
+
+// lossyLink delivers packets written to it to a Connection after a fixed delay, and drops a fraction of them.
+type lossyLink struct {
+	sync.Mutex
+	rnd    *mrand.Rand
+	loss   float64
+	delay  time.Duration
+	reader PacketReader
+	cache  chan delayedPacket
+	closed bool
+}
+
+func newLossyLink(loss float64, delay time.Duration, seed int64, reader PacketReader) *lossyLink {
+	return &lossyLink{
+		rnd:    mrand.New(mrand.NewSource(seed)),
+		loss:   loss,
+		delay:  delay,
+		reader: reader,
+		cache:  make(chan delayedPacket, 4096),
+	}
+}
+
+func (l *lossyLink) Write(b []byte) (int, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed || l.rnd.Float64() < l.loss {
+		return len(b), nil
+	}
+	select {
+	case l.cache <- delayedPacket{payload: append([]byte(nil), b...), arrival: time.Now().Add(l.delay)}:
+	default:
+	}
+	return len(b), nil
+}
+
+func (l *lossyLink) Close() error {
+	l.Lock()
+	defer l.Unlock()
+
+	l.closed = true
+	close(l.cache)
+	return nil
+}
+
+func (l *lossyLink) deliver(conn *Connection) {
+	for p := range l.cache {
+		time.Sleep(time.Until(p.arrival))
+		if segments := l.reader.Read(p.payload); len(segments) > 0 {
+			conn.Input(segments)
+		}
+	}
+}
+
+// transferOverLossyLink sends size bytes from a client to a server over lossy links, and returns the number
+// of segments the client retransmitted. loss applies to packets to the server, and ackLoss to packets back.
+func transferOverLossyLink(t *testing.T, config *Config, loss float64, ackLoss float64, delay time.Duration, size int) uint32 {
+	header, err := config.GetPackerHeader()
+	common.Must(err)
+	security, err := config.GetSecurity()
+	common.Must(err)
+
+	newPeer := func(loss float64, seed int64) (*lossyLink, *KCPPacketWriter) {
+		decoder, err := config.GetFECDecoder()
+		common.Must(err)
+		encoder, err := config.GetFECEncoder()
+		common.Must(err)
+		link := newLossyLink(loss, delay, seed, &KCPPacketReader{
+			Header:   header,
+			Security: security,
+			FEC:      decoder,
+		})
+		return link, &KCPPacketWriter{
+			Header:   header,
+			Security: security,
+			FEC:      encoder,
+			Writer:   link,
+		}
+	}
+
+	// Packets written by the client go through toServer, and vice versa.
+	toServer, clientWriter := newPeer(loss, 1)
+	toClient, serverWriter := newPeer(ackLoss, 2)
+	client := NewConnection(ConnMetadata{Conversation: 1}, clientWriter, NoOpCloser(0), config)
+	server := NewConnection(ConnMetadata{Conversation: 1}, serverWriter, NoOpCloser(0), config)
+	go toServer.deliver(server)
+	go toClient.deliver(client)
+	defer func() {
+		client.Terminate()
+		server.Terminate()
+		toServer.Close()
+		toClient.Close()
+	}()
+
+	payload := make([]byte, size)
+	common.Must2(rand.Read(payload))
+
+	go client.Write(payload)
+
+	server.SetReadDeadline(time.Now().Add(time.Minute))
+	received := make([]byte, size)
+	if _, err := io.ReadFull(server, received); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := cmp.Diff(received, payload); r != "" {
+		t.Fatal(r)
+	}
+	return client.Metrics().Retransmits
+}
+
+func TestFECRetransmitsUnderLoss(t *testing.T) {
+	const (
+		loss  = 0.15
+		delay = 10 * time.Millisecond
+		size  = 128 * 1024
+	)
+
+	// Acks are not lost, so that segments are only retransmitted after data loss, and not after timeouts of
+	// segments that were received.
+	config := &Config{
+		Tti:              &TTI{Value: 20},
+		UplinkCapacity:   &UplinkCapacity{Value: 20},
+		DownlinkCapacity: &DownlinkCapacity{Value: 20},
+	}
+	withoutFEC := transferOverLossyLink(t, config, loss, 0, delay, size)
+	config.Fec = &FEC{DataShards: 10, ParityShards: 3}
+	withFEC := transferOverLossyLink(t, config, loss, 0, delay, size)
+
+	t.Log("retransmits without FEC: ", withoutFEC, ", with FEC: ", withFEC)
+	if withFEC >= withoutFEC {
+		t.Error("FEC does not reduce retransmits: ", withFEC, " vs ", withoutFEC)
+	}
+}
+
+func TestFECWithPacketHeaders(t *testing.T) {
+	for _, header := range []*serial.TypedMessage{
+		nil,
+		serial.ToTypedMessage(&srtp.Config{}),
+		serial.ToTypedMessage(&wechat.VideoConfig{}),
+	} {
+		config := &Config{
+			HeaderConfig: header,
+			Fec:          &FEC{DataShards: 4, ParityShards: 2},
+		}
+		transferOverLossyLink(t, config, 0.05, 0.05, 0, 64*1024)
+	}
+}
+
+func TestDialAndListenWithFEC(t *testing.T) {
+	config := &Config{
+		HeaderConfig: serial.ToTypedMessage(&srtp.Config{}),
+		Fec:          &FEC{DataShards: 10, ParityShards: 3},
+	}
+	listener, err := NewListener(context.Background(), net.LocalHostIP, net.Port(0), &internet.MemoryStreamConfig{
+		ProtocolName:     "mkcp",
+		ProtocolSettings: config,
+	}, func(conn internet.Connection) {
+		go func(c internet.Connection) {
+			defer c.Close()
+			io.Copy(c, c)
+		}(conn)
+	})
+	common.Must(err)
+	defer listener.Close()
+
+	port := net.Port(listener.Addr().(*net.UDPAddr).Port)
+	conn, err := DialKCP(context.Background(), net.UDPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+		ProtocolName:     "mkcp",
+		ProtocolSettings: config,
+	})
+	common.Must(err)
+	defer conn.Close()
+
+	payload := make([]byte, 256*1024)
+	common.Must2(rand.Read(payload))
+	go conn.Write(payload)
+
+	received := make([]byte, len(payload))
+	common.Must2(io.ReadFull(conn, received))
+	if r := cmp.Diff(received, payload); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestInvalidFECConfig(t *testing.T) {
+	for _, shards := range [][2]int{{0, 3}, {10, 0}, {200, 100}} {
+		if _, err := NewFECEncoder(shards[0], shards[1]); err == nil {
+			t.Error("expected error for shards ", shards)
+		}
+	}
+}
@@ -24,6 +24,7 @@ type PacketWriter interface {
 type KCPPacketReader struct {
 	Security cipher.AEAD
 	Header   internet.PacketHeader
+	FEC      *FECDecoder
 }
 
 func (r *KCPPacketReader) Read(b []byte) []Segment {
@@ -45,6 +46,17 @@ func (r *KCPPacketReader) Read(b []byte) []Segment {
 		}
 		b = out
 	}
+	if r.FEC != nil {
+		var result []Segment
+		for _, payload := range r.FEC.Decode(b) {
+			result = append(result, readSegments(payload)...)
+		}
+		return result
+	}
+	return readSegments(b)
+}
+
+func readSegments(b []byte) []Segment {
 	var result []Segment
 	for len(b) > 0 {
 		seg, x := ReadSegment(b)
@@ -60,6 +72,7 @@ func (r *KCPPacketReader) Read(b []byte) []Segment {
 type KCPPacketWriter struct {
 	Header   internet.PacketHeader
 	Security cipher.AEAD
+	FEC      *FECEncoder
 	Writer   io.Writer
 }
 
@@ -71,10 +84,20 @@ func (w *KCPPacketWriter) Overhead() int {
 	if w.Security != nil {
 		overhead += w.Security.Overhead()
 	}
+	if w.FEC != nil {
+		overhead += FECOverhead
+	}
 	return overhead
 }
 
 func (w *KCPPacketWriter) Write(b []byte) (int, error) {
+	if w.FEC != nil {
+		return len(b), w.FEC.Encode(b, w.writePacket)
+	}
+	return len(b), w.writePacket(b)
+}
+
+func (w *KCPPacketWriter) writePacket(b []byte) error {
 	bb := buf.StackNew()
 	defer bb.Release()
 
@@ -93,5 +116,5 @@ func (w *KCPPacketWriter) Write(b []byte) (int, error) {
 	}
 
 	_, err := w.Writer.Write(bb.Bytes())
-	return len(b), err
+	return err
 }
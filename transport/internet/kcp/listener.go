@@ -7,10 +7,12 @@ import (
 	"crypto/cipher"
 	"crypto/tls"
 	"sync"
+	"time"
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/features/stats"
 	"v2ray.com/core/transport/internet"
 	v2tls "v2ray.com/core/transport/internet/tls"
 	"v2ray.com/core/transport/internet/udp"
@@ -33,8 +35,26 @@ type Listener struct {
 	header    internet.PacketHeader
 	security  cipher.AEAD
 	addConn   internet.ConnHandler
+	stats     stats.Manager
+
+	// FEC state of each client, keyed by its source address.
+	fecReaders map[net.Destination]PacketReader
+	// FEC state of sources that have no session yet.
+	pendingFECReaders map[net.Destination]*pendingFECReader
+}
+
+// pendingFECReader is the FEC state of a source without a session. It is kept for a short time, so that
+// packets from the source before its first valid segment share one decoder.
+type pendingFECReader struct {
+	reader PacketReader
+	expire time.Time
 }
 
+const (
+	pendingFECTimeout    = 5 * time.Second
+	maxPendingFECReaders = 1024
+)
+
 func NewListener(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, addConn internet.ConnHandler) (*Listener, error) {
 	kcpSettings := streamSettings.ProtocolSettings.(*Config)
 	header, err := kcpSettings.GetPackerHeader()
@@ -45,6 +65,9 @@ func NewListener(ctx context.Context, address net.Address, port net.Port, stream
 	if err != nil {
 		return nil, newError("failed to create security").Base(err).AtError()
 	}
+	if _, err := kcpSettings.GetFECDecoder(); err != nil {
+		return nil, newError("failed to create FEC decoder").Base(err).AtError()
+	}
 	l := &Listener{
 		header:   header,
 		security: security,
@@ -52,13 +75,16 @@ func NewListener(ctx context.Context, address net.Address, port net.Port, stream
 			Header:   header,
 			Security: security,
 		},
-		sessions: make(map[ConnectionID]*Connection),
-		config:   kcpSettings,
-		addConn:  addConn,
+		sessions:          make(map[ConnectionID]*Connection),
+		fecReaders:        make(map[net.Destination]PacketReader),
+		pendingFECReaders: make(map[net.Destination]*pendingFECReader),
+		config:            kcpSettings,
+		addConn:           addConn,
+		stats:             getStatsManager(ctx, kcpSettings),
 	}
 
 	if config := v2tls.ConfigFromStreamSettings(streamSettings); config != nil {
-		l.tlsConfig = config.GetTLSConfig()
+		l.tlsConfig = config.GetTLSConfig(v2tls.WithContext(ctx))
 	}
 
 	hub, err := udp.ListenUDP(ctx, address, port, streamSettings, udp.HubCapacity(1024))
@@ -82,12 +108,66 @@ func (l *Listener) handlePackets() {
 	}
 }
 
+// getReader returns the PacketReader for packets from src. With FEC enabled, each client needs a
+// decoder of its own. Decoders of sources without a session expire shortly, and only a limited number
+// of them are kept.
+func (l *Listener) getReader(src net.Destination) (PacketReader, error) {
+	if l.config.Fec == nil {
+		return l.reader, nil
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if reader, found := l.fecReaders[src]; found {
+		return reader, nil
+	}
+	now := time.Now()
+	if pending, found := l.pendingFECReaders[src]; found && now.Before(pending.expire) {
+		return pending.reader, nil
+	}
+
+	decoder, err := l.config.GetFECDecoder()
+	if err != nil {
+		return nil, err
+	}
+	reader := &KCPPacketReader{
+		Header:   l.header,
+		Security: l.security,
+		FEC:      decoder,
+	}
+
+	if len(l.pendingFECReaders) >= maxPendingFECReaders {
+		for s, pending := range l.pendingFECReaders {
+			if !now.Before(pending.expire) {
+				delete(l.pendingFECReaders, s)
+			}
+		}
+	}
+	if len(l.pendingFECReaders) < maxPendingFECReaders {
+		l.pendingFECReaders[src] = &pendingFECReader{
+			reader: reader,
+			expire: now.Add(pendingFECTimeout),
+		}
+	}
+	return reader, nil
+}
+
 func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
-	segments := l.reader.Read(payload.Bytes())
+	reader, err := l.getReader(src)
+	if err != nil {
+		payload.Release()
+		newError("failed to create FEC decoder").Base(err).WriteToLog()
+		return
+	}
+	segments := reader.Read(payload.Bytes())
 	payload.Release()
 
 	if len(segments) == 0 {
-		newError("discarding invalid payload from ", src).WriteToLog()
+		// With FEC, parity packets carry no segments until they recover a lost packet.
+		if l.config.Fec == nil {
+			newError("discarding invalid payload from ", src).WriteToLog()
+		}
 		return
 	}
 
@@ -109,6 +189,11 @@ func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
 		if cmd == CommandTerminate {
 			return
 		}
+		fecEncoder, err := l.config.GetFECEncoder()
+		if err != nil {
+			newError("failed to create FEC encoder").Base(err).WriteToLog()
+			return
+		}
 		writer := &Writer{
 			id:       id,
 			hub:      l.hub,
@@ -124,9 +209,11 @@ func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
 			LocalAddr:    localAddr,
 			RemoteAddr:   remoteAddr,
 			Conversation: conv,
+			StatsManager: l.stats,
 		}, &KCPPacketWriter{
 			Header:   l.header,
 			Security: l.security,
+			FEC:      fecEncoder,
 			Writer:   writer,
 		}, writer, l.config)
 		var netConn internet.Connection = conn
@@ -137,6 +224,10 @@ func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
 
 		l.addConn(netConn)
 		l.sessions[id] = conn
+		if l.config.Fec != nil {
+			l.fecReaders[src] = reader
+			delete(l.pendingFECReaders, src)
+		}
 	}
 	conn.Input(segments)
 }
@@ -144,6 +235,7 @@ func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
 func (l *Listener) Remove(id ConnectionID) {
 	l.Lock()
 	delete(l.sessions, id)
+	delete(l.fecReaders, net.UDPDestination(id.Remote, id.Port))
 	l.Unlock()
 }
 
@@ -0,0 +1,97 @@
+// +build !confonly
+
+package kcp
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"v2ray.com/core"
+	"v2ray.com/core/features/stats"
+)
+
+// ConnectionMetrics is a snapshot of the transmission state of a Connection.
+type ConnectionMetrics struct {
+	// Smoothed round trip time, in milliseconds.
+	RTT uint32
+	// Congestion window, in segments.
+	Window uint32
+	// Number of retransmitted segments.
+	Retransmits uint32
+}
+
+// Metrics returns the current metrics of the connection.
+func (c *Connection) Metrics() ConnectionMetrics {
+	window, retransmits := c.sendingWorker.Metrics(c.Elapsed())
+	return ConnectionMetrics{
+		RTT:         c.roundTrip.SmoothedTime(),
+		Window:      window,
+		Retransmits: retransmits,
+	}
+}
+
+// ConnectionCounterName returns the name of the stats counter of a metric of the connection to remote
+// with the given conversation ID. The metric is one of "rtt", "cwnd" and "retransmits".
+func ConnectionCounterName(remote net.Addr, conv uint16, metric string) string {
+	return "mkcp>>>" + remote.String() + ">>>" + strconv.Itoa(int(conv)) + ">>>" + metric
+}
+
+// getStatsManager returns the stats manager of the V2Ray instance in ctx, or nil if stats are disabled.
+func getStatsManager(ctx context.Context, config *Config) stats.Manager {
+	if !config.Stats {
+		return nil
+	}
+	if v := core.FromContext(ctx); v != nil {
+		if m, ok := v.GetFeature(stats.ManagerType()).(stats.Manager); ok {
+			return m
+		}
+	}
+	return nil
+}
+
+// connectionStats reports metrics of a Connection to stats counters.
+type connectionStats struct {
+	manager     stats.Manager
+	names       []string
+	rtt         stats.Counter
+	cwnd        stats.Counter
+	retransmits stats.Counter
+}
+
+func newConnectionStats(manager stats.Manager, meta ConnMetadata) *connectionStats {
+	s := &connectionStats{
+		manager: manager,
+	}
+	for _, c := range []struct {
+		metric  string
+		counter *stats.Counter
+	}{
+		{"rtt", &s.rtt},
+		{"cwnd", &s.cwnd},
+		{"retransmits", &s.retransmits},
+	} {
+		name := ConnectionCounterName(meta.RemoteAddr, meta.Conversation, c.metric)
+		counter, err := stats.GetOrRegisterCounter(manager, name)
+		if err != nil {
+			newError("failed to register counter ", name).Base(err).WriteToLog()
+			s.Close()
+			return nil
+		}
+		*c.counter = counter
+		s.names = append(s.names, name)
+	}
+	return s
+}
+
+func (s *connectionStats) Update(m ConnectionMetrics) {
+	s.rtt.Set(int64(m.RTT))
+	s.cwnd.Set(int64(m.Window))
+	s.retransmits.Set(int64(m.Retransmits))
+}
+
+func (s *connectionStats) Close() {
+	for _, name := range s.names {
+		stats.UnregisterCounter(s.manager, name)
+	}
+}
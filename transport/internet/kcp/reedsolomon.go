@@ -0,0 +1,178 @@
+// +build !confonly
+
+package kcp
+
+// Arithmetic in GF(2^8) with the generator polynomial x^8 + x^4 + x^3 + x^2 + 1.
+var (
+	gfExp [510]byte
+	gfLog [256]byte
+	gfMul [256][256]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for i := 255; i < len(gfExp); i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+	for a := 1; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			gfMul[a][b] = gfExp[int(gfLog[a])+int(gfLog[b])]
+		}
+	}
+}
+
+func gfInv(a byte) byte {
+	return gfExp[255-int(gfLog[a])]
+}
+
+// mulAdd sets out[i] ^= c * in[i] for every byte of in.
+func mulAdd(c byte, in, out []byte) {
+	if c == 0 {
+		return
+	}
+	t := &gfMul[c]
+	for i, v := range in {
+		out[i] ^= t[v]
+	}
+}
+
+// reedSolomon is a systematic Reed-Solomon erasure code. Parity rows are taken from a Cauchy
+// matrix, so any dataShards out of the dataShards+parityShards shards recover the data.
+type reedSolomon struct {
+	dataShards   int
+	parityShards int
+	parity       [][]byte
+}
+
+func newReedSolomon(dataShards, parityShards int) (*reedSolomon, error) {
+	if dataShards <= 0 || parityShards <= 0 {
+		return nil, newError("invalid number of FEC shards: ", dataShards, "/", parityShards)
+	}
+	if dataShards+parityShards > 256 {
+		return nil, newError("too many FEC shards: ", dataShards+parityShards)
+	}
+
+	parity := make([][]byte, parityShards)
+	for i := range parity {
+		parity[i] = make([]byte, dataShards)
+		for j := range parity[i] {
+			parity[i][j] = gfInv(byte(dataShards+i) ^ byte(j))
+		}
+	}
+	return &reedSolomon{
+		dataShards:   dataShards,
+		parityShards: parityShards,
+		parity:       parity,
+	}, nil
+}
+
+// Encode fills shards[dataShards:] with parity of shards[:dataShards]. All shards must have the same length.
+func (r *reedSolomon) Encode(shards [][]byte) {
+	for i, row := range r.parity {
+		out := shards[r.dataShards+i]
+		for j := range out {
+			out[j] = 0
+		}
+		for j, c := range row {
+			mulAdd(c, shards[j], out)
+		}
+	}
+}
+
+// Reconstruct recovers missing data shards in place. Missing shards are nil, and all present shards
+// must have the same length. Parity shards are not recovered.
+func (r *reedSolomon) Reconstruct(shards [][]byte) error {
+	present := make([]int, 0, r.dataShards)
+	dataMissing := false
+	for i, s := range shards {
+		if s == nil {
+			if i < r.dataShards {
+				dataMissing = true
+			}
+			continue
+		}
+		if len(present) < r.dataShards {
+			present = append(present, i)
+		}
+	}
+	if !dataMissing {
+		return nil
+	}
+	if len(present) < r.dataShards {
+		return newError("not enough FEC shards: ", len(present), "/", r.dataShards)
+	}
+
+	m := make([][]byte, r.dataShards)
+	for i, idx := range present {
+		if idx < r.dataShards {
+			m[i] = make([]byte, r.dataShards)
+			m[i][idx] = 1
+		} else {
+			m[i] = append([]byte(nil), r.parity[idx-r.dataShards]...)
+		}
+	}
+	inv, err := invertMatrix(m)
+	if err != nil {
+		return err
+	}
+
+	size := len(shards[present[0]])
+	for d := 0; d < r.dataShards; d++ {
+		if shards[d] != nil {
+			continue
+		}
+		out := make([]byte, size)
+		for j, idx := range present {
+			mulAdd(inv[d][j], shards[idx], out)
+		}
+		shards[d] = out
+	}
+	return nil
+}
+
+// invertMatrix inverts a square matrix with Gauss-Jordan elimination. The input is modified.
+func invertMatrix(m [][]byte) ([][]byte, error) {
+	n := len(m)
+	inv := make([][]byte, n)
+	for i := range inv {
+		inv[i] = make([]byte, n)
+		inv[i][i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for pivot < n && m[pivot][col] == 0 {
+			pivot++
+		}
+		if pivot == n {
+			return nil, newError("singular FEC matrix")
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+
+		if c := m[col][col]; c != 1 {
+			f := gfInv(c)
+			for j := 0; j < n; j++ {
+				m[col][j] = gfMul[f][m[col][j]]
+				inv[col][j] = gfMul[f][inv[col][j]]
+			}
+		}
+		for row := 0; row < n; row++ {
+			if row == col || m[row][col] == 0 {
+				continue
+			}
+			f := m[row][col]
+			mulAdd(f, m[col], m[row])
+			mulAdd(f, inv[col], inv[row])
+		}
+	}
+	return inv, nil
+}
@@ -9,6 +9,77 @@ import (
 	"v2ray.com/core/common/buf"
 )
 
+// CongestionController decides how many data segments a connection may have in flight.
+type CongestionController interface {
+	// OnAck is called when an ack segment acknowledges acked data segments. rtt is the round trip time
+	// measured by the ack in milliseconds, or 0 if it is not measured.
+	OnAck(current uint32, acked uint32, rtt uint32)
+	// OnPacketLoss is called after each flush with the percentage of data segments that were retransmitted.
+	OnPacketLoss(lossRate uint32)
+	// Window returns the max number of data segments in flight.
+	Window(current uint32) uint32
+}
+
+// fixedController keeps the window at the configured uplink capacity.
+type fixedController struct {
+	inFlightSize uint32
+}
+
+func (*fixedController) OnAck(current uint32, acked uint32, rtt uint32) {}
+
+func (*fixedController) OnPacketLoss(lossRate uint32) {}
+
+func (c *fixedController) Window(current uint32) uint32 {
+	return c.inFlightSize
+}
+
+// lossController shrinks the window under heavy packet loss, and grows it back when loss is low.
+type lossController struct {
+	fixedController
+	controlWindow uint32
+}
+
+func (c *lossController) OnPacketLoss(lossRate uint32) {
+	if lossRate >= 15 {
+		c.controlWindow = 3 * c.controlWindow / 4
+	} else if lossRate <= 5 {
+		c.controlWindow += c.controlWindow / 4
+	}
+	if c.controlWindow < 16 {
+		c.controlWindow = 16
+	}
+	if c.controlWindow > 2*c.inFlightSize {
+		c.controlWindow = 2 * c.inFlightSize
+	}
+}
+
+func (c *lossController) Window(current uint32) uint32 {
+	if c.controlWindow < c.inFlightSize {
+		return c.controlWindow
+	}
+	return c.inFlightSize
+}
+
+// NewCongestionController creates the CongestionController of a connection with the given config.
+func NewCongestionController(config *Config) CongestionController {
+	fixed := fixedController{
+		inFlightSize: config.GetSendingInFlightSize(),
+	}
+	if !config.Congestion {
+		return &fixed
+	}
+
+	switch config.CongestionControl {
+	case CongestionControl_BBR:
+		return newBBRController(config)
+	default:
+		return &lossController{
+			fixedController: fixed,
+			controlWindow:   fixed.inFlightSize,
+		}
+	}
+}
+
 type SendingWindow struct {
 	cache             *list.List
 	totalInFlightSize uint32
@@ -56,7 +127,9 @@ func (sw *SendingWindow) FirstNumber() uint32 {
 	return sw.cache.Front().Value.(*DataSegment).Number
 }
 
-func (sw *SendingWindow) Clear(una uint32) {
+// Clear removes all segments numbered before una, and returns the number of removed segments.
+func (sw *SendingWindow) Clear(una uint32) uint32 {
+	var removed uint32
 	for !sw.IsEmpty() {
 		seg := sw.cache.Front().Value.(*DataSegment)
 		if seg.Number >= una {
@@ -64,7 +137,9 @@ func (sw *SendingWindow) Clear(una uint32) {
 		}
 		seg.Release()
 		sw.cache.Remove(sw.cache.Front())
+		removed++
 	}
+	return removed
 }
 
 func (sw *SendingWindow) HandleFastAck(number uint32, rto uint32) {
@@ -97,15 +172,22 @@ func (sw *SendingWindow) Visit(visitor func(seg *DataSegment) bool) {
 	}
 }
 
-func (sw *SendingWindow) Flush(current uint32, rto uint32, maxInFlightSize uint32) {
+// Flush sends segments that are new or timed out, and returns the number of retransmitted segments. If strict
+// is set, only segments numbered before cwnd are sent. Otherwise cwnd limits the number of segments sent
+// in this flush.
+func (sw *SendingWindow) Flush(current uint32, rto uint32, cwnd uint32, strict bool) uint32 {
 	if sw.IsEmpty() {
-		return
+		return 0
 	}
 
 	var lost uint32
 	var inFlightSize uint32
 
 	sw.Visit(func(segment *DataSegment) bool {
+		// segment.Number >= cwnd
+		if strict && segment.Number-cwnd < 0x7FFFFFFF {
+			return false
+		}
 		if current-segment.timeout >= 0x7FFFFFFF {
 			return true
 		}
@@ -121,7 +203,7 @@ func (sw *SendingWindow) Flush(current uint32, rto uint32, maxInFlightSize uint3
 		segment.transmit++
 		sw.writer.Write(segment)
 		inFlightSize++
-		if inFlightSize >= maxInFlightSize {
+		if inFlightSize >= cwnd {
 			return false
 		}
 		return true
@@ -131,6 +213,7 @@ func (sw *SendingWindow) Flush(current uint32, rto uint32, maxInFlightSize uint3
 		rate := lost * 100 / sw.totalInFlightSize
 		sw.onPacketLoss(rate)
 	}
+	return lost
 }
 
 func (sw *SendingWindow) Remove(number uint32) bool {
@@ -162,11 +245,15 @@ type SendingWorker struct {
 	firstUnacknowledged        uint32
 	nextNumber                 uint32
 	remoteNextNumber           uint32
-	controlWindow              uint32
 	fastResend                 uint32
 	windowSize                 uint32
+	retransmits                uint32
+	congestion                 CongestionController
 	firstUnacknowledgedUpdated bool
 	closed                     bool
+	// strictWindow is set if segments beyond the congestion window must not be sent. Only BBR sizes its
+	// window to the segments in flight. Windows of the other controllers are per TTI.
+	strictWindow bool
 }
 
 func NewSendingWorker(kcp *Connection) *SendingWorker {
@@ -174,9 +261,10 @@ func NewSendingWorker(kcp *Connection) *SendingWorker {
 		conn:             kcp,
 		fastResend:       2,
 		remoteNextNumber: 32,
-		controlWindow:    kcp.Config.GetSendingInFlightSize(),
 		windowSize:       kcp.Config.GetSendingBufferSize(),
+		congestion:       NewCongestionController(kcp.Config),
 	}
+	_, worker.strictWindow = worker.congestion.(*bbrController)
 	worker.window = NewSendingWindow(worker, worker.OnPacketLoss)
 	return worker
 }
@@ -195,9 +283,11 @@ func (w *SendingWorker) ProcessReceivingNext(nextNumber uint32) {
 	w.ProcessReceivingNextWithoutLock(nextNumber)
 }
 
-func (w *SendingWorker) ProcessReceivingNextWithoutLock(nextNumber uint32) {
-	w.window.Clear(nextNumber)
+// ProcessReceivingNextWithoutLock removes segments acknowledged by nextNumber, and returns the number of them.
+func (w *SendingWorker) ProcessReceivingNextWithoutLock(nextNumber uint32) uint32 {
+	removed := w.window.Clear(nextNumber)
 	w.FindFirstUnacknowledged()
+	return removed
 }
 
 func (w *SendingWorker) FindFirstUnacknowledged() {
@@ -238,28 +328,32 @@ func (w *SendingWorker) ProcessSegment(current uint32, seg *AckSegment, rto uint
 	if w.remoteNextNumber < seg.ReceivingWindow {
 		w.remoteNextNumber = seg.ReceivingWindow
 	}
-	w.ProcessReceivingNextWithoutLock(seg.ReceivingNext)
-
-	if seg.IsEmpty() {
-		return
-	}
+	acked := w.ProcessReceivingNextWithoutLock(seg.ReceivingNext)
 
 	var maxack uint32
 	var maxackRemoved bool
 	for _, number := range seg.NumberList {
 		removed := w.processAck(number)
+		if removed {
+			acked++
+		}
 		if maxack < number {
 			maxack = number
 			maxackRemoved = removed
 		}
 	}
 
+	var rtt uint32
 	if maxackRemoved {
 		w.window.HandleFastAck(maxack, rto)
 		if current-seg.Timestamp < 10000 {
-			w.conn.roundTrip.Update(current-seg.Timestamp, current)
+			rtt = current - seg.Timestamp
+			w.conn.roundTrip.Update(rtt, current)
 		}
 	}
+	if acked > 0 {
+		w.congestion.OnAck(current, acked, rtt)
+	}
 }
 
 func (w *SendingWorker) Push(b *buf.Buffer) bool {
@@ -293,21 +387,10 @@ func (w *SendingWorker) Write(seg Segment) error {
 }
 
 func (w *SendingWorker) OnPacketLoss(lossRate uint32) {
-	if !w.conn.Config.Congestion || w.conn.roundTrip.Timeout() == 0 {
+	if w.conn.roundTrip.Timeout() == 0 {
 		return
 	}
-
-	if lossRate >= 15 {
-		w.controlWindow = 3 * w.controlWindow / 4
-	} else if lossRate <= 5 {
-		w.controlWindow += w.controlWindow / 4
-	}
-	if w.controlWindow < 16 {
-		w.controlWindow = 16
-	}
-	if w.controlWindow > 2*w.conn.Config.GetSendingInFlightSize() {
-		w.controlWindow = 2 * w.conn.Config.GetSendingInFlightSize()
-	}
+	w.congestion.OnPacketLoss(lossRate)
 }
 
 func (w *SendingWorker) Flush(current uint32) {
@@ -318,16 +401,13 @@ func (w *SendingWorker) Flush(current uint32) {
 		return
 	}
 
-	cwnd := w.firstUnacknowledged + w.conn.Config.GetSendingInFlightSize()
+	cwnd := w.firstUnacknowledged + w.congestion.Window(current)
 	if cwnd > w.remoteNextNumber {
 		cwnd = w.remoteNextNumber
 	}
-	if w.conn.Config.Congestion && cwnd > w.firstUnacknowledged+w.controlWindow {
-		cwnd = w.firstUnacknowledged + w.controlWindow
-	}
 
 	if !w.window.IsEmpty() {
-		w.window.Flush(current, w.conn.roundTrip.Timeout(), cwnd)
+		w.retransmits += w.window.Flush(current, w.conn.roundTrip.Timeout(), cwnd, w.strictWindow)
 		w.firstUnacknowledgedUpdated = false
 	}
 
@@ -365,3 +445,11 @@ func (w *SendingWorker) FirstUnacknowledged() uint32 {
 
 	return w.firstUnacknowledged
 }
+
+// Metrics returns the congestion window in segments, and the number of retransmitted segments.
+func (w *SendingWorker) Metrics(current uint32) (uint32, uint32) {
+	w.RLock()
+	defer w.RUnlock()
+
+	return w.congestion.Window(current), w.retransmits
+}
@@ -0,0 +1,132 @@
+package kcp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	. "v2ray.com/core/transport/internet/kcp"
+)
+
+func TestFixedCongestionController(t *testing.T) {
+	config := &Config{}
+	controller := NewCongestionController(config)
+
+	if w := controller.Window(0); w != config.GetSendingInFlightSize() {
+		t.Error("unexpected window: ", w)
+	}
+	controller.OnPacketLoss(50)
+	if w := controller.Window(0); w != config.GetSendingInFlightSize() {
+		t.Error("window should not change without congestion control: ", w)
+	}
+}
+
+func TestLossBasedCongestionController(t *testing.T) {
+	config := &Config{Congestion: true}
+	controller := NewCongestionController(config)
+
+	initial := controller.Window(0)
+	controller.OnPacketLoss(50)
+	if w := controller.Window(0); w != initial*3/4 {
+		t.Error("window should shrink on heavy loss: ", w, " vs ", initial)
+	}
+	for i := 0; i < 100; i++ {
+		controller.OnPacketLoss(80)
+	}
+	if w := controller.Window(0); w != 16 {
+		t.Error("window should not shrink below 16: ", w)
+	}
+	for i := 0; i < 100; i++ {
+		controller.OnPacketLoss(0)
+	}
+	if w := controller.Window(0); w != initial {
+		t.Error("window should recover on low loss: ", w, " vs ", initial)
+	}
+}
+
+func TestBBRCongestionController(t *testing.T) {
+	config := &Config{
+		Congestion:        true,
+		CongestionControl: CongestionControl_BBR,
+	}
+	controller := NewCongestionController(config)
+	if w := controller.Window(0); w != config.GetSendingInFlightSize() {
+		t.Error("unexpected initial window: ", w)
+	}
+
+	// A path that delivers 1 segment per millisecond with a round trip time of 100ms, i.e. a
+	// bandwidth-delay product of 100 segments.
+	var current uint32
+	for ; current < 20000; current += 10 {
+		controller.OnAck(current, 10, 100)
+	}
+	if w := controller.Window(current); w < 75 || w > 250 {
+		t.Error("window is not around the bandwidth-delay product: ", w)
+	}
+
+	// Window drops to the minimum when probing RTT, after the min RTT is not seen for 10 seconds.
+	var probed bool
+	for end := current + 20000; current < end; current += 10 {
+		controller.OnAck(current, 10, 120)
+		if controller.Window(current) == 16 {
+			probed = true
+		}
+	}
+	if !probed {
+		t.Error("RTT is not probed")
+	}
+	if w := controller.Window(current); w < 90 || w > 300 {
+		t.Error("window is not around the bandwidth-delay product after probing RTT: ", w)
+	}
+}
+
+func TestBBROverLossyLink(t *testing.T) {
+	transferOverLossyLink(t, &Config{
+		Congestion:        true,
+		CongestionControl: CongestionControl_BBR,
+	}, 0.05, 0.05, 10*time.Millisecond, 256*1024)
+}
+
+func TestConnectionStats(t *testing.T) {
+	manager, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	remote := &net.UDPAddr{IP: []byte{127, 0, 0, 1}, Port: 1234}
+	server := newLossyLink(0, 0, 1, &KCPPacketReader{})
+	conn := NewConnection(ConnMetadata{
+		RemoteAddr:   remote,
+		Conversation: 1,
+		StatsManager: manager,
+	}, &KCPPacketWriter{
+		Writer: server,
+	}, NoOpCloser(0), &Config{})
+	defer server.Close()
+
+	for _, metric := range []string{"rtt", "cwnd", "retransmits"} {
+		if manager.GetCounter(ConnectionCounterName(remote, 1, metric)) == nil {
+			t.Error("counter of ", metric, " is not registered")
+		}
+	}
+
+	common.Must2(conn.Write(make([]byte, 1024)))
+	time.Sleep(time.Second)
+	if v := manager.GetCounter(ConnectionCounterName(remote, 1, "cwnd")).Value(); v == 0 {
+		t.Error("cwnd is not reported")
+	}
+	if v := manager.GetCounter(ConnectionCounterName(remote, 1, "retransmits")).Value(); v == 0 {
+		t.Error("retransmits are not reported")
+	}
+	if m := conn.Metrics(); m.Retransmits == 0 {
+		t.Error("unexpected metrics: ", m)
+	}
+
+	conn.Terminate()
+	for _, metric := range []string{"rtt", "cwnd", "retransmits"} {
+		if manager.GetCounter(ConnectionCounterName(remote, 1, metric)) != nil {
+			t.Error("counter of ", metric, " is not unregistered")
+		}
+	}
+}
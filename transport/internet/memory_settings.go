@@ -1,5 +1,11 @@
 package internet
 
+import (
+	"fmt"
+
+	"v2ray.com/core/common/net"
+)
+
 // MemoryStreamConfig is a parsed form of StreamConfig. This is used to reduce number of Protobuf parsing.
 type MemoryStreamConfig struct {
 	ProtocolName     string
@@ -36,3 +42,23 @@ func ToMemoryStreamConfig(s *StreamConfig) (*MemoryStreamConfig, error) {
 
 	return mss, nil
 }
+
+// DialerKey identifies the clients that can be shared among connections to the same destination. Security and
+// socket settings are in text form, as the same settings may come in different stream settings.
+type DialerKey struct {
+	net.Destination
+	security string
+	sockopt  string
+}
+
+// NewDialerKey returns the DialerKey of connections to dest with the given stream settings.
+func NewDialerKey(dest net.Destination, streamSettings *MemoryStreamConfig) DialerKey {
+	key := DialerKey{
+		Destination: dest,
+		sockopt:     streamSettings.SocketSettings.String(),
+	}
+	if security, ok := streamSettings.SecuritySettings.(fmt.Stringer); ok {
+		key.security = security.String()
+	}
+	return key
+}
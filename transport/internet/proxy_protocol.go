@@ -0,0 +1,91 @@
+package internet
+
+import (
+	"sync"
+	"time"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/proxyproto"
+)
+
+// proxyProtocolTimeout is the max time to wait for a PROXY protocol header, if the header is requested before the first read.
+const proxyProtocolTimeout = time.Second * 5
+
+type proxyProtocolListener struct {
+	net.Listener
+}
+
+// Accept implements net.Listener.
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &proxyProtocolConn{Conn: conn}, nil
+}
+
+// proxyProtocolConn is a connection that starts with a PROXY protocol header.
+// The header is read on first use of the connection, and the addresses in it replace the ones of the underlying connection.
+type proxyProtocolConn struct {
+	net.Conn
+
+	once       sync.Once
+	err        error
+	remoteAddr net.Addr
+	localAddr  net.Addr
+}
+
+// NetConn returns the underlying connection.
+func (c *proxyProtocolConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+func (c *proxyProtocolConn) readHeader(withTimeout bool) error {
+	c.once.Do(func() {
+		if withTimeout {
+			c.Conn.SetReadDeadline(time.Now().Add(proxyProtocolTimeout)) // nolint: errcheck
+			defer c.Conn.SetReadDeadline(time.Time{})                    // nolint: errcheck
+		}
+
+		header, err := proxyproto.ReadHeader(c.Conn)
+		if err != nil {
+			c.err = newError("failed to read PROXY protocol header from ", c.Conn.RemoteAddr()).Base(err)
+			return
+		}
+		if header.Source.IsValid() {
+			c.remoteAddr = &net.TCPAddr{
+				IP:   header.Source.Address.IP(),
+				Port: int(header.Source.Port),
+			}
+			c.localAddr = &net.TCPAddr{
+				IP:   header.Destination.Address.IP(),
+				Port: int(header.Destination.Port),
+			}
+		}
+	})
+	return c.err
+}
+
+// Read implements net.Conn.
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	if err := c.readHeader(false); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+// RemoteAddr implements net.Conn. It returns the source address in the PROXY protocol header if available.
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	if err := c.readHeader(true); err == nil && c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr implements net.Conn. It returns the destination address in the PROXY protocol header if available.
+func (c *proxyProtocolConn) LocalAddr() net.Addr {
+	if err := c.readHeader(true); err == nil && c.localAddr != nil {
+		return c.localAddr
+	}
+	return c.Conn.LocalAddr()
+}
@@ -6,10 +6,12 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/sha256"
+	"time"
 
 	"golang.org/x/crypto/chacha20poly1305"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/protocol"
+	quic "v2ray.com/core/external/github.com/lucas-clemente/quic-go"
 	"v2ray.com/core/transport/internet"
 )
 
@@ -47,3 +49,39 @@ func getHeader(config *Config) (internet.PacketHeader, error) {
 
 	return internet.CreatePacketHeader(msg)
 }
+
+const defaultMaxConcurrentStreams = 32
+
+func (c *Config) getMaxConcurrentStreams() int {
+	if c.MaxConcurrentStreams == 0 {
+		return defaultMaxConcurrentStreams
+	}
+	return int(c.MaxConcurrentStreams)
+}
+
+// getQUICConfig returns the quic-go config of clients or servers.
+func (c *Config) getQUICConfig(server bool) *quic.Config {
+	config := &quic.Config{
+		ConnectionIDLength:                    12,
+		HandshakeTimeout:                      time.Second * 8,
+		IdleTimeout:                           time.Second * 30,
+		MaxReceiveStreamFlowControlWindow:     c.StreamReceiveWindow,
+		MaxReceiveConnectionFlowControlWindow: c.ConnectionReceiveWindow,
+	}
+	if server {
+		config.IdleTimeout = time.Second * 45
+		config.MaxIncomingStreams = c.getMaxConcurrentStreams()
+		config.MaxIncomingUniStreams = -1
+	}
+	if c.IdleTimeout > 0 {
+		config.IdleTimeout = time.Duration(c.IdleTimeout) * time.Second
+	}
+	return config
+}
+
+func (c *Config) getConnectionPoolSize() int {
+	if c.ConnectionPoolSize == 0 {
+		return 1
+	}
+	return int(c.ConnectionPoolSize)
+}
@@ -20,12 +20,28 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type Config struct {
-	Key                  string                   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Security             *protocol.SecurityConfig `protobuf:"bytes,2,opt,name=security,proto3" json:"security,omitempty"`
-	Header               *serial.TypedMessage     `protobuf:"bytes,3,opt,name=header,proto3" json:"header,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
-	XXX_unrecognized     []byte                   `json:"-"`
-	XXX_sizecache        int32                    `json:"-"`
+	Key      string                   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Security *protocol.SecurityConfig `protobuf:"bytes,2,opt,name=security,proto3" json:"security,omitempty"`
+	Header   *serial.TypedMessage     `protobuf:"bytes,3,opt,name=header,proto3" json:"header,omitempty"`
+	// Seconds that a connection may stay idle before it is closed. Defaults to 30 for clients and
+	// 45 for servers.
+	IdleTimeout uint32 `protobuf:"varint,4,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
+	// Max number of concurrent streams on one connection. Servers accept no more
+	// streams, and clients open another connection when all connections in the pool
+	// reach the limit. Defaults to 32.
+	MaxConcurrentStreams uint32 `protobuf:"varint,5,opt,name=max_concurrent_streams,json=maxConcurrentStreams,proto3" json:"max_concurrent_streams,omitempty"`
+	// Max stream-level flow control window for receiving data, in bytes. Defaults to the quic-go
+	// default.
+	StreamReceiveWindow uint64 `protobuf:"varint,6,opt,name=stream_receive_window,json=streamReceiveWindow,proto3" json:"stream_receive_window,omitempty"`
+	// Max connection-level flow control window for receiving data, in bytes. Defaults to the
+	// quic-go default.
+	ConnectionReceiveWindow uint64 `protobuf:"varint,7,opt,name=connection_receive_window,json=connectionReceiveWindow,proto3" json:"connection_receive_window,omitempty"`
+	// Number of connections that a client spreads streams across. Defaults to 1.
+	// More connections are opened when all of them reach max_concurrent_streams.
+	ConnectionPoolSize   uint32   `protobuf:"varint,8,opt,name=connection_pool_size,json=connectionPoolSize,proto3" json:"connection_pool_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
@@ -74,6 +90,41 @@ func (m *Config) GetHeader() *serial.TypedMessage {
 	return nil
 }
 
+func (m *Config) GetIdleTimeout() uint32 {
+	if m != nil {
+		return m.IdleTimeout
+	}
+	return 0
+}
+
+func (m *Config) GetMaxConcurrentStreams() uint32 {
+	if m != nil {
+		return m.MaxConcurrentStreams
+	}
+	return 0
+}
+
+func (m *Config) GetStreamReceiveWindow() uint64 {
+	if m != nil {
+		return m.StreamReceiveWindow
+	}
+	return 0
+}
+
+func (m *Config) GetConnectionReceiveWindow() uint64 {
+	if m != nil {
+		return m.ConnectionReceiveWindow
+	}
+	return 0
+}
+
+func (m *Config) GetConnectionPoolSize() uint32 {
+	if m != nil {
+		return m.ConnectionPoolSize
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Config)(nil), "v2ray.core.transport.internet.quic.Config")
 }
@@ -83,22 +134,30 @@ func init() {
 }
 
 var fileDescriptor_462e2eb906061b36 = []byte{
-	// 269 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x90, 0xc1, 0x4b, 0xc3, 0x30,
-	0x14, 0xc6, 0xe9, 0x26, 0x45, 0xe3, 0x45, 0x7a, 0x2a, 0x3b, 0x8d, 0x1e, 0xc6, 0x10, 0x79, 0x91,
-	0xee, 0xee, 0xc1, 0x81, 0xe0, 0x41, 0xd0, 0x3a, 0x3d, 0x78, 0x91, 0x98, 0x3d, 0x67, 0x70, 0xed,
-	0xab, 0x2f, 0xa9, 0xd0, 0x7f, 0xc7, 0xa3, 0x7f, 0xa5, 0xa4, 0x59, 0x8b, 0xc8, 0xc0, 0x53, 0x0b,
-	0xf9, 0x7e, 0xbf, 0x7c, 0x5f, 0xc4, 0xe2, 0x33, 0x67, 0xd5, 0x82, 0xa6, 0x52, 0x6a, 0x62, 0x94,
-	0x8e, 0x55, 0x65, 0x6b, 0x62, 0x27, 0x4d, 0xe5, 0x90, 0x2b, 0x74, 0xf2, 0xa3, 0x31, 0x5a, 0x6a,
-	0xaa, 0x5e, 0xcd, 0x06, 0x6a, 0x26, 0x47, 0x49, 0xd6, 0x43, 0x8c, 0x30, 0x00, 0xd0, 0x03, 0xe0,
-	0x81, 0xc9, 0xf9, 0x1f, 0xb1, 0xa6, 0xb2, 0xa4, 0x4a, 0x5a, 0x64, 0xa3, 0xb6, 0xd2, 0xb5, 0x35,
-	0xae, 0x9f, 0x4b, 0xb4, 0x56, 0x6d, 0x30, 0x58, 0x27, 0x67, 0xfb, 0x89, 0xee, 0x50, 0xd3, 0x56,
-	0xbe, 0xa1, 0x5a, 0x23, 0xdb, 0x90, 0xce, 0xbe, 0x22, 0x11, 0x2f, 0xbb, 0x52, 0xc9, 0x89, 0x18,
-	0xbf, 0x63, 0x9b, 0x46, 0xd3, 0x68, 0x7e, 0x54, 0xf8, 0xdf, 0xe4, 0x4a, 0x1c, 0x5a, 0xd4, 0x0d,
-	0x1b, 0xd7, 0xa6, 0xa3, 0x69, 0x34, 0x3f, 0xce, 0x4f, 0xe1, 0x57, 0xe7, 0x60, 0x86, 0xde, 0x0c,
-	0xf7, 0xbb, 0x6c, 0xf0, 0x15, 0x03, 0x9b, 0x5c, 0x88, 0x38, 0xdc, 0x9a, 0x8e, 0x3b, 0xcb, 0x6c,
-	0x8f, 0x25, 0x2c, 0x82, 0x95, 0x5f, 0x74, 0x13, 0x06, 0x15, 0x3b, 0xea, 0xf2, 0x41, 0xcc, 0x34,
-	0x95, 0xf0, 0xff, 0x73, 0xdd, 0x46, 0x4f, 0x07, 0xfe, 0xfb, 0x3d, 0xca, 0x1e, 0xf3, 0x42, 0xb5,
-	0xb0, 0xf4, 0xe1, 0xd5, 0x10, 0xbe, 0xee, 0xc3, 0x77, 0x8d, 0xd1, 0x2f, 0x71, 0xd7, 0x7c, 0xf1,
-	0x13, 0x00, 0x00, 0xff, 0xff, 0xd0, 0x05, 0x0f, 0xf6, 0xbd, 0x01, 0x00, 0x00,
+	// 399 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0xc1, 0x6e, 0xd4, 0x30,
+	0x10, 0x86, 0x95, 0xee, 0xb2, 0x14, 0x17, 0x24, 0x64, 0x0a, 0x84, 0x9e, 0xc2, 0x1e, 0xaa, 0x08,
+	0x21, 0xbb, 0x4a, 0x39, 0x71, 0xe0, 0xc0, 0x4a, 0x48, 0x1c, 0x90, 0x4a, 0x76, 0x01, 0x89, 0x4b,
+	0x64, 0x9c, 0xa1, 0x58, 0xc4, 0x9e, 0x60, 0x3b, 0x6d, 0xd3, 0x77, 0xe1, 0x05, 0x78, 0x4a, 0x14,
+	0xbb, 0x49, 0x4b, 0xb5, 0x12, 0xa7, 0x44, 0xfe, 0xbf, 0xef, 0xcf, 0x8c, 0x62, 0x72, 0x7c, 0x56,
+	0x58, 0xd1, 0x33, 0x89, 0x9a, 0x4b, 0xb4, 0xc0, 0xbd, 0x15, 0xc6, 0xb5, 0x68, 0x3d, 0x57, 0xc6,
+	0x83, 0x35, 0xe0, 0xf9, 0xaf, 0x4e, 0x49, 0x2e, 0xd1, 0x7c, 0x57, 0xa7, 0xac, 0xb5, 0xe8, 0x91,
+	0x2e, 0x47, 0xc9, 0x02, 0x9b, 0x04, 0x36, 0x0a, 0x6c, 0x10, 0x0e, 0x8e, 0x6e, 0x15, 0x4b, 0xd4,
+	0x1a, 0x0d, 0x77, 0x60, 0x95, 0x68, 0xb8, 0xef, 0x5b, 0xa8, 0x2b, 0x0d, 0xce, 0x89, 0x53, 0x88,
+	0xad, 0x07, 0x2f, 0xb7, 0x1b, 0x21, 0x94, 0xd8, 0xf0, 0x1f, 0x20, 0x6a, 0xb0, 0x2e, 0xd2, 0xcb,
+	0xdf, 0x33, 0xb2, 0x58, 0x85, 0xa1, 0xe8, 0x43, 0x32, 0xfb, 0x09, 0x7d, 0x9a, 0x64, 0x49, 0x7e,
+	0xaf, 0x1c, 0x5e, 0xe9, 0x3b, 0xb2, 0xeb, 0x40, 0x76, 0x56, 0xf9, 0x3e, 0xdd, 0xc9, 0x92, 0x7c,
+	0xaf, 0x78, 0xc1, 0x6e, 0xcc, 0x1c, 0x9b, 0xd9, 0xd8, 0xcc, 0xd6, 0x57, 0x6c, 0xec, 0x2b, 0x27,
+	0x97, 0xbe, 0x21, 0x8b, 0xf8, 0xd5, 0x74, 0x16, 0x5a, 0x0e, 0xb7, 0xb4, 0xc4, 0x8d, 0xd8, 0x66,
+	0xd8, 0xe8, 0x43, 0x5c, 0xa8, 0xbc, 0xb2, 0xe8, 0x73, 0x72, 0x5f, 0xd5, 0x0d, 0x54, 0x5e, 0x69,
+	0xc0, 0xce, 0xa7, 0xf3, 0x2c, 0xc9, 0x1f, 0x94, 0x7b, 0xc3, 0xd9, 0x26, 0x1e, 0xd1, 0x57, 0xe4,
+	0x89, 0x16, 0x17, 0x95, 0x44, 0x23, 0x3b, 0x6b, 0xc1, 0xf8, 0xca, 0x79, 0x0b, 0x42, 0xbb, 0xf4,
+	0x4e, 0x80, 0xf7, 0xb5, 0xb8, 0x58, 0x4d, 0xe1, 0x3a, 0x66, 0xb4, 0x20, 0x8f, 0x23, 0x56, 0x59,
+	0x90, 0xa0, 0xce, 0xa0, 0x3a, 0x57, 0xa6, 0xc6, 0xf3, 0x74, 0x91, 0x25, 0xf9, 0xbc, 0x7c, 0x14,
+	0xc3, 0x32, 0x66, 0x5f, 0x42, 0x44, 0x5f, 0x93, 0x67, 0x12, 0x8d, 0x01, 0xe9, 0x15, 0x9a, 0xdb,
+	0xde, 0xdd, 0xe0, 0x3d, 0xbd, 0x06, 0xfe, 0x75, 0x8f, 0xc8, 0xfe, 0x0d, 0xb7, 0x45, 0x6c, 0x2a,
+	0xa7, 0x2e, 0x21, 0xdd, 0x0d, 0x33, 0xd2, 0xeb, 0xec, 0x04, 0xb1, 0x59, 0xab, 0x4b, 0x78, 0xfb,
+	0x89, 0x1c, 0x4a, 0xd4, 0xec, 0xff, 0x37, 0xe5, 0x24, 0xf9, 0x3a, 0x1f, 0x9e, 0x7f, 0x76, 0x96,
+	0x9f, 0x8b, 0x52, 0xf4, 0x6c, 0x35, 0xc0, 0x9b, 0x09, 0x7e, 0x3f, 0xc2, 0x1f, 0x3b, 0x25, 0xbf,
+	0x2d, 0xc2, 0x4f, 0x3b, 0xfe, 0x3b, 0x00, 0xf9, 0xfb, 0x89, 0x2c, 0xb8, 0x02, 0x00, 0x00,
 }
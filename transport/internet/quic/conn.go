@@ -6,6 +6,7 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	"v2ray.com/core/common"
@@ -145,6 +146,9 @@ type interConn struct {
 	stream quic.Stream
 	local  net.Addr
 	remote net.Addr
+	// onClose is called once when the connection is closed.
+	onClose func()
+	closed  int32
 }
 
 func (c *interConn) Read(b []byte) (int, error) {
@@ -163,6 +167,9 @@ func (c *interConn) Write(b []byte) (int, error) {
 }
 
 func (c *interConn) Close() error {
+	if c.onClose != nil && atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		c.onClose()
+	}
 	return c.stream.Close()
 }
 
@@ -4,7 +4,9 @@ package quic
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"v2ray.com/core/common"
@@ -18,24 +20,36 @@ import (
 type sessionContext struct {
 	rawConn *sysConn
 	session quic.Session
+	// streams is the number of open streams in this session.
+	streams int32
 }
 
-var errSessionClosed = newError("session closed")
+var (
+	errSessionClosed  = newError("session closed")
+	errTooManyStreams = newError("too many streams")
+)
 
-func (c *sessionContext) openStream(destAddr net.Addr) (*interConn, error) {
+func (c *sessionContext) openStream(destAddr net.Addr, maxStreams int) (*interConn, error) {
 	if !isActive(c.session) {
 		return nil, errSessionClosed
 	}
+	if int(atomic.LoadInt32(&c.streams)) >= maxStreams {
+		return nil, errTooManyStreams
+	}
 
 	stream, err := c.session.OpenStream()
 	if err != nil {
 		return nil, err
 	}
 
+	atomic.AddInt32(&c.streams, 1)
 	conn := &interConn{
 		stream: stream,
 		local:  c.session.LocalAddr(),
 		remote: destAddr,
+		onClose: func() {
+			atomic.AddInt32(&c.streams, -1)
+		},
 	}
 
 	return conn, nil
@@ -78,13 +92,21 @@ func removeInactiveSessions(sessions []*sessionContext) []*sessionContext {
 	return sessions
 }
 
-func openStream(sessions []*sessionContext, destAddr net.Addr) *interConn {
-	for _, s := range sessions {
+// openStream opens a stream in the session with the fewest open streams, or returns nil if all sessions
+// have maxStreams open streams.
+func openStream(sessions []*sessionContext, destAddr net.Addr, maxStreams int) *interConn {
+	sorted := make([]*sessionContext, len(sessions))
+	copy(sorted, sessions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return atomic.LoadInt32(&sorted[i].streams) < atomic.LoadInt32(&sorted[j].streams)
+	})
+
+	for _, s := range sorted {
 		if !isActive(s.session) {
 			continue
 		}
 
-		conn, err := s.openStream(destAddr)
+		conn, err := s.openStream(destAddr, maxStreams)
 		if err != nil {
 			continue
 		}
@@ -131,15 +153,17 @@ func (s *clientSessions) openConnection(destAddr net.Addr, config *Config, tlsCo
 		sessions = s
 	}
 
-	if true {
-		conn := openStream(sessions, destAddr)
+	sessions = removeInactiveSessions(sessions)
+
+	// Open new sessions until the pool is full, or when all sessions reach the stream limit.
+	maxStreams := config.getMaxConcurrentStreams()
+	if len(sessions) >= config.getConnectionPoolSize() {
+		conn := openStream(sessions, destAddr, maxStreams)
 		if conn != nil {
 			return conn, nil
 		}
 	}
 
-	sessions = removeInactiveSessions(sessions)
-
 	rawConn, err := internet.ListenSystemPacket(context.Background(), &net.UDPAddr{
 		IP:   []byte{0, 0, 0, 0},
 		Port: 0,
@@ -148,11 +172,7 @@ func (s *clientSessions) openConnection(destAddr net.Addr, config *Config, tlsCo
 		return nil, err
 	}
 
-	quicConfig := &quic.Config{
-		ConnectionIDLength: 12,
-		HandshakeTimeout:   time.Second * 8,
-		IdleTimeout:        time.Second * 30,
-	}
+	quicConfig := config.getQUICConfig(false)
 
 	conn, err := wrapSysConn(rawConn, config)
 	if err != nil {
@@ -171,7 +191,7 @@ func (s *clientSessions) openConnection(destAddr net.Addr, config *Config, tlsCo
 		rawConn: conn,
 	}
 	s.sessions[dest] = append(sessions, context)
-	return context.openStream(destAddr)
+	return context.openStream(destAddr, maxStreams)
 }
 
 var client clientSessions
@@ -102,13 +102,7 @@ func Listen(ctx context.Context, address net.Address, port net.Port, streamSetti
 		return nil, err
 	}
 
-	quicConfig := &quic.Config{
-		ConnectionIDLength:    12,
-		HandshakeTimeout:      time.Second * 8,
-		IdleTimeout:           time.Second * 45,
-		MaxIncomingStreams:    32,
-		MaxIncomingUniStreams: -1,
-	}
+	quicConfig := config.getQUICConfig(true)
 
 	conn, err := wrapSysConn(rawConn, config)
 	if err != nil {
@@ -116,7 +110,7 @@ func Listen(ctx context.Context, address net.Address, port net.Port, streamSetti
 		return nil, err
 	}
 
-	qListener, err := quic.Listen(conn, tlsConfig.GetTLSConfig(), quicConfig)
+	qListener, err := quic.Listen(conn, tlsConfig.GetTLSConfig(tls.WithContext(ctx)), quicConfig)
 	if err != nil {
 		conn.Close()
 		return nil, err
@@ -3,6 +3,7 @@ package quic_test
 import (
 	"context"
 	"crypto/rand"
+	"io"
 	"testing"
 	"time"
 
@@ -215,3 +216,128 @@ func TestQuicConnectionAuthHeader(t *testing.T) {
 		t.Error(r)
 	}
 }
+
+func TestQuicConnectionPool(t *testing.T) {
+	port := udp.PickPort()
+
+	config := &quic.Config{
+		IdleTimeout:             10,
+		MaxConcurrentStreams:    4,
+		StreamReceiveWindow:     512 * 1024,
+		ConnectionReceiveWindow: 1024 * 1024,
+		ConnectionPoolSize:      2,
+	}
+	sources := make(chan string, 4)
+	listener, err := quic.Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "quic",
+		ProtocolSettings: config,
+	}, func(conn internet.Connection) {
+		sources <- conn.RemoteAddr().String()
+		go func() {
+			defer conn.Close()
+
+			b := buf.New()
+			defer b.Release()
+
+			for {
+				b.Clear()
+				if _, err := b.ReadFrom(conn); err != nil {
+					return
+				}
+				common.Must2(conn.Write(b.Bytes()))
+			}
+		}()
+	})
+	common.Must(err)
+
+	defer listener.Close()
+
+	time.Sleep(time.Second)
+
+	for i := 0; i < 4; i++ {
+		conn, err := quic.Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+			ProtocolName:     "quic",
+			ProtocolSettings: config,
+		})
+		common.Must(err)
+		defer conn.Close()
+
+		const N = 1024
+		b1 := make([]byte, N)
+		common.Must2(rand.Read(b1))
+		common.Must2(conn.Write(b1))
+
+		b2 := buf.New()
+		common.Must2(b2.ReadFullFrom(conn, N))
+		if r := cmp.Diff(b2.Bytes(), b1); r != "" {
+			t.Error(r)
+		}
+		b2.Release()
+	}
+
+	// Streams are spread evenly across two connections.
+	count := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		count[<-sources]++
+	}
+	if len(count) != 2 {
+		t.Error("unexpected number of connections: ", count)
+	}
+	for source, n := range count {
+		if n != 2 {
+			t.Error("connection from ", source, " has ", n, " streams")
+		}
+	}
+}
+
+func TestQuicConnectionStreamLimit(t *testing.T) {
+	port := udp.PickPort()
+
+	// The server accepts more streams than the client opens on one connection.
+	config := &quic.Config{
+		MaxConcurrentStreams: 2,
+	}
+	sources := make(chan string, 4)
+	listener, err := quic.Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "quic",
+		ProtocolSettings: &quic.Config{},
+	}, func(conn internet.Connection) {
+		sources <- conn.RemoteAddr().String()
+		go func() {
+			defer conn.Close()
+			io.Copy(conn, conn) // nolint: errcheck
+		}()
+	})
+	common.Must(err)
+	defer listener.Close()
+
+	time.Sleep(time.Second)
+
+	for i := 0; i < 4; i++ {
+		conn, err := quic.Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+			ProtocolName:     "quic",
+			ProtocolSettings: config,
+		})
+		common.Must(err)
+		defer conn.Close()
+
+		// Streams are only accepted by the server after data is sent.
+		common.Must2(conn.Write([]byte("test")))
+		b := make([]byte, 4)
+		common.Must2(io.ReadFull(conn, b))
+	}
+
+	// The client opens another connection when the only one in the pool has two streams.
+	count := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		count[<-sources]++
+	}
+	if len(count) != 2 {
+		t.Error("unexpected number of connections: ", count)
+	}
+	for source, n := range count {
+		if n != 2 {
+			t.Error("connection from ", source, " has ", n, " streams")
+		}
+	}
+}
@@ -95,6 +95,15 @@ func (d *DefaultSystemDialer) Dial(ctx context.Context, src net.Address, dest ne
 		}
 	}
 
+	if dest.Network == net.Network_TCP && sockopt != nil && sockopt.HappyEyeballs != nil {
+		if ips := happyEyeballsIPs(ctx, src, dest); ips != nil {
+			config := sockopt.HappyEyeballs
+			return raceDial(ctx, config.SortIPs(ips), config.GetTryDelay(), func(ctx context.Context, ip net.IP) (net.Conn, error) {
+				return dialer.DialContext(ctx, "tcp", net.TCPDestination(net.IPAddress(ip), dest.Port).NetAddr())
+			})
+		}
+	}
+
 	return dialer.DialContext(ctx, dest.Network.SystemString(), dest.NetAddr())
 }
 
@@ -43,7 +43,14 @@ func (dl *DefaultListener) Listen(ctx context.Context, addr net.Addr, sockopt *S
 		lc.Control = getControlFunc(ctx, sockopt, dl.contollers)
 	}
 
-	return lc.Listen(ctx, addr.Network(), addr.String())
+	l, err := lc.Listen(ctx, addr.Network(), addr.String())
+	if err != nil {
+		return nil, err
+	}
+	if sockopt != nil && sockopt.AcceptProxyProtocol {
+		l = &proxyProtocolListener{Listener: l}
+	}
+	return l, nil
 }
 
 func (dl *DefaultListener) ListenPacket(ctx context.Context, addr net.Addr, sockopt *SocketConfig) (net.PacketConn, error) {
@@ -2,6 +2,7 @@ package internet_test
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"testing"
 
@@ -27,3 +28,24 @@ func TestRegisterListenerController(t *testing.T) {
 		t.Error("expected none-zero fd, but actually 0")
 	}
 }
+
+func TestUnwrapConnection(t *testing.T) {
+	listener, err := internet.ListenSystem(context.Background(), &net.TCPAddr{
+		IP: net.IPv4(127, 0, 0, 1),
+	}, &internet.SocketConfig{AcceptProxyProtocol: true})
+	common.Must(err)
+	defer listener.Close()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	common.Must(err)
+	defer client.Close()
+
+	conn, err := listener.Accept()
+	common.Must(err)
+	defer conn.Close()
+
+	tlsConn := tls.Server(conn, &tls.Config{})
+	if _, ok := internet.UnwrapConnection(tlsConn).(*net.TCPConn); !ok {
+		t.Error("expect TCP connection under TLS and PROXY protocol")
+	}
+}
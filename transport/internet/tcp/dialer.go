@@ -22,7 +22,14 @@ func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.Me
 
 	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
 		tlsConfig := config.GetTLSConfig(tls.WithDestination(dest), tls.WithNextProto("h2"))
-		if config.IsExperiment8357() {
+		if fingerprint := tls.GetFingerprint(config.Fingerprint); fingerprint != nil {
+			uConn, err := tls.FingerprintClient(conn, tlsConfig, fingerprint)
+			if err != nil {
+				conn.Close()
+				return nil, err
+			}
+			conn = uConn
+		} else if config.IsExperiment8357() {
 			conn = tls.UClient(conn, tlsConfig)
 		} else {
 			conn = tls.Client(conn, tlsConfig)
@@ -43,7 +43,7 @@ func ListenTCP(ctx context.Context, address net.Address, port net.Port, streamSe
 	}
 
 	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
-		l.tlsConfig = config.GetTLSConfig(tls.WithNextProto("h2"))
+		l.tlsConfig = config.GetTLSConfig(tls.WithNextProto("h2"), tls.WithContext(ctx))
 	}
 
 	if tcpSettings.HeaderSettings != nil {
@@ -0,0 +1,154 @@
+// +build !confonly
+
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common/net"
+)
+
+const acmeChallengePath = "/.well-known/acme-challenge/"
+
+// acmeManager obtains and renews certificates of the configured domains. Certificates are renewed in
+// background before they expire, as long as the manager is in use.
+type acmeManager struct {
+	*autocert.Manager
+	domains     []string
+	httpHandler http.Handler
+}
+
+// acmeKey identifies the ACME managers of a V2Ray instance.
+type acmeKey struct {
+	instance *core.Instance
+	config   string
+}
+
+var (
+	acmeAccess   sync.Mutex
+	acmeManagers = make(map[acmeKey]*acmeManager)
+)
+
+// getACMEManager returns the ACME manager of the config in the V2Ray instance of ctx. Managers are shared by
+// listeners of the instance with the same config, so that each certificate is obtained only once. A new manager
+// starts obtaining certificates right away.
+func getACMEManager(ctx context.Context, c *AcmeConfig) *acmeManager {
+	acmeAccess.Lock()
+	defer acmeAccess.Unlock()
+
+	key := acmeKey{
+		instance: core.FromContext(ctx),
+		config:   c.String(),
+	}
+	if m, found := acmeManagers[key]; found {
+		return m
+	}
+
+	directoryURL := c.DirectoryUrl
+	if len(directoryURL) == 0 {
+		directoryURL = acme.LetsEncryptURL
+	}
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(c.Domain...),
+		Email:      c.Email,
+		Client: &acme.Client{
+			DirectoryURL: directoryURL,
+			HTTPClient:   newHTTPClient(ctx),
+			UserAgent:    "V2Ray/" + core.Version(),
+		},
+	}
+	if len(c.StoragePath) > 0 {
+		manager.Cache = autocert.DirCache(c.StoragePath)
+	}
+	if c.RenewBeforeDays > 0 {
+		manager.RenewBefore = time.Duration(c.RenewBeforeDays) * 24 * time.Hour
+	}
+
+	m := &acmeManager{
+		Manager: manager,
+		domains: c.Domain,
+		// Creating the handler enables HTTP-01 challenges, in addition to TLS-ALPN-01.
+		httpHandler: manager.HTTPHandler(http.NotFoundHandler()),
+	}
+	acmeManagers[key] = m
+	go m.obtainCertificates()
+	return m
+}
+
+// obtainCertificates loads or obtains the certificates of all domains. Certificates that fail are obtained again
+// in the first handshake for them.
+func (m *acmeManager) obtainCertificates() {
+	for _, domain := range m.domains {
+		// Most clients support ECDSA, so the ECDSA certificate is obtained.
+		hello := &tls.ClientHelloInfo{
+			ServerName:   domain,
+			CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+		}
+		if _, err := m.Manager.GetCertificate(hello); err != nil {
+			newError("failed to obtain certificate for ", domain).Base(err).AtWarning().WriteToLog()
+		}
+	}
+}
+
+func (m *acmeManager) hasDomain(domain string) bool {
+	for _, d := range m.domains {
+		if strings.EqualFold(d, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func findACMEManager(ctx context.Context, domain string) *acmeManager {
+	acmeAccess.Lock()
+	defer acmeAccess.Unlock()
+
+	v := core.FromContext(ctx)
+	for key, m := range acmeManagers {
+		if key.instance == v && m.hasDomain(domain) {
+			return m
+		}
+	}
+	return nil
+}
+
+// ServeACMEChallenge answers the request if it is an ACME HTTP-01 challenge for a domain managed by the V2Ray
+// instance in ctx. It returns false if the request is not such a challenge.
+func ServeACMEChallenge(ctx context.Context, writer http.ResponseWriter, request *http.Request) bool {
+	if !strings.HasPrefix(request.URL.Path, acmeChallengePath) {
+		return false
+	}
+	host := request.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	m := findACMEManager(ctx, host)
+	if m == nil {
+		return false
+	}
+
+	newError("answering ACME challenge for ", host).WriteToLog()
+	request.Host = host
+	m.httpHandler.ServeHTTP(writer, request)
+	return true
+}
+
+// GetCertificate returns the certificate of the domain in the ClientHello, obtaining it first if necessary.
+// TLS-ALPN-01 challenges are answered with the challenge certificate.
+func (m *acmeManager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	certificate, err := m.Manager.GetCertificate(hello)
+	if err != nil {
+		return nil, newError("failed to get ACME certificate for ", hello.ServerName).Base(err)
+	}
+	return certificate, nil
+}
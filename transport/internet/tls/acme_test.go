@@ -0,0 +1,419 @@
+package tls_test
+
+import (
+	"context"
+	"crypto/sha256"
+	gotls "crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/protocol/tls/cert"
+	. "v2ray.com/core/transport/internet/tls"
+)
+
+var idPeACMEIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 31}
+
+type acmeAuthorization struct {
+	domain string
+	token  string
+	status string
+}
+
+// acmeServer is a minimal ACME CA in the spirit of Pebble. Signatures of requests are not verified, and
+// challenges are validated against validateAddr instead of the address of the domain.
+type acmeServer struct {
+	*httptest.Server
+	ca            *cert.Certificate
+	challengeType string
+	validateAddr  string
+
+	sync.Mutex
+	nonce      int
+	thumbprint string
+	authzs     []*acmeAuthorization
+	certPEM    []byte
+}
+
+func newACMEServer(challengeType string) *acmeServer {
+	s := &acmeServer{
+		ca:            cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign|x509.KeyUsageDigitalSignature)),
+		challengeType: challengeType,
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+func (s *acmeServer) CertPool() *x509.CertPool {
+	caCert, err := x509.ParseCertificate(s.ca.Certificate)
+	common.Must(err)
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return pool
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	common.Must(json.NewEncoder(w).Encode(v))
+}
+
+func decodeBase64(s string) []byte {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	common.Must(err)
+	return b
+}
+
+func (s *acmeServer) orderStatus() string {
+	if s.certPEM != nil {
+		return "valid"
+	}
+	for _, authz := range s.authzs {
+		if authz.status != "valid" {
+			return "pending"
+		}
+	}
+	return "ready"
+}
+
+func (s *acmeServer) order() interface{} {
+	var identifiers []interface{}
+	var authorizations []string
+	for idx, authz := range s.authzs {
+		identifiers = append(identifiers, map[string]string{"type": "dns", "value": authz.domain})
+		authorizations = append(authorizations, s.URL+"/authz/"+strconv.Itoa(idx))
+	}
+	order := map[string]interface{}{
+		"status":         s.orderStatus(),
+		"identifiers":    identifiers,
+		"authorizations": authorizations,
+		"finalize":       s.URL + "/finalize",
+	}
+	if s.certPEM != nil {
+		order["certificate"] = s.URL + "/cert"
+	}
+	return order
+}
+
+func (s *acmeServer) authorization(idx int) interface{} {
+	authz := s.authzs[idx]
+	return map[string]interface{}{
+		"status":     authz.status,
+		"identifier": map[string]string{"type": "dns", "value": authz.domain},
+		"challenges": []interface{}{s.challenge(idx)},
+	}
+}
+
+func (s *acmeServer) challenge(idx int) interface{} {
+	authz := s.authzs[idx]
+	return map[string]string{
+		"type":   s.challengeType,
+		"url":    s.URL + "/challenge/" + strconv.Itoa(idx),
+		"token":  authz.token,
+		"status": authz.status,
+	}
+}
+
+func (s *acmeServer) validate(domain string, keyAuth string) error {
+	switch s.challengeType {
+	case "http-01":
+		request, err := http.NewRequest("GET", "http://"+s.validateAddr+"/.well-known/acme-challenge/"+strings.Split(keyAuth, ".")[0], nil)
+		common.Must(err)
+		request.Host = domain
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			return err
+		}
+		if string(body) != keyAuth {
+			return fmt.Errorf("unexpected key authorization: %s", body)
+		}
+	case "tls-alpn-01":
+		conn, err := gotls.Dial("tcp", s.validateAddr, &gotls.Config{
+			ServerName:         domain,
+			NextProtos:         []string{"acme-tls/1"},
+			InsecureSkipVerify: true,
+		})
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		state := conn.ConnectionState()
+		if state.NegotiatedProtocol != "acme-tls/1" {
+			return fmt.Errorf("unexpected ALPN: %s", state.NegotiatedProtocol)
+		}
+		hash := sha256.Sum256([]byte(keyAuth))
+		expected, err := asn1.Marshal(hash[:])
+		common.Must(err)
+		for _, ext := range state.PeerCertificates[0].Extensions {
+			if ext.Id.Equal(idPeACMEIdentifier) && string(ext.Value) == string(expected) {
+				return nil
+			}
+		}
+		return fmt.Errorf("no valid acmeIdentifier extension")
+	}
+	return nil
+}
+
+func (s *acmeServer) issue(csrDER []byte) []byte {
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	common.Must(err)
+	caCert, err := x509.ParseCertificate(s.ca.Certificate)
+	common.Must(err)
+	caKey, err := x509.ParsePKCS1PrivateKey(s.ca.PrivateKey)
+	common.Must(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		DNSNames:     csr.DNSNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour * 24 * 90),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	leaf, err := x509.CreateCertificate(nil, template, caCert, csr.PublicKey, caKey)
+	common.Must(err)
+	return append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.ca.Certificate})...)
+}
+
+func (s *acmeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	s.nonce++
+	w.Header().Set("Replay-Nonce", "nonce-"+strconv.Itoa(s.nonce))
+	s.Unlock()
+
+	switch r.URL.Path {
+	case "/directory":
+		writeJSON(w, http.StatusOK, map[string]string{
+			"newNonce":   s.URL + "/new-nonce",
+			"newAccount": s.URL + "/new-account",
+			"newOrder":   s.URL + "/new-order",
+			"revokeCert": s.URL + "/revoke-cert",
+			"keyChange":  s.URL + "/key-change",
+		})
+		return
+	case "/new-nonce":
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var jws struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+	}
+	common.Must(json.NewDecoder(r.Body).Decode(&jws))
+	var protected struct {
+		JWK struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"jwk"`
+	}
+	common.Must(json.Unmarshal(decodeBase64(jws.Protected), &protected))
+	payload := decodeBase64(jws.Payload)
+
+	s.Lock()
+	defer s.Unlock()
+
+	switch path := r.URL.Path; {
+	case path == "/new-account":
+		jwk := protected.JWK
+		thumbprint := sha256.Sum256([]byte(fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q,"y":%q}`, jwk.Crv, jwk.Kty, jwk.X, jwk.Y)))
+		s.thumbprint = base64.RawURLEncoding.EncodeToString(thumbprint[:])
+		w.Header().Set("Location", s.URL+"/account")
+		writeJSON(w, http.StatusCreated, map[string]string{"status": "valid"})
+	case path == "/new-order":
+		var request struct {
+			Identifiers []struct {
+				Value string `json:"value"`
+			} `json:"identifiers"`
+		}
+		common.Must(json.Unmarshal(payload, &request))
+		s.authzs = nil
+		s.certPEM = nil
+		for _, id := range request.Identifiers {
+			s.authzs = append(s.authzs, &acmeAuthorization{
+				domain: id.Value,
+				token:  base64.RawURLEncoding.EncodeToString([]byte(id.Value + time.Now().String())),
+				status: "pending",
+			})
+		}
+		w.Header().Set("Location", s.URL+"/order")
+		writeJSON(w, http.StatusCreated, s.order())
+	case path == "/order":
+		w.Header().Set("Location", s.URL+"/order")
+		writeJSON(w, http.StatusOK, s.order())
+	case strings.HasPrefix(path, "/authz/"):
+		idx, err := strconv.Atoi(strings.TrimPrefix(path, "/authz/"))
+		common.Must(err)
+		writeJSON(w, http.StatusOK, s.authorization(idx))
+	case strings.HasPrefix(path, "/challenge/"):
+		idx, err := strconv.Atoi(strings.TrimPrefix(path, "/challenge/"))
+		common.Must(err)
+		authz := s.authzs[idx]
+		s.Unlock()
+		err = s.validate(authz.domain, authz.token+"."+s.thumbprint)
+		s.Lock()
+		if err != nil {
+			authz.status = "invalid"
+		} else {
+			authz.status = "valid"
+		}
+		writeJSON(w, http.StatusOK, s.challenge(idx))
+	case path == "/finalize":
+		var request struct {
+			CSR string `json:"csr"`
+		}
+		common.Must(json.Unmarshal(payload, &request))
+		s.certPEM = s.issue(decodeBase64(request.CSR))
+		w.Header().Set("Location", s.URL+"/order")
+		writeJSON(w, http.StatusOK, s.order())
+	case path == "/cert":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		w.Write(s.certPEM)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func dialACMEServer(t *testing.T, addr string, domain string, roots *x509.CertPool) {
+	conn, err := gotls.Dial("tcp", addr, &gotls.Config{
+		ServerName: domain,
+		RootCAs:    roots,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	common.Must2(conn.Write([]byte("test")))
+	b := make([]byte, 4)
+	common.Must2(io.ReadFull(conn, b))
+	if string(b) != "test" {
+		t.Error("unexpected response: ", string(b))
+	}
+}
+
+// waitForCertificate waits until the certificate of the domain is in the storage directory.
+func waitForCertificate(t *testing.T, dir string, domain string) {
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(filepath.Join(dir, domain)); err == nil {
+			return
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+	t.Fatal("certificate of ", domain, " is not obtained")
+}
+
+func TestACMETLSALPNChallenge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-acme")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+
+	ca := newACMEServer("tls-alpn-01")
+	defer ca.Close()
+
+	// The listener is up before the TLS config is built, as certificates are obtained right away.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	ca.validateAddr = listener.Addr().String()
+
+	config := &Config{
+		Acme: &AcmeConfig{
+			Domain:       []string{"alpn.v2ray.com"},
+			DirectoryUrl: ca.URL + "/directory",
+			StoragePath:  dir,
+		},
+	}
+	addr, closeServer := serveEcho(gotls.NewListener(listener, config.GetTLSConfig()))
+	defer closeServer()
+
+	waitForCertificate(t, dir, "alpn.v2ray.com")
+	dialACMEServer(t, addr, "alpn.v2ray.com", ca.CertPool())
+}
+
+func TestACMEHTTPChallenge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-acme")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+
+	ca := newACMEServer("http-01")
+	defer ca.Close()
+
+	inbound := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ServeACMEChallenge(context.Background(), w, r) {
+			http.NotFound(w, r)
+		}
+	}))
+	defer inbound.Close()
+	ca.validateAddr = inbound.Listener.Addr().String()
+
+	config := &Config{
+		Acme: &AcmeConfig{
+			Domain:       []string{"http.v2ray.com"},
+			DirectoryUrl: ca.URL + "/directory",
+			StoragePath:  dir,
+		},
+	}
+	addr, closeServer := startTLSServer(config.GetTLSConfig())
+	defer closeServer()
+
+	waitForCertificate(t, dir, "http.v2ray.com")
+	dialACMEServer(t, addr, "http.v2ray.com", ca.CertPool())
+
+	// Certificates are loaded from the storage after restart.
+	ca.Close()
+	restarted := &Config{
+		Acme: &AcmeConfig{
+			Domain:       []string{"http.v2ray.com"},
+			DirectoryUrl: ca.URL + "/directory",
+			StoragePath:  dir,
+			Email:        "restarted@v2ray.com",
+		},
+	}
+	addr, closeRestarted := startTLSServer(restarted.GetTLSConfig())
+	defer closeRestarted()
+
+	dialACMEServer(t, addr, "http.v2ray.com", ca.CertPool())
+}
+
+func TestACMEChallengeOfOtherInstance(t *testing.T) {
+	config := &Config{
+		Acme: &AcmeConfig{
+			Domain:       []string{"instance.v2ray.com"},
+			DirectoryUrl: "http://127.0.0.1:0/directory",
+		},
+	}
+	config.GetTLSConfig(WithContext(core.ToContext(context.Background(), new(core.Instance))))
+
+	for _, domain := range []string{"unknown.v2ray.com", "instance.v2ray.com"} {
+		request := httptest.NewRequest("GET", "http://"+domain+"/.well-known/acme-challenge/token", nil)
+		if ServeACMEChallenge(context.Background(), httptest.NewRecorder(), request) {
+			t.Error("expect no response for ", domain)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// +build !confonly
+
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/platform/filesystem"
+	"v2ray.com/core/transport/internet"
+)
+
+const (
+	// Interval to check certificate files for changes.
+	certificateCheckInterval = time.Second
+	// Interval to retry fetching OCSP responses after a failure.
+	ocspRetryInterval = time.Minute
+	// Max size of OCSP responses.
+	ocspMaxResponseSize = 1024 * 1024
+)
+
+// certificateFile is a certificate loaded from files, which are reloaded when they change. Handshakes in
+// progress keep the certificate they got, so connections are not affected by reloading.
+type certificateFile struct {
+	sync.Mutex
+	ctx       context.Context
+	certPath  string
+	keyPath   string
+	ocspPath  string
+	fetchOCSP bool
+
+	certificate *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	ocspModTime time.Time
+	checkTime   time.Time
+
+	ocspNextFetch time.Time
+	ocspFetching  bool
+}
+
+func newCertificateFile(ctx context.Context, c *Certificate) *certificateFile {
+	keyPath := c.KeyPath
+	if len(keyPath) == 0 {
+		keyPath = c.CertificatePath
+	}
+	return &certificateFile{
+		ctx:       ctx,
+		certPath:  c.CertificatePath,
+		keyPath:   keyPath,
+		ocspPath:  c.OcspResponsePath,
+		fetchOCSP: c.OcspFetch,
+	}
+}
+
+func modTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// Get returns the latest certificate, and starts fetching its OCSP response if necessary.
+func (f *certificateFile) Get() (*tls.Certificate, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	now := time.Now()
+	if f.certificate == nil || now.Sub(f.checkTime) >= certificateCheckInterval {
+		f.checkTime = now
+		if err := f.reload(); err != nil {
+			if f.certificate == nil {
+				return nil, err
+			}
+			newError("failed to reload certificate ", f.certPath).Base(err).AtWarning().WriteToLog()
+		}
+	}
+
+	if f.fetchOCSP && !f.ocspFetching && !now.Before(f.ocspNextFetch) {
+		f.ocspFetching = true
+		go f.updateOCSP(f.certificate)
+	}
+	return f.certificate, nil
+}
+
+func (f *certificateFile) reload() error {
+	certModTime, err := modTime(f.certPath)
+	if err != nil {
+		return err
+	}
+	keyModTime, err := modTime(f.keyPath)
+	if err != nil {
+		return err
+	}
+
+	if f.certificate == nil || !certModTime.Equal(f.certModTime) || !keyModTime.Equal(f.keyModTime) {
+		certPEM, err := filesystem.ReadFile(f.certPath)
+		if err != nil {
+			return err
+		}
+		keyPEM, err := filesystem.ReadFile(f.keyPath)
+		if err != nil {
+			return err
+		}
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return newError("failed to parse X509 key pair").Base(err)
+		}
+		certificate.Leaf, err = x509.ParseCertificate(certificate.Certificate[0])
+		if err != nil {
+			return newError("failed to parse certificate").Base(err)
+		}
+
+		if f.certificate != nil {
+			newError("certificate ", f.certPath, " reloaded").AtInfo().WriteToLog()
+		}
+		f.certificate = &certificate
+		f.certModTime = certModTime
+		f.keyModTime = keyModTime
+		// OCSP response of the previous certificate does not apply any more.
+		f.ocspModTime = time.Time{}
+		f.ocspNextFetch = time.Time{}
+	}
+
+	if len(f.ocspPath) > 0 {
+		ocspModTime, err := modTime(f.ocspPath)
+		if err == nil && !ocspModTime.Equal(f.ocspModTime) {
+			f.ocspModTime = ocspModTime
+			if response, err := filesystem.ReadFile(f.ocspPath); err != nil {
+				newError("failed to read OCSP response ", f.ocspPath).Base(err).AtWarning().WriteToLog()
+			} else if err := f.staple(response); err != nil {
+				newError("failed to staple OCSP response ", f.ocspPath).Base(err).AtWarning().WriteToLog()
+			}
+		}
+	}
+
+	return nil
+}
+
+func getIssuer(certificate *tls.Certificate) *x509.Certificate {
+	if len(certificate.Certificate) < 2 {
+		return nil
+	}
+	issuer, err := x509.ParseCertificate(certificate.Certificate[1])
+	if err != nil {
+		return nil
+	}
+	return issuer
+}
+
+// staple sets the OCSP response of the certificate, if the response is valid for the certificate.
+func (f *certificateFile) staple(response []byte) error {
+	certificate := f.certificate
+	parsed, err := ocsp.ParseResponseForCert(response, certificate.Leaf, getIssuer(certificate))
+	if err != nil {
+		return newError("invalid OCSP response").Base(err)
+	}
+	if parsed.Status != ocsp.Good {
+		return newError("certificate status is not good: ", parsed.Status)
+	}
+	if !parsed.NextUpdate.IsZero() && parsed.NextUpdate.Before(time.Now()) {
+		return newError("OCSP response expired at ", parsed.NextUpdate)
+	}
+
+	stapled := *certificate
+	stapled.OCSPStaple = response
+	f.certificate = &stapled
+
+	// Fetch again halfway through the validity of the response.
+	if !parsed.NextUpdate.IsZero() {
+		f.ocspNextFetch = parsed.ThisUpdate.Add(parsed.NextUpdate.Sub(parsed.ThisUpdate) / 2)
+	} else {
+		f.ocspNextFetch = time.Now().Add(time.Hour)
+	}
+	return nil
+}
+
+func (f *certificateFile) updateOCSP(certificate *tls.Certificate) {
+	response, err := fetchOCSPResponse(f.ctx, certificate)
+
+	f.Lock()
+	defer f.Unlock()
+
+	f.ocspFetching = false
+	if err == nil && !bytes.Equal(f.certificate.Certificate[0], certificate.Certificate[0]) {
+		// The certificate is reloaded meanwhile, and the new one will be fetched for.
+		return
+	}
+	if err == nil {
+		err = f.staple(response)
+	}
+	if err != nil {
+		newError("failed to update OCSP response of ", f.certPath).Base(err).AtWarning().WriteToLog()
+		f.ocspNextFetch = time.Now().Add(ocspRetryInterval)
+		return
+	}
+	newError("OCSP response of ", f.certPath, " updated").AtInfo().WriteToLog()
+
+	if len(f.ocspPath) > 0 {
+		if err := ioutil.WriteFile(f.ocspPath, response, 0644); err != nil {
+			newError("failed to save OCSP response to ", f.ocspPath).Base(err).AtWarning().WriteToLog()
+		} else if t, err := modTime(f.ocspPath); err == nil {
+			f.ocspModTime = t
+		}
+	}
+}
+
+// dialOutbound dials through the outbounds of the V2Ray instance, or directly if there is no instance.
+func dialOutbound(ctx context.Context, v *core.Instance, dest net.Destination) (net.Conn, error) {
+	if v != nil {
+		return core.Dial(ctx, v, dest)
+	}
+	return internet.DialSystem(ctx, dest, nil)
+}
+
+// newHTTPClient returns an HTTP client that sends requests through the V2Ray instance in the context, if any.
+func newHTTPClient(ctx context.Context) *http.Client {
+	v := core.FromContext(ctx)
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dest, err := net.ParseDestination(network + ":" + addr)
+				if err != nil {
+					return nil, err
+				}
+				return dialOutbound(ctx, v, dest)
+			},
+		},
+		Timeout: time.Second * 30,
+	}
+}
+
+func fetchOCSPResponse(ctx context.Context, certificate *tls.Certificate) ([]byte, error) {
+	if len(certificate.Leaf.OCSPServer) == 0 {
+		return nil, newError("no OCSP server in certificate")
+	}
+	issuer := getIssuer(certificate)
+	if issuer == nil {
+		return nil, newError("no issuer in certificate chain")
+	}
+	request, err := ocsp.CreateRequest(certificate.Leaf, issuer, nil)
+	if err != nil {
+		return nil, newError("failed to create OCSP request").Base(err)
+	}
+
+	client := newHTTPClient(ctx)
+	defer client.CloseIdleConnections()
+
+	server := certificate.Leaf.OCSPServer[0]
+	resp, err := client.Post(server, "application/ocsp-request", bytes.NewReader(request))
+	if err != nil {
+		return nil, newError("failed to request OCSP server ", server).Base(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newError("unexpected status from OCSP server ", server, ": ", resp.Status)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, ocspMaxResponseSize))
+}
+
+// getFileCertificateFunc returns a function that serves certificates loaded from files, with next for
+// server names that none of the files are for.
+func getFileCertificateFunc(c *tls.Config, files []*certificateFile, next func(*tls.ClientHelloInfo) (*tls.Certificate, error)) func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		var first *tls.Certificate
+		for _, file := range files {
+			certificate, err := file.Get()
+			if err != nil {
+				newError("failed to load certificate ", file.certPath).Base(err).AtWarning().WriteToLog()
+				continue
+			}
+			if first == nil {
+				first = certificate
+			}
+			if len(hello.ServerName) == 0 || certificate.Leaf.VerifyHostname(hello.ServerName) == nil {
+				return certificate, nil
+			}
+		}
+
+		if next != nil {
+			return next(hello)
+		}
+		if len(c.Certificates) > 0 || first == nil {
+			// Let crypto/tls pick one from the static certificates.
+			return nil, nil
+		}
+		return first, nil
+	}
+}
@@ -0,0 +1,245 @@
+package tls_test
+
+import (
+	"bytes"
+	"crypto"
+	gotls "crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/protocol/tls/cert"
+	. "v2ray.com/core/transport/internet/tls"
+)
+
+func writeCertificate(certPath, keyPath string, modTime time.Time, chain ...*cert.Certificate) {
+	var certPEM []byte
+	for _, c := range chain {
+		p, _ := c.ToPEM()
+		certPEM = append(certPEM, p...)
+	}
+	_, keyPEM := chain[0].ToPEM()
+	common.Must(ioutil.WriteFile(certPath, certPEM, 0644))
+	common.Must(ioutil.WriteFile(keyPath, keyPEM, 0644))
+	common.Must(os.Chtimes(certPath, modTime, modTime))
+}
+
+func createOCSPResponse(issuer, leaf *cert.Certificate, nextUpdate time.Time) []byte {
+	issuerCert, err := x509.ParseCertificate(issuer.Certificate)
+	common.Must(err)
+	leafCert, err := x509.ParseCertificate(leaf.Certificate)
+	common.Must(err)
+	issuerKey, err := x509.ParsePKCS1PrivateKey(issuer.PrivateKey)
+	common.Must(err)
+
+	response, err := ocsp.CreateResponse(issuerCert, issuerCert, ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: leafCert.SerialNumber,
+		ThisUpdate:   time.Now().Add(-time.Minute),
+		NextUpdate:   nextUpdate,
+	}, crypto.Signer(issuerKey))
+	common.Must(err)
+	return response
+}
+
+func startTLSServer(config *gotls.Config) (string, func()) {
+	listener, err := gotls.Listen("tcp", "127.0.0.1:0", config)
+	common.Must(err)
+	return serveEcho(listener)
+}
+
+func serveEcho(listener net.Listener) (string, func()) {
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener.Addr().String(), func() { listener.Close() }
+}
+
+func dialTLSServer(addr string) *gotls.Conn {
+	conn, err := gotls.Dial("tcp", addr, &gotls.Config{
+		ServerName:         "www.v2ray.com",
+		InsecureSkipVerify: true,
+	})
+	common.Must(err)
+	return conn
+}
+
+func TestCertificateFileReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-tls")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	first := cert.MustGenerate(nil, cert.CommonName("www.v2ray.com"), cert.DNSNames("www.v2ray.com"))
+	writeCertificate(certPath, keyPath, time.Now(), first)
+
+	config := &Config{
+		Certificate: []*Certificate{{
+			CertificatePath: certPath,
+			KeyPath:         keyPath,
+		}},
+	}
+	addr, closeServer := startTLSServer(config.GetTLSConfig())
+	defer closeServer()
+
+	conn := dialTLSServer(addr)
+	defer conn.Close()
+	if c := conn.ConnectionState().PeerCertificates[0]; !bytes.Equal(c.Raw, first.Certificate) {
+		t.Fatal("unexpected certificate: ", c.Subject)
+	}
+
+	second := cert.MustGenerate(nil, cert.CommonName("www.v2ray.com"), cert.DNSNames("www.v2ray.com"))
+	writeCertificate(certPath, keyPath, time.Now().Add(time.Minute), second)
+	time.Sleep(time.Second * 2)
+
+	newConn := dialTLSServer(addr)
+	defer newConn.Close()
+	if c := newConn.ConnectionState().PeerCertificates[0]; !bytes.Equal(c.Raw, second.Certificate) {
+		t.Error("certificate is not reloaded")
+	}
+
+	// Existing connections are not affected.
+	common.Must2(conn.Write([]byte("test")))
+	b := make([]byte, 4)
+	common.Must2(io.ReadFull(conn, b))
+	if string(b) != "test" {
+		t.Error("unexpected response: ", string(b))
+	}
+}
+
+func TestOCSPStaplingFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-tls")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	ocspPath := filepath.Join(dir, "ocsp.der")
+
+	ca := cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign|x509.KeyUsageDigitalSignature))
+	leaf := cert.MustGenerate(ca, cert.CommonName("www.v2ray.com"), cert.DNSNames("www.v2ray.com"))
+	writeCertificate(certPath, keyPath, time.Now(), leaf, ca)
+	response := createOCSPResponse(ca, leaf, time.Now().Add(time.Hour))
+	common.Must(ioutil.WriteFile(ocspPath, response, 0644))
+
+	config := &Config{
+		Certificate: []*Certificate{{
+			CertificatePath:  certPath,
+			KeyPath:          keyPath,
+			OcspResponsePath: ocspPath,
+		}},
+	}
+	addr, closeServer := startTLSServer(config.GetTLSConfig())
+	defer closeServer()
+
+	conn := dialTLSServer(addr)
+	defer conn.Close()
+	if !bytes.Equal(conn.OCSPResponse(), response) {
+		t.Error("OCSP response is not stapled")
+	}
+}
+
+func TestOCSPStaplingExpiredResponse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-tls")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	ocspPath := filepath.Join(dir, "ocsp.der")
+
+	ca := cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign|x509.KeyUsageDigitalSignature))
+	leaf := cert.MustGenerate(ca, cert.CommonName("www.v2ray.com"), cert.DNSNames("www.v2ray.com"))
+	writeCertificate(certPath, keyPath, time.Now(), leaf, ca)
+	common.Must(ioutil.WriteFile(ocspPath, createOCSPResponse(ca, leaf, time.Now().Add(-time.Second)), 0644))
+
+	config := &Config{
+		Certificate: []*Certificate{{
+			CertificatePath:  certPath,
+			KeyPath:          keyPath,
+			OcspResponsePath: ocspPath,
+		}},
+	}
+	addr, closeServer := startTLSServer(config.GetTLSConfig())
+	defer closeServer()
+
+	conn := dialTLSServer(addr)
+	defer conn.Close()
+	if len(conn.OCSPResponse()) != 0 {
+		t.Error("expired OCSP response is stapled")
+	}
+}
+
+func TestOCSPStaplingFetch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-tls")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	ocspPath := filepath.Join(dir, "ocsp.der")
+
+	ca := cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign|x509.KeyUsageDigitalSignature))
+	var leaf *cert.Certificate
+	var response []byte
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		common.Must(err)
+		if _, err := ocsp.ParseRequest(body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write(response)
+	}))
+	defer responder.Close()
+
+	leaf = cert.MustGenerate(ca, cert.CommonName("www.v2ray.com"), cert.DNSNames("www.v2ray.com"), func(c *x509.Certificate) {
+		c.OCSPServer = []string{responder.URL}
+	})
+	response = createOCSPResponse(ca, leaf, time.Now().Add(time.Hour))
+	writeCertificate(certPath, keyPath, time.Now(), leaf, ca)
+
+	config := &Config{
+		Certificate: []*Certificate{{
+			CertificatePath:  certPath,
+			KeyPath:          keyPath,
+			OcspResponsePath: ocspPath,
+			OcspFetch:        true,
+		}},
+	}
+	addr, closeServer := startTLSServer(config.GetTLSConfig())
+	defer closeServer()
+
+	// The first handshake starts fetching the OCSP response.
+	dialTLSServer(addr).Close()
+	time.Sleep(time.Second)
+
+	conn := dialTLSServer(addr)
+	defer conn.Close()
+	if !bytes.Equal(conn.OCSPResponse(), response) {
+		t.Error("OCSP response is not fetched")
+	}
+
+	cached, err := ioutil.ReadFile(ocspPath)
+	common.Must(err)
+	if !bytes.Equal(cached, response) {
+		t.Error("OCSP response is not saved")
+	}
+}
@@ -3,12 +3,15 @@
 package tls
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/crypto/acme"
+
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol/tls/cert"
 	"v2ray.com/core/transport/internet"
@@ -32,6 +35,9 @@ func ParseCertificate(c *cert.Certificate) *Certificate {
 func (c *Config) loadSelfCertPool() (*x509.CertPool, error) {
 	root := x509.NewCertPool()
 	for _, cert := range c.Certificate {
+		if len(cert.CertificatePath) > 0 {
+			continue
+		}
 		if !root.AppendCertsFromPEM(cert.Certificate) {
 			return nil, newError("failed to append cert").AtWarning()
 		}
@@ -43,7 +49,7 @@ func (c *Config) loadSelfCertPool() (*x509.CertPool, error) {
 func (c *Config) BuildCertificates() []tls.Certificate {
 	certs := make([]tls.Certificate, 0, len(c.Certificate))
 	for _, entry := range c.Certificate {
-		if entry.Usage != Certificate_ENCIPHERMENT {
+		if entry.Usage != Certificate_ENCIPHERMENT || len(entry.CertificatePath) > 0 {
 			continue
 		}
 		keyPair, err := tls.X509KeyPair(entry.Certificate, entry.Key)
@@ -91,11 +97,31 @@ func (c *Config) getCustomCA() []*Certificate {
 	return certs
 }
 
-func getGetCertificateFunc(c *tls.Config, ca []*Certificate) func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+func (c *Config) getCertificateFiles(ctx context.Context) []*certificateFile {
+	var files []*certificateFile
+	for _, certificate := range c.Certificate {
+		if certificate.Usage == Certificate_ENCIPHERMENT && len(certificate.CertificatePath) > 0 {
+			files = append(files, newCertificateFile(ctx, certificate))
+		}
+	}
+	return files
+}
+
+func getGetCertificateFunc(c *tls.Config, ca []*Certificate, manager *acmeManager) func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
 	var access sync.RWMutex
 
 	return func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
 		domain := hello.ServerName
+
+		// Domains managed by ACME are served by the manager, including TLS-ALPN-01 challenges for them.
+		if manager != nil && manager.hasDomain(domain) {
+			return manager.GetCertificate(hello)
+		}
+		if len(ca) == 0 {
+			// Let crypto/tls pick one from the static certificates.
+			return nil, nil
+		}
+
 		certExpired := false
 
 		access.RLock()
@@ -182,8 +208,12 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 		return config
 	}
 
+	options := &options{
+		Config: config,
+		ctx:    context.Background(),
+	}
 	for _, opt := range opts {
-		opt(config)
+		opt(options)
 	}
 
 	if !c.AllowInsecureCiphers && len(config.CipherSuites) == 0 {
@@ -211,9 +241,18 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 	config.Certificates = c.BuildCertificates()
 	config.BuildNameToCertificate()
 
+	var manager *acmeManager
+	if c.Acme != nil && len(c.Acme.Domain) > 0 {
+		manager = getACMEManager(options.ctx, c.Acme)
+	}
+
 	caCerts := c.getCustomCA()
-	if len(caCerts) > 0 {
-		config.GetCertificate = getGetCertificateFunc(config, caCerts)
+	if len(caCerts) > 0 || manager != nil {
+		config.GetCertificate = getGetCertificateFunc(config, caCerts, manager)
+	}
+
+	if files := c.getCertificateFiles(options.ctx); len(files) > 0 {
+		config.GetCertificate = getFileCertificateFunc(config, files, config.GetCertificate)
 	}
 
 	if sn := c.parseServerName(); len(sn) > 0 {
@@ -226,16 +265,25 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 	if len(config.NextProtos) == 0 {
 		config.NextProtos = []string{"http/1.1"}
 	}
+	if manager != nil {
+		// TLS-ALPN-01 challenges are negotiated with a dedicated protocol.
+		config.NextProtos = append(append([]string(nil), config.NextProtos...), acme.ALPNProto)
+	}
 
 	return config
 }
 
+type options struct {
+	*tls.Config
+	ctx context.Context
+}
+
 // Option for building TLS config.
-type Option func(*tls.Config)
+type Option func(*options)
 
 // WithDestination sets the server name in TLS config.
 func WithDestination(dest net.Destination) Option {
-	return func(config *tls.Config) {
+	return func(config *options) {
 		if dest.Address.Family().IsDomain() && config.ServerName == "" {
 			config.ServerName = dest.Address.Domain()
 		}
@@ -244,13 +292,21 @@ func WithDestination(dest net.Destination) Option {
 
 // WithNextProto sets the ALPN values in TLS config.
 func WithNextProto(protocol ...string) Option {
-	return func(config *tls.Config) {
+	return func(config *options) {
 		if len(config.NextProtos) == 0 {
 			config.NextProtos = protocol
 		}
 	}
 }
 
+// WithContext sets the context of the listener or dialer. OCSP responses and ACME certificates are fetched
+// through the V2Ray instance in the context, if any.
+func WithContext(ctx context.Context) Option {
+	return func(config *options) {
+		config.ctx = ctx
+	}
+}
+
 // ConfigFromStreamSettings fetches Config from stream settings. Nil if not found.
 func ConfigFromStreamSettings(settings *internet.MemoryStreamConfig) *Config {
 	if settings == nil {
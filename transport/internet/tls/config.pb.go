@@ -49,11 +49,21 @@ type Certificate struct {
 	// TLS certificate in x509 format.
 	Certificate []byte `protobuf:"bytes,1,opt,name=Certificate,proto3" json:"Certificate,omitempty"`
 	// TLS key in x509 format.
-	Key                  []byte            `protobuf:"bytes,2,opt,name=Key,proto3" json:"Key,omitempty"`
-	Usage                Certificate_Usage `protobuf:"varint,3,opt,name=usage,proto3,enum=v2ray.core.transport.internet.tls.Certificate_Usage" json:"usage,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Key   []byte            `protobuf:"bytes,2,opt,name=Key,proto3" json:"Key,omitempty"`
+	Usage Certificate_Usage `protobuf:"varint,3,opt,name=usage,proto3,enum=v2ray.core.transport.internet.tls.Certificate_Usage" json:"usage,omitempty"`
+	// Path of the TLS certificate file in PEM format, for ENCIPHERMENT usage. If set, the certificate is
+	// loaded from the file, and reloaded when the file changes.
+	CertificatePath string `protobuf:"bytes,4,opt,name=certificate_path,json=certificatePath,proto3" json:"certificate_path,omitempty"`
+	// Path of the TLS key file in PEM format, which is reloaded along with certificate_path.
+	KeyPath string `protobuf:"bytes,5,opt,name=key_path,json=keyPath,proto3" json:"key_path,omitempty"`
+	// Path of the DER encoded OCSP response file to staple, which is reloaded when the file changes.
+	OcspResponsePath string `protobuf:"bytes,6,opt,name=ocsp_response_path,json=ocspResponsePath,proto3" json:"ocsp_response_path,omitempty"`
+	// Whether or not to fetch OCSP responses from the OCSP server of the certificate. Fetched responses are
+	// saved to ocsp_response_path if it is set.
+	OcspFetch            bool     `protobuf:"varint,7,opt,name=ocsp_fetch,json=ocspFetch,proto3" json:"ocsp_fetch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Certificate) Reset()         { *m = Certificate{} }
@@ -102,6 +112,110 @@ func (m *Certificate) GetUsage() Certificate_Usage {
 	return Certificate_ENCIPHERMENT
 }
 
+func (m *Certificate) GetCertificatePath() string {
+	if m != nil {
+		return m.CertificatePath
+	}
+	return ""
+}
+
+func (m *Certificate) GetKeyPath() string {
+	if m != nil {
+		return m.KeyPath
+	}
+	return ""
+}
+
+func (m *Certificate) GetOcspResponsePath() string {
+	if m != nil {
+		return m.OcspResponsePath
+	}
+	return ""
+}
+
+func (m *Certificate) GetOcspFetch() bool {
+	if m != nil {
+		return m.OcspFetch
+	}
+	return false
+}
+
+type AcmeConfig struct {
+	// Domains to obtain certificates for.
+	Domain []string `protobuf:"bytes,1,rep,name=domain,proto3" json:"domain,omitempty"`
+	// Contact email of the ACME account.
+	Email string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	// URL of the ACME directory. Let's Encrypt is used if empty.
+	DirectoryUrl string `protobuf:"bytes,3,opt,name=directory_url,json=directoryUrl,proto3" json:"directory_url,omitempty"`
+	// Directory to store the account key and certificates in.
+	StoragePath string `protobuf:"bytes,4,opt,name=storage_path,json=storagePath,proto3" json:"storage_path,omitempty"`
+	// Number of days before expiry to renew certificates. 30 days if zero.
+	RenewBeforeDays      uint32   `protobuf:"varint,5,opt,name=renew_before_days,json=renewBeforeDays,proto3" json:"renew_before_days,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcmeConfig) Reset()         { *m = AcmeConfig{} }
+func (m *AcmeConfig) String() string { return proto.CompactTextString(m) }
+func (*AcmeConfig) ProtoMessage()    {}
+func (*AcmeConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_42ed70cad60a2736, []int{1}
+}
+
+func (m *AcmeConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcmeConfig.Unmarshal(m, b)
+}
+func (m *AcmeConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcmeConfig.Marshal(b, m, deterministic)
+}
+func (m *AcmeConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcmeConfig.Merge(m, src)
+}
+func (m *AcmeConfig) XXX_Size() int {
+	return xxx_messageInfo_AcmeConfig.Size(m)
+}
+func (m *AcmeConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcmeConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcmeConfig proto.InternalMessageInfo
+
+func (m *AcmeConfig) GetDomain() []string {
+	if m != nil {
+		return m.Domain
+	}
+	return nil
+}
+
+func (m *AcmeConfig) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *AcmeConfig) GetDirectoryUrl() string {
+	if m != nil {
+		return m.DirectoryUrl
+	}
+	return ""
+}
+
+func (m *AcmeConfig) GetStoragePath() string {
+	if m != nil {
+		return m.StoragePath
+	}
+	return ""
+}
+
+func (m *AcmeConfig) GetRenewBeforeDays() uint32 {
+	if m != nil {
+		return m.RenewBeforeDays
+	}
+	return 0
+}
+
 type Config struct {
 	// Whether or not to allow self-signed certificates.
 	AllowInsecure bool `protobuf:"varint,1,opt,name=allow_insecure,json=allowInsecure,proto3" json:"allow_insecure,omitempty"`
@@ -116,17 +230,22 @@ type Config struct {
 	// Whether or not to disable session (ticket) resumption.
 	DisableSessionResumption bool `protobuf:"varint,6,opt,name=disable_session_resumption,json=disableSessionResumption,proto3" json:"disable_session_resumption,omitempty"`
 	// If true, root certificates on the system will not be loaded for verification.
-	DisableSystemRoot    bool     `protobuf:"varint,7,opt,name=disable_system_root,json=disableSystemRoot,proto3" json:"disable_system_root,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	DisableSystemRoot bool `protobuf:"varint,7,opt,name=disable_system_root,json=disableSystemRoot,proto3" json:"disable_system_root,omitempty"`
+	// Fingerprint of the ClientHello to mimic with uTLS. One of "chrome", "firefox", "safari", "ios",
+	// "randomized" and "golang". If empty, the Go TLS stack is used.
+	Fingerprint string `protobuf:"bytes,8,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	// Settings to obtain and renew certificates automatically with ACME.
+	Acme                 *AcmeConfig `protobuf:"bytes,9,opt,name=acme,proto3" json:"acme,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_42ed70cad60a2736, []int{1}
+	return fileDescriptor_42ed70cad60a2736, []int{2}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -196,9 +315,24 @@ func (m *Config) GetDisableSystemRoot() bool {
 	return false
 }
 
+func (m *Config) GetFingerprint() string {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return ""
+}
+
+func (m *Config) GetAcme() *AcmeConfig {
+	if m != nil {
+		return m.Acme
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("v2ray.core.transport.internet.tls.Certificate_Usage", Certificate_Usage_name, Certificate_Usage_value)
 	proto.RegisterType((*Certificate)(nil), "v2ray.core.transport.internet.tls.Certificate")
+	proto.RegisterType((*AcmeConfig)(nil), "v2ray.core.transport.internet.tls.AcmeConfig")
 	proto.RegisterType((*Config)(nil), "v2ray.core.transport.internet.tls.Config")
 }
 
@@ -207,33 +341,45 @@ func init() {
 }
 
 var fileDescriptor_42ed70cad60a2736 = []byte{
-	// 435 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x91, 0xd1, 0x6e, 0xd3, 0x30,
-	0x14, 0x86, 0x49, 0x43, 0x0b, 0x3b, 0xed, 0x46, 0xf0, 0x26, 0x14, 0x71, 0x43, 0x56, 0x34, 0xa9,
-	0x57, 0x8e, 0x14, 0x76, 0xc9, 0x0d, 0x84, 0xa0, 0x05, 0x44, 0xa9, 0xdc, 0x74, 0xd2, 0xb8, 0x89,
-	0x32, 0x73, 0x36, 0x2c, 0x25, 0x76, 0x65, 0xbb, 0x83, 0xbe, 0x12, 0xaf, 0xc3, 0x63, 0xf0, 0x12,
-	0xa8, 0x4e, 0x5b, 0xda, 0xab, 0x69, 0x77, 0x39, 0xff, 0xff, 0xfd, 0x27, 0xfa, 0x8f, 0x21, 0xb9,
-	0x4b, 0x74, 0xb5, 0xa4, 0x5c, 0x35, 0x31, 0x57, 0x1a, 0x63, 0xab, 0x2b, 0x69, 0xe6, 0x4a, 0xdb,
-	0x58, 0x48, 0x8b, 0x5a, 0xa2, 0x8d, 0x6d, 0x6d, 0x62, 0xae, 0xe4, 0x8d, 0xb8, 0xa5, 0x73, 0xad,
-	0xac, 0x22, 0xa7, 0x9b, 0x8c, 0x46, 0xba, 0xe5, 0xe9, 0x86, 0xa7, 0xb6, 0x36, 0xc3, 0x3f, 0x1e,
-	0xf4, 0x53, 0xd4, 0x56, 0xdc, 0x08, 0x5e, 0x59, 0x24, 0xd1, 0xde, 0x18, 0x7a, 0x91, 0x37, 0x1a,
-	0xb0, 0x3d, 0x22, 0x00, 0xff, 0x33, 0x2e, 0xc3, 0x8e, 0x73, 0x56, 0x9f, 0xe4, 0x13, 0x74, 0x17,
-	0xa6, 0xba, 0xc5, 0xd0, 0x8f, 0xbc, 0xd1, 0x51, 0x72, 0x4e, 0xef, 0xfd, 0x2d, 0xdd, 0x59, 0x48,
-	0x67, 0xab, 0x2c, 0x6b, 0x57, 0x0c, 0x3f, 0x40, 0xd7, 0xcd, 0x24, 0x80, 0x41, 0x36, 0x4e, 0xf3,
-	0xc9, 0x45, 0xc6, 0xbe, 0x64, 0xe3, 0x22, 0x78, 0x44, 0x4e, 0x20, 0x78, 0x37, 0x2b, 0x2e, 0xbe,
-	0xb2, 0xbc, 0xb8, 0x2a, 0x2f, 0x33, 0x96, 0x7f, 0xbc, 0x0a, 0x3c, 0x72, 0x0c, 0xcf, 0xfe, 0xab,
-	0xf9, 0x74, 0x3a, 0xcb, 0x82, 0xce, 0xf0, 0x6f, 0x07, 0x7a, 0xa9, 0xbb, 0x04, 0x39, 0x83, 0xa3,
-	0xaa, 0xae, 0xd5, 0xcf, 0x52, 0x48, 0x83, 0x7c, 0xa1, 0xdb, 0x4e, 0x4f, 0xd9, 0xa1, 0x53, 0xf3,
-	0xb5, 0x48, 0xce, 0xe1, 0xc5, 0x3e, 0x56, 0x72, 0x31, 0xff, 0x81, 0xda, 0x84, 0x5d, 0x87, 0x9f,
-	0xec, 0xe1, 0x69, 0xeb, 0x91, 0x09, 0xf4, 0xf9, 0xce, 0xb5, 0x3a, 0x91, 0x3f, 0xea, 0x27, 0xf4,
-	0x61, 0xfd, 0xd9, 0xee, 0x0a, 0xf2, 0x0a, 0xfa, 0x06, 0xf5, 0x1d, 0xea, 0x52, 0x56, 0x4d, 0x7b,
-	0xd1, 0x03, 0x06, 0xad, 0x34, 0xae, 0x1a, 0x24, 0xaf, 0xe1, 0x50, 0xe2, 0x2f, 0x5b, 0xba, 0x17,
-	0xe6, 0xaa, 0x0e, 0x1f, 0x47, 0xfe, 0xe8, 0x80, 0x0d, 0x56, 0xe2, 0x64, 0xad, 0x91, 0xb7, 0xf0,
-	0xf2, 0xbb, 0x30, 0xd5, 0x75, 0x8d, 0xa5, 0x41, 0x63, 0x84, 0x92, 0xa5, 0x46, 0xb3, 0x68, 0xe6,
-	0x56, 0x28, 0x19, 0xf6, 0x5c, 0xa3, 0x70, 0x4d, 0x4c, 0x5b, 0x80, 0x6d, 0x7d, 0x42, 0xe1, 0x78,
-	0x9b, 0x5e, 0x1a, 0x8b, 0x4d, 0xa9, 0x95, 0xb2, 0xe1, 0x13, 0x17, 0x7b, 0xbe, 0x89, 0x39, 0x87,
-	0x29, 0x65, 0xdf, 0x33, 0x38, 0xe3, 0xaa, 0xb9, 0xbf, 0xf5, 0xc4, 0xfb, 0xe6, 0xdb, 0xda, 0xfc,
-	0xee, 0x9c, 0x5e, 0x26, 0xac, 0x5a, 0xd2, 0x74, 0x85, 0x16, 0x5b, 0x34, 0xdf, 0xa0, 0x45, 0x6d,
-	0xae, 0x7b, 0xae, 0xdf, 0x9b, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xb6, 0x58, 0x74, 0x54, 0xf7,
-	0x02, 0x00, 0x00,
+	// 640 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xc1, 0x6e, 0xda, 0x4a,
+	0x14, 0x86, 0xaf, 0x21, 0x10, 0x38, 0x90, 0xc4, 0x99, 0x44, 0x91, 0xef, 0x95, 0xae, 0xae, 0xc3,
+	0x55, 0x24, 0x5a, 0xb5, 0x46, 0xa2, 0x59, 0x76, 0x93, 0x10, 0xa2, 0xd0, 0xaa, 0x29, 0x9a, 0x40,
+	0xa4, 0x74, 0x63, 0x4d, 0xcc, 0x01, 0xac, 0xd8, 0x33, 0xd6, 0xcc, 0x90, 0xd4, 0xaf, 0xd4, 0x45,
+	0x9f, 0xa5, 0x4f, 0xd1, 0xe7, 0xa8, 0x3c, 0x36, 0x04, 0x56, 0x69, 0x77, 0xcc, 0xff, 0x7f, 0xe7,
+	0x78, 0xf8, 0xcf, 0x19, 0xe8, 0x3e, 0x76, 0x25, 0x4b, 0xbd, 0x40, 0xc4, 0x9d, 0x40, 0x48, 0xec,
+	0x68, 0xc9, 0xb8, 0x4a, 0x84, 0xd4, 0x9d, 0x90, 0x6b, 0x94, 0x1c, 0x75, 0x47, 0x47, 0xaa, 0x13,
+	0x08, 0x3e, 0x0d, 0x67, 0x5e, 0x22, 0x85, 0x16, 0xe4, 0x78, 0x59, 0x23, 0xd1, 0x5b, 0xf1, 0xde,
+	0x92, 0xf7, 0x74, 0xa4, 0x5a, 0x3f, 0x4b, 0xd0, 0xe8, 0xa1, 0xd4, 0xe1, 0x34, 0x0c, 0x98, 0x46,
+	0xe2, 0x6e, 0x1c, 0x1d, 0xcb, 0xb5, 0xda, 0x4d, 0xba, 0x41, 0xd8, 0x50, 0xfe, 0x88, 0xa9, 0x53,
+	0x32, 0x4e, 0xf6, 0x93, 0x7c, 0x80, 0xca, 0x42, 0xb1, 0x19, 0x3a, 0x65, 0xd7, 0x6a, 0xef, 0x76,
+	0x4f, 0xbd, 0x17, 0x3f, 0xeb, 0xad, 0x35, 0xf4, 0xc6, 0x59, 0x2d, 0xcd, 0x5b, 0x90, 0x57, 0x60,
+	0x07, 0xcf, 0x9e, 0x9f, 0x30, 0x3d, 0x77, 0xb6, 0x5c, 0xab, 0x5d, 0xa7, 0x7b, 0x6b, 0xfa, 0x90,
+	0xe9, 0x39, 0xf9, 0x1b, 0x6a, 0x0f, 0x98, 0xe6, 0x48, 0xc5, 0x20, 0xdb, 0x0f, 0x98, 0x1a, 0xeb,
+	0x0d, 0x10, 0x11, 0xa8, 0xc4, 0x97, 0xa8, 0x12, 0xc1, 0x55, 0xd1, 0xa7, 0x6a, 0x20, 0x3b, 0x73,
+	0x68, 0x61, 0x18, 0xfa, 0x5f, 0x00, 0x43, 0x4f, 0x51, 0x07, 0x73, 0x67, 0xdb, 0xb5, 0xda, 0x35,
+	0x5a, 0xcf, 0x94, 0xcb, 0x4c, 0x68, 0x5d, 0x40, 0xc5, 0x5c, 0x91, 0xd8, 0xd0, 0xec, 0x5f, 0xf7,
+	0x06, 0xc3, 0xab, 0x3e, 0xfd, 0xd4, 0xbf, 0x1e, 0xd9, 0x7f, 0x91, 0x43, 0xb0, 0xcf, 0xc6, 0xa3,
+	0xab, 0xcf, 0x74, 0x30, 0xba, 0xf3, 0x6f, 0xfb, 0x74, 0x70, 0x79, 0x67, 0x5b, 0xe4, 0x00, 0xf6,
+	0x9e, 0xd5, 0xc1, 0xcd, 0xcd, 0xb8, 0x6f, 0x97, 0x5a, 0xdf, 0x2d, 0x80, 0xb3, 0x20, 0xc6, 0x9e,
+	0x19, 0x10, 0x39, 0x82, 0xea, 0x44, 0xc4, 0x2c, 0xe4, 0x8e, 0xe5, 0x96, 0xdb, 0x75, 0x5a, 0x9c,
+	0xc8, 0x21, 0x54, 0x30, 0x66, 0x61, 0x64, 0xf2, 0xad, 0xd3, 0xfc, 0x40, 0xfe, 0x87, 0x9d, 0x49,
+	0x28, 0x31, 0xd0, 0x42, 0xa6, 0xfe, 0x42, 0x46, 0x26, 0xe9, 0x3a, 0x6d, 0xae, 0xc4, 0xb1, 0x8c,
+	0xc8, 0x31, 0x34, 0x95, 0x16, 0x92, 0xcd, 0x36, 0x62, 0x6b, 0x14, 0x9a, 0xf9, 0xa7, 0xaf, 0x61,
+	0x5f, 0x22, 0xc7, 0x27, 0xff, 0x1e, 0xa7, 0x42, 0xa2, 0x3f, 0x61, 0xa9, 0x32, 0xd9, 0xed, 0xd0,
+	0x3d, 0x63, 0x9c, 0x1b, 0xfd, 0x82, 0xa5, 0xaa, 0xf5, 0xa3, 0x0c, 0xd5, 0xe2, 0xb2, 0x27, 0xb0,
+	0xcb, 0xa2, 0x48, 0x3c, 0xf9, 0x21, 0x57, 0x18, 0x2c, 0x64, 0xbe, 0x17, 0x35, 0xba, 0x63, 0xd4,
+	0x41, 0x21, 0x92, 0x53, 0x38, 0xda, 0xc4, 0xfc, 0x20, 0x4c, 0xe6, 0x28, 0xf3, 0x4f, 0xd4, 0xe8,
+	0xe1, 0x06, 0xde, 0xcb, 0x3d, 0x32, 0x84, 0xc6, 0xda, 0x64, 0x9d, 0x92, 0x5b, 0x6e, 0x37, 0xba,
+	0xde, 0x9f, 0xed, 0x10, 0x5d, 0x6f, 0x41, 0xfe, 0x83, 0x86, 0x42, 0xf9, 0x88, 0xd2, 0xe7, 0x2c,
+	0xc6, 0x22, 0x2b, 0xc8, 0xa5, 0x6b, 0x16, 0x63, 0x16, 0x27, 0xc7, 0xaf, 0xda, 0x37, 0xaf, 0x24,
+	0x10, 0x91, 0xb3, 0x65, 0x66, 0xd0, 0xcc, 0xc4, 0x61, 0xa1, 0x91, 0xf7, 0xf0, 0xcf, 0x24, 0x54,
+	0xec, 0x3e, 0x42, 0x5f, 0xa1, 0x52, 0xa1, 0xe0, 0xd9, 0x3a, 0x2d, 0xe2, 0x44, 0x87, 0x82, 0x9b,
+	0x5d, 0xaa, 0x51, 0xa7, 0x20, 0x6e, 0x72, 0x80, 0xae, 0x7c, 0xe2, 0xc1, 0xc1, 0xaa, 0x3a, 0x55,
+	0x1a, 0x63, 0x5f, 0x0a, 0xa1, 0x8b, 0xe5, 0xda, 0x5f, 0x96, 0x19, 0x87, 0x0a, 0xa1, 0xb3, 0x77,
+	0x37, 0x0d, 0xf9, 0x0c, 0x65, 0x22, 0x43, 0xae, 0x9d, 0x5a, 0x3e, 0xbb, 0x35, 0x89, 0x9c, 0xc1,
+	0x16, 0x0b, 0x62, 0x74, 0xea, 0xae, 0xd5, 0x6e, 0x74, 0xdf, 0xfe, 0x46, 0x40, 0xcf, 0xeb, 0x46,
+	0x4d, 0xe9, 0x39, 0x85, 0x93, 0x40, 0xc4, 0x2f, 0x57, 0x0e, 0xad, 0x2f, 0x65, 0x1d, 0xa9, 0x6f,
+	0xa5, 0xe3, 0xdb, 0x2e, 0x65, 0xa9, 0xd7, 0xcb, 0xd0, 0xd1, 0x0a, 0x1d, 0x2c, 0xd1, 0x51, 0xa4,
+	0xee, 0xab, 0x26, 0xc4, 0x77, 0xbf, 0x06, 0x00, 0xc7, 0xb0, 0xbe, 0x58, 0xa0, 0x04, 0x00, 0x00,
 }
@@ -45,6 +45,9 @@ func (c *Config) getCertPool() (*x509.CertPool, error) {
 		return nil, newError("system root").AtWarning().Base(err)
 	}
 	for _, cert := range c.Certificate {
+		if len(cert.CertificatePath) > 0 {
+			continue
+		}
 		if !pool.AppendCertsFromPEM(cert.Certificate) {
 			return nil, newError("append cert to root").AtWarning().Base(err)
 		}
@@ -45,21 +45,123 @@ func Client(c net.Conn, config *tls.Config) net.Conn {
 	return &conn{Conn: tlsConn}
 }
 
+type uConn struct {
+	*utls.UConn
+}
+
+// Read reads data from the connection, after the uTLS handshake. utls.Conn.Read would otherwise run the
+// handshake of crypto/tls instead.
+func (c *uConn) Read(b []byte) (int, error) {
+	if err := c.UConn.Handshake(); err != nil {
+		return 0, err
+	}
+	return c.UConn.Read(b)
+}
+
+func (c *uConn) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	mb = buf.Compact(mb)
+	mb, err := buf.WriteMultiBuffer(c, mb)
+	buf.ReleaseMulti(mb)
+	return err
+}
+
+func (c *uConn) HandshakeAddress() net.Address {
+	if err := c.UConn.Handshake(); err != nil {
+		return nil
+	}
+	state := c.UConn.ConnectionState()
+	if state.ServerName == "" {
+		return nil
+	}
+	return net.ParseAddress(state.ServerName)
+}
+
+var fingerprints = map[string]*utls.ClientHelloID{
+	"chrome":  &utls.HelloChrome_Auto,
+	"firefox": &utls.HelloFirefox_Auto,
+	// uTLS has no ClientHello of Safari on macOS, which shares the TLS stack of Safari on iOS.
+	"safari":     &utls.HelloIOS_Auto,
+	"ios":        &utls.HelloIOS_Auto,
+	"randomized": &utls.HelloRandomizedALPN,
+	"golang":     &utls.HelloGolang,
+}
+
+// GetFingerprint returns the uTLS ClientHelloID of the named fingerprint, or nil if there is no such fingerprint.
+func GetFingerprint(name string) *utls.ClientHelloID {
+	return fingerprints[name]
+}
+
+// globalUSessionCache is the ClientSessionCache of uTLS clients, as sessions of crypto/tls can't be shared with uTLS.
+var globalUSessionCache = utls.NewLRUClientSessionCache(128)
+
+// copyConfig copies the settings of c that uTLS clients use. Client certificates are copied as well, but with the
+// ClientHellos of browsers, this version of uTLS only sends them correctly in TLS 1.3.
 func copyConfig(c *tls.Config) *utls.Config {
-	return &utls.Config{
-		NextProtos:         c.NextProtos,
-		ServerName:         c.ServerName,
-		InsecureSkipVerify: c.InsecureSkipVerify,
-		MinVersion:         utls.VersionTLS12,
-		MaxVersion:         utls.VersionTLS12,
+	config := &utls.Config{
+		RootCAs:                c.RootCAs,
+		NextProtos:             c.NextProtos,
+		ServerName:             c.ServerName,
+		InsecureSkipVerify:     c.InsecureSkipVerify,
+		SessionTicketsDisabled: c.SessionTicketsDisabled,
 	}
+	for _, certificate := range c.Certificates {
+		config.Certificates = append(config.Certificates, utls.Certificate{
+			Certificate:                 certificate.Certificate,
+			PrivateKey:                  certificate.PrivateKey,
+			OCSPStaple:                  certificate.OCSPStaple,
+			SignedCertificateTimestamps: certificate.SignedCertificateTimestamps,
+			Leaf:                        certificate.Leaf,
+		})
+	}
+	return config
 }
 
 func UClient(c net.Conn, config *tls.Config) net.Conn {
 	uConfig := copyConfig(config)
+	uConfig.MinVersion = utls.VersionTLS12
+	uConfig.MaxVersion = utls.VersionTLS12
 	return utls.Client(c, uConfig)
 }
 
+// FingerprintClient initiates a uTLS client handshake on the given connection, sending the ClientHello of
+// the given fingerprint. The ALPN values of the ClientHello are replaced by those in config.
+func FingerprintClient(c net.Conn, config *tls.Config, fingerprint *utls.ClientHelloID) (net.Conn, error) {
+	uConfig := copyConfig(config)
+	// This version of uTLS fails to resume sessions with the ClientHellos of browsers, so sessions are only
+	// cached for the ClientHello of crypto/tls.
+	if config.ClientSessionCache != nil && *fingerprint == utls.HelloGolang {
+		uConfig.ClientSessionCache = globalUSessionCache
+	}
+	client := utls.UClient(c, uConfig, *fingerprint)
+	if err := client.BuildHandshakeState(); err != nil {
+		return nil, newError("failed to build ClientHello of ", fingerprint.Str()).Base(err)
+	}
+	var grease *utls.UtlsGREASEExtension
+	extensions := client.Extensions[:0]
+	for _, ext := range client.Extensions {
+		switch ext := ext.(type) {
+		case *utls.UtlsGREASEExtension:
+			// uTLS may pick the same value for both GREASE extensions, which servers reject as duplicates.
+			if grease != nil && grease.Value == ext.Value {
+				ext.Value ^= 0x1010
+			}
+			grease = ext
+		case *utls.SNIExtension:
+			// Browsers send no SNI for IP addresses, and an empty one is rejected by servers.
+			if len(ext.ServerName) == 0 {
+				continue
+			}
+		case *utls.ALPNExtension:
+			if len(config.NextProtos) > 0 {
+				ext.AlpnProtocols = config.NextProtos
+			}
+		}
+		extensions = append(extensions, ext)
+	}
+	client.Extensions = extensions
+	return &uConn{UConn: client}, nil
+}
+
 // Server initiates a TLS server handshake on the given connection.
 func Server(c net.Conn, config *tls.Config) net.Conn {
 	tlsConn := tls.Server(c, config)
@@ -0,0 +1,179 @@
+package tls_test
+
+import (
+	gotls "crypto/tls"
+	"io"
+	"testing"
+
+	utls "github.com/refraction-networking/utls"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/tls/cert"
+	. "v2ray.com/core/transport/internet/tls"
+)
+
+func TestFingerprintClient(t *testing.T) {
+	certificate := ParseCertificate(cert.MustGenerate(nil, cert.CommonName("www.v2ray.com"), cert.DNSNames("www.v2ray.com")))
+	serverConfig := (&Config{
+		Certificate:  []*Certificate{certificate},
+		NextProtocol: []string{"h2", "http/1.1"},
+	}).GetTLSConfig()
+
+	listener, err := gotls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	common.Must(err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	test := func(config *Config) {
+		rawConn, err := net.Dial("tcp", listener.Addr().String())
+		common.Must(err)
+		conn, err := FingerprintClient(rawConn, config.GetTLSConfig(), GetFingerprint(config.Fingerprint))
+		common.Must(err)
+		defer conn.Close()
+
+		payload := []byte("fingerprint: " + config.Fingerprint)
+		common.Must2(conn.Write(payload))
+		response := make([]byte, len(payload))
+		common.Must2(io.ReadFull(conn, response))
+		if string(response) != string(payload) {
+			t.Error(config.Fingerprint, ": unexpected response: ", string(response))
+		}
+
+		// ALPN values of the fingerprint are replaced by the configured ones.
+		state := conn.(interface {
+			ConnectionState() utls.ConnectionState
+		}).ConnectionState()
+		if state.NegotiatedProtocol != "http/1.1" {
+			t.Error(config.Fingerprint, ": unexpected ALPN: ", state.NegotiatedProtocol)
+		}
+	}
+
+	for _, fingerprint := range []string{"chrome", "firefox", "safari", "ios", "randomized", "golang"} {
+		test(&Config{
+			Certificate: []*Certificate{{
+				Certificate: certificate.Certificate,
+				Usage:       Certificate_AUTHORITY_VERIFY,
+			}},
+			ServerName:        "www.v2ray.com",
+			NextProtocol:      []string{"http/1.1"},
+			DisableSystemRoot: true,
+			Fingerprint:       fingerprint,
+		})
+
+		// No SNI is sent without a server name, e.g. when dialing an IP address.
+		test(&Config{
+			AllowInsecure:     true,
+			NextProtocol:      []string{"http/1.1"},
+			DisableSystemRoot: true,
+			Fingerprint:       fingerprint,
+		})
+	}
+}
+
+func TestFingerprintClientCertificateAndResumption(t *testing.T) {
+	serverCert := ParseCertificate(cert.MustGenerate(nil, cert.CommonName("www.v2ray.com"), cert.DNSNames("www.v2ray.com")))
+	clientCert := ParseCertificate(cert.MustGenerate(nil, cert.CommonName("client.v2ray.com")))
+	serverConfig := (&Config{
+		Certificate: []*Certificate{serverCert},
+	}).GetTLSConfig()
+	serverConfig.ClientAuth = gotls.RequireAnyClientCert
+
+	listener, err := gotls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	common.Must(err)
+	defer listener.Close()
+
+	peers := make(chan string, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				tlsConn := conn.(*gotls.Conn)
+				if err := tlsConn.Handshake(); err != nil {
+					peers <- ""
+					return
+				}
+				state := tlsConn.ConnectionState()
+				if len(state.PeerCertificates) > 0 {
+					peers <- state.PeerCertificates[0].Subject.CommonName
+				} else {
+					// Resumed sessions carry no certificates.
+					peers <- "resumed"
+				}
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	config := &Config{
+		Certificate: []*Certificate{
+			clientCert,
+			{
+				Certificate: serverCert.Certificate,
+				Usage:       Certificate_AUTHORITY_VERIFY,
+			},
+		},
+		ServerName:        "www.v2ray.com",
+		DisableSystemRoot: true,
+	}
+	dial := func() utls.ConnectionState {
+		rawConn, err := net.Dial("tcp", listener.Addr().String())
+		common.Must(err)
+		conn, err := FingerprintClient(rawConn, config.GetTLSConfig(), GetFingerprint(config.Fingerprint))
+		common.Must(err)
+		defer conn.Close()
+
+		// Read the echo, so that session tickets after the handshake are received.
+		common.Must2(conn.Write([]byte("ping")))
+		common.Must2(io.ReadFull(conn, make([]byte, 4)))
+		return conn.(interface {
+			ConnectionState() utls.ConnectionState
+		}).ConnectionState()
+	}
+
+	// Client certificates of browser ClientHellos only work with TLS 1.3 in this version of uTLS, which the
+	// ClientHellos of Safari and some randomized ones don't offer.
+	for _, fingerprint := range []string{"chrome", "firefox"} {
+		config.Fingerprint = fingerprint
+		dial()
+		if peer := <-peers; peer != "client.v2ray.com" {
+			t.Error(fingerprint, ": unexpected client certificate: ", peer)
+		}
+	}
+
+	config.Fingerprint = "golang"
+	if state := dial(); state.DidResume {
+		t.Error("first connection is resumed")
+	}
+	if peer := <-peers; peer != "client.v2ray.com" {
+		t.Error("unexpected client certificate: ", peer)
+	}
+	if state := dial(); !state.DidResume {
+		t.Error("second connection is not resumed")
+	}
+	<-peers
+}
+
+func TestUnknownFingerprint(t *testing.T) {
+	if GetFingerprint("netscape") != nil {
+		t.Error("expect nil fingerprint for unknown name")
+	}
+	if GetFingerprint("") != nil {
+		t.Error("expect nil fingerprint for empty name")
+	}
+}
@@ -3,13 +3,21 @@
 package websocket
 
 import (
+	"encoding/base64"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
 	"v2ray.com/core/transport/internet"
 )
 
-const protocolName = "websocket"
+const (
+	protocolName = "websocket"
+	// earlyDataQuery is the query parameter that carries early data if the header name is not set.
+	earlyDataQuery = "ed"
+)
 
 func (c *Config) GetNormalizedPath() string {
 	path := c.Path
@@ -30,6 +38,79 @@ func (c *Config) GetRequestHeader() http.Header {
 	return header
 }
 
+// isValidHost returns true if this config serves requests of the given host.
+func (c *Config) isValidHost(host string) bool {
+	if len(c.Host) == 0 {
+		return true
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	for _, h := range c.Host {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps returns true if this config and the other config serve some requests in common.
+func (c *Config) overlaps(other *Config) bool {
+	if c.GetNormalizedPath() != other.GetNormalizedPath() {
+		return false
+	}
+	if len(c.Host) == 0 || len(other.Host) == 0 {
+		return true
+	}
+	for _, h := range other.Host {
+		if c.isValidHost(h) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) getFallbackURL() (*url.URL, error) {
+	u, err := url.Parse(c.Fallback)
+	if err != nil {
+		return nil, newError("invalid fallback ", c.Fallback).Base(err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return nil, newError("invalid fallback ", c.Fallback, ", expect http(s)://host:port")
+	}
+	return u, nil
+}
+
+// encodeEarlyData puts early data into the request URI or header.
+func (c *Config) encodeEarlyData(uri string, header http.Header, earlyData []byte) string {
+	data := base64.RawURLEncoding.EncodeToString(earlyData)
+	if len(c.EarlyDataHeaderName) > 0 {
+		header.Set(c.EarlyDataHeaderName, data)
+		return uri
+	}
+	if strings.Contains(uri, "?") {
+		return uri + "&" + earlyDataQuery + "=" + data
+	}
+	return uri + "?" + earlyDataQuery + "=" + data
+}
+
+// decodeEarlyData returns early data in the request.
+func (c *Config) decodeEarlyData(request *http.Request) ([]byte, error) {
+	var data string
+	if len(c.EarlyDataHeaderName) > 0 {
+		data = request.Header.Get(c.EarlyDataHeaderName)
+	} else {
+		data = request.URL.Query().Get(earlyDataQuery)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if base64.RawURLEncoding.DecodedLen(len(data)) > int(c.MaxEarlyData) {
+		return nil, newError("early data too large: ", len(data))
+	}
+	return base64.RawURLEncoding.DecodeString(data)
+}
+
 func init() {
 	common.Must(internet.RegisterProtocolConfigCreator(protocolName, func() interface{} {
 		return new(Config)
@@ -66,11 +66,23 @@ func (m *Header) GetValue() string {
 
 type Config struct {
 	// URL path to the WebSocket service. Empty value means root(/).
-	Path                 string    `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
-	Header               []*Header `protobuf:"bytes,3,rep,name=header,proto3" json:"header,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	Path   string    `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Header []*Header `protobuf:"bytes,3,rep,name=header,proto3" json:"header,omitempty"`
+	// Max size of early data, which is the beginning of the payload sent in the upgrade request.
+	// 0 disables early data.
+	MaxEarlyData int32 `protobuf:"varint,4,opt,name=max_early_data,json=maxEarlyData,proto3" json:"max_early_data,omitempty"`
+	// Name of the request header that carries early data. Early data is carried in the "ed" query
+	// parameter of the path if empty.
+	EarlyDataHeaderName string `protobuf:"bytes,5,opt,name=early_data_header_name,json=earlyDataHeaderName,proto3" json:"early_data_header_name,omitempty"`
+	// Hosts that this inbound serves. Empty value means any host. Inbounds listening on the same
+	// address share one listener, and each inbound serves requests of its own path and hosts.
+	Host []string `protobuf:"bytes,6,rep,name=host,proto3" json:"host,omitempty"`
+	// URL of the HTTP server that requests not served by any inbound are proxied to, for example
+	// "http://127.0.0.1:8080". Requests are answered with 404 if empty.
+	Fallback             string   `protobuf:"bytes,7,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
@@ -112,6 +124,34 @@ func (m *Config) GetHeader() []*Header {
 	return nil
 }
 
+func (m *Config) GetMaxEarlyData() int32 {
+	if m != nil {
+		return m.MaxEarlyData
+	}
+	return 0
+}
+
+func (m *Config) GetEarlyDataHeaderName() string {
+	if m != nil {
+		return m.EarlyDataHeaderName
+	}
+	return ""
+}
+
+func (m *Config) GetHost() []string {
+	if m != nil {
+		return m.Host
+	}
+	return nil
+}
+
+func (m *Config) GetFallback() string {
+	if m != nil {
+		return m.Fallback
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Header)(nil), "v2ray.core.transport.internet.websocket.Header")
 	proto.RegisterType((*Config)(nil), "v2ray.core.transport.internet.websocket.Config")
@@ -122,20 +162,25 @@ func init() {
 }
 
 var fileDescriptor_c4869c9c0fc9b72f = []byte{
-	// 229 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xb2, 0x28, 0x33, 0x2a, 0x4a,
-	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x2f, 0x29, 0x4a, 0xcc, 0x2b,
-	0x2e, 0xc8, 0x2f, 0x2a, 0xd1, 0xcf, 0xcc, 0x2b, 0x49, 0x2d, 0xca, 0x4b, 0x2d, 0xd1, 0x2f, 0x4f,
-	0x4d, 0x2a, 0xce, 0x4f, 0xce, 0x4e, 0x2d, 0xd1, 0x4f, 0xce, 0xcf, 0x4b, 0xcb, 0x4c, 0xd7, 0x2b,
-	0x28, 0xca, 0x2f, 0xc9, 0x17, 0x52, 0x87, 0xe9, 0x2c, 0x4a, 0xd5, 0x83, 0xeb, 0xd2, 0x83, 0xe9,
-	0xd2, 0x83, 0xeb, 0x52, 0x32, 0xe0, 0x62, 0xf3, 0x48, 0x4d, 0x4c, 0x49, 0x2d, 0x12, 0x12, 0xe0,
-	0x62, 0xce, 0x4e, 0xad, 0x94, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0x02, 0x31, 0x85, 0x44, 0xb8,
-	0x58, 0xcb, 0x12, 0x73, 0x4a, 0x53, 0x25, 0x98, 0xc0, 0x62, 0x10, 0x8e, 0x52, 0x36, 0x17, 0x9b,
-	0x33, 0xd8, 0x2a, 0x21, 0x21, 0x2e, 0x96, 0x82, 0xc4, 0x92, 0x0c, 0xa8, 0x34, 0x98, 0x2d, 0xe4,
-	0xce, 0xc5, 0x96, 0x01, 0x36, 0x4f, 0x82, 0x59, 0x81, 0x59, 0x83, 0xdb, 0x48, 0x5f, 0x8f, 0x48,
-	0x97, 0xe8, 0x41, 0x9c, 0x11, 0x04, 0xd5, 0xee, 0xc5, 0xc2, 0xc1, 0x28, 0xc0, 0xe4, 0x94, 0xc2,
-	0xa5, 0x9d, 0x9c, 0x9f, 0x4b, 0xac, 0x19, 0x01, 0x8c, 0x51, 0x9c, 0x70, 0xce, 0x2a, 0x26, 0xf5,
-	0x30, 0xa3, 0xa0, 0xc4, 0x4a, 0x3d, 0x67, 0x90, 0xb6, 0x10, 0xb8, 0x36, 0x4f, 0x98, 0xb6, 0x70,
-	0x98, 0xca, 0x24, 0x36, 0x70, 0xa0, 0x19, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0xf5, 0x7e, 0x60,
-	0xf9, 0x70, 0x01, 0x00, 0x00,
+	// 308 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x91, 0x4f, 0x4b, 0xc3, 0x30,
+	0x18, 0xc6, 0xe9, 0xba, 0xd5, 0x2d, 0x8a, 0x8c, 0x28, 0x12, 0x3c, 0x95, 0x21, 0x6c, 0x20, 0x24,
+	0xb2, 0x5d, 0x3c, 0x3b, 0xc5, 0x3f, 0x07, 0x91, 0x22, 0x0a, 0x5e, 0xca, 0xbb, 0xec, 0x9d, 0x1b,
+	0x6b, 0x9a, 0x91, 0xc5, 0xb9, 0x7e, 0x25, 0x3f, 0xa0, 0x67, 0x49, 0xba, 0xd6, 0xeb, 0x6e, 0xcf,
+	0xf3, 0xe6, 0xf9, 0xbd, 0x79, 0x42, 0xc8, 0xf5, 0x66, 0x68, 0xa0, 0xe0, 0x52, 0x2b, 0x21, 0xb5,
+	0x41, 0x61, 0x0d, 0xe4, 0xeb, 0x95, 0x36, 0x56, 0x2c, 0x72, 0x8b, 0x26, 0x47, 0x2b, 0xbe, 0x71,
+	0xb2, 0xd6, 0x72, 0x89, 0x56, 0x48, 0x9d, 0xcf, 0x16, 0x9f, 0x7c, 0x65, 0xb4, 0xd5, 0xb4, 0x5f,
+	0x91, 0x06, 0x79, 0x4d, 0xf1, 0x8a, 0xe2, 0x35, 0xd5, 0xbb, 0x22, 0xd1, 0x03, 0xc2, 0x14, 0x0d,
+	0xed, 0x92, 0x70, 0x89, 0x05, 0x0b, 0xe2, 0x60, 0xd0, 0x49, 0x9c, 0xa4, 0xa7, 0xa4, 0xb5, 0x81,
+	0xec, 0x0b, 0x59, 0xc3, 0xcf, 0x4a, 0xd3, 0xfb, 0x0d, 0x48, 0x34, 0xf6, 0x77, 0x51, 0x4a, 0x9a,
+	0x2b, 0xb0, 0xf3, 0xdd, 0xb9, 0xd7, 0xf4, 0x9e, 0x44, 0x73, 0xbf, 0x90, 0x85, 0x71, 0x38, 0x38,
+	0x1c, 0x0a, 0xbe, 0x67, 0x15, 0x5e, 0xf6, 0x48, 0x76, 0x38, 0xbd, 0x20, 0xc7, 0x0a, 0xb6, 0x29,
+	0x82, 0xc9, 0x8a, 0x74, 0x0a, 0x16, 0x58, 0x33, 0x0e, 0x06, 0xad, 0xe4, 0x48, 0xc1, 0xf6, 0xce,
+	0x0d, 0x6f, 0xc1, 0x02, 0x1d, 0x91, 0xb3, 0xff, 0x44, 0x5a, 0xa2, 0x69, 0x0e, 0x0a, 0x59, 0xcb,
+	0x97, 0x3a, 0xc1, 0x2a, 0x5a, 0xae, 0x7f, 0x06, 0x85, 0xae, 0xf7, 0x5c, 0xaf, 0x2d, 0x8b, 0xe2,
+	0xd0, 0xf5, 0x76, 0x9a, 0x9e, 0x93, 0xf6, 0x0c, 0xb2, 0x6c, 0x02, 0x72, 0xc9, 0x0e, 0x3c, 0x5a,
+	0xfb, 0xa7, 0x66, 0x3b, 0xe8, 0x36, 0x6e, 0xa6, 0xe4, 0x52, 0x6a, 0xb5, 0xef, 0x73, 0x5e, 0x82,
+	0x8f, 0x4e, 0x6d, 0x7e, 0x1a, 0xfd, 0xb7, 0x61, 0x02, 0x05, 0x1f, 0x3b, 0xec, 0xb5, 0xc6, 0x1e,
+	0x2b, 0xec, 0xbd, 0x4a, 0x4e, 0x22, 0xff, 0x81, 0xa3, 0xbf, 0x01, 0x00, 0x22, 0xc2, 0xf5, 0xa9,
+	0xfc, 0x01, 0x00, 0x00,
 }
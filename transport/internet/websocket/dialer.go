@@ -18,7 +18,11 @@ import (
 func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (internet.Connection, error) {
 	newError("creating connection to ", dest).WriteToLog(session.ExportIDToError(ctx))
 
-	conn, err := dialWebsocket(ctx, dest, streamSettings)
+	if wsSettings := streamSettings.ProtocolSettings.(*Config); wsSettings.MaxEarlyData > 0 {
+		return newDelayDialConn(ctx, dest, streamSettings), nil
+	}
+
+	conn, err := dialWebsocket(ctx, dest, streamSettings, nil)
 	if err != nil {
 		return nil, newError("failed to dial WebSocket").Base(err)
 	}
@@ -29,7 +33,7 @@ func init() {
 	common.Must(internet.RegisterTransportDialer(protocolName, Dial))
 }
 
-func dialWebsocket(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (net.Conn, error) {
+func dialWebsocket(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig, earlyData []byte) (net.Conn, error) {
 	wsSettings := streamSettings.ProtocolSettings.(*Config)
 
 	dialer := &websocket.Dialer{
@@ -42,19 +46,42 @@ func dialWebsocket(ctx context.Context, dest net.Destination, streamSettings *in
 	}
 
 	protocol := "ws"
+	scheme := "ws"
 
 	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
 		protocol = "wss"
-		dialer.TLSClientConfig = config.GetTLSConfig(tls.WithDestination(dest))
+		tlsConfig := config.GetTLSConfig(tls.WithDestination(dest))
+		if fingerprint := tls.GetFingerprint(config.Fingerprint); fingerprint != nil {
+			// TLS is done by uTLS when dialing, so the WebSocket dialer sees a plain connection.
+			dialer.NetDial = func(network, addr string) (net.Conn, error) {
+				pconn, err := internet.DialSystem(ctx, dest, streamSettings.SocketSettings)
+				if err != nil {
+					return nil, err
+				}
+				conn, err := tls.FingerprintClient(pconn, tlsConfig, fingerprint)
+				if err != nil {
+					pconn.Close()
+					return nil, err
+				}
+				return conn, nil
+			}
+		} else {
+			scheme = "wss"
+			dialer.TLSClientConfig = tlsConfig
+		}
 	}
 
 	host := dest.NetAddr()
 	if (protocol == "ws" && dest.Port == 80) || (protocol == "wss" && dest.Port == 443) {
 		host = dest.Address.String()
 	}
-	uri := protocol + "://" + host + wsSettings.GetNormalizedPath()
+	uri := scheme + "://" + host + wsSettings.GetNormalizedPath()
+	header := wsSettings.GetRequestHeader()
+	if len(earlyData) > 0 {
+		uri = wsSettings.encodeEarlyData(uri, header, earlyData)
+	}
 
-	conn, resp, err := dialer.Dial(uri, wsSettings.GetRequestHeader())
+	conn, resp, err := dialer.Dial(uri, header)
 	if err != nil {
 		var reason string
 		if resp != nil {
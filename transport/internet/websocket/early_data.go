@@ -0,0 +1,133 @@
+// +build !confonly
+
+package websocket
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/signal/done"
+	"v2ray.com/core/transport/internet"
+)
+
+// delayDialConn is a WebSocket connection that dials on the first write, so that the beginning
+// of the payload is sent as early data in the upgrade request.
+type delayDialConn struct {
+	ctx            context.Context
+	dest           net.Destination
+	streamSettings *internet.MemoryStreamConfig
+
+	access sync.Mutex
+	dialed *done.Instance
+	conn   net.Conn
+	err    error
+}
+
+func newDelayDialConn(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) *delayDialConn {
+	return &delayDialConn{
+		ctx:            ctx,
+		dest:           dest,
+		streamSettings: streamSettings,
+		dialed:         done.New(),
+	}
+}
+
+// Write implements io.Writer. The first write dials the connection with early data.
+func (c *delayDialConn) Write(b []byte) (int, error) {
+	c.access.Lock()
+	if c.dialed.Done() {
+		c.access.Unlock()
+		if c.err != nil {
+			return 0, c.err
+		}
+		return c.conn.Write(b)
+	}
+
+	maxEarlyData := int(c.streamSettings.ProtocolSettings.(*Config).MaxEarlyData)
+	earlyData := b
+	if len(earlyData) > maxEarlyData {
+		earlyData = earlyData[:maxEarlyData]
+	}
+	c.conn, c.err = dialWebsocket(c.ctx, c.dest, c.streamSettings, earlyData)
+	if c.err != nil {
+		c.err = newError("failed to dial WebSocket").Base(c.err)
+	}
+	c.dialed.Close()
+	c.access.Unlock()
+
+	if c.err != nil {
+		return 0, c.err
+	}
+	if len(earlyData) < len(b) {
+		n, err := c.conn.Write(b[len(earlyData):])
+		return len(earlyData) + n, err
+	}
+	return len(b), nil
+}
+
+// Read implements io.Reader. It blocks until the connection is dialed.
+func (c *delayDialConn) Read(b []byte) (int, error) {
+	<-c.dialed.Wait()
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.conn.Read(b)
+}
+
+func (c *delayDialConn) Close() error {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if !c.dialed.Done() {
+		c.err = io.ErrClosedPipe
+		return c.dialed.Close()
+	}
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+func (c *delayDialConn) LocalAddr() net.Addr {
+	if c.dialed.Done() && c.conn != nil {
+		return c.conn.LocalAddr()
+	}
+	return &net.TCPAddr{}
+}
+
+func (c *delayDialConn) RemoteAddr() net.Addr {
+	if c.dialed.Done() && c.conn != nil {
+		return c.conn.RemoteAddr()
+	}
+	if !c.dest.Address.Family().IsIP() {
+		return &net.TCPAddr{}
+	}
+	return &net.TCPAddr{
+		IP:   c.dest.Address.IP(),
+		Port: int(c.dest.Port),
+	}
+}
+
+func (c *delayDialConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+func (c *delayDialConn) SetReadDeadline(t time.Time) error {
+	if c.dialed.Done() && c.conn != nil {
+		return c.conn.SetReadDeadline(t)
+	}
+	return nil
+}
+
+func (c *delayDialConn) SetWriteDeadline(t time.Time) error {
+	if c.dialed.Done() && c.conn != nil {
+		return c.conn.SetWriteDeadline(t)
+	}
+	return nil
+}
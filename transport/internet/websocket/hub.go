@@ -3,38 +3,195 @@
 package websocket
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"net/http"
+	"net/http/httputil"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/websocket"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/net"
 	http_proto "v2ray.com/core/common/protocol/http"
 	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal/done"
 	"v2ray.com/core/transport/internet"
 	v2tls "v2ray.com/core/transport/internet/tls"
 )
 
-type requestHandler struct {
-	path string
-	ln   *Listener
-}
-
 var upgrader = &websocket.Upgrader{
 	ReadBufferSize:   4 * 1024,
 	WriteBufferSize:  4 * 1024,
 	HandshakeTimeout: time.Second * 4,
 }
 
+// requestHandler is an HTTP server that serves WebSocket inbounds listening on the same address.
+type requestHandler struct {
+	sync.RWMutex
+	ctx      context.Context
+	key      string
+	listener net.Listener
+	// settings are the TLS and socket settings of the listener. All inbounds on it must have the same.
+	settings *internet.MemoryStreamConfig
+	// server serves new connections of the listener. It is replaced when an inbound needs a larger header limit.
+	server         *serverListener
+	maxHeaderBytes int
+	routes         []*Listener
+}
+
+// serverListener passes connections of the shared listener to an HTTP server.
+type serverListener struct {
+	addr  net.Addr
+	conns chan net.Conn
+	done  *done.Instance
+}
+
+var errServerListenerClosed = newError("server listener closed")
+
+// Accept implements net.Listener.
+func (l *serverListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done.Wait():
+		return nil, errServerListenerClosed
+	}
+}
+
+// Close implements net.Listener. Connections accepted before keep being served.
+func (l *serverListener) Close() error {
+	return l.done.Close()
+}
+
+// Addr implements net.Listener.
+func (l *serverListener) Addr() net.Addr {
+	return l.addr
+}
+
+// headerLimit returns the max size of request headers of the inbound. Early data in headers counts towards it.
+func headerLimit(config *Config) int {
+	return 2048 + base64.RawURLEncoding.EncodedLen(int(config.MaxEarlyData))
+}
+
+// startServer serves new connections with an HTTP server of the given header limit. The previous server, if any,
+// keeps serving the connections it has accepted. It must be called with the lock held.
+func (h *requestHandler) startServer(maxHeaderBytes int) {
+	l := &serverListener{
+		addr:  h.listener.Addr(),
+		conns: make(chan net.Conn),
+		done:  done.New(),
+	}
+	server := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: time.Second * 4,
+		MaxHeaderBytes:    maxHeaderBytes,
+	}
+	if h.server != nil {
+		h.server.Close() // nolint: errcheck
+	}
+	h.server = l
+	h.maxHeaderBytes = maxHeaderBytes
+
+	// Serve only returns when the server listener is closed.
+	go server.Serve(l) // nolint: errcheck
+}
+
+// serve passes connections of the listener to the current HTTP server, until the listener is closed.
+func (h *requestHandler) serve(ctx context.Context) {
+	for {
+		conn, err := h.listener.Accept()
+		if err != nil {
+			h.Lock()
+			h.server.Close() // nolint: errcheck
+			h.Unlock()
+			newError("failed to serve http for WebSocket").Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+			return
+		}
+
+		h.RLock()
+		select {
+		case h.server.conns <- conn:
+		case <-h.server.done.Wait():
+			conn.Close() // nolint: errcheck
+		}
+		h.RUnlock()
+	}
+}
+
+var (
+	globalHandlers       = make(map[string]*requestHandler)
+	globalHandlersAccess sync.Mutex
+)
+
+// route returns the inbound that serves the request, or nil if none.
+func (h *requestHandler) route(request *http.Request) *Listener {
+	h.RLock()
+	defer h.RUnlock()
+
+	for _, ln := range h.routes {
+		if request.URL.Path == ln.path && ln.config.isValidHost(request.Host) {
+			return ln
+		}
+	}
+	return nil
+}
+
+// fallback returns the proxy for requests not served by any inbound, or nil if none. Inbounds that have a
+// fallback all have the same one, so it doesn't matter which inbound it comes from.
+func (h *requestHandler) fallback() *httputil.ReverseProxy {
+	h.RLock()
+	defer h.RUnlock()
+
+	for _, ln := range h.routes {
+		if ln.fallback != nil {
+			return ln.fallback
+		}
+	}
+	return nil
+}
+
 func (h *requestHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	if request.URL.Path != h.path {
+	// ACME HTTP-01 challenges for certificates of the TLS transport are answered before routing, so that a
+	// WebSocket inbound on port 80 can validate the domains.
+	if v2tls.ServeACMEChallenge(h.ctx, writer, request) {
+		return
+	}
+
+	ln := h.route(request)
+	if ln == nil || !websocket.IsWebSocketUpgrade(request) {
+		if fallback := h.fallback(); fallback != nil {
+			fallback.ServeHTTP(writer, request)
+			return
+		}
+	}
+	if ln == nil {
 		writer.WriteHeader(http.StatusNotFound)
 		return
 	}
-	conn, err := upgrader.Upgrade(writer, request, nil)
+
+	var earlyData []byte
+	var responseHeader http.Header
+	if ln.config.MaxEarlyData > 0 {
+		var err error
+		earlyData, err = ln.config.decodeEarlyData(request)
+		if err != nil {
+			newError("invalid early data").Base(err).WriteToLog()
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// Browsers require the server to echo the subprotocol if early data is sent in it.
+		if len(earlyData) > 0 && strings.EqualFold(ln.config.EarlyDataHeaderName, "Sec-WebSocket-Protocol") {
+			responseHeader = http.Header{}
+			responseHeader.Set("Sec-WebSocket-Protocol", request.Header.Get("Sec-WebSocket-Protocol"))
+		}
+	}
+
+	conn, err := upgrader.Upgrade(writer, request, responseHeader)
 	if err != nil {
 		newError("failed to convert to WebSocket connection").Base(err).WriteToLog()
 		return
@@ -46,23 +203,105 @@ func (h *requestHandler) ServeHTTP(writer http.ResponseWriter, request *http.Req
 		remoteAddr.(*net.TCPAddr).IP = forwardedAddrs[0].IP()
 	}
 
-	h.ln.addConn(newConnection(conn, remoteAddr))
+	wsConn := newConnection(conn, remoteAddr)
+	if len(earlyData) > 0 {
+		wsConn.reader = bytes.NewReader(earlyData)
+	}
+	ln.addConn(wsConn)
+}
+
+// sameListenerSettings returns true if the stream settings have the same TLS and socket settings.
+func sameListenerSettings(a, b *internet.MemoryStreamConfig) bool {
+	if a.SecurityType != b.SecurityType || !proto.Equal(a.SocketSettings, b.SocketSettings) {
+		return false
+	}
+	sa, _ := a.SecuritySettings.(proto.Message)
+	sb, _ := b.SecuritySettings.(proto.Message)
+	if sa == nil || sb == nil {
+		return sa == nil && sb == nil
+	}
+	return proto.Equal(sa, sb)
+}
+
+// addRoute adds an inbound to the server. The inbound must have the same TLS and socket settings as the others,
+// and must not have a different fallback.
+func (h *requestHandler) addRoute(ln *Listener, streamSettings *internet.MemoryStreamConfig) error {
+	h.Lock()
+	defer h.Unlock()
+
+	if !sameListenerSettings(h.settings, streamSettings) {
+		return newError("TLS or socket settings of path ", ln.path, " differ from other inbounds on ", h.key)
+	}
+	for _, r := range h.routes {
+		if r.config.overlaps(ln.config) {
+			return newError("path ", ln.path, " is already served on ", h.key)
+		}
+		if len(r.config.Fallback) > 0 && len(ln.config.Fallback) > 0 && r.config.Fallback != ln.config.Fallback {
+			return newError("fallback of path ", ln.path, " conflicts with the one of path ", r.path, " on ", h.key)
+		}
+	}
+	h.routes = append(h.routes, ln)
+	if limit := headerLimit(ln.config); limit > h.maxHeaderBytes {
+		h.startServer(limit)
+	}
+	return nil
+}
+
+// removeRoute removes the inbound, and returns the number of remaining inbounds.
+func (h *requestHandler) removeRoute(ln *Listener) int {
+	h.Lock()
+	defer h.Unlock()
+
+	for i, r := range h.routes {
+		if r == ln {
+			h.routes = append(h.routes[:i], h.routes[i+1:]...)
+			break
+		}
+	}
+	return len(h.routes)
 }
 
+// Listener is a WebSocket inbound. Inbounds on the same address share one HTTP server.
 type Listener struct {
-	sync.Mutex
-	server   http.Server
-	listener net.Listener
+	handler  *requestHandler
+	path     string
 	config   *Config
+	fallback *httputil.ReverseProxy
 	addConn  internet.ConnHandler
+	closed   bool
 }
 
 func ListenWS(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, addConn internet.ConnHandler) (internet.Listener, error) {
 	wsSettings := streamSettings.ProtocolSettings.(*Config)
 
+	l := &Listener{
+		path:    wsSettings.GetNormalizedPath(),
+		config:  wsSettings,
+		addConn: addConn,
+	}
+	if len(wsSettings.Fallback) > 0 {
+		u, err := wsSettings.getFallbackURL()
+		if err != nil {
+			return nil, err
+		}
+		l.fallback = httputil.NewSingleHostReverseProxy(u)
+	}
+
+	globalHandlersAccess.Lock()
+	defer globalHandlersAccess.Unlock()
+
+	key := net.TCPDestination(address, port).NetAddr()
+	if h, found := globalHandlers[key]; found {
+		if err := h.addRoute(l, streamSettings); err != nil {
+			return nil, err
+		}
+		l.handler = h
+		return l, nil
+	}
+
 	var tlsConfig *tls.Config
 	if config := v2tls.ConfigFromStreamSettings(streamSettings); config != nil {
-		tlsConfig = config.GetTLSConfig()
+		tlsConfig = config.GetTLSConfig(v2tls.WithContext(ctx))
 	}
 
 	listener, err := listenTCP(ctx, address, port, tlsConfig, streamSettings.SocketSettings)
@@ -70,28 +309,20 @@ func ListenWS(ctx context.Context, address net.Address, port net.Port, streamSet
 		return nil, err
 	}
 
-	l := &Listener{
-		config:   wsSettings,
-		addConn:  addConn,
+	h := &requestHandler{
+		ctx:      ctx,
+		key:      key,
 		listener: listener,
+		settings: streamSettings,
+		routes:   []*Listener{l},
 	}
+	h.startServer(headerLimit(wsSettings))
+	l.handler = h
+	globalHandlers[key] = h
 
-	l.server = http.Server{
-		Handler: &requestHandler{
-			path: wsSettings.GetNormalizedPath(),
-			ln:   l,
-		},
-		ReadHeaderTimeout: time.Second * 4,
-		MaxHeaderBytes:    2048,
-	}
+	go h.serve(ctx)
 
-	go func() {
-		if err := l.server.Serve(l.listener); err != nil {
-			newError("failed to serve http for WebSocket").Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
-		}
-	}()
-
-	return l, err
+	return l, nil
 }
 
 func listenTCP(ctx context.Context, address net.Address, port net.Port, tlsConfig *tls.Config, sockopt *internet.SocketConfig) (net.Listener, error) {
@@ -112,12 +343,24 @@ func listenTCP(ctx context.Context, address net.Address, port net.Port, tlsConfi
 
 // Addr implements net.Listener.Addr().
 func (ln *Listener) Addr() net.Addr {
-	return ln.listener.Addr()
+	return ln.handler.listener.Addr()
 }
 
-// Close implements net.Listener.Close().
+// Close implements net.Listener.Close(). The shared listener is closed with the last inbound on it.
 func (ln *Listener) Close() error {
-	return ln.listener.Close()
+	globalHandlersAccess.Lock()
+	defer globalHandlersAccess.Unlock()
+
+	if ln.closed {
+		return nil
+	}
+	ln.closed = true
+
+	if ln.handler.removeRoute(ln) > 0 {
+		return nil
+	}
+	delete(globalHandlers, ln.handler.key)
+	return ln.handler.listener.Close()
 }
 
 func init() {
@@ -2,13 +2,21 @@ package websocket_test
 
 import (
 	"context"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"runtime"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol/tls/cert"
+	"v2ray.com/core/testing/servers/tcp"
 	"v2ray.com/core/transport/internet"
 	"v2ray.com/core/transport/internet/tls"
 	. "v2ray.com/core/transport/internet/websocket"
@@ -146,3 +154,237 @@ func Test_listenWSAndDial_TLS(t *testing.T) {
 		t.Error("end: ", end, " start: ", start)
 	}
 }
+
+func TestDialWithEarlyData(t *testing.T) {
+	for _, headerName := range []string{"", "Sec-WebSocket-Protocol"} {
+		config := &Config{
+			Path:                "ws",
+			MaxEarlyData:        64,
+			EarlyDataHeaderName: headerName,
+		}
+		port := tcp.PickPort()
+		listen, err := ListenWS(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+			ProtocolName:     "websocket",
+			ProtocolSettings: config,
+		}, func(conn internet.Connection) {
+			go func(c internet.Connection) {
+				defer c.Close()
+
+				var b [1024]byte
+				for {
+					n, err := c.Read(b[:])
+					if err != nil {
+						return
+					}
+					common.Must2(c.Write(b[:n]))
+				}
+			}(conn)
+		})
+		common.Must(err)
+
+		for _, size := range []int{16, 64, 200} {
+			conn, err := Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+				ProtocolName:     "websocket",
+				ProtocolSettings: config,
+			})
+			common.Must(err)
+
+			payload := make([]byte, size)
+			common.Must2(rand.Read(payload))
+			common.Must2(conn.Write(payload))
+
+			response := make([]byte, size)
+			common.Must2(io.ReadFull(conn, response))
+			if r := cmp.Diff(response, payload); r != "" {
+				t.Error("header ", headerName, ", size ", size, ": ", r)
+			}
+			common.Must(conn.Close())
+		}
+
+		common.Must(listen.Close())
+	}
+}
+
+func TestListenWSSharedPort(t *testing.T) {
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		common.Must2(w.Write([]byte("fallback " + r.URL.Path)))
+	}))
+	defer fallback.Close()
+
+	port := tcp.PickPort()
+	listen := func(config *Config, response string) internet.Listener {
+		l, err := ListenWS(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+			ProtocolName:     "websocket",
+			ProtocolSettings: config,
+		}, func(conn internet.Connection) {
+			go func() {
+				defer conn.Close()
+				common.Must2(conn.Write([]byte(response)))
+			}()
+		})
+		common.Must(err)
+		return l
+	}
+	la := listen(&Config{Path: "/a", Fallback: fallback.URL}, "a")
+	lb := listen(&Config{Path: "/b", Host: []string{"b.v2ray.com"}}, "b")
+	lc := listen(&Config{Path: "/b", Host: []string{"c.v2ray.com"}}, "c")
+	defer la.Close()
+
+	if _, err := ListenWS(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "websocket",
+		ProtocolSettings: &Config{Path: "/a"},
+	}, func(internet.Connection) {}); err == nil {
+		t.Error("expect error for duplicate path")
+	}
+
+	dial := func(config *Config) string {
+		conn, err := Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+			ProtocolName:     "websocket",
+			ProtocolSettings: config,
+		})
+		if err != nil {
+			return ""
+		}
+		defer conn.Close()
+
+		var b [16]byte
+		n, err := conn.Read(b[:])
+		common.Must(err)
+		return string(b[:n])
+	}
+	testCases := []struct {
+		config   *Config
+		response string
+	}{
+		{config: &Config{Path: "/a"}, response: "a"},
+		{config: &Config{Path: "/b", Header: []*Header{{Key: "Host", Value: "b.v2ray.com"}}}, response: "b"},
+		{config: &Config{Path: "/b", Header: []*Header{{Key: "Host", Value: "c.v2ray.com"}}}, response: "c"},
+		{config: &Config{Path: "/b"}, response: ""},
+	}
+	for _, tc := range testCases {
+		if r := dial(tc.config); r != tc.response {
+			t.Error("dial ", tc.config, ": got ", r, ", want ", tc.response)
+		}
+	}
+
+	resp, err := http.Get("http://" + la.Addr().String() + "/index.html")
+	common.Must(err)
+	body, err := ioutil.ReadAll(resp.Body)
+	common.Must(err)
+	resp.Body.Close()
+	if string(body) != "fallback /index.html" {
+		t.Error("unexpected fallback response: ", string(body))
+	}
+
+	common.Must(lb.Close())
+	common.Must(lc.Close())
+	if r := dial(&Config{Path: "/b", Header: []*Header{{Key: "Host", Value: "b.v2ray.com"}}}); r != "" {
+		t.Error("closed inbound still serves: ", r)
+	}
+	if r := dial(&Config{Path: "/a"}); r != "a" {
+		t.Error("dial /a after closing /b: ", r)
+	}
+}
+
+func TestListenWSACMEChallenge(t *testing.T) {
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		common.Must2(w.Write([]byte("fallback")))
+	}))
+	defer fallback.Close()
+
+	// The config of the TLS transport that obtains the certificate of the domain.
+	acme := &tls.Config{
+		Acme: &tls.AcmeConfig{
+			Domain:       []string{"acme-ws.v2ray.com"},
+			DirectoryUrl: "http://127.0.0.1:1/directory",
+		},
+	}
+	acme.GetTLSConfig(tls.WithContext(context.Background()))
+
+	l, err := ListenWS(context.Background(), net.LocalHostIP, tcp.PickPort(), &internet.MemoryStreamConfig{
+		ProtocolName:     "websocket",
+		ProtocolSettings: &Config{Path: "/ws", Fallback: fallback.URL},
+	}, func(conn internet.Connection) { conn.Close() })
+	common.Must(err)
+	defer l.Close()
+
+	get := func(host string) string {
+		request, err := http.NewRequest("GET", "http://"+l.Addr().String()+"/.well-known/acme-challenge/token", nil)
+		common.Must(err)
+		request.Host = host
+		resp, err := http.DefaultClient.Do(request)
+		common.Must(err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		common.Must(err)
+		return string(body)
+	}
+	if r := get("acme-ws.v2ray.com"); r == "fallback" {
+		t.Error("ACME challenge is not answered")
+	}
+	if r := get("other.v2ray.com"); r != "fallback" {
+		t.Error("unexpected response for unmanaged domain: ", r)
+	}
+}
+
+func TestListenWSSharedPortSettings(t *testing.T) {
+	port := tcp.PickPort()
+	echo := func(conn internet.Connection) {
+		go func() {
+			defer conn.Close()
+			io.Copy(conn, conn) // nolint: errcheck
+		}()
+	}
+	listen := func(config *Config, security interface{}) (internet.Listener, error) {
+		streamSettings := &internet.MemoryStreamConfig{
+			ProtocolName:     "websocket",
+			ProtocolSettings: config,
+		}
+		if security != nil {
+			streamSettings.SecurityType = "tls"
+			streamSettings.SecuritySettings = security
+		}
+		return ListenWS(context.Background(), net.LocalHostIP, port, streamSettings, echo)
+	}
+
+	la, err := listen(&Config{Path: "/a", Fallback: "http://127.0.0.1:1"}, nil)
+	common.Must(err)
+	defer la.Close()
+
+	tlsSettings := &tls.Config{
+		Certificate: []*tls.Certificate{tls.ParseCertificate(cert.MustGenerate(nil, cert.CommonName("localhost")))},
+	}
+	if _, err := listen(&Config{Path: "/tls"}, tlsSettings); err == nil {
+		t.Error("expect error for different TLS settings")
+	}
+	if _, err := listen(&Config{Path: "/fallback", Fallback: "http://127.0.0.1:2"}, nil); err == nil {
+		t.Error("expect error for conflicting fallback")
+	}
+
+	// The inbound joining later needs a larger header limit for its early data.
+	config := &Config{
+		Path:                "/early",
+		MaxEarlyData:        8192,
+		EarlyDataHeaderName: "Sec-WebSocket-Protocol",
+	}
+	lb, err := listen(config, nil)
+	common.Must(err)
+	defer lb.Close()
+
+	conn, err := Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+		ProtocolName:     "websocket",
+		ProtocolSettings: config,
+	})
+	common.Must(err)
+	defer conn.Close()
+
+	payload := make([]byte, 8192)
+	common.Must2(rand.Read(payload))
+	common.Must2(conn.Write(payload))
+	response := make([]byte, len(payload))
+	common.Must(conn.SetReadDeadline(time.Now().Add(time.Second * 5)))
+	common.Must2(io.ReadFull(conn, response))
+	if r := cmp.Diff(response, payload); r != "" {
+		t.Error(r)
+	}
+}